@@ -0,0 +1,204 @@
+package templator
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultArchiveQueueSize is the number of renders waiting to be archived when
+// ArchivePolicy.QueueSize is not set.
+const DefaultArchiveQueueSize = 256
+
+// Archived is a render persisted by WithArchive.
+type Archived[T any] struct {
+	// Template is the name of the rendered template.
+	Template string `json:"template"`
+	// Version identifies the content of the template and of the templates it depends on.
+	Version string `json:"version"`
+	// Labels are the labels set on the request with WithArchiveLabels.
+	Labels map[string]string `json:"labels,omitempty"`
+	Data   T                 `json:"data"`
+	Output []byte            `json:"output"`
+	// Time is when the render started.
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration"`
+}
+
+// ArchiveStore persists archived renders. Save is called by a single background goroutine.
+type ArchiveStore[T any] interface {
+	Save(ctx context.Context, a Archived[T]) error
+}
+
+// ArchivePolicy selects the renders WithArchive persists.
+type ArchivePolicy struct {
+	// SampleRate is the fraction of renders archived, from 0 to 1. Renders of requests
+	// labeled with WithArchiveLabels are always archived.
+	SampleRate float64
+	// QueueSize bounds the renders waiting to be saved, DefaultArchiveQueueSize by default.
+	// Renders are dropped while the queue is full, so archiving never slows rendering down.
+	QueueSize int
+	// OnError, if set, is called with every error saving a render and for every dropped render,
+	// so failures stay observable.
+	OnError func(error)
+}
+
+// ErrArchiveDropped is passed to ArchivePolicy.OnError when a render is dropped
+// because the archive queue is full.
+type ErrArchiveDropped struct {
+	Template string
+}
+
+func (e ErrArchiveDropped) Error() string {
+	return fmt.Sprintf("archive queue full, dropped render of template '%s'", e.Template)
+}
+
+// WithArchive returns an Option that asynchronously saves successful renders selected by
+// policy to store, with their data and output, so support can see exactly what a user was
+// shown. Data is saved as passed to Execute; pointers it holds must not be modified after
+// rendering. Close waits for queued renders to be saved.
+func WithArchive[T any](store ArchiveStore[T], policy ArchivePolicy) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.archive = &archiver[T]{store: store, policy: policy}
+	}
+}
+
+type archiveLabelsKey struct{}
+
+// WithArchiveLabels returns a context flagging renders for archiving, regardless of the
+// sample rate, and attaching labels, such as a user or request ID, to their archives.
+func WithArchiveLabels(ctx context.Context, labels map[string]string) context.Context {
+	return context.WithValue(ctx, archiveLabelsKey{}, labels)
+}
+
+// archiver queues renders and saves them from a background goroutine.
+type archiver[T any] struct {
+	store  ArchiveStore[T]
+	policy ArchivePolicy
+
+	once   sync.Once
+	mu     sync.Mutex
+	closed bool
+	queue  chan Archived[T]
+	done   chan struct{}
+}
+
+// capture reports whether the render of ctx is archived, and its labels.
+func (a *archiver[T]) capture(ctx context.Context) (map[string]string, bool) {
+	if labels, ok := ctx.Value(archiveLabelsKey{}).(map[string]string); ok {
+		return labels, true
+	}
+	return nil, a.policy.SampleRate > 0 && rand.Float64() < a.policy.SampleRate
+}
+
+// enqueue queues a render for saving, starting the background goroutine on first use.
+func (a *archiver[T]) enqueue(r *Registry[T], rec Archived[T]) {
+	a.once.Do(func() {
+		a.queue = make(chan Archived[T], cmp.Or(a.policy.QueueSize, DefaultArchiveQueueSize))
+		a.done = make(chan struct{})
+		go a.run()
+		r.onClose(a.close)
+	})
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+
+	select {
+	case a.queue <- rec:
+	default:
+		a.report(ErrArchiveDropped{Template: rec.Template})
+	}
+}
+
+func (a *archiver[T]) run() {
+	defer close(a.done)
+	for rec := range a.queue {
+		if err := a.store.Save(context.Background(), rec); err != nil {
+			a.report(fmt.Errorf("archive render of template '%s': %w", rec.Template, err))
+		}
+	}
+}
+
+// close stops accepting renders and waits for the queued ones to be saved.
+func (a *archiver[T]) close(ctx context.Context) error {
+	a.mu.Lock()
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (a *archiver[T]) report(err error) {
+	if a.policy.OnError != nil {
+		a.policy.OnError(err)
+	}
+}
+
+// archived renders through w, teeing the output to a buffer that is archived on success.
+func (h *Handler[T]) archived(w io.Writer, data T, labels map[string]string, render func(io.Writer) error) error {
+	buf := new(bytes.Buffer)
+	start := time.Now()
+
+	if err := render(io.MultiWriter(w, buf)); err != nil {
+		return err
+	}
+
+	h.reg.config.archive.enqueue(h.reg, Archived[T]{
+		Template: h.name,
+		Version:  h.src.version(),
+		Labels:   labels,
+		Data:     data,
+		Output:   buf.Bytes(),
+		Time:     start,
+		Duration: time.Since(start),
+	})
+	return nil
+}
+
+// DirArchive is an ArchiveStore saving each render as a JSON file in a directory,
+// for data types that can be encoded as JSON.
+type DirArchive[T any] struct {
+	dir string
+}
+
+// NewDirArchive returns a DirArchive saving renders in dir, which is created if needed.
+func NewDirArchive[T any](dir string) (*DirArchive[T], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirArchive[T]{dir: dir}, nil
+}
+
+// Save writes the render to a new file named after its time.
+func (d *DirArchive[T]) Save(_ context.Context, a Archived[T]) error {
+	content, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(d.dir, a.Time.UTC().Format("20060102T150405.000000000Z")+"-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,156 @@
+package templator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryArchive is an ArchiveStore keeping renders in memory.
+type memoryArchive struct {
+	mu       sync.Mutex
+	archived []Archived[TestData]
+	err      error
+	block    chan struct{}
+}
+
+func (m *memoryArchive) Save(_ context.Context, a Archived[TestData]) error {
+	if m.block != nil {
+		<-m.block
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.archived = append(m.archived, a)
+	return m.err
+}
+
+func TestWithArchive(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":   &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+	}
+
+	t.Run("archives labeled and sampled renders", func(t *testing.T) {
+		t.Parallel()
+
+		var store memoryArchive
+		reg := MustNewRegistry(fs, WithArchive[TestData](&store, ArchivePolicy{}))
+		home := reg.MustGet("home")
+
+		_, err := home.ExecuteToString(context.Background(), TestData{Title: "skipped"})
+		require.NoError(t, err)
+
+		ctx := WithArchiveLabels(context.Background(), map[string]string{"user": "42"})
+		got, err := home.ExecuteToString(ctx, TestData{Title: "kept"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>kept</p>", got)
+
+		_, err = reg.MustGet("broken").ExecuteToString(ctx, TestData{})
+		require.Error(t, err)
+
+		require.NoError(t, reg.Close(context.Background()))
+
+		require.Len(t, store.archived, 1)
+		a := store.archived[0]
+		assert.Equal(t, "home", a.Template)
+		assert.Equal(t, home.src.version(), a.Version)
+		assert.Len(t, a.Version, 16)
+		assert.Equal(t, map[string]string{"user": "42"}, a.Labels)
+		assert.Equal(t, TestData{Title: "kept"}, a.Data)
+		assert.Equal(t, "<p>kept</p>", string(a.Output))
+		assert.False(t, a.Time.IsZero())
+
+		sampled := memoryArchive{}
+		reg = MustNewRegistry(fs, WithArchive[TestData](&sampled, ArchivePolicy{SampleRate: 1}))
+		_, err = reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "sampled"})
+		require.NoError(t, err)
+		require.NoError(t, reg.Close(context.Background()))
+		require.Len(t, sampled.archived, 1)
+		assert.Nil(t, sampled.archived[0].Labels)
+	})
+
+	t.Run("reports save errors and dropped renders", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu   sync.Mutex
+			errs []error
+		)
+		errSave := errors.New("store down")
+		store := memoryArchive{err: errSave, block: make(chan struct{})}
+
+		reg := MustNewRegistry(fs, WithArchive[TestData](&store, ArchivePolicy{
+			SampleRate: 1,
+			QueueSize:  1,
+			OnError: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+			},
+		}))
+
+		// The first render is taken by the blocked store, the second fills the queue.
+		home := reg.MustGet("home")
+		_, err := home.ExecuteToString(context.Background(), TestData{})
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return len(reg.config.archive.queue) == 0 }, time.Second, time.Millisecond)
+		for range 2 {
+			_, err = home.ExecuteToString(context.Background(), TestData{})
+			require.NoError(t, err)
+		}
+
+		close(store.block)
+		require.NoError(t, reg.Close(context.Background()))
+
+		mu.Lock()
+		defer mu.Unlock()
+		require.Len(t, errs, 3)
+		assert.Equal(t, ErrArchiveDropped{Template: "home"}, errs[0])
+		require.ErrorIs(t, errs[1], errSave)
+		require.ErrorIs(t, errs[2], errSave)
+	})
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fs, WithArchive[TestData](nil, ArchivePolicy{SampleRate: 2, QueueSize: -1}))
+		require.ErrorContains(t, err, "archive store must not be nil")
+		require.ErrorContains(t, err, "sample rate must be between 0 and 1")
+		require.ErrorContains(t, err, "queue size must not be negative")
+	})
+}
+
+func TestDirArchive(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	store, err := NewDirArchive[TestData](dir)
+	require.NoError(t, err)
+
+	a := Archived[TestData]{Template: "home", Version: "v1", Data: TestData{Title: "Hi"}, Output: []byte("<p>Hi</p>")}
+	require.NoError(t, store.Save(context.Background(), a))
+	require.NoError(t, store.Save(context.Background(), a))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+
+	var got Archived[TestData]
+	require.NoError(t, json.Unmarshal(content, &got))
+	assert.Equal(t, a, got)
+}
@@ -0,0 +1,167 @@
+package templator
+
+import (
+	"cmp"
+	"errors"
+	"path"
+	"slices"
+	"strings"
+)
+
+// AuditScope lists what an application renders, as the starting points of Audit.
+type AuditScope struct {
+	// Templates are the templates the application gets handlers for, e.g. through generated
+	// accessors. Locale variants of these templates are rendered through GetLocalized.
+	Templates []string
+	// Blocks are the blocks the application renders directly with ExecuteTemplate.
+	Blocks []string
+}
+
+// AuditReport lists the templates and blocks Audit found unused.
+type AuditReport struct {
+	// Unreachable are the templates not rendered by the scope, directly or through
+	// layouts, template calls, and @include macros.
+	Unreachable []string
+	// UnusedBlocks are the blocks defined with define or block that no template invokes
+	// and that are not in the scope.
+	UnusedBlocks []UnusedBlock
+	// Deprecated are the deprecated templates and blocks the scope still renders. They are
+	// not unused, so they do not count against Empty.
+	Deprecated []Deprecation
+}
+
+// UnusedBlock is a block defined by a template and never invoked.
+type UnusedBlock struct {
+	Template string
+	Block    string
+}
+
+// Empty reports whether the audit found nothing unused.
+func (a AuditReport) Empty() bool {
+	return len(a.Unreachable) == 0 && len(a.UnusedBlocks) == 0
+}
+
+// Audit cross-references every template of the registry against the dependency graph
+// reachable from scope, reporting the templates never reachable and the blocks never
+// invoked, so dead templates can be deleted with confidence. Every template is parsed;
+// templates that fail to parse are reported in the joined error, and their dependencies
+// are unknown. Unknown scope templates are reported as ErrTemplateNotFound.
+func (r *Registry[T]) Audit(scope AuditScope) (AuditReport, error) {
+	names, err := r.ownTemplates()
+	if err != nil {
+		return AuditReport{}, err
+	}
+
+	files := make(map[string]bool, len(names))
+	for _, name := range names {
+		files[name+string(r.config.ext)] = true
+	}
+
+	var (
+		errs     []error
+		handlers = make(map[string]*Handler[T], len(names))
+		deps     = make(map[string][]string)
+		defined  = make(map[UnusedBlock]bool)
+		called   = make(map[string]bool)
+	)
+	for _, block := range scope.Blocks {
+		called[block] = true
+	}
+
+	for _, name := range names {
+		h, err := r.parse(name, getConfig{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		handlers[name] = h
+
+		for dep := range h.src.dependencies() {
+			deps[name] = append(deps[name], dep)
+			deps[name] = append(deps[name], r.includes(dep)...)
+		}
+
+		for _, t := range h.src.template().Templates() {
+			if t.Tree == nil {
+				continue
+			}
+			templateCalls(t.Tree.Root, func(block string) { called[block] = true })
+			if !files[t.Name()] {
+				defined[UnusedBlock{Template: strings.TrimSuffix(t.Tree.ParseName, string(r.config.ext)), Block: t.Name()}] = true
+			}
+		}
+	}
+
+	reachable := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if reachable[name] {
+			return
+		}
+		reachable[name] = true
+		for _, dep := range deps[name] {
+			visit(dep)
+		}
+	}
+	for _, name := range scope.Templates {
+		if !slices.Contains(names, name) {
+			errs = append(errs, ErrTemplateNotFound{Name: name})
+			continue
+		}
+		visit(name)
+	}
+
+	// Locale variants, such as "home.de", are rendered when their base template is.
+	for _, name := range names {
+		dir, file := path.Split(name)
+		if i := strings.Index(file, "."); i > 0 && reachable[dir+file[:i]] {
+			visit(name)
+		}
+	}
+
+	var report AuditReport
+	for _, name := range names {
+		if !reachable[name] {
+			report.Unreachable = append(report.Unreachable, name)
+		}
+	}
+	for block := range defined {
+		if !called[block.Block] {
+			report.UnusedBlocks = append(report.UnusedBlocks, block)
+		}
+	}
+	for name, h := range handlers {
+		if reachable[name] {
+			report.Deprecated = append(report.Deprecated, h.src.deprecationsFrom(h.file)...)
+		}
+		for _, block := range scope.Blocks {
+			if h.src.template().Lookup(block) != nil {
+				report.Deprecated = append(report.Deprecated, h.src.deprecationsFrom(block)...)
+			}
+		}
+	}
+
+	slices.Sort(report.Unreachable)
+	slices.SortFunc(report.UnusedBlocks, func(a, b UnusedBlock) int {
+		return cmp.Or(cmp.Compare(a.Template, b.Template), cmp.Compare(a.Block, b.Block))
+	})
+	slices.SortFunc(report.Deprecated, func(a, b Deprecation) int {
+		return cmp.Or(cmp.Compare(a.Template, b.Template), cmp.Compare(a.Block, b.Block))
+	})
+	report.Deprecated = slices.Compact(report.Deprecated)
+	return report, errors.Join(errs...)
+}
+
+// includes returns the templates the named template includes with @include macros.
+func (r *Registry[T]) includes(name string) []string {
+	r.sourceMaps.mu.RLock()
+	defer r.sourceMaps.mu.RUnlock()
+
+	var included []string
+	for _, line := range r.sourceMaps.lines[name] {
+		if line.name != name && !slices.Contains(included, line.name) {
+			included = append(included, line.name)
+		}
+	}
+	return included
+}
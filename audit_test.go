@@ -0,0 +1,50 @@
+package templator
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Audit(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":            &fstest.MapFile{Data: []byte(`{{/* templator: layout="layouts/base" */}}{{define "title"}}Home{{end}}{{template "components/card.html" .}}`)},
+		"templates/home.de.html":         &fstest.MapFile{Data: []byte(`Startseite`)},
+		"templates/layouts/base.html":    &fstest.MapFile{Data: []byte(`<title>{{block "title" .}}{{end}}</title>{{template "content" .}}`)},
+		"templates/components/card.html": &fstest.MapFile{Data: []byte("@include \"legal/terms\"\n{{define \"row\"}}<tr></tr>{{end}}{{define \"legacy\"}}old{{end}}")},
+		"templates/legal/terms.html":     &fstest.MapFile{Data: []byte(`terms`)},
+		"templates/old/promo.html":       &fstest.MapFile{Data: []byte(`{{define "banner"}}promo{{end}}`)},
+	}
+
+	reg := MustNewRegistry(fs,
+		WithMacros[TestData](),
+		WithPartials[TestData]("components/*"),
+	)
+
+	report, err := reg.Audit(AuditScope{Templates: []string{"home"}, Blocks: []string{"row"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old/promo"}, report.Unreachable)
+	assert.Equal(t, []UnusedBlock{
+		{Template: "components/card", Block: "legacy"},
+		{Template: "old/promo", Block: "banner"},
+	}, report.UnusedBlocks)
+	assert.False(t, report.Empty())
+
+	t.Run("reports unknown scope and broken templates", func(t *testing.T) {
+		t.Parallel()
+
+		broken := fstest.MapFS{
+			"templates/home.html":   &fstest.MapFile{Data: []byte(`home`)},
+			"templates/broken.html": &fstest.MapFile{Data: []byte(`{{.Title`)},
+		}
+
+		report, err := MustNewRegistry[TestData](broken).Audit(AuditScope{Templates: []string{"home", "missing"}})
+		require.ErrorAs(t, err, new(ErrTemplateParse))
+		require.ErrorAs(t, err, new(ErrTemplateNotFound))
+		assert.Equal(t, []string{"broken"}, report.Unreachable)
+	})
+}
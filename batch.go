@@ -0,0 +1,62 @@
+package templator
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// BatchItem is a render of a batch passed to ExecuteBatch.
+type BatchItem[T any] struct {
+	// Template is the name of the template to render, as passed to Get.
+	Template string
+	Data     T
+	// W receives the output of the render.
+	W io.Writer
+}
+
+// ExecuteBatch renders every item of batch with Execute, for bulk renders such as email
+// campaigns. Items render concurrently on up to concurrency workers, or one at a time when
+// concurrency is below 1. It returns the error of each item at the item's index, nil for
+// those that rendered. Cancelling ctx fails the items not yet started with its error.
+func (r *Registry[T]) ExecuteBatch(ctx context.Context, batch []BatchItem[T], concurrency int) []error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(batch))
+		todo = make(chan int)
+	)
+	for range min(max(concurrency, 1), len(batch)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range todo {
+				errs[i] = r.executeItem(ctx, batch[i])
+			}
+		}()
+	}
+
+	for i, item := range batch {
+		if err := ctx.Err(); err != nil {
+			errs[i] = ErrTemplateExecution{Name: item.Template, Err: err}
+			continue
+		}
+		select {
+		case todo <- i:
+		case <-ctx.Done():
+			errs[i] = ErrTemplateExecution{Name: item.Template, Err: ctx.Err()}
+		}
+	}
+	close(todo)
+	wg.Wait()
+
+	return errs
+}
+
+// executeItem renders a single item of a batch.
+func (r *Registry[T]) executeItem(ctx context.Context, item BatchItem[T]) error {
+	h, err := r.Get(item.Template)
+	if err != nil {
+		return err
+	}
+	return h.Execute(ctx, item.W, item.Data)
+}
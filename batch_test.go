@@ -0,0 +1,66 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ExecuteBatch(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/welcome.html": &fstest.MapFile{Data: []byte("<p>Hello {{.Title}}</p>")},
+	}
+	reg := MustNewRegistry[TestData](fs)
+
+	t.Run("renders every item", func(t *testing.T) {
+		t.Parallel()
+
+		bufs := make([]bytes.Buffer, 3)
+		errs := reg.ExecuteBatch(context.Background(), []BatchItem[TestData]{
+			{Template: "welcome", Data: TestData{Title: "Ada"}, W: &bufs[0]},
+			{Template: "missing", Data: TestData{Title: "Bob"}, W: &bufs[1]},
+			{Template: "welcome", Data: TestData{Title: "Eve"}, W: &bufs[2]},
+		}, 2)
+
+		require.Len(t, errs, 3)
+		assert.NoError(t, errs[0])
+		assert.ErrorAs(t, errs[1], new(ErrTemplateNotFound))
+		assert.NoError(t, errs[2])
+		assert.Equal(t, "<p>Hello Ada</p>", bufs[0].String())
+		assert.Empty(t, bufs[1].String())
+		assert.Equal(t, "<p>Hello Eve</p>", bufs[2].String())
+	})
+
+	t.Run("renders one at a time without concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		errs := reg.ExecuteBatch(context.Background(), []BatchItem[TestData]{
+			{Template: "welcome", Data: TestData{Title: "Ada"}, W: &buf},
+			{Template: "welcome", Data: TestData{Title: "Bob"}, W: &buf},
+		}, 0)
+
+		assert.Equal(t, []error{nil, nil}, errs)
+		assert.Equal(t, "<p>Hello Ada</p><p>Hello Bob</p>", buf.String())
+	})
+
+	t.Run("fails items when cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var buf bytes.Buffer
+		errs := reg.ExecuteBatch(ctx, []BatchItem[TestData]{{Template: "welcome", W: &buf}}, 4)
+
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], context.Canceled)
+		assert.Empty(t, buf.String())
+	})
+}
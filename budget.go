@@ -0,0 +1,41 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrBudgetExceeded is returned when rendering runs past the render budget carried by the context.
+var ErrBudgetExceeded = errors.New("render budget exceeded")
+
+type budgetKey struct{}
+
+// WithBudget returns a context carrying a render time budget shared by every Execute using it,
+// such as the layout, partials, and cached blocks of a single page. Unlike a context deadline,
+// an exhausted budget fails only the stage being rendered, leaving the request context usable
+// for rendering placeholders. Nested budgets can only shorten the remaining time.
+func WithBudget(ctx context.Context, d time.Duration) context.Context {
+	deadline := time.Now().Add(d)
+	if parent, ok := ctx.Value(budgetKey{}).(time.Time); ok && parent.Before(deadline) {
+		deadline = parent
+	}
+	return context.WithValue(ctx, budgetKey{}, deadline)
+}
+
+// BudgetRemaining returns the time left in the context's render budget
+// and whether the context carries one.
+func BudgetRemaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Value(budgetKey{}).(time.Time)
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+func checkBudget(ctx context.Context) error {
+	if remaining, ok := BudgetRemaining(ctx); ok && remaining <= 0 {
+		return ErrBudgetExceeded
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports remaining time", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := BudgetRemaining(context.Background())
+		assert.False(t, ok)
+
+		remaining, ok := BudgetRemaining(WithBudget(context.Background(), time.Minute))
+		require.True(t, ok)
+		assert.InDelta(t, time.Minute, remaining, float64(time.Second))
+	})
+
+	t.Run("nested budgets only shrink", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := WithBudget(context.Background(), time.Second)
+
+		remaining, ok := BudgetRemaining(WithBudget(ctx, time.Hour))
+		require.True(t, ok)
+		assert.LessOrEqual(t, remaining, time.Second)
+
+		remaining, ok = BudgetRemaining(WithBudget(ctx, time.Millisecond))
+		require.True(t, ok)
+		assert.LessOrEqual(t, remaining, time.Millisecond)
+	})
+}
+
+func TestHandler_ExecuteWithBudget(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/fast.html": &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+		"templates/slow.html": &fstest.MapFile{Data: []byte("<p>{{slow}}</p>")},
+	}
+
+	reg, err := NewRegistry(fs, WithTemplateFuncs[TestData](template.FuncMap{
+		"slow": func() string {
+			time.Sleep(20 * time.Millisecond)
+			return "done"
+		},
+	}))
+	require.NoError(t, err)
+
+	fast, err := reg.Get("fast")
+	require.NoError(t, err)
+
+	slow, err := reg.Get("slow")
+	require.NoError(t, err)
+
+	t.Run("renders within budget", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := fast.ExecuteToString(WithBudget(context.Background(), time.Minute), TestData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>hi</p>", got)
+	})
+
+	t.Run("fails before rendering when budget is spent", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := fast.ExecuteToString(WithBudget(context.Background(), 0), TestData{})
+		require.ErrorIs(t, err, ErrBudgetExceeded)
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+	})
+
+	t.Run("fails stage that runs past budget without canceling the request", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		_, err := slow.ExecuteFragment(WithBudget(ctx, 5*time.Millisecond), TestData{})
+		require.ErrorIs(t, err, ErrBudgetExceeded)
+
+		require.NoError(t, ctx.Err())
+		got, err := fast.ExecuteFragment(ctx, TestData{Title: "placeholder"})
+		require.NoError(t, err)
+		assert.Equal(t, template.HTML("<p>placeholder</p>"), got)
+	})
+}
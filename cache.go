@@ -0,0 +1,335 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// evictionSample is the number of entries examined to pick an eviction victim.
+const evictionSample = 5
+
+// CacheOption configures the render cache created by WithCache.
+type CacheOption func(*cacheConfig)
+
+// WithMaxEntries returns a CacheOption bounding the cache to n entries. Once full, a new key
+// is admitted only if it has been requested more often than the entry it would evict, so
+// one-off renders, such as per-user pages, don't push out hot fragments. Non-positive values
+// leave the cache unbounded.
+func WithMaxEntries(n int) CacheOption {
+	return func(c *cacheConfig) {
+		c.maxEntries = n
+	}
+}
+
+// WithStaleWhileRevalidate returns a CacheOption that keeps serving expired output for up to
+// window after it expires, while a single background render per key refreshes it. Requests
+// never wait for an expensive render once a key has been cached. The refresh uses the context
+// of the request that found the entry stale, without its cancellation.
+func WithStaleWhileRevalidate(window time.Duration) CacheOption {
+	return func(c *cacheConfig) {
+		c.stale = window
+	}
+}
+
+type cacheConfig struct {
+	maxEntries int
+	stale      time.Duration
+}
+
+// WithCache returns a handler for the same template that memoizes rendered output per key,
+// as computed by keyFn from the data, for ttl. It suits templates rendering identical content
+// for many requests, such as navigation bars, footers, and product cards. Expired entries are
+// swept by a single background goroutine per registry until it is closed. The cache lives as
+// long as the returned handler, so create cached handlers once, at setup, to share their
+// output across requests. A non-positive ttl disables caching and returns h itself.
+func (h *Handler[T]) WithCache(ttl time.Duration, keyFn func(T) string, opts ...CacheOption) *Handler[T] {
+	if ttl <= 0 {
+		return h
+	}
+
+	var cfg cacheConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	cache := newRenderCache(ttl, cfg.maxEntries)
+	cache.stale = max(cfg.stale, 0)
+	h.reg.sweeper.add(cache, h.reg.onClose)
+	h.src.track(cache)
+
+	return &Handler[T]{
+		name:         h.name,
+		file:         h.file,
+		src:          h.src,
+		reg:          h.reg,
+		cache:        cache,
+		keyFn:        keyFn,
+		translations: h.translations,
+		overrides:    h.overrides,
+	}
+}
+
+// executeCached writes the cached output for the data's key, rendering and storing it on a miss.
+// With a translator, keys are scoped by locale so translations never leak across languages.
+func (h *Handler[T]) executeCached(ctx context.Context, w io.Writer, data T) error {
+	key := h.keyFn(data)
+	if h.translations != nil {
+		key = h.reg.locale(ctx) + "\x00" + key
+	}
+
+	out, fresh, ok := h.cache.lookup(key)
+	ok = ok && !inject(h.reg.config.chaos.CacheMissRate)
+	h.observeCache(ok)
+	traceFrom(ctx).addCacheLookup(h.name, ok)
+	if ok && !fresh && h.cache.startRefresh(key) {
+		go h.refresh(context.WithoutCancel(ctx), key, data)
+	}
+
+	if !ok {
+		var err error
+		out, err = h.cache.coalesce(ctx, key, func() ([]byte, error) {
+			return h.render(ctx, key, data)
+		})
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ErrTemplateExecution{Name: h.file, Err: ctxErr}
+			}
+			return err
+		}
+	}
+
+	if _, err := (contextWriter{Writer: w, ctx: ctx}).Write(out); err != nil {
+		return ErrTemplateExecution{Name: h.file, Err: err}
+	}
+	return nil
+}
+
+// refresh re-renders the output for key in the background, keeping the stale output on failure.
+func (h *Handler[T]) refresh(ctx context.Context, key string, data T) {
+	defer h.cache.finishRefresh(key)
+	h.render(ctx, key, data)
+}
+
+// render executes the template and stores the output under key, unless the cache
+// was purged by Reload in the meantime.
+func (h *Handler[T]) render(ctx context.Context, key string, data T) ([]byte, error) {
+	gen := h.cache.generation()
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+
+	if err := h.execute(ctx, buf, data); err != nil {
+		return nil, err
+	}
+
+	out := bytes.Clone(buf.Bytes())
+	h.cache.store(key, out, gen)
+	return out, nil
+}
+
+type cacheEntry struct {
+	out     []byte
+	expires time.Time
+}
+
+// renderCache stores rendered output by key until it expires, and for another stale
+// period during which it is served while being refreshed. Bounded caches track key
+// popularity in sketch to decide which entries to admit.
+type renderCache struct {
+	ttl        time.Duration
+	stale      time.Duration
+	now        func() time.Time
+	maxEntries int
+	sketch     *frequencySketch
+
+	mu         sync.RWMutex
+	gen        uint64
+	entries    map[string]cacheEntry
+	refreshing map[string]bool
+	inflight   map[string]*inflightRender
+}
+
+// inflightRender is a render of a missing key that concurrent requests wait on.
+type inflightRender struct {
+	done chan struct{}
+	out  []byte
+	err  error
+}
+
+func newRenderCache(ttl time.Duration, maxEntries int) *renderCache {
+	c := &renderCache{
+		ttl:        ttl,
+		now:        time.Now,
+		maxEntries: maxEntries,
+		entries:    make(map[string]cacheEntry),
+		refreshing: make(map[string]bool),
+		inflight:   make(map[string]*inflightRender),
+	}
+	if maxEntries > 0 {
+		c.sketch = newFrequencySketch(maxEntries)
+	}
+	return c
+}
+
+// lookup returns the output for key, reporting whether it is still fresh
+// or only served within the stale period.
+func (c *renderCache) lookup(key string) (out []byte, fresh, ok bool) {
+	if c.sketch != nil {
+		c.sketch.increment(key)
+	}
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	now := c.now()
+	if !ok || c.expired(entry, now) {
+		return nil, false, false
+	}
+	return entry.out, now.Before(entry.expires), true
+}
+
+// startRefresh reports whether the caller should refresh key, allowing one refresh per key at a time.
+func (c *renderCache) startRefresh(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.refreshing[key] {
+		return false
+	}
+	c.refreshing[key] = true
+	return true
+}
+
+func (c *renderCache) finishRefresh(key string) {
+	c.mu.Lock()
+	delete(c.refreshing, key)
+	c.mu.Unlock()
+}
+
+// coalesce runs render for key unless a render of key is already in flight, in which case
+// it waits and shares its result. When the render was abandoned by the request running it,
+// because its context was canceled or its budget ran out, waiters render on their own.
+func (c *renderCache) coalesce(ctx context.Context, key string, render func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if call, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		if abandoned(call.err) {
+			return render()
+		}
+		return call.out, call.err
+	}
+
+	call := &inflightRender{done: make(chan struct{})}
+	c.inflight[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.out, call.err = render()
+	return call.out, call.err
+}
+
+// abandoned reports whether err comes from the rendering request giving up rather than the template.
+func abandoned(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrBudgetExceeded)
+}
+
+// expired reports whether entry can no longer be served, even stale.
+func (c *renderCache) expired(entry cacheEntry, now time.Time) bool {
+	return !now.Before(entry.expires.Add(c.stale))
+}
+
+// store sets the output for key if it was rendered in generation gen of the cache.
+func (c *renderCache) store(key string, out []byte, gen uint64) {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if gen != c.gen {
+		return
+	}
+
+	if _, ok := c.entries[key]; !ok && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		if !c.admit(key, now) {
+			return
+		}
+	}
+	c.entries[key] = cacheEntry{out: out, expires: now.Add(c.ttl)}
+}
+
+func (c *renderCache) generation() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.gen
+}
+
+// purge removes every entry and starts a new generation, so renders in flight don't store
+// output of the previous template.
+func (c *renderCache) purge() {
+	c.mu.Lock()
+	clear(c.entries)
+	c.gen++
+	c.mu.Unlock()
+}
+
+// admit makes room for key in a full cache, returning false if key is less popular than
+// the least popular of a sample of entries. Expired entries met while sampling are evicted first.
+// It must be called with mu held.
+func (c *renderCache) admit(key string, now time.Time) bool {
+	var (
+		victim     string
+		victimFreq uint8
+		sampled    int
+	)
+	for k, entry := range c.entries {
+		if c.expired(entry, now) {
+			delete(c.entries, k)
+			return true
+		}
+
+		if freq := c.sketch.estimate(k); sampled == 0 || freq < victimFreq {
+			victim, victimFreq = k, freq
+		}
+		if sampled++; sampled == evictionSample {
+			break
+		}
+	}
+
+	if c.sketch.estimate(key) <= victimFreq {
+		return false
+	}
+	delete(c.entries, victim)
+	return true
+}
+
+// sweep removes entries that can no longer be served.
+func (c *renderCache) sweep() {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if c.expired(entry, now) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package templator
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+const (
+	// sketchDepth is the number of counter rows in a frequencySketch.
+	sketchDepth = 4
+	// sketchMinCapacity keeps sketches of small caches from aging and colliding too fast.
+	sketchMinCapacity = 16
+)
+
+// frequencySketch is a count-min sketch estimating how often keys are requested, in the style
+// of TinyLFU. Counters are halved every sample increments so the estimate follows recent
+// popularity rather than all-time totals.
+type frequencySketch struct {
+	mu        sync.Mutex
+	seed      maphash.Seed
+	rows      [sketchDepth][]uint8
+	mask      uint64
+	additions int
+	sample    int
+}
+
+// newFrequencySketch returns a sketch sized for a cache holding up to capacity entries.
+func newFrequencySketch(capacity int) *frequencySketch {
+	capacity = max(capacity, sketchMinCapacity)
+
+	width := 16
+	for width < capacity*4 {
+		width <<= 1
+	}
+
+	s := &frequencySketch{
+		seed:   maphash.MakeSeed(),
+		mask:   uint64(width - 1),
+		sample: capacity * 10,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// increment records one request for key.
+func (s *frequencySketch) increment(key string) {
+	h := maphash.String(s.seed, key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.rows {
+		if idx := s.index(h, i); s.rows[i][idx] < 255 {
+			s.rows[i][idx]++
+		}
+	}
+
+	if s.additions++; s.additions >= s.sample {
+		s.reset()
+	}
+}
+
+// estimate returns the approximate number of recent requests for key.
+func (s *frequencySketch) estimate(key string) uint8 {
+	h := maphash.String(s.seed, key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	est := uint8(255)
+	for i := range s.rows {
+		est = min(est, s.rows[i][s.index(h, i)])
+	}
+	return est
+}
+
+// index derives the counter position of hash h in row i, remixing h per row
+// with the splitmix64 finalizer so rows collide independently.
+func (s *frequencySketch) index(h uint64, i int) uint64 {
+	h += uint64(i+1) * 0x9e3779b97f4a7c15
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return (h ^ h>>31) & s.mask
+}
+
+// reset halves every counter, aging out past popularity.
+func (s *frequencySketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
@@ -0,0 +1,33 @@
+package templator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrequencySketch(t *testing.T) {
+	t.Parallel()
+
+	s := newFrequencySketch(100)
+
+	for range 10 {
+		s.increment("hot")
+	}
+	s.increment("cold")
+
+	assert.GreaterOrEqual(t, s.estimate("hot"), uint8(10))
+	assert.GreaterOrEqual(t, s.estimate("cold"), uint8(1))
+	assert.Less(t, s.estimate("cold"), s.estimate("hot"))
+
+	t.Run("ages counters", func(t *testing.T) {
+		s := newFrequencySketch(1)
+		for range sketchMinCapacity*10 - 1 {
+			s.increment("key")
+		}
+		assert.Equal(t, uint8(sketchMinCapacity*10-1), s.estimate("key"))
+
+		s.increment("key")
+		assert.Equal(t, uint8(sketchMinCapacity*5), s.estimate("key"))
+	})
+}
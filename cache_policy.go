@@ -0,0 +1,178 @@
+package templator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const cacheKey = "cache"
+
+// CachePolicy describes how responses rendered from a template may be cached
+// by browsers and by edge/CDN caches.
+type CachePolicy struct {
+	// MaxAge sets the max-age directive of Cache-Control.
+	MaxAge time.Duration `yaml:"max_age"`
+	// SharedMaxAge sets the s-maxage directive of Cache-Control.
+	SharedMaxAge time.Duration `yaml:"shared_max_age"`
+	// StaleWhileRevalidate sets the stale-while-revalidate directive of Cache-Control.
+	StaleWhileRevalidate time.Duration `yaml:"stale_while_revalidate"`
+	// Public marks the response as cacheable by shared caches.
+	Public bool `yaml:"public"`
+	// Private restricts caching to the client. It takes precedence over Public.
+	Private bool `yaml:"private"`
+	// NoStore disables caching entirely. All other directives are ignored.
+	NoStore bool `yaml:"no_store"`
+	// SurrogateMaxAge sets the max-age directive of Surrogate-Control,
+	// which edge caches consume and strip before forwarding to clients.
+	SurrogateMaxAge time.Duration `yaml:"surrogate_max_age"`
+}
+
+// WithCachePolicy returns an Option that assigns a cache policy to the named template.
+func WithCachePolicy[T any](name string, policy CachePolicy) Option[T] {
+	return func(r *Registry[T]) {
+		if r.config.cachePolicies == nil {
+			r.config.cachePolicies = make(map[string]CachePolicy)
+		}
+		r.config.cachePolicies[name] = policy
+	}
+}
+
+// CacheControl returns the Cache-Control header value for the policy.
+func (p CachePolicy) CacheControl() string {
+	if p.NoStore {
+		return "no-store"
+	}
+
+	var directives []string
+	switch {
+	case p.Private:
+		directives = append(directives, "private")
+	case p.Public:
+		directives = append(directives, "public")
+	}
+
+	if p.MaxAge > 0 {
+		directives = append(directives, "max-age="+seconds(p.MaxAge))
+	}
+	if p.SharedMaxAge > 0 && !p.Private {
+		directives = append(directives, "s-maxage="+seconds(p.SharedMaxAge))
+	}
+	if p.StaleWhileRevalidate > 0 {
+		directives = append(directives, "stale-while-revalidate="+seconds(p.StaleWhileRevalidate))
+	}
+	return strings.Join(directives, ", ")
+}
+
+// SurrogateControl returns the Surrogate-Control header value for the policy.
+func (p CachePolicy) SurrogateControl() string {
+	if p.NoStore {
+		return "no-store"
+	}
+	if p.SurrogateMaxAge > 0 {
+		return "max-age=" + seconds(p.SurrogateMaxAge)
+	}
+	return ""
+}
+
+// Apply sets the Cache-Control and Surrogate-Control headers described by the policy.
+// Headers with an empty value are left untouched.
+func (p CachePolicy) Apply(h http.Header) {
+	if v := p.CacheControl(); v != "" {
+		h.Set("Cache-Control", v)
+	}
+	if v := p.SurrogateControl(); v != "" {
+		h.Set("Surrogate-Control", v)
+	}
+}
+
+// CachePolicy returns the cache policy of the handler's template and whether it has one.
+// Policies set with WithCachePolicy take precedence over the cache block of the template's
+// frontmatter.
+func (h *Handler[T]) CachePolicy() (CachePolicy, bool) {
+	if p, ok := h.reg.config.cachePolicies[h.name]; ok {
+		return p, true
+	}
+	if p := h.src.cachePolicy(); p != nil {
+		return *p, true
+	}
+	return CachePolicy{}, false
+}
+
+// cachePolicyOf returns the policy of the cache block in the metadata of the named template,
+// or nil without one. Ages are seconds or durations such as "1h":
+//
+//	cache:
+//	  public: true
+//	  max_age: 60
+func cachePolicyOf(name string, md Metadata) (*CachePolicy, error) {
+	value, ok := md[cacheKey]
+	if !ok {
+		return nil, nil
+	}
+
+	block, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("template '%s': %w: cache must be a map of directives", name, ErrInvalidPragma)
+	}
+
+	var policy CachePolicy
+	for _, key := range slices.Sorted(maps.Keys(block)) {
+		var err error
+		switch key {
+		case "max_age":
+			policy.MaxAge, err = cacheAge(block[key])
+		case "shared_max_age":
+			policy.SharedMaxAge, err = cacheAge(block[key])
+		case "stale_while_revalidate":
+			policy.StaleWhileRevalidate, err = cacheAge(block[key])
+		case "surrogate_max_age":
+			policy.SurrogateMaxAge, err = cacheAge(block[key])
+		case "public":
+			policy.Public, err = cacheFlag(block[key])
+		case "private":
+			policy.Private, err = cacheFlag(block[key])
+		case "no_store":
+			policy.NoStore, err = cacheFlag(block[key])
+		default:
+			err = errors.New("unknown directive")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("template '%s': %w: cache %s: %w", name, ErrInvalidPragma, key, err)
+		}
+	}
+	return &policy, nil
+}
+
+// cacheAge decodes an age of a frontmatter cache block: seconds, or a duration string.
+func cacheAge(value any) (time.Duration, error) {
+	switch v := value.(type) {
+	case int64:
+		if v >= 0 {
+			return time.Duration(v) * time.Second, nil
+		}
+	case string:
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d, nil
+		}
+	}
+	return 0, errors.New("must be seconds or a duration")
+}
+
+// cacheFlag decodes a switch of a frontmatter cache block.
+func cacheFlag(value any) (bool, error) {
+	v, ok := value.(bool)
+	if !ok {
+		return false, errors.New("must be true or false")
+	}
+	return v, nil
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
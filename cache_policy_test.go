@@ -0,0 +1,159 @@
+package templator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachePolicy_Headers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		policy           CachePolicy
+		wantCacheControl string
+		wantSurrogate    string
+	}{
+		{
+			name:             "empty policy",
+			policy:           CachePolicy{},
+			wantCacheControl: "",
+			wantSurrogate:    "",
+		},
+		{
+			name: "public with shared max age",
+			policy: CachePolicy{
+				Public:               true,
+				MaxAge:               time.Minute,
+				SharedMaxAge:         time.Hour,
+				StaleWhileRevalidate: 30 * time.Second,
+				SurrogateMaxAge:      24 * time.Hour,
+			},
+			wantCacheControl: "public, max-age=60, s-maxage=3600, stale-while-revalidate=30",
+			wantSurrogate:    "max-age=86400",
+		},
+		{
+			name: "private drops shared max age",
+			policy: CachePolicy{
+				Private:      true,
+				Public:       true,
+				MaxAge:       time.Minute,
+				SharedMaxAge: time.Hour,
+			},
+			wantCacheControl: "private, max-age=60",
+			wantSurrogate:    "",
+		},
+		{
+			name: "no store overrides everything",
+			policy: CachePolicy{
+				NoStore:         true,
+				Public:          true,
+				MaxAge:          time.Minute,
+				SurrogateMaxAge: time.Minute,
+			},
+			wantCacheControl: "no-store",
+			wantSurrogate:    "no-store",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.wantCacheControl, tc.policy.CacheControl())
+			assert.Equal(t, tc.wantSurrogate, tc.policy.SurrogateControl())
+
+			h := http.Header{}
+			tc.policy.Apply(h)
+			assert.Equal(t, tc.wantCacheControl, h.Get("Cache-Control"))
+			assert.Equal(t, tc.wantSurrogate, h.Get("Surrogate-Control"))
+		})
+	}
+}
+
+func TestHandler_CachePolicy(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":  &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+		"templates/about.html": &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+	}
+
+	policy := CachePolicy{Public: true, MaxAge: time.Minute}
+
+	reg, err := NewRegistry(fs, WithCachePolicy[TestData]("home", policy))
+	require.NoError(t, err)
+
+	home, err := reg.Get("home")
+	require.NoError(t, err)
+
+	got, ok := home.CachePolicy()
+	require.True(t, ok)
+	assert.Equal(t, policy, got)
+
+	about, err := reg.Get("about")
+	require.NoError(t, err)
+
+	_, ok = about.CachePolicy()
+	assert.False(t, ok)
+}
+
+func TestHandler_CachePolicy_Frontmatter(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":    &fstest.MapFile{Data: []byte("---\ncache:\n  public: true\n  max_age: 60\n  surrogate_max_age: 1h\n---\n<h1>{{.Title}}</h1>")},
+		"templates/about.html":   &fstest.MapFile{Data: []byte("---\ncache:\n  no_store: true\n---\n<p>About</p>")},
+		"templates/invalid.html": &fstest.MapFile{Data: []byte("---\ncache:\n  max_age: soon\n---\n<p></p>")},
+		"templates/unknown.html": &fstest.MapFile{Data: []byte("---\ncache:\n  immutable: true\n---\n<p></p>")},
+	}
+
+	t.Run("applies the frontmatter cache block", func(t *testing.T) {
+		t.Parallel()
+
+		home := MustNewRegistry[TestData](fs).MustGet("home")
+
+		got, ok := home.CachePolicy()
+		require.True(t, ok)
+		assert.Equal(t, CachePolicy{Public: true, MaxAge: time.Minute, SurrogateMaxAge: time.Hour}, got)
+
+		rec := httptest.NewRecorder()
+		home.HTTP(func(*http.Request) (TestData, error) {
+			return TestData{Title: "Hi"}, nil
+		}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "max-age=3600", rec.Header().Get("Surrogate-Control"))
+	})
+
+	t.Run("prefers registry options", func(t *testing.T) {
+		t.Parallel()
+
+		policy := CachePolicy{Private: true, MaxAge: time.Second}
+		reg := MustNewRegistry(fs, WithCachePolicy[TestData]("about", policy))
+
+		got, ok := reg.MustGet("about").CachePolicy()
+		require.True(t, ok)
+		assert.Equal(t, policy, got)
+	})
+
+	t.Run("rejects invalid directives", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+
+		_, err := reg.Get("invalid")
+		require.ErrorIs(t, err, ErrInvalidPragma)
+		assert.ErrorContains(t, err, "cache max_age: must be seconds or a duration")
+
+		_, err = reg.Get("unknown")
+		require.ErrorIs(t, err, ErrInvalidPragma)
+		assert.ErrorContains(t, err, "cache immutable: unknown directive")
+	})
+}
@@ -0,0 +1,107 @@
+package templator
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"weak"
+)
+
+// cacheSweeper removes expired entries from the render caches of a registry, from a single
+// goroutine started with the first cache. It holds the caches weakly, so the caches of
+// cached handlers that are no longer referenced are collected with them.
+type cacheSweeper struct {
+	mu       sync.Mutex
+	caches   []sweptCache
+	interval time.Duration
+	ticker   *time.Ticker
+	stop     chan struct{}
+}
+
+// sweptCache is a cache of a sweeper, with the time it is next swept at.
+type sweptCache struct {
+	cache weak.Pointer[renderCache]
+	next  time.Time
+}
+
+// add makes the sweeper sweep c every ttl of c, starting the sweeper with onClose registering
+// its shutdown when c is its first cache.
+func (s *cacheSweeper) add(c *renderCache, onClose func(func(context.Context) error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.caches = append(s.caches, sweptCache{cache: weak.Make(c), next: c.now().Add(c.ttl)})
+
+	switch {
+	case s.ticker == nil:
+		s.interval = c.ttl
+		s.ticker = time.NewTicker(c.ttl)
+		s.stop = make(chan struct{})
+		go s.run(s.ticker, s.stop)
+		onClose(s.close)
+	case c.ttl < s.interval:
+		s.interval = c.ttl
+		s.ticker.Reset(c.ttl)
+	}
+}
+
+func (s *cacheSweeper) run(ticker *time.Ticker, stop chan struct{}) {
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.sweep()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// sweep sweeps the caches that are due and forgets the collected ones.
+func (s *cacheSweeper) sweep() {
+	s.mu.Lock()
+	s.caches = slices.DeleteFunc(s.caches, func(sc sweptCache) bool { return sc.cache.Value() == nil })
+	var due []*renderCache
+	for i, sc := range s.caches {
+		c := sc.cache.Value()
+		if c == nil {
+			continue
+		}
+		if now := c.now(); !now.Before(sc.next) {
+			s.caches[i].next = now.Add(c.ttl)
+			due = append(due, c)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range due {
+		c.sweep()
+	}
+}
+
+// live returns the number of caches of the sweeper that have not been collected.
+func (s *cacheSweeper) live() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, sc := range s.caches {
+		if sc.cache.Value() != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *cacheSweeper) close(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	return nil
+}
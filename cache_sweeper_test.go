@@ -0,0 +1,87 @@
+package templator
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheSweeper(t *testing.T) {
+	t.Parallel()
+
+	byTitle := func(d TestData) string { return d.Title }
+
+	t.Run("collects unreferenced caches", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newCountingRegistry(t, &renders)
+		handler, err := reg.Get("card")
+		require.NoError(t, err)
+
+		kept := handler.WithCache(time.Minute, byTitle)
+		for range 100 {
+			handler.WithCache(time.Minute, byTitle)
+		}
+
+		require.Eventually(t, func() bool {
+			runtime.GC()
+			reg.sweeper.sweep()
+			return reg.sweeper.live() == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		reg.sweeper.mu.Lock()
+		assert.Len(t, reg.sweeper.caches, 1)
+		reg.sweeper.mu.Unlock()
+		runtime.KeepAlive(kept)
+	})
+
+	t.Run("sweeps due caches", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newCountingRegistry(t, &renders)
+		handler, err := reg.Get("card")
+		require.NoError(t, err)
+
+		cached := handler.WithCache(time.Minute, byTitle)
+		now := time.Now()
+		cached.cache.now = func() time.Time { return now }
+
+		cached.cache.store("old", []byte("old"), cached.cache.generation())
+		reg.sweeper.sweep()
+		_, _, ok := cached.cache.lookup("old")
+		require.True(t, ok)
+
+		now = now.Add(2 * time.Minute)
+		reg.sweeper.sweep()
+
+		cached.cache.mu.RLock()
+		defer cached.cache.mu.RUnlock()
+		assert.NotContains(t, cached.cache.entries, "old")
+	})
+
+	t.Run("stops with the registry", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newCountingRegistry(t, &renders)
+		handler, err := reg.Get("card")
+		require.NoError(t, err)
+
+		handler.WithCache(time.Minute, byTitle)
+		stop := reg.sweeper.stop
+		require.NoError(t, reg.Close(context.Background()))
+
+		select {
+		case <-stop:
+		default:
+			t.Fatal("cache sweeper not stopped")
+		}
+	})
+}
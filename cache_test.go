@@ -0,0 +1,351 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingRegistry(t *testing.T, renders *atomic.Int64) *Registry[TestData] {
+	t.Helper()
+
+	fs := fstest.MapFS{
+		"templates/card.html": &fstest.MapFile{Data: []byte("{{count}}<p>{{.Title}}</p>")},
+	}
+
+	reg, err := NewRegistry(fs, WithTemplateFuncs[TestData](template.FuncMap{
+		"count": func() string {
+			renders.Add(1)
+			return ""
+		},
+	}))
+	require.NoError(t, err)
+	t.Cleanup(func() { reg.Close(context.Background()) })
+	return reg
+}
+
+func TestHandler_WithCache(t *testing.T) {
+	t.Parallel()
+
+	byTitle := func(d TestData) string { return d.Title }
+
+	t.Run("memoizes output per key until expiry", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newCountingRegistry(t, &renders)
+
+		handler, err := reg.Get("card")
+		require.NoError(t, err)
+
+		cached := handler.WithCache(time.Minute, byTitle)
+
+		now := time.Now()
+		cached.cache.now = func() time.Time { return now }
+
+		for range 3 {
+			got, err := cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+			require.NoError(t, err)
+			assert.Equal(t, "<p>a</p>", got)
+		}
+		assert.Equal(t, int64(1), renders.Load())
+
+		got, err := cached.ExecuteToString(context.Background(), TestData{Title: "b"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>b</p>", got)
+		assert.Equal(t, int64(2), renders.Load())
+
+		now = now.Add(time.Minute)
+		_, err = cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), renders.Load())
+
+		_, err = handler.ExecuteToString(context.Background(), TestData{Title: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), renders.Load(), "original handler is not cached")
+	})
+
+	t.Run("honors context on cache hit", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newCountingRegistry(t, &renders)
+
+		handler, err := reg.Get("card")
+		require.NoError(t, err)
+
+		cached := handler.WithCache(time.Minute, byTitle)
+		_, err = cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = cached.ExecuteToString(ctx, TestData{Title: "a"})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("non-positive ttl disables caching", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newCountingRegistry(t, &renders)
+
+		handler, err := reg.Get("card")
+		require.NoError(t, err)
+
+		assert.Same(t, handler, handler.WithCache(0, byTitle))
+	})
+}
+
+func TestRenderCache_Sweep(t *testing.T) {
+	t.Parallel()
+
+	c := newRenderCache(time.Minute, 0)
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.store("old", []byte("old"), c.generation())
+	now = now.Add(30 * time.Second)
+	c.store("new", []byte("new"), c.generation())
+	now = now.Add(30 * time.Second)
+
+	c.sweep()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	assert.NotContains(t, c.entries, "old")
+	assert.Contains(t, c.entries, "new")
+}
+
+func TestRenderCache_Admission(t *testing.T) {
+	t.Parallel()
+
+	c := newRenderCache(time.Minute, 2)
+
+	for _, key := range []string{"nav", "footer"} {
+		for range 5 {
+			c.lookup(key)
+		}
+		c.store(key, []byte(key), c.generation())
+	}
+
+	for i := range 20 {
+		key := fmt.Sprintf("user-%d", i)
+		c.lookup(key)
+		c.store(key, []byte(key), c.generation())
+	}
+
+	_, _, ok := c.lookup("nav")
+	assert.True(t, ok, "hot entry kept")
+	_, _, ok = c.lookup("footer")
+	assert.True(t, ok, "hot entry kept")
+
+	for range 10 {
+		c.lookup("popular")
+	}
+	c.store("popular", []byte("popular"), c.generation())
+
+	_, _, ok = c.lookup("popular")
+	assert.True(t, ok, "popular key admitted")
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	assert.Len(t, c.entries, 2)
+}
+
+func TestRenderCache_AdmissionEvictsExpired(t *testing.T) {
+	t.Parallel()
+
+	c := newRenderCache(time.Minute, 1)
+
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	for range 5 {
+		c.lookup("old")
+	}
+	c.store("old", []byte("old"), c.generation())
+
+	now = now.Add(time.Minute)
+	c.store("new", []byte("new"), c.generation())
+
+	_, _, ok := c.lookup("new")
+	assert.True(t, ok)
+}
+
+func TestHandler_WithCache_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		renders atomic.Int64
+		gate    = make(chan struct{})
+	)
+
+	fs := fstest.MapFS{
+		"templates/page.html": &fstest.MapFile{Data: []byte("{{render}}")},
+	}
+
+	reg, err := NewRegistry(fs, WithTemplateFuncs[TestData](template.FuncMap{
+		"render": func() int64 {
+			n := renders.Add(1)
+			if n > 1 {
+				<-gate
+			}
+			return n
+		},
+	}))
+	require.NoError(t, err)
+	t.Cleanup(func() { reg.Close(context.Background()) })
+
+	handler, err := reg.Get("page")
+	require.NoError(t, err)
+
+	cached := handler.WithCache(time.Minute, func(d TestData) string { return d.Title }, WithStaleWhileRevalidate(time.Minute))
+
+	var now atomic.Int64
+	now.Store(time.Now().UnixNano())
+	cached.cache.now = func() time.Time { return time.Unix(0, now.Load()) }
+
+	got, err := cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", got)
+
+	now.Add(int64(90 * time.Second))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			got, err := cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+			assert.NoError(t, err)
+			assert.Equal(t, "1", got, "stale output served without waiting")
+		}()
+	}
+	wg.Wait()
+
+	close(gate)
+	require.Eventually(t, func() bool {
+		out, fresh, _ := cached.cache.lookup("a")
+		return fresh && string(out) == "2"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int64(2), renders.Load(), "refresh coalesced")
+
+	now.Add(int64(3 * time.Minute))
+	got, err = cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, "3", got, "past the stale window renders synchronously")
+}
+
+func TestHandler_WithCache_Coalescing(t *testing.T) {
+	t.Parallel()
+
+	newGatedHandler := func(t *testing.T, renders *atomic.Int64, gate chan struct{}) *Handler[TestData] {
+		t.Helper()
+
+		fs := fstest.MapFS{
+			"templates/page.html": &fstest.MapFile{Data: []byte("{{render}}<p>{{.Title}}</p>")},
+		}
+
+		reg, err := NewRegistry(fs, WithTemplateFuncs[TestData](template.FuncMap{
+			"render": func() string {
+				renders.Add(1)
+				<-gate
+				return ""
+			},
+		}))
+		require.NoError(t, err)
+		t.Cleanup(func() { reg.Close(context.Background()) })
+
+		handler, err := reg.Get("page")
+		require.NoError(t, err)
+		return handler.WithCache(time.Minute, func(d TestData) string { return d.Title })
+	}
+
+	t.Run("concurrent misses render once", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		gate := make(chan struct{})
+		cached := newGatedHandler(t, &renders, gate)
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				got, err := cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+				assert.NoError(t, err)
+				assert.Equal(t, "<p>a</p>", got)
+			}()
+		}
+
+		require.Eventually(t, func() bool { return renders.Load() == 1 }, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+		close(gate)
+		wg.Wait()
+
+		assert.Equal(t, int64(1), renders.Load())
+	})
+
+	t.Run("waiters render when the leader gives up", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		gate := make(chan struct{})
+		cached := newGatedHandler(t, &renders, gate)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		leaderErr := make(chan error, 1)
+		go func() {
+			_, err := cached.ExecuteToString(ctx, TestData{Title: "a"})
+			leaderErr <- err
+		}()
+		require.Eventually(t, func() bool { return renders.Load() == 1 }, time.Second, time.Millisecond)
+
+		followerOut := make(chan string, 1)
+		go func() {
+			got, err := cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+			assert.NoError(t, err)
+			followerOut <- got
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		close(gate)
+
+		require.ErrorIs(t, <-leaderErr, context.Canceled)
+		assert.Equal(t, "<p>a</p>", <-followerOut)
+		assert.Equal(t, int64(2), renders.Load())
+	})
+
+	t.Run("waiters honor their own context", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		gate := make(chan struct{})
+		cached := newGatedHandler(t, &renders, gate)
+		defer close(gate)
+
+		go cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+		require.Eventually(t, func() bool { return renders.Load() == 1 }, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := cached.ExecuteToString(ctx, TestData{Title: "a"})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
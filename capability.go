@@ -0,0 +1,68 @@
+package templator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// needsKey is the metadata key listing the capabilities a template needs.
+const needsKey = "needs"
+
+// CapabilityI18n is the capability provided by WithTranslator.
+const CapabilityI18n = "i18n"
+
+// WithCapabilities returns an Option declaring capabilities the registry provides besides
+// those of its func packs and of WithTranslator, such as functions installed with
+// WithTemplateFuncs, for templates that declare needing them.
+func WithCapabilities[T any](names ...string) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.capabilities = append(r.config.capabilities, names...)
+	}
+}
+
+// provides reports whether the registry provides the capability: a func pack of that name,
+// CapabilityI18n with a translator, or one declared with WithCapabilities.
+func (r *Registry[T]) provides(capability string) bool {
+	if capability == CapabilityI18n && r.config.translator != nil {
+		return true
+	}
+	if slices.Contains(r.config.capabilities, capability) {
+		return true
+	}
+	return slices.ContainsFunc(r.config.funcPacks, func(pack FuncPack) bool {
+		return pack != nil && pack.Name() == capability
+	})
+}
+
+// checkNeeds returns ErrMissingCapabilities when the named template declares needing
+// capabilities the registry does not provide, either as a list or as a comma-separated string.
+func (r *Registry[T]) checkNeeds(name string, md Metadata) error {
+	needs, err := metadataList(name, needsKey, "capabilities", md)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, capability := range needs {
+		if !r.provides(capability) {
+			missing = append(missing, capability)
+		}
+	}
+	if len(missing) > 0 {
+		return ErrMissingCapabilities{Template: name, Capabilities: missing}
+	}
+	return nil
+}
+
+// ErrMissingCapabilities is returned by Get when a template needs capabilities, declared with
+// a needs pragma, that the registry does not provide.
+type ErrMissingCapabilities struct {
+	Template     string
+	Capabilities []string
+}
+
+func (e ErrMissingCapabilities) Error() string {
+	return fmt.Sprintf("template '%s' needs capabilities the registry does not provide: %s",
+		e.Template, strings.Join(e.Capabilities, ", "))
+}
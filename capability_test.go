@@ -0,0 +1,64 @@
+package templator
+
+import (
+	"html/template"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/form.html": &fstest.MapFile{Data: []byte(
+			`{{/* templator: needs="i18n, assets, csrf" */}}<form>{{csrfField}}{{t "submit"}}<img src="{{asset "logo.png"}}"></form>`,
+		)},
+		"templates/list.html": &fstest.MapFile{Data: []byte("---\nneeds: [assets]\n---\n{{asset \"x\"}}")},
+		"templates/bad.html":  &fstest.MapFile{Data: []byte(`{{/* templator: needs=42 */}}`)},
+	}
+
+	assets := testPack{name: "assets", funcs: template.FuncMap{"asset": func(s string) string { return s }}}
+	translator := func(_, key string, _ ...any) string { return key }
+
+	t.Run("provided capabilities", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs,
+			WithFuncPacks[TestData](assets),
+			WithTranslator[TestData](translator),
+			WithTemplateFuncs[TestData](template.FuncMap{"csrfField": func() template.HTML { return "" }}),
+			WithCapabilities[TestData]("csrf"),
+		)
+
+		_, err := reg.Get("form")
+		require.NoError(t, err)
+		_, err = reg.Get("list")
+		require.NoError(t, err)
+	})
+
+	t.Run("missing capabilities", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithFuncPacks[TestData](assets), WithFuncValidation[TestData]())
+
+		_, err := reg.Get("form")
+		var capErr ErrMissingCapabilities
+		require.ErrorAs(t, err, &capErr)
+		assert.Equal(t, ErrMissingCapabilities{Template: "form", Capabilities: []string{"i18n", "csrf"}}, capErr)
+		assert.EqualError(t, err, "template 'form' needs capabilities the registry does not provide: i18n, csrf")
+
+		_, err = MustNewRegistry[TestData](fs).Get("list")
+		require.ErrorAs(t, err, &capErr)
+	})
+
+	t.Run("invalid declaration", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := MustNewRegistry[TestData](fs).Get("bad")
+		require.ErrorIs(t, err, ErrInvalidPragma)
+		require.ErrorContains(t, err, "needs must list capabilities")
+	})
+}
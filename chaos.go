@@ -0,0 +1,52 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrChaos is the render error injected by WithChaos.
+var ErrChaos = errors.New("injected failure")
+
+// ChaosPolicy sets the failures WithChaos injects. Rates range from 0, never, to 1, always.
+type ChaosPolicy struct {
+	// ParseDelayRate is the fraction of template loads delayed by ParseDelay.
+	ParseDelayRate float64
+	ParseDelay     time.Duration
+	// ExecutionErrorRate is the fraction of renders failing with ErrChaos,
+	// wrapped in ErrTemplateExecution.
+	ExecutionErrorRate float64
+	// CacheMissRate is the fraction of cached renders that miss the cache and render again.
+	CacheMissRate float64
+}
+
+// WithChaos returns an Option that randomly injects parse delays, render errors, and cache
+// misses, to verify that fallback templates, circuit breakers, and error pages work under
+// failure. It is meant for tests and staging environments, never for production.
+func WithChaos[T any](policy ChaosPolicy) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.chaos = policy
+		r.config.hooks = append(r.config.hooks, executionHooks[T]{
+			pre: func(context.Context, string, T) error {
+				if inject(policy.ExecutionErrorRate) {
+					return ErrChaos
+				}
+				return nil
+			},
+		})
+	}
+}
+
+// delayParse sleeps for the parse delay of the policy, at its rate.
+func (p ChaosPolicy) delayParse() {
+	if inject(p.ParseDelayRate) {
+		time.Sleep(p.ParseDelay)
+	}
+}
+
+// inject reports whether to inject a failure occurring at rate.
+func inject(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
@@ -0,0 +1,95 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithChaos(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("{{count}}<p>{{.Title}}</p>")},
+	}
+
+	newRegistry := func(renders *atomic.Int64, policy ChaosPolicy) *Registry[TestData] {
+		return MustNewRegistry(fs,
+			WithTemplateFuncs[TestData](template.FuncMap{
+				"count": func() string {
+					renders.Add(1)
+					return ""
+				},
+			}),
+			WithChaos[TestData](policy),
+		)
+	}
+
+	t.Run("injects render errors", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newRegistry(&renders, ChaosPolicy{ExecutionErrorRate: 1})
+
+		_, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "a"})
+		require.ErrorIs(t, err, ErrChaos)
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		assert.Equal(t, "home.html", execErr.Name)
+		assert.Zero(t, renders.Load())
+	})
+
+	t.Run("injects cache misses", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newRegistry(&renders, ChaosPolicy{CacheMissRate: 1})
+		t.Cleanup(func() { reg.Close(context.Background()) })
+
+		cached := reg.MustGet("home").WithCache(time.Minute, func(d TestData) string { return d.Title })
+		for range 3 {
+			got, err := cached.ExecuteToString(context.Background(), TestData{Title: "a"})
+			require.NoError(t, err)
+			assert.Equal(t, "<p>a</p>", got)
+		}
+		assert.Equal(t, int64(3), renders.Load())
+	})
+
+	t.Run("delays parsing", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newRegistry(&renders, ChaosPolicy{ParseDelayRate: 1, ParseDelay: 20 * time.Millisecond})
+
+		start := time.Now()
+		_, err := reg.Get("home")
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("zero policy injects nothing", func(t *testing.T) {
+		t.Parallel()
+
+		var renders atomic.Int64
+		reg := newRegistry(&renders, ChaosPolicy{})
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>a</p>", got)
+	})
+
+	t.Run("validates policy", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fs, WithChaos[TestData](ChaosPolicy{ExecutionErrorRate: 2, ParseDelay: -time.Second}))
+		require.ErrorContains(t, err, "rates must be between 0 and 1")
+		require.ErrorContains(t, err, "parse delay must not be negative")
+	})
+}
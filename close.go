@@ -0,0 +1,53 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrRegistryClosed is returned by Get and Execute once the registry has been closed.
+var ErrRegistryClosed = errors.New("registry closed")
+
+// Close stops background components owned by the registry, such as watchers and the cache
+// sweeper, and flushes pending telemetry. After Close, Get returns ErrRegistryClosed and
+// Execute returns an ErrTemplateExecution wrapping it. Components are stopped in reverse
+// order of registration and receive ctx to bound their shutdown. Calling Close more than
+// once is a no-op.
+func (r *Registry[T]) Close(ctx context.Context) error {
+	if !r.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	r.mu.Lock()
+	closers := r.closers
+	r.closers = nil
+	r.mu.Unlock()
+
+	var errs []error
+	for _, closeFn := range slices.Backward(closers) {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := closeFn(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// onClose registers a function run by Close, used by background components
+// to stop their goroutines and flush state. If the registry is already closed,
+// fn runs immediately.
+func (r *Registry[T]) onClose(fn func(context.Context) error) {
+	r.mu.Lock()
+	if !r.closed.Load() {
+		r.closers = append(r.closers, fn)
+		r.mu.Unlock()
+		return
+	}
+	r.mu.Unlock()
+
+	fn(context.Background())
+}
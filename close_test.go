@@ -0,0 +1,88 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Close(t *testing.T) {
+	t.Parallel()
+
+	newRegistry := func(t *testing.T) *Registry[TestData] {
+		t.Helper()
+
+		reg, err := NewRegistry[TestData](fstest.MapFS{
+			"templates/home.html": &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+		})
+		require.NoError(t, err)
+		return reg
+	}
+
+	t.Run("rejects Get and Execute after close", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newRegistry(t)
+
+		handler, err := reg.Get("home")
+		require.NoError(t, err)
+
+		require.NoError(t, reg.Close(context.Background()))
+
+		_, err = reg.Get("home")
+		require.ErrorIs(t, err, ErrRegistryClosed)
+
+		_, err = handler.ExecuteToString(context.Background(), TestData{})
+		require.ErrorIs(t, err, ErrRegistryClosed)
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+	})
+
+	t.Run("stops components in reverse order once", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newRegistry(t)
+
+		var order []string
+		reg.onClose(func(context.Context) error {
+			order = append(order, "first")
+			return nil
+		})
+		reg.onClose(func(context.Context) error {
+			order = append(order, "second")
+			return errors.New("flush failed")
+		})
+
+		err := reg.Close(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flush failed")
+		assert.Equal(t, []string{"second", "first"}, order)
+
+		require.NoError(t, reg.Close(context.Background()))
+		assert.Len(t, order, 2)
+	})
+
+	t.Run("stops early when context is done", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newRegistry(t)
+
+		called := false
+		reg.onClose(func(context.Context) error {
+			called = true
+			return nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := reg.Close(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.False(t, called)
+	})
+}
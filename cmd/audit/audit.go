@@ -0,0 +1,207 @@
+// Package main reports dead templates: templates the application never renders, directly or
+// through other templates, and blocks no template invokes. The templates the application
+// renders are found in its Go sources, as the string arguments of Get, MustGet, and
+// GetLocalized calls naming a template of the tree, which include generated accessors and
+// routes, and the blocks as those of ExecuteTemplate calls. See Registry.Audit.
+//
+// Usage:
+//
+//	go run ./cmd/audit [flags]
+//
+// Flags:
+//
+//	-templates string
+//	  	Directory containing template files (default "templates")
+//	-ext string
+//	  	Template file extension (default ".html")
+//	-src string
+//	  	Directory of the Go sources rendering the templates, scanned recursively (default ".")
+//	-roots string
+//	  	Comma-separated templates rendered in ways the scan cannot see (optional)
+//	-partials string
+//	  	Comma-separated partial globs, as passed to WithPartials (optional)
+//	-macros
+//	  	Expand @include and @component macros, as with WithMacros (optional)
+//
+// Findings are printed one per line:
+//
+//	unreachable  old/promo
+//	unused       components/card: legacy
+//	deprecated   components/banner (use components/alert)
+//
+// Deprecated templates and blocks still rendered are listed to track their retirement, but
+// are not dead. The exit status is 1 when dead templates were found or the audit failed,
+// and 0 otherwise.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/alesr/templator"
+)
+
+// errDead is returned by run when dead templates were found.
+var errDead = errors.New("dead templates found")
+
+var (
+	// getCall matches the template name of Get, MustGet, and GetLocalized calls.
+	getCall = regexp.MustCompile(`\b(?:Get|MustGet|GetLocalized)\(\s*"([^"]+)"`)
+	// routeCall matches the template name of the registerRoute calls of generated routes.
+	routeCall = regexp.MustCompile(`\bregisterRoute\([^,]+,[^,]+,\s*"[^"]*",\s*"([^"]+)"`)
+	// blockCall matches the block name of ExecuteTemplate calls.
+	blockCall = regexp.MustCompile(`\bExecuteTemplate\([^,]+,[^,]+,\s*"([^"]+)"`)
+)
+
+type config struct {
+	templateDir string
+	ext         string
+	srcDir      string
+	roots       []string
+	partials    []string
+	macros      bool
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	report, err := audit(cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range report.Unreachable {
+		fmt.Fprintf(out, "%-12s %s\n", "unreachable", name)
+	}
+	for _, block := range report.UnusedBlocks {
+		fmt.Fprintf(out, "%-12s %s: %s\n", "unused", block.Template, block.Block)
+	}
+	for _, d := range report.Deprecated {
+		fmt.Fprintf(out, "%-12s %s\n", "deprecated", d)
+	}
+	if !report.Empty() {
+		return fmt.Errorf("%w: %d unreachable templates, %d unused blocks",
+			errDead, len(report.Unreachable), len(report.UnusedBlocks))
+	}
+	return nil
+}
+
+func parseFlags(args []string) (config, error) {
+	var (
+		cfg             config
+		roots, partials string
+	)
+	flags := flag.NewFlagSet("audit", flag.ContinueOnError)
+	flags.StringVar(&cfg.templateDir, "templates", "templates", "Directory containing template files")
+	flags.StringVar(&cfg.ext, "ext", ".html", "Template file extension")
+	flags.StringVar(&cfg.srcDir, "src", ".", "Directory of the Go sources rendering the templates")
+	flags.StringVar(&roots, "roots", "", "Comma-separated templates rendered in ways the scan cannot see")
+	flags.StringVar(&partials, "partials", "", "Comma-separated partial globs, as passed to WithPartials")
+	flags.BoolVar(&cfg.macros, "macros", false, "Expand @include and @component macros")
+
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+	cfg.roots = splitList(roots)
+	cfg.partials = splitList(partials)
+	return cfg, nil
+}
+
+func splitList(s string) []string {
+	var list []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// audit audits the template tree against the templates and blocks rendered by the sources.
+func audit(cfg config) (templator.AuditReport, error) {
+	abs, err := filepath.Abs(cfg.templateDir)
+	if err != nil {
+		return templator.AuditReport{}, err
+	}
+
+	opts := []templator.Option[any]{
+		templator.WithTemplatesPath[any](filepath.Base(abs)),
+		templator.WithExtension[any](templator.Extension(cfg.ext)),
+		templator.WithPartials[any](cfg.partials...),
+	}
+	if cfg.macros {
+		opts = append(opts, templator.WithMacros[any]())
+	}
+
+	reg, err := templator.NewRegistry(os.DirFS(filepath.Dir(abs)), opts...)
+	if err != nil {
+		return templator.AuditReport{}, err
+	}
+
+	names, err := reg.ListTemplates()
+	if err != nil {
+		return templator.AuditReport{}, err
+	}
+
+	scope, err := scanSources(cfg.srcDir)
+	if err != nil {
+		return templator.AuditReport{}, err
+	}
+	// Get calls of other types, such as url.Values.Get, name no template.
+	scope.Templates = slices.DeleteFunc(scope.Templates, func(name string) bool {
+		return !slices.Contains(names, name)
+	})
+	scope.Templates = append(scope.Templates, cfg.roots...)
+	return reg.Audit(scope)
+}
+
+// scanSources returns the templates and blocks rendered by the Go files under dir.
+func scanSources(dir string) (templator.AuditScope, error) {
+	var scope templator.AuditScope
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".go" {
+			return err
+		}
+
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		for _, re := range []*regexp.Regexp{getCall, routeCall} {
+			for _, m := range re.FindAllSubmatch(content, -1) {
+				scope.Templates = append(scope.Templates, string(m[1]))
+			}
+		}
+		for _, m := range blockCall.FindAllSubmatch(content, -1) {
+			scope.Blocks = append(scope.Blocks, string(m[1]))
+		}
+		return nil
+	})
+	if err != nil {
+		return templator.AuditScope{}, err
+	}
+
+	slices.Sort(scope.Templates)
+	slices.Sort(scope.Blocks)
+	scope.Templates = slices.Compact(scope.Templates)
+	scope.Blocks = slices.Compact(scope.Blocks)
+	return scope, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"templates/home.html":             `{{template "partials/nav.html" .}}`,
+		"templates/about.html":            `about`,
+		"templates/rows.html":             `{{define "row"}}<tr></tr>{{end}}{{define "cell"}}<td></td>{{end}}`,
+		"templates/partials/nav.html":     `<nav></nav>`,
+		"templates/partials/footer.html":  `<footer></footer>`,
+		"templates/errors/not_found.html": `404`,
+		"app/main.go": `package main
+
+func main() {
+	tpl.MustGetHome()
+	page := r.URL.Query().Get("page")
+	rows := reg.MustGet("rows")
+	rows.ExecuteTemplate(ctx, w, "row", data)
+}
+`,
+		"app/accessors_gen.go": `func (r *TemplateAccessors[T]) GetHome() (*templator.Handler[T], error) {
+	return r.registry.Get("home")
+}
+`,
+	})
+
+	var out bytes.Buffer
+	err := run([]string{
+		"-templates", filepath.Join(dir, "templates"),
+		"-src", filepath.Join(dir, "app"),
+		"-partials", "partials/*",
+		"-roots", "errors/not_found",
+	}, &out)
+	require.ErrorIs(t, err, errDead)
+	assert.Equal(t, "unreachable  about\nunreachable  partials/footer\nunused       rows: cell\n", out.String())
+
+	t.Run("clean tree", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := run([]string{
+			"-templates", filepath.Join(dir, "templates"),
+			"-src", filepath.Join(dir, "app"),
+			"-roots", "about,errors/not_found,partials/footer,partials/nav",
+			"-partials", "partials/*",
+		}, &out)
+		require.ErrorIs(t, err, errDead, "the cell block is still unused")
+
+		err = run([]string{"-templates", filepath.Join(dir, "templates"), "-src", filepath.Join(dir, "app"), "-roots", "missing"}, &out)
+		require.ErrorContains(t, err, "missing")
+	})
+}
+
+func TestRun_Deprecated(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"templates/home.html":         `{{template "partials/nav.html" .}}`,
+		"templates/rows.html":         `{{define "row"}}{{/* templator: deprecated=true */}}<tr></tr>{{end}}`,
+		"templates/partials/nav.html": `{{/* templator: deprecated="use partials/menu" */}}<nav></nav>`,
+		"app/main.go": `package main
+
+func main() {
+	reg.MustGet("home")
+	reg.MustGet("rows").ExecuteTemplate(ctx, w, "row", data)
+}
+`,
+	})
+
+	var out bytes.Buffer
+	err := run([]string{
+		"-templates", filepath.Join(dir, "templates"),
+		"-src", filepath.Join(dir, "app"),
+		"-partials", "partials/*",
+	}, &out)
+	require.NoError(t, err, "deprecated templates still in use are not dead")
+	assert.Equal(t, "deprecated   partials/nav (use partials/menu)\ndeprecated   rows: row\n", out.String())
+}
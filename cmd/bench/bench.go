@@ -0,0 +1,310 @@
+// Package main benchmarks the rendering of a template with a fixture under several registry
+// configurations, against a baseline executing the parsed html/template directly, so options
+// can be chosen with data rather than guesswork.
+//
+// Usage:
+//
+//	go run ./cmd/bench -template home -data fixtures/home.json [flags]
+//
+// Flags:
+//
+//	-template string
+//	  	Template to render (required)
+//	-data string
+//	  	JSON or YAML fixture of the data, by extension (optional)
+//	-n int
+//	  	Renders per configuration (default 10000)
+//	-configs string
+//	  	Comma-separated configurations to run (default all)
+//	-templates string
+//	  	Directory containing template files (default "templates")
+//	-ext string
+//	  	Template file extension (default ".html")
+//	-partials string
+//	  	Comma-separated partial patterns, e.g. "components/*"
+//	-macros
+//	  	Expand template macros
+//
+// The configurations are:
+//
+//	stdlib      the html/template of the handler, see Handler.Template
+//	buffered    Execute with the default buffered writes
+//	unbuffered  Execute with WithUnbufferedWrites
+//	minified    Execute with WithMinification
+//	cached      Execute through Handler.WithCache, with a single key
+//
+// Results are printed as a table, with the time per render relative to stdlib:
+//
+//	config      ns/op  B/op   allocs/op  bytes  vs stdlib
+//	stdlib      8120   2304   41         1840   1.00x
+//	buffered    9034   2496   45         1840   1.11x
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alesr/templator"
+	"gopkg.in/yaml.v3"
+)
+
+// Data is the data templates are rendered with: a decoded JSON or YAML object.
+type Data = map[string]any
+
+type config struct {
+	template    string
+	data        string
+	n           int
+	configs     []string
+	templateDir string
+	ext         string
+	partials    string
+	macros      bool
+}
+
+// renderFunc renders the template of a configuration.
+type renderFunc func(ctx context.Context, w io.Writer, data Data) error
+
+// benchmark is a configuration: the registry options it adds, and how it renders a handler.
+type benchmark struct {
+	name   string
+	opts   []templator.Option[Data]
+	render func(h *templator.Handler[Data]) renderFunc
+}
+
+// execute renders with Handler.Execute.
+func execute(h *templator.Handler[Data]) renderFunc {
+	return h.Execute
+}
+
+var benchmarks = []benchmark{
+	{
+		name: "stdlib",
+		render: func(h *templator.Handler[Data]) renderFunc {
+			tmpl := h.Template()
+			return func(_ context.Context, w io.Writer, data Data) error {
+				return tmpl.Execute(w, data)
+			}
+		},
+	},
+	{name: "buffered", render: execute},
+	{name: "unbuffered", opts: []templator.Option[Data]{templator.WithUnbufferedWrites[Data]()}, render: execute},
+	{name: "minified", opts: []templator.Option[Data]{templator.WithMinification[Data]()}, render: execute},
+	{
+		name: "cached",
+		render: func(h *templator.Handler[Data]) renderFunc {
+			return h.WithCache(time.Hour, func(Data) string { return "" }).Execute
+		},
+	},
+}
+
+// Result is the measurement of a configuration.
+type Result struct {
+	Config      string
+	NsPerOp     int64
+	BytesPerOp  uint64
+	AllocsPerOp uint64
+	// OutputBytes is the size of a render.
+	OutputBytes int
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	data, err := readData(cfg.data)
+	if err != nil {
+		return err
+	}
+
+	var results []Result
+	for _, b := range benchmarks {
+		if len(cfg.configs) > 0 && !slices.Contains(cfg.configs, b.name) {
+			continue
+		}
+
+		result, err := measure(cfg, b, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", b.name, err)
+		}
+		results = append(results, result)
+	}
+	return printResults(out, results)
+}
+
+func parseFlags(args []string) (config, error) {
+	var (
+		cfg     config
+		configs string
+	)
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	flags.StringVar(&cfg.template, "template", "", "Template to render")
+	flags.StringVar(&cfg.data, "data", "", "JSON or YAML fixture of the data, by extension")
+	flags.IntVar(&cfg.n, "n", 10000, "Renders per configuration")
+	flags.StringVar(&configs, "configs", "", "Comma-separated configurations to run (default all)")
+	flags.StringVar(&cfg.templateDir, "templates", "templates", "Directory containing template files")
+	flags.StringVar(&cfg.ext, "ext", ".html", "Template file extension")
+	flags.StringVar(&cfg.partials, "partials", "", `Comma-separated partial patterns, e.g. "components/*"`)
+	flags.BoolVar(&cfg.macros, "macros", false, "Expand template macros")
+
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+	if cfg.template == "" {
+		return config{}, errors.New("-template is required")
+	}
+	if cfg.n <= 0 {
+		return config{}, fmt.Errorf("-n must be positive, got %d", cfg.n)
+	}
+
+	for name := range strings.SplitSeq(configs, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !slices.ContainsFunc(benchmarks, func(b benchmark) bool { return b.name == name }) {
+			return config{}, fmt.Errorf("unknown configuration %q", name)
+		}
+		cfg.configs = append(cfg.configs, name)
+	}
+	return cfg, nil
+}
+
+// measure renders the template cfg.n times in the configuration of b, after a warm-up
+// render that parses the template and fills caches.
+func measure(cfg config, b benchmark, data Data) (Result, error) {
+	reg, err := newRegistry(cfg, b.opts...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer reg.Close(context.Background())
+
+	h, err := reg.Get(cfg.template)
+	if err != nil {
+		return Result{}, err
+	}
+	render := b.render(h)
+
+	ctx := context.Background()
+	var w countingWriter
+	if err := render(ctx, &w, data); err != nil {
+		return Result{}, err
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	for range cfg.n {
+		if err := render(ctx, io.Discard, data); err != nil {
+			return Result{}, err
+		}
+	}
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	n := uint64(cfg.n)
+	return Result{
+		Config:      b.name,
+		NsPerOp:     elapsed.Nanoseconds() / int64(cfg.n),
+		BytesPerOp:  (after.TotalAlloc - before.TotalAlloc) / n,
+		AllocsPerOp: (after.Mallocs - before.Mallocs) / n,
+		OutputBytes: int(w),
+	}, nil
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter int
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+// printResults prints the results as a table, comparing the time per render to stdlib.
+func printResults(out io.Writer, results []Result) error {
+	var baseline int64
+	for _, r := range results {
+		if r.Config == "stdlib" {
+			baseline = r.NsPerOp
+		}
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "config\tns/op\tB/op\tallocs/op\tbytes\tvs stdlib")
+	for _, r := range results {
+		ratio := "-"
+		if baseline > 0 {
+			ratio = fmt.Sprintf("%.2fx", float64(r.NsPerOp)/float64(baseline))
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\n", r.Config, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp, r.OutputBytes, ratio)
+	}
+	return tw.Flush()
+}
+
+// newRegistry creates a registry over the template directory, with the options of a configuration.
+func newRegistry(cfg config, extra ...templator.Option[Data]) (*templator.Registry[Data], error) {
+	abs, err := filepath.Abs(cfg.templateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []templator.Option[Data]{
+		templator.WithTemplatesPath[Data](filepath.Base(abs)),
+		templator.WithExtension[Data](templator.Extension(cfg.ext)),
+	}
+	if cfg.partials != "" {
+		opts = append(opts, templator.WithPartials[Data](strings.Split(cfg.partials, ",")...))
+	}
+	if cfg.macros {
+		opts = append(opts, templator.WithMacros[Data]())
+	}
+	return templator.NewRegistry(os.DirFS(filepath.Dir(abs)), append(opts, extra...)...)
+}
+
+// readData decodes the fixture at path. Without a path the data is empty.
+func readData(path string) (Data, error) {
+	data := Data{}
+	if path == "" {
+		return data, nil
+	}
+
+	unmarshal := json.Unmarshal
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	case ".json":
+	default:
+		return nil, fmt.Errorf("fixture %s: unsupported extension %q, expected .json, .yaml, or .yml", path, ext)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return data, nil
+}
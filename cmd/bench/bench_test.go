@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"templates/home.html":            "<main>\n  <h1>{{.title}}</h1>\n  {{template \"components/list.html\" .}}\n</main>",
+		"templates/components/list.html": `<ul>{{range .items}}<li>{{.}}</li>{{end}}</ul>`,
+		"fixtures/home.json":             `{"title": "Home", "items": ["a", "b", "c"]}`,
+		"fixtures/home.yaml":             "title: Home\nitems: [a, b, c]\n",
+	})
+
+	base := []string{"-templates", filepath.Join(dir, "templates"), "-partials", "components/*", "-template", "home", "-n", "20"}
+
+	t.Run("all configurations", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, run(append(base, "-data", filepath.Join(dir, "fixtures/home.json")), &out))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 6)
+		assert.Equal(t, []string{"config", "ns/op", "B/op", "allocs/op", "bytes", "vs", "stdlib"}, strings.Fields(lines[0]))
+
+		var configs []string
+		bytesOf := make(map[string]int)
+		for _, line := range lines[1:] {
+			fields := strings.Fields(line)
+			require.Len(t, fields, 6, line)
+			configs = append(configs, fields[0])
+			bytesOf[fields[0]], _ = strconv.Atoi(fields[4])
+		}
+		assert.Equal(t, []string{"stdlib", "buffered", "unbuffered", "minified", "cached"}, configs)
+		assert.Equal(t, "1.00x", strings.Fields(lines[1])[5])
+		assert.Equal(t, bytesOf["stdlib"], bytesOf["buffered"])
+		assert.Less(t, bytesOf["minified"], bytesOf["buffered"])
+	})
+
+	t.Run("selected configurations", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, run(append(base, "-data", filepath.Join(dir, "fixtures/home.yaml"), "-configs", "cached,minified"), &out))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasPrefix(lines[1], "minified "))
+		assert.True(t, strings.HasSuffix(lines[1], " -"), "no stdlib baseline to compare with")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name    string
+			args    []string
+			wantErr string
+		}{
+			{name: "missing template", args: []string{"-n", "1"}, wantErr: "-template is required"},
+			{name: "invalid n", args: append(base, "-n", "0"), wantErr: "-n must be positive"},
+			{name: "unknown configuration", args: append(base, "-configs", "turbo"), wantErr: `unknown configuration "turbo"`},
+			{name: "unknown template", args: append(base, "-template", "missing"), wantErr: "stdlib:"},
+			{name: "unsupported fixture", args: append(base, "-data", "fixture.txt"), wantErr: "unsupported extension"},
+		}
+
+		for _, tt := range tests {
+			err := run(tt.args, &bytes.Buffer{})
+			require.ErrorContains(t, err, tt.wantErr, tt.name)
+		}
+	})
+}
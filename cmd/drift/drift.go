@@ -0,0 +1,183 @@
+// Package main reports drift between the templates served by a running service and a local
+// template tree. It downloads the manifest exposed by the service's templator health endpoint
+// (see Registry.HealthHandler) and compares its checksums with those of the local files.
+//
+// Usage:
+//
+//	go run ./cmd/drift -url https://app.example.com/healthz [flags]
+//
+// Flags:
+//
+//	-url string
+//	  	URL of the health endpoint of the running service (required)
+//	-templates string
+//	  	Directory containing template files (default "templates")
+//	-ext string
+//	  	Template file extension (default ".html")
+//	-timeout duration
+//	  	Timeout for downloading the manifest (default 10s)
+//
+// Every drifted template is printed on its own line, prefixed by its state:
+//
+//	modified     the deployed template differs from the local one
+//	deployed     the template is only deployed
+//	undeployed   the template only exists locally
+//
+// The exit status is 1 when templates drifted or the comparison failed, and 0 otherwise.
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/alesr/templator"
+)
+
+// errDrift is returned by run when templates drifted.
+var errDrift = errors.New("templates drifted")
+
+type config struct {
+	url         string
+	templateDir string
+	ext         string
+	timeout     time.Duration
+}
+
+// Drift is a template whose deployed and local versions differ.
+type Drift struct {
+	Name  string
+	State string
+}
+
+func main() {
+	if err := run(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(out io.Writer) error {
+	cfg, err := parseFlags(os.Args[1:])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+
+	drifts, err := compare(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range drifts {
+		fmt.Fprintf(out, "%-12s %s\n", d.State, d.Name)
+	}
+	if len(drifts) > 0 {
+		return fmt.Errorf("%w: %d templates", errDrift, len(drifts))
+	}
+	return nil
+}
+
+func parseFlags(args []string) (config, error) {
+	var cfg config
+	flags := flag.NewFlagSet("drift", flag.ContinueOnError)
+	flags.StringVar(&cfg.url, "url", "", "URL of the health endpoint of the running service (required)")
+	flags.StringVar(&cfg.templateDir, "templates", "templates", "Directory containing template files")
+	flags.StringVar(&cfg.ext, "ext", ".html", "Template file extension")
+	flags.DurationVar(&cfg.timeout, "timeout", 10*time.Second, "Timeout for downloading the manifest")
+
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+	if cfg.url == "" {
+		return config{}, errors.New("-url is required")
+	}
+	return cfg, nil
+}
+
+// compare returns the templates that drifted between the service and the local tree, by name.
+func compare(ctx context.Context, cfg config) ([]Drift, error) {
+	deployed, err := fetchManifest(ctx, cfg.url)
+	if err != nil {
+		return nil, err
+	}
+
+	local, err := localManifest(cfg.templateDir, cfg.ext)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []Drift
+	for name, sum := range deployed.Templates {
+		switch localSum, ok := local.Templates[name]; {
+		case !ok:
+			drifts = append(drifts, Drift{Name: name, State: "deployed"})
+		case localSum != sum:
+			drifts = append(drifts, Drift{Name: name, State: "modified"})
+		}
+	}
+	for name := range local.Templates {
+		if _, ok := deployed.Templates[name]; !ok {
+			drifts = append(drifts, Drift{Name: name, State: "undeployed"})
+		}
+	}
+
+	slices.SortFunc(drifts, func(a, b Drift) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return drifts, nil
+}
+
+// fetchManifest downloads the manifest served by the health endpoint at url.
+func fetchManifest(ctx context.Context, url string) (templator.Manifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return templator.Manifest{}, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return templator.Manifest{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return templator.Manifest{}, fmt.Errorf("health endpoint replied %s", res.Status)
+	}
+
+	var m templator.Manifest
+	if err := json.NewDecoder(res.Body).Decode(&m); err != nil {
+		return templator.Manifest{}, fmt.Errorf("decode manifest: %w", err)
+	}
+	return m, nil
+}
+
+// localManifest returns the manifest of the templates in dir, checksummed like the service's.
+func localManifest(dir, ext string) (templator.Manifest, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return templator.Manifest{}, err
+	}
+
+	reg, err := templator.NewRegistry[any](os.DirFS(filepath.Dir(abs)),
+		templator.WithTemplatesPath[any](filepath.Base(abs)),
+		templator.WithExtension[any](templator.Extension(ext)),
+	)
+	if err != nil {
+		return templator.Manifest{}, err
+	}
+	defer reg.Close(context.Background())
+
+	return reg.Manifest()
+}
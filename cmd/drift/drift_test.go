@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	deployed := templator.MustNewRegistry[any](fstest.MapFS{
+		"templates/home.html":            &fstest.MapFile{Data: []byte("<h1>home</h1>")},
+		"templates/about.html":           &fstest.MapFile{Data: []byte("<h1>about v1</h1>")},
+		"templates/legacy.html":          &fstest.MapFile{Data: []byte("<h1>legacy</h1>")},
+		"templates/components/menu.html": &fstest.MapFile{Data: []byte("<nav></nav>")},
+	})
+	srv := httptest.NewServer(deployed.HealthHandler())
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"home.html":            "<h1>home</h1>",
+		"about.html":           "<h1>about v2</h1>",
+		"contact.html":         "<h1>contact</h1>",
+		"components/menu.html": "<nav></nav>",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	t.Run("reports drifted templates", func(t *testing.T) {
+		t.Parallel()
+
+		drifts, err := compare(context.Background(), config{url: srv.URL, templateDir: dir, ext: ".html"})
+		require.NoError(t, err)
+		assert.Equal(t, []Drift{
+			{Name: "about", State: "modified"},
+			{Name: "contact", State: "undeployed"},
+			{Name: "legacy", State: "deployed"},
+		}, drifts)
+	})
+
+	t.Run("fails on unhealthy service", func(t *testing.T) {
+		t.Parallel()
+
+		unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		t.Cleanup(unhealthy.Close)
+
+		_, err := compare(context.Background(), config{url: unhealthy.URL, templateDir: dir, ext: ".html"})
+		require.ErrorContains(t, err, "health endpoint replied 503")
+	})
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseFlags([]string{"-url", "http://localhost/healthz", "-templates", "views"})
+	require.NoError(t, err)
+	assert.Equal(t, config{url: "http://localhost/healthz", templateDir: "views", ext: ".html", timeout: 10 * time.Second}, cfg)
+
+	_, err = parseFlags(nil)
+	require.ErrorContains(t, err, "-url is required")
+}
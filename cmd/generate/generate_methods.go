@@ -15,18 +15,95 @@
 //	  	Package name for generated code (default "main")
 //	-templator-import string
 //	  	Import path for templator (default "github.com/alesr/templator")
+//	-registry-import string
+//	  	Alias of -templator-import, for forks or wrappers of the registry package
+//	-registry-type string
+//	  	Emit methods on this type of the output package, embedding a registry, instead of TemplateAccessors (optional)
+//	-data-type string
+//	  	With a non-generic -registry-type, the data type of its registry (optional)
+//	-ext string
+//	  	Template file extension (default ".html")
+//	-types string
+//	  	JSON manifest mapping template names to data types (optional)
+//	-config string
+//	  	templator YAML config file; flags set explicitly take precedence (optional)
+//	-render
+//	  	Also emit RenderX(ctx, w, data) methods combining Get and Execute (optional)
+//	-include value
+//	  	Only generate methods for templates matching these globs, repeatable or comma-separated (optional)
+//	-exclude value
+//	  	Skip templates matching these globs, e.g. "partials,layouts/*" (optional)
+//	-schema
+//	  	With -types, also emit TemplateSchemas returning the JSON Schema of each template data type (optional)
+//	-gen-structs
+//	  	Instead of accessors, emit skeleton data structs inferred from template field references (optional)
+//	-watch
+//	  	Keep running and regenerate whenever templates are added, removed, or renamed (optional)
+//	-watch-interval duration
+//	  	How often -watch polls the templates directory (default 1s)
+//	-routes string
+//	  	Instead of accessors, emit RegisterRoutes serving the routes of this JSON manifest (optional)
+//
+// With -registry-type, accessors are methods of a type of the output package embedding the
+// registry, so they extend it rather than wrapping it. Generic types, such as "Views[T]",
+// keep their single type parameter; others name the data type of their registry with -data-type:
+//
+//	type Views struct {
+//		*templator.Registry[PageData]
+//	}
+//
+//	//go:generate go run github.com/alesr/templator/cmd/generate -package views -registry-type Views -data-type PageData
+//
+// Include and exclude globs use path.Match syntax and match template names, such as
+// "components/menu", or any of their parent directories, so "partials" skips the whole tree.
+//
+// When -types is set, the generator emits a non-generic Templates wrapper
+// over a templator.RegistryGroup, where each accessor returns a handler
+// typed with the data type declared for its template in the manifest:
+//
+//	{
+//	  "imports": ["github.com/acme/app/models"],
+//	  "templates": {
+//	    "home": "models.HomeData",
+//	    "components/menu": "models.MenuData"
+//	  }
+//	}
+//
+// When -routes is set, the generator emits a RegisterRoutes function registering a net/http
+// handler per route, rendering its template with the data returned by its data function,
+// which must be a func(*http.Request) (T, error) for the route's data type:
+//
+//	{
+//	  "imports": ["github.com/acme/app/models"],
+//	  "routes": [
+//	    {"pattern": "GET /{$}", "template": "home", "type": "models.HomeData", "data": "models.LoadHome"},
+//	    {"pattern": "POST /signup", "template": "welcome", "type": "models.User", "data": "models.SignUp", "status": 201}
+//	  ]
+//	}
 package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"go/format"
+	"go/token"
+	"maps"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 	"text/template"
+	"text/template/parse"
+	"time"
 
 	"github.com/alesr/templator"
 	"golang.org/x/text/cases"
@@ -35,9 +112,17 @@ import (
 
 const methodsTemplate = `{{ define "header" }}// Code generated by go generate; DO NOT EDIT.
 package {{.PackageName}}
+{{ if .Render }}
+import (
+	"context"
+	"io"
 
-import "{{ .TemplatorImport }}"
-
+	{{ .ImportAlias }}"{{ .TemplatorImport }}"
+)
+{{ else }}
+import {{ .ImportAlias }}"{{ .TemplatorImport }}"
+{{ end }}
+{{- if not .RegistryType }}
 // TemplateAccessors provides typed helper accessors over templator Registry.
 type TemplateAccessors[T any] struct {
 	registry *templator.Registry[T]
@@ -47,33 +132,285 @@ type TemplateAccessors[T any] struct {
 func NewTemplateAccessors[T any](registry *templator.Registry[T]) *TemplateAccessors[T] {
 	return &TemplateAccessors[T]{registry: registry}
 }
-
+{{ end }}
 {{ end }}
 
 {{ define "method" }}
 // {{ .MethodName }} returns a handler for the {{ .TemplateName }} template.
-func (r *TemplateAccessors[T]) {{.MethodName}}() (*templator.Handler[T], error) {
-	return r.registry.Get("{{ .TemplateName }}")
+func (r {{ .Receiver }}) {{.MethodName}}() (*templator.Handler[{{ .DataType }}], error) {
+	return {{ .Registry }}.Get("{{ .TemplateName }}")
 }
+
+// Must{{ .MethodName }} is like {{ .MethodName }} but panics if the template cannot be loaded.
+func (r {{ .Receiver }}) Must{{ .MethodName }}() *templator.Handler[{{ .DataType }}] {
+	return {{ .Registry }}.MustGet("{{ .TemplateName }}")
+}
+{{- if .Localized }}
+
+// {{ .MethodName }}Localized returns a handler for the locale-specific variant of the {{ .TemplateName }} template.
+func (r {{ .Receiver }}) {{ .MethodName }}Localized(locale string) (*templator.Handler[{{ .DataType }}], error) {
+	return {{ .Registry }}.GetLocalized("{{ .TemplateName }}", locale)
+}
+{{- end }}
+{{- if .Render }}
+
+// Render{{ .Name }} renders the {{ .TemplateName }} template with data to w.
+func (r {{ .Receiver }}) Render{{ .Name }}(ctx context.Context, w io.Writer, data {{ .DataType }}) error {
+	h, err := {{ .Registry }}.Get("{{ .TemplateName }}")
+	if err != nil {
+		return err
+	}
+	return h.Execute(ctx, w, data)
+}
+{{- end }}
+{{ end }}
+
+{{ define "typed" }}// Code generated by go generate; DO NOT EDIT.
+package {{.PackageName}}
+
+import (
+{{- if .Render }}
+	"context"
+	"io"
+{{ end }}
+	{{ .ImportAlias }}"{{ .TemplatorImport }}"
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
+)
+
+// Templates provides accessors returning handlers typed per template.
+type Templates struct {
+{{- range .Registries }}
+	{{ .FieldName }} *templator.Registry[{{ .DataType }}]
+{{- end }}
+}
+
+// NewTemplates creates a Templates wrapper, deriving one registry per data type from the group.
+func NewTemplates(group *templator.RegistryGroup) (*Templates, error) {
+	var (
+		t   Templates
+		err error
+	)
+{{- range .Registries }}
+	if t.{{ .FieldName }}, err = templator.NewGroupRegistry[{{ .DataType }}](group); err != nil {
+		return nil, err
+	}
+{{- end }}
+	return &t, nil
+}
+{{ range .Methods }}
+// {{ .MethodName }} returns a handler for the {{ .TemplateName }} template.
+func (t *Templates) {{ .MethodName }}() (*templator.Handler[{{ .DataType }}], error) {
+	return t.{{ .FieldName }}.Get("{{ .TemplateName }}")
+}
+
+// Must{{ .MethodName }} is like {{ .MethodName }} but panics if the template cannot be loaded.
+func (t *Templates) Must{{ .MethodName }}() *templator.Handler[{{ .DataType }}] {
+	return t.{{ .FieldName }}.MustGet("{{ .TemplateName }}")
+}
+{{- if .Localized }}
+
+// {{ .MethodName }}Localized returns a handler for the locale-specific variant of the {{ .TemplateName }} template.
+func (t *Templates) {{ .MethodName }}Localized(locale string) (*templator.Handler[{{ .DataType }}], error) {
+	return t.{{ .FieldName }}.GetLocalized("{{ .TemplateName }}", locale)
+}
+{{- end }}
+{{- if .Render }}
+
+// Render{{ .Name }} renders the {{ .TemplateName }} template with data to w.
+func (t *Templates) Render{{ .Name }}(ctx context.Context, w io.Writer, data {{ .DataType }}) error {
+	h, err := t.{{ .FieldName }}.Get("{{ .TemplateName }}")
+	if err != nil {
+		return err
+	}
+	return h.Execute(ctx, w, data)
+}
+{{- end }}
+{{ end }}
+{{- if .Schema }}
+// TemplateSchemas returns the JSON Schema of the data type of each template,
+// for consumers such as headless CMS editors and frontend previews.
+func TemplateSchemas() map[string]*templator.Schema {
+	return map[string]*templator.Schema{
+{{- range .Methods }}
+		"{{ .TemplateName }}": templator.JSONSchema[{{ .DataType }}](),
+{{- end }}
+	}
+}
+{{ end }}
+{{ end }}
+
+{{ define "routes" }}// Code generated by go generate; DO NOT EDIT.
+package {{.PackageName}}
+
+import (
+	"net/http"
+
+	{{ .ImportAlias }}"{{ .TemplatorImport }}"
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
+)
+
+// RegisterRoutes registers on mux a handler for each route, rendering its template with the
+// data returned by its data function. Data function errors reply with the status of a
+// templator.ErrHTTPStatus, or 500.
+func RegisterRoutes(mux *http.ServeMux, group *templator.RegistryGroup) error {
+{{- range .Registries }}
+	{{ .FieldName }}, err := templator.NewGroupRegistry[{{ .DataType }}](group)
+	if err != nil {
+		return err
+	}
+{{- end }}
+{{ range .Routes }}
+	if err := registerRoute(mux, {{ .FieldName }}, "{{ .Pattern }}", "{{ .Template }}", {{ or .Data "nil" }}
+		{{- if .Status }}, templator.WithStatus({{ .Status }}){{ end }}); err != nil {
+		return err
+	}
+{{- end }}
+	return nil
+}
+
+// registerRoute registers on mux a handler rendering the named template with the data returned by data.
+func registerRoute[T any](mux *http.ServeMux, reg *templator.Registry[T], pattern, name string, data func(*http.Request) (T, error), opts ...templator.HTTPOption) error {
+	h, err := reg.Get(name)
+	if err != nil {
+		return err
+	}
+	mux.Handle(pattern, h.HTTP(data, opts...))
+	return nil
+}
+{{ end }}
+
+{{ define "structs" }}// Code generated by templator -gen-structs as a starting point; edit freely.
+package {{ .PackageName }}
+{{ range .Structs }}
+// {{ .Name }} holds the data of the {{ .TemplateName }} template.
+type {{ .Name }} struct {
+{{- range .Fields }}
+	{{ .Name }} {{ .Type }}
+{{- end }}
+}
+{{ end }}
 {{ end }}`
 
 type (
 	TemplateData struct {
+		Name         string
 		MethodName   string
 		TemplateName string
+		DataType     string
+		FieldName    string
+		Localized    bool
+		Render       bool
+		// Receiver is the receiver type of the accessors, and Registry the expression
+		// of the registry they call.
+		Receiver string
+		Registry string
 	}
 	headerData struct {
 		PackageName     string
 		TemplatorImport string
+		ImportAlias     string
+		Render          bool
+		RegistryType    string
+	}
+	typedData struct {
+		PackageName     string
+		TemplatorImport string
+		ImportAlias     string
+		Render          bool
+		Schema          bool
+		Imports         []string
+		Registries      []TemplateData
+		Methods         []TemplateData
+	}
+	structsData struct {
+		PackageName string
+		Structs     []structDef
+	}
+	structDef struct {
+		Name         string
+		TemplateName string
+		Fields       []structField
+	}
+	structField struct {
+		Name string
+		Type string
+	}
+	typeManifest struct {
+		Imports   []string          `json:"imports"`
+		Templates map[string]string `json:"templates"`
+	}
+	routeManifest struct {
+		Imports []string `json:"imports"`
+		Routes  []route  `json:"routes"`
+	}
+	route struct {
+		Pattern  string `json:"pattern"`
+		Template string `json:"template"`
+		Type     string `json:"type"`
+		Data     string `json:"data"`
+		Status   int    `json:"status"`
+		// FieldName is the variable holding the registry of the route's data type.
+		FieldName string `json:"-"`
+	}
+	routesData struct {
+		PackageName     string
+		TemplatorImport string
+		ImportAlias     string
+		Imports         []string
+		Registries      []TemplateData
+		Routes          []route
 	}
 	config struct {
 		templateDir     string
 		outputFile      string
 		packageName     string
 		templatorImport string
+		registryType    string
+		dataType        string
+		extension       string
+		typesFile       string
+		routesFile      string
+		render          bool
+		include         globList
+		exclude         globList
+		schema          bool
+		genStructs      bool
+		watch           bool
+		watchInterval   time.Duration
 	}
+	// globList is a repeatable flag holding comma-separated glob patterns.
+	globList []string
 )
 
+func (g *globList) String() string {
+	return strings.Join(*g, ",")
+}
+
+func (g *globList) Set(value string) error {
+	for pattern := range strings.SplitSeq(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			*g = append(*g, pattern)
+		}
+	}
+	return nil
+}
+
+// matches reports whether name, or one of its parent directories, matches any pattern.
+func (g globList) matches(name string) bool {
+	for ; name != "." && name != "/"; name = path.Dir(name) {
+		for _, pattern := range g {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func main() {
 	if err := run(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -91,9 +428,47 @@ func run() error {
 	if err != nil {
 		return err
 	}
+
+	if cfg.watch {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return watch(ctx, cfg, tmpl)
+	}
 	return generateMethods(cfg, tmpl)
 }
 
+// watch generates the output, then polls the templates directory and regenerates it whenever
+// the set of templates changes, until ctx is done. Content changes don't affect generated methods
+// and are ignored. Errors are reported and watching continues, so a half-renamed tree doesn't
+// stop the watcher.
+func watch(ctx context.Context, cfg config, tmpl *template.Template) error {
+	fmt.Printf("Watching %s for template changes...\n", cfg.templateDir)
+
+	ticker := time.NewTicker(cfg.watchInterval)
+	defer ticker.Stop()
+
+	var last []TemplateData
+	for {
+		current, err := collectTemplates(cfg)
+		switch {
+		case err != nil:
+			fmt.Fprintln(os.Stderr, err)
+		case last == nil || !slices.Equal(current, last):
+			if err := generateMethods(cfg, tmpl); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				break
+			}
+			last = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
 func parseFlags() (config, error) {
 	flagSet := flag.NewFlagSet("generate", flag.ExitOnError)
 	templateDir := flagSet.String(
@@ -116,14 +491,126 @@ func parseFlags() (config, error) {
 		"github.com/alesr/templator",
 		"templator import path",
 	)
+	flagSet.StringVar(
+		templatorImport,
+		"registry-import",
+		"github.com/alesr/templator",
+		"alias of -templator-import",
+	)
+	registryType := flagSet.String(
+		"registry-type",
+		"",
+		"emit methods on this type of the output package, embedding a registry, instead of TemplateAccessors",
+	)
+	dataType := flagSet.String(
+		"data-type",
+		"",
+		"with a non-generic -registry-type, the data type of its registry",
+	)
+	extension := flagSet.String(
+		"ext",
+		string(templator.ExtensionHTML),
+		"template file extension",
+	)
+	typesFile := flagSet.String(
+		"types",
+		"",
+		"JSON manifest mapping template names to data types",
+	)
+	routesFile := flagSet.String(
+		"routes",
+		"",
+		"JSON manifest of routes to emit RegisterRoutes for, instead of accessors",
+	)
+	render := flagSet.Bool(
+		"render",
+		false,
+		"also emit RenderX methods combining Get and Execute",
+	)
+	var include, exclude globList
+	flagSet.Var(&include, "include", "only generate methods for templates matching these globs")
+	flagSet.Var(&exclude, "exclude", "skip templates matching these globs")
+	schema := flagSet.Bool(
+		"schema",
+		false,
+		"with -types, also emit TemplateSchemas returning the JSON Schema of each data type",
+	)
+	genStructs := flagSet.Bool(
+		"gen-structs",
+		false,
+		"emit skeleton data structs inferred from template field references instead of accessors",
+	)
+	watchMode := flagSet.Bool(
+		"watch",
+		false,
+		"regenerate whenever templates are added, removed, or renamed",
+	)
+	watchInterval := flagSet.Duration(
+		"watch-interval",
+		time.Second,
+		"how often -watch polls the templates directory",
+	)
+	configFile := flagSet.String(
+		"config",
+		"",
+		"templator YAML config file",
+	)
 
 	flagSet.Parse(os.Args[1:])
 
+	if *configFile != "" {
+		fileCfg, err := templator.LoadConfig(*configFile)
+		if err != nil {
+			return config{}, err
+		}
+
+		explicit := make(map[string]bool)
+		flagSet.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+		explicit["templator-import"] = explicit["templator-import"] || explicit["registry-import"]
+
+		templatesDir := fileCfg.Generate.Templates
+		if templatesDir == "" {
+			templatesDir = fileCfg.Path
+		}
+
+		for name, value := range map[string]string{
+			"templates":        templatesDir,
+			"out":              fileCfg.Generate.Out,
+			"package":          fileCfg.Generate.Package,
+			"templator-import": fileCfg.Generate.TemplatorImport,
+			"registry-type":    fileCfg.Generate.RegistryType,
+			"data-type":        fileCfg.Generate.DataType,
+			"ext":              string(fileCfg.Extension),
+			"types":            fileCfg.Generate.Types,
+			"routes":           fileCfg.Generate.Routes,
+			"render":           strconv.FormatBool(fileCfg.Generate.Render),
+			"include":          strings.Join(fileCfg.Generate.Include, ","),
+			"exclude":          strings.Join(fileCfg.Generate.Exclude, ","),
+			"schema":           strconv.FormatBool(fileCfg.Generate.Schema),
+		} {
+			if !explicit[name] && value != "" {
+				flagSet.Set(name, value)
+			}
+		}
+	}
+
 	cfg := config{
 		templateDir:     *templateDir,
 		outputFile:      *outputFile,
 		packageName:     *packageName,
 		templatorImport: *templatorImport,
+		registryType:    *registryType,
+		dataType:        *dataType,
+		extension:       *extension,
+		typesFile:       *typesFile,
+		routesFile:      *routesFile,
+		render:          *render,
+		include:         include,
+		exclude:         exclude,
+		schema:          *schema,
+		genStructs:      *genStructs,
+		watch:           *watchMode,
+		watchInterval:   *watchInterval,
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -139,6 +626,41 @@ func (c config) validate() error {
 	if c.templatorImport == "" {
 		return errors.New("requires non-empty -templator-import")
 	}
+	if c.extension == "" {
+		return errors.New("requires non-empty -ext")
+	}
+	if c.schema && c.typesFile == "" {
+		return errors.New("-schema requires -types")
+	}
+	if c.genStructs && c.typesFile != "" {
+		return errors.New("-gen-structs cannot be combined with -types")
+	}
+	if c.routesFile != "" && (c.typesFile != "" || c.genStructs) {
+		return errors.New("-routes cannot be combined with -types or -gen-structs")
+	}
+	if c.registryType != "" {
+		name, params, generic := strings.Cut(c.registryType, "[")
+		switch {
+		case !token.IsIdentifier(name) || generic && !token.IsIdentifier(strings.TrimSuffix(params, "]")):
+			return fmt.Errorf("malformed -registry-type '%s'", c.registryType)
+		case generic && c.dataType != "":
+			return errors.New("-data-type cannot be combined with a generic -registry-type")
+		case !generic && c.dataType == "":
+			return errors.New("a non-generic -registry-type requires -data-type")
+		case c.typesFile != "" || c.routesFile != "" || c.genStructs:
+			return errors.New("-registry-type cannot be combined with -types, -routes or -gen-structs")
+		}
+	} else if c.dataType != "" {
+		return errors.New("-data-type requires -registry-type")
+	}
+	if c.watch && c.watchInterval <= 0 {
+		return errors.New("requires positive -watch-interval")
+	}
+	for _, pattern := range slices.Concat(c.include, c.exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("malformed glob '%s'", pattern)
+		}
+	}
 	return nil
 }
 
@@ -151,6 +673,16 @@ func loadTemplateGenerator() (*template.Template, error) {
 }
 
 func generateMethods(cfg config, tmpl *template.Template) error {
+	if cfg.genStructs {
+		return generateStructs(cfg, tmpl)
+	}
+	if cfg.routesFile != "" {
+		return generateRoutes(cfg, tmpl)
+	}
+	if cfg.typesFile != "" {
+		return generateTypedMethods(cfg, tmpl)
+	}
+
 	var buf bytes.Buffer
 	if err := writeHeader(&buf, cfg, tmpl); err != nil {
 		return fmt.Errorf("could not write header: %w", err)
@@ -172,62 +704,512 @@ func writeHeader(buf *bytes.Buffer, cfg config, tmpl *template.Template) error {
 	return tmpl.ExecuteTemplate(buf, "header", headerData{
 		PackageName:     cfg.packageName,
 		TemplatorImport: cfg.templatorImport,
+		ImportAlias:     importAlias(cfg.templatorImport),
+		Render:          cfg.render,
+		RegistryType:    cfg.registryType,
 	})
 }
 
+// receiver returns the receiver type of the accessors, the expression of the registry they
+// call, and the data type of the handlers they return.
+func (c config) receiver() (receiver, registry, dataType string) {
+	if c.registryType == "" {
+		return "*TemplateAccessors[T]", "r.registry", "T"
+	}
+	if _, params, generic := strings.Cut(c.registryType, "["); generic {
+		return "*" + c.registryType, "r", strings.TrimSuffix(params, "]")
+	}
+	return "*" + c.registryType, "r", c.dataType
+}
+
 func processTemplates(cfg config, buf *bytes.Buffer, tmpl *template.Template) error {
-	return filepath.Walk(cfg.templateDir, func(path string, info os.FileInfo, err error) error {
+	templates, err := collectTemplates(cfg)
+	if err != nil {
+		return err
+	}
+
+	receiver, registry, dataType := cfg.receiver()
+	for _, data := range templates {
+		data.Receiver, data.Registry, data.DataType = receiver, registry, dataType
+		if err := tmpl.ExecuteTemplate(buf, "method", data); err != nil {
+			return fmt.Errorf("could not execute template: %w", err)
+		}
+		buf.WriteString("\n")
+	}
+	return nil
+}
+
+func buildTemplateData(relPath string, caser cases.Caser) (TemplateData, error) {
+	basePath := filepath.ToSlash(strings.TrimSuffix(relPath, filepath.Ext(relPath)))
+	parts := strings.Split(basePath, "/")
+	for i, part := range parts {
+		parts[i] = caser.String(part)
+	}
+
+	name := strings.Join(parts, "")
+	return TemplateData{
+		Name:         name,
+		MethodName:   "Get" + name,
+		TemplateName: basePath,
+	}, nil
+}
+
+// importAlias returns the import name to prefix the templator import with, so generated code can
+// refer to it as templator even when the import path ends differently, e.g. in forks or /v2 paths.
+func importAlias(importPath string) string {
+	if path.Base(importPath) == "templator" {
+		return ""
+	}
+	return "templator "
+}
+
+func writeOutput(outputFile string, buf *bytes.Buffer) error {
+	formattedSource, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("could not format generated code: %w", err)
+	}
+	return os.WriteFile(outputFile, formattedSource, 0o644)
+}
+
+func generateTypedMethods(cfg config, tmpl *template.Template) error {
+	manifest, err := loadTypeManifest(cfg.typesFile)
+	if err != nil {
+		return fmt.Errorf("could not load types manifest: %w", err)
+	}
+
+	templates, err := collectTemplates(cfg)
+	if err != nil {
+		return fmt.Errorf("could not process templates: %w", err)
+	}
+
+	data, err := buildTypedData(cfg, manifest, templates)
+	if err != nil {
+		return fmt.Errorf("could not build typed data: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "typed", data); err != nil {
+		return fmt.Errorf("could not execute template: %w", err)
+	}
+
+	if err := writeOutput(cfg.outputFile, &buf); err != nil {
+		return fmt.Errorf("could not write output: %w", err)
+	}
+	fmt.Println("Methods generated and formatted successfully.")
+
+	return nil
+}
+
+func loadTypeManifest(path string) (typeManifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return typeManifest{}, err
+	}
+
+	var manifest typeManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return typeManifest{}, fmt.Errorf("could not decode manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+func collectTemplates(cfg config) ([]TemplateData, error) {
+	caser := cases.Title(language.English)
+
+	var templates []TemplateData
+	err := filepath.Walk(cfg.templateDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return fmt.Errorf("could not walk directory: %w", err)
 		}
 
-		if info.IsDir() {
+		if info.IsDir() || filepath.Ext(path) != cfg.extension {
 			return nil
 		}
 
-		if filepath.Ext(path) != string(templator.ExtensionHTML) {
-			return nil
+		relPath, err := filepath.Rel(cfg.templateDir, path)
+		if err != nil {
+			return fmt.Errorf("could not get relative path: %w", err)
+		}
+
+		data, err := buildTemplateData(relPath, caser)
+		if err != nil {
+			return fmt.Errorf("could not build template data: %w", err)
 		}
-		return generateTemplateMethod(path, cfg, buf, tmpl, cases.Title(language.English))
+		data.Render = cfg.render
+		templates = append(templates, data)
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	templates = slices.DeleteFunc(foldLocalized(templates), func(data TemplateData) bool {
+		if len(cfg.include) > 0 && !cfg.include.matches(data.TemplateName) {
+			return true
+		}
+		return cfg.exclude.matches(data.TemplateName)
+	})
+	return templates, nil
+}
+
+// foldLocalized drops locale variants such as "home.de", which are reached through
+// GetLocalized, and marks their base templates as localized.
+func foldLocalized(templates []TemplateData) []TemplateData {
+	localized := make(map[string]bool)
+	for _, data := range templates {
+		if base, ok := localeBase(data.TemplateName); ok {
+			localized[base] = true
+		}
+	}
+
+	var folded []TemplateData
+	for _, data := range templates {
+		if _, ok := localeBase(data.TemplateName); ok {
+			continue
+		}
+		data.Localized = localized[data.TemplateName]
+		folded = append(folded, data)
+	}
+	return folded
+}
+
+// localeBase returns the base template name of a locale variant, e.g. "users/home" for "users/home.de".
+func localeBase(name string) (string, bool) {
+	dir, file := path.Split(name)
+	i := strings.Index(file, ".")
+	if i < 0 {
+		return "", false
+	}
+	return dir + file[:i], true
+}
+
+func buildTypedData(cfg config, manifest typeManifest, templates []TemplateData) (typedData, error) {
+	data := typedData{
+		PackageName:     cfg.packageName,
+		TemplatorImport: cfg.templatorImport,
+		ImportAlias:     importAlias(cfg.templatorImport),
+		Render:          cfg.render,
+		Schema:          cfg.schema,
+		Imports:         manifest.Imports,
+	}
+
+	fields := make(map[string]string)
+	for _, tmplData := range templates {
+		dataType, ok := manifest.Templates[tmplData.TemplateName]
+		if !ok || dataType == "" {
+			return typedData{}, fmt.Errorf("template '%s' has no data type in manifest", tmplData.TemplateName)
+		}
+
+		fieldName, ok := fields[dataType]
+		if !ok {
+			fieldName = registryFieldName(dataType)
+			fields[dataType] = fieldName
+			data.Registries = append(data.Registries, TemplateData{DataType: dataType, FieldName: fieldName})
+		}
+
+		tmplData.DataType = dataType
+		tmplData.FieldName = fieldName
+		data.Methods = append(data.Methods, tmplData)
+	}
+
+	slices.SortFunc(data.Registries, func(a, b TemplateData) int {
+		return strings.Compare(a.FieldName, b.FieldName)
+	})
+	return data, nil
+}
+
+// registryFieldName derives an unexported struct field name from a data type,
+// e.g. models.HomeData becomes modelsHomeDataRegistry.
+func registryFieldName(dataType string) string {
+	name := strings.ReplaceAll(dataType, ".", "")
+	return strings.ToLower(name[:1]) + name[1:] + "Registry"
 }
 
-func generateTemplateMethod(path string, cfg config, buf *bytes.Buffer, tmpl *template.Template, caser cases.Caser) error {
-	relPath, err := filepath.Rel(cfg.templateDir, path)
+func generateRoutes(cfg config, tmpl *template.Template) error {
+	content, err := os.ReadFile(cfg.routesFile)
 	if err != nil {
-		return fmt.Errorf("could not get relative path: %w", err)
+		return fmt.Errorf("could not load routes manifest: %w", err)
+	}
+
+	var manifest routeManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return fmt.Errorf("could not load routes manifest: could not decode manifest: %w", err)
 	}
 
-	data, err := buildTemplateData(relPath, caser)
+	templates, err := collectTemplates(cfg)
 	if err != nil {
-		return fmt.Errorf("could not build template data: %w", err)
+		return fmt.Errorf("could not process templates: %w", err)
 	}
 
-	if err := tmpl.ExecuteTemplate(buf, "method", data); err != nil {
+	data, err := buildRoutesData(cfg, manifest, templates)
+	if err != nil {
+		return fmt.Errorf("could not build routes: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "routes", data); err != nil {
 		return fmt.Errorf("could not execute template: %w", err)
 	}
-	buf.WriteString("\n")
+
+	if err := writeOutput(cfg.outputFile, &buf); err != nil {
+		return fmt.Errorf("could not write output: %w", err)
+	}
+	fmt.Println("Routes generated and formatted successfully.")
 
 	return nil
 }
 
-func buildTemplateData(relPath string, caser cases.Caser) (TemplateData, error) {
-	basePath := strings.TrimSuffix(relPath, string(templator.ExtensionHTML))
-	parts := strings.Split(basePath, string(filepath.Separator))
-	for i, part := range parts {
-		parts[i] = caser.String(part)
+func buildRoutesData(cfg config, manifest routeManifest, templates []TemplateData) (routesData, error) {
+	data := routesData{
+		PackageName:     cfg.packageName,
+		TemplatorImport: cfg.templatorImport,
+		ImportAlias:     importAlias(cfg.templatorImport),
+		Imports:         manifest.Imports,
 	}
 
-	return TemplateData{
-		MethodName:   "Get" + strings.Join(parts, ""),
-		TemplateName: filepath.ToSlash(basePath),
-	}, nil
+	fields := make(map[string]string)
+	for i, r := range manifest.Routes {
+		switch {
+		case r.Pattern == "" || r.Template == "" || r.Type == "":
+			return routesData{}, fmt.Errorf("route %d requires a pattern, a template, and a type", i)
+		case !slices.ContainsFunc(templates, func(t TemplateData) bool { return t.TemplateName == r.Template }):
+			return routesData{}, fmt.Errorf("route '%s' renders unknown template '%s'", r.Pattern, r.Template)
+		case r.Status != 0 && (r.Status < 100 || r.Status > 599):
+			return routesData{}, fmt.Errorf("route '%s' has invalid status %d", r.Pattern, r.Status)
+		}
+
+		fieldName, ok := fields[r.Type]
+		if !ok {
+			fieldName = registryFieldName(r.Type)
+			fields[r.Type] = fieldName
+			data.Registries = append(data.Registries, TemplateData{DataType: r.Type, FieldName: fieldName})
+		}
+		r.FieldName = fieldName
+		data.Routes = append(data.Routes, r)
+	}
+	return data, nil
 }
 
-func writeOutput(outputFile string, buf *bytes.Buffer) error {
-	formattedSource, err := format.Source(buf.Bytes())
+// editableRegion matches the opening action of a templator editable region.
+var editableRegion = regexp.MustCompile(`\{\{(-?\s*)editable\b`)
+
+func generateStructs(cfg config, tmpl *template.Template) error {
+	templates, err := collectTemplates(cfg)
 	if err != nil {
-		return fmt.Errorf("could not format generated code: %w", err)
+		return fmt.Errorf("could not process templates: %w", err)
 	}
-	return os.WriteFile(outputFile, formattedSource, 0o644)
+
+	data := structsData{PackageName: cfg.packageName}
+	for _, tmplData := range templates {
+		content, err := os.ReadFile(filepath.Join(cfg.templateDir, filepath.FromSlash(tmplData.TemplateName)+cfg.extension))
+		if err != nil {
+			return fmt.Errorf("could not read template: %w", err)
+		}
+
+		tree := parse.New(tmplData.TemplateName)
+		tree.Mode = parse.SkipFuncCheck
+		// Editable regions are closed by {{end}}, so parse them as the if blocks templator turns them into.
+		source := editableRegion.ReplaceAllString(string(content), "{{${1}if editable")
+		if _, err := tree.Parse(source, "", "", make(map[string]*parse.Tree)); err != nil {
+			return fmt.Errorf("could not parse template: %w", err)
+		}
+
+		root := newInferredField()
+		inferList(tree.Root, inferScope{dot: root, vars: map[string]*inferredField{"$": root}})
+		data.Structs = append(data.Structs, root.structs(tmplData.Name, tmplData.Name+"Data", tmplData.TemplateName)...)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "structs", data); err != nil {
+		return fmt.Errorf("could not execute template: %w", err)
+	}
+
+	if err := writeOutput(cfg.outputFile, &buf); err != nil {
+		return fmt.Errorf("could not write output: %w", err)
+	}
+	fmt.Println("Structs generated and formatted successfully.")
+
+	return nil
+}
+
+// inferredField accumulates how a template uses a value, to guess its Go type.
+type inferredField struct {
+	fields    map[string]*inferredField
+	order     []string
+	elem      *inferredField
+	value     bool
+	condition bool
+}
+
+func newInferredField() *inferredField {
+	return &inferredField{fields: make(map[string]*inferredField)}
+}
+
+func (f *inferredField) field(name string) *inferredField {
+	child, ok := f.fields[name]
+	if !ok {
+		child = newInferredField()
+		f.fields[name] = child
+		f.order = append(f.order, name)
+	}
+	return child
+}
+
+// element returns the inferred element of a ranged value.
+func (f *inferredField) element() *inferredField {
+	if f.elem == nil {
+		f.elem = newInferredField()
+	}
+	return f.elem
+}
+
+// goType returns the Go type of the field, naming nested structs after name.
+func (f *inferredField) goType(name string) string {
+	switch {
+	case f.elem != nil:
+		return "[]" + f.elem.goType(strings.TrimSuffix(name, "s"))
+	case len(f.fields) > 0:
+		return name
+	case f.condition && !f.value:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// structs returns the struct of the field, named typeName, followed by its nested structs,
+// which are named after prefix and their field names.
+func (f *inferredField) structs(prefix, typeName, templateName string) []structDef {
+	def := structDef{Name: typeName, TemplateName: templateName}
+
+	var nested []structDef
+	for _, name := range f.order {
+		child := f.fields[name]
+		childType := prefix + name
+		def.Fields = append(def.Fields, structField{Name: name, Type: child.goType(childType)})
+
+		for child.elem != nil {
+			child, childType = child.elem, strings.TrimSuffix(childType, "s")
+		}
+		if len(child.fields) > 0 {
+			nested = append(nested, child.structs(childType, childType, templateName)...)
+		}
+	}
+	return append([]structDef{def}, nested...)
+}
+
+// inferScope tracks the value dot and variables refer to at a point in the template.
+type inferScope struct {
+	dot  *inferredField
+	vars map[string]*inferredField
+}
+
+func (s inferScope) with(dot *inferredField) inferScope {
+	return inferScope{dot: dot, vars: maps.Clone(s.vars)}
+}
+
+func inferList(list *parse.ListNode, s inferScope) {
+	if list == nil {
+		return
+	}
+	for _, node := range list.Nodes {
+		inferNode(node, s)
+	}
+}
+
+func inferNode(node parse.Node, s inferScope) {
+	switch n := node.(type) {
+	case *parse.ActionNode:
+		if target := inferPipe(n.Pipe, s, false); target != nil {
+			for _, v := range n.Pipe.Decl {
+				s.vars[v.Ident[0]] = target
+			}
+		}
+	case *parse.IfNode:
+		inferPipe(n.Pipe, s, true)
+		inferList(n.List, s.with(s.dot))
+		inferList(n.ElseList, s.with(s.dot))
+	case *parse.WithNode:
+		target := inferPipe(n.Pipe, s, true)
+		inner := s.with(target)
+		if target == nil {
+			inner = s.with(newInferredField())
+		}
+		inferList(n.List, inner)
+		inferList(n.ElseList, s.with(s.dot))
+	case *parse.RangeNode:
+		target := inferPipe(n.Pipe, s, false)
+		elem := newInferredField()
+		if target != nil {
+			target.value = false
+			elem = target.element()
+		}
+
+		inner := s.with(elem)
+		if decl := n.Pipe.Decl; len(decl) > 0 {
+			inner.vars[decl[len(decl)-1].Ident[0]] = elem
+		}
+		inferList(n.List, inner)
+		inferList(n.ElseList, s.with(s.dot))
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			inferPipe(n.Pipe, s, false)
+		}
+	case *parse.ListNode:
+		inferList(n, s)
+	}
+}
+
+// inferPipe records the values used by a pipeline and returns the value it evaluates to when it
+// is a single field reference. A lone field in a condition is marked as a condition, others as values.
+func inferPipe(pipe *parse.PipeNode, s inferScope, condition bool) *inferredField {
+	if pipe == nil {
+		return nil
+	}
+
+	lone := len(pipe.Cmds) == 1 && len(pipe.Cmds[0].Args) == 1
+	var target *inferredField
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			f := inferArg(arg, s)
+			if f == nil {
+				continue
+			}
+			if lone {
+				target = f
+				if condition {
+					f.condition = true
+					continue
+				}
+			}
+			f.value = true
+		}
+	}
+	return target
+}
+
+// inferArg returns the value an argument refers to, recursing into parenthesized pipelines.
+func inferArg(arg parse.Node, s inferScope) *inferredField {
+	switch a := arg.(type) {
+	case *parse.DotNode:
+		return s.dot
+	case *parse.FieldNode:
+		return inferChain(s.dot, a.Ident)
+	case *parse.VariableNode:
+		base, ok := s.vars[a.Ident[0]]
+		if !ok {
+			return nil
+		}
+		return inferChain(base, a.Ident[1:])
+	case *parse.PipeNode:
+		inferPipe(a, s, false)
+	}
+	return nil
+}
+
+func inferChain(f *inferredField, idents []string) *inferredField {
+	for _, ident := range idents {
+		f = f.field(ident)
+	}
+	return f
 }
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,7 +16,11 @@ import (
 	"golang.org/x/text/language"
 )
 
-var osArgsMu sync.Mutex
+var (
+	osArgsMu sync.Mutex
+
+	updateGoldens = flag.Bool("update-goldens", false, "rewrite the golden files of the generated code")
+)
 
 func TestParseFlags(t *testing.T) {
 	osArgsMu.Lock()
@@ -32,6 +39,7 @@ func TestParseFlags(t *testing.T) {
 		wantTemplates string
 		wantOutput    string
 		wantPackage   string
+		wantImport    string
 		wantErr       string
 	}{
 		{
@@ -55,6 +63,14 @@ func TestParseFlags(t *testing.T) {
 			wantOutput:    "./templator_accessors_gen.go",
 			wantPackage:   "myapp",
 		},
+		{
+			name:          "registry import alias",
+			args:          []string{"cmd", "-registry-import", "github.com/acme/templator/v2"},
+			wantTemplates: "templates",
+			wantOutput:    "./templator_accessors_gen.go",
+			wantPackage:   "main",
+			wantImport:    "github.com/acme/templator/v2",
+		},
 		{
 			name:    "rejects empty package",
 			args:    []string{"cmd", "-package", ""},
@@ -79,10 +95,47 @@ func TestParseFlags(t *testing.T) {
 			assert.Equal(t, tt.wantTemplates, cfg.templateDir)
 			assert.Equal(t, tt.wantOutput, cfg.outputFile)
 			assert.Equal(t, tt.wantPackage, cfg.packageName)
+			if tt.wantImport != "" {
+				assert.Equal(t, tt.wantImport, cfg.templatorImport)
+			}
 		})
 	}
 }
 
+func TestParseFlags_ConfigFile(t *testing.T) {
+	osArgsMu.Lock()
+	oldArgs := os.Args
+	osArgsMu.Unlock()
+
+	defer func() {
+		osArgsMu.Lock()
+		os.Args = oldArgs
+		osArgsMu.Unlock()
+	}()
+
+	configFile := filepath.Join(t.TempDir(), "templator.yaml")
+	err := os.WriteFile(configFile, []byte(`
+path: views
+extension: .tmpl
+generate:
+  package: views
+  out: ./views_gen.go
+`), 0o644)
+	require.NoError(t, err)
+
+	osArgsMu.Lock()
+	os.Args = []string{"cmd", "-config", configFile, "-out", "override.go"}
+	osArgsMu.Unlock()
+
+	cfg, err := parseFlags()
+	require.NoError(t, err)
+	assert.Equal(t, "views", cfg.templateDir)
+	assert.Equal(t, ".tmpl", cfg.extension)
+	assert.Equal(t, "views", cfg.packageName)
+	assert.Equal(t, "override.go", cfg.outputFile, "explicit flags take precedence")
+	assert.Equal(t, "github.com/alesr/templator", cfg.templatorImport)
+}
+
 func TestLoadTemplateGenerator(t *testing.T) {
 	tmpl, err := loadTemplateGenerator()
 	require.NoError(t, err)
@@ -106,6 +159,7 @@ func TestGenerateMethods(t *testing.T) {
 		outputFile:      outputFile,
 		packageName:     "myapp",
 		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
 	}
 
 	tmpl, err := loadTemplateGenerator()
@@ -124,6 +178,616 @@ func TestGenerateMethods(t *testing.T) {
 	assert.Contains(t, generatedCode, "func NewTemplateAccessors[T any](registry *templator.Registry[T]) *TemplateAccessors[T]")
 	assert.Contains(t, generatedCode, "func (r *TemplateAccessors[T]) GetIndex() (*templator.Handler[T], error)")
 	assert.Contains(t, generatedCode, "func (r *TemplateAccessors[T]) GetUsersProfile() (*templator.Handler[T], error)")
+	assert.Contains(t, generatedCode, "func (r *TemplateAccessors[T]) MustGetIndex() *templator.Handler[T]")
+	assert.Contains(t, generatedCode, "return r.registry.MustGet(\"users/profile\")")
+}
+
+func TestGenerateMethods_Localized(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "index.de.html")
+	writeTemplateFixture(t, tempDir, "users/profile.pt-BR.html")
+	writeTemplateFixture(t, tempDir, "users/profile.html")
+	writeTemplateFixture(t, tempDir, "about.html")
+
+	outputFile := filepath.Join(tempDir, "output.go")
+	cfg := config{
+		templateDir:     tempDir,
+		outputFile:      outputFile,
+		packageName:     "myapp",
+		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	err = generateMethods(cfg, tmpl)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+
+	generatedCode := string(content)
+	assert.Contains(t, generatedCode, "func (r *TemplateAccessors[T]) GetIndex() (*templator.Handler[T], error)")
+	assert.Contains(t, generatedCode, "func (r *TemplateAccessors[T]) GetIndexLocalized(locale string) (*templator.Handler[T], error)")
+	assert.Contains(t, generatedCode, "r.registry.GetLocalized(\"users/profile\", locale)")
+	assert.NotContains(t, generatedCode, "GetAboutLocalized")
+	assert.NotContains(t, generatedCode, "\"index.de\"")
+}
+
+func TestGenerateMethods_ImportAlias(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFixture(t, tempDir, "index.html")
+
+	tests := []struct {
+		name            string
+		templatorImport string
+		want            string
+	}{
+		{
+			name:            "default import",
+			templatorImport: "github.com/alesr/templator",
+			want:            "import \"github.com/alesr/templator\"",
+		},
+		{
+			name:            "versioned fork",
+			templatorImport: "github.com/acme/templator/v2",
+			want:            "import templator \"github.com/acme/templator/v2\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "output.go")
+			cfg := config{
+				templateDir:     tempDir,
+				outputFile:      outputFile,
+				packageName:     "views",
+				templatorImport: tt.templatorImport,
+				extension:       ".html",
+			}
+
+			tmpl, err := loadTemplateGenerator()
+			require.NoError(t, err)
+
+			err = generateMethods(cfg, tmpl)
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(outputFile)
+			require.NoError(t, err)
+			assert.Contains(t, string(content), tt.want)
+			assert.Contains(t, string(content), "package views")
+		})
+	}
+}
+
+func TestGenerateMethods_RegistryType(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "index.de.html")
+	writeTemplateFixture(t, tempDir, "users/profile.html")
+
+	tests := []struct {
+		name         string
+		registryType string
+		dataType     string
+		golden       string
+	}{
+		{name: "embedding type", registryType: "Views", dataType: "PageData", golden: "registry_type.golden"},
+		{name: "generic type", registryType: "Views[T]", golden: "registry_type_generic.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "output.go")
+			cfg := config{
+				templateDir:     tempDir,
+				outputFile:      outputFile,
+				packageName:     "views",
+				templatorImport: "github.com/alesr/templator",
+				registryType:    tt.registryType,
+				dataType:        tt.dataType,
+				extension:       ".html",
+				render:          true,
+			}
+			require.NoError(t, cfg.validate())
+
+			tmpl, err := loadTemplateGenerator()
+			require.NoError(t, err)
+			require.NoError(t, generateMethods(cfg, tmpl))
+
+			got, err := os.ReadFile(outputFile)
+			require.NoError(t, err)
+
+			golden := filepath.Join("testdata", tt.golden)
+			if *updateGoldens {
+				require.NoError(t, os.WriteFile(golden, got, 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+		})
+	}
+}
+
+func TestConfig_ValidateRegistryType(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config
+		wantErr string
+	}{
+		{name: "malformed", cfg: config{registryType: "*Views"}, wantErr: "malformed -registry-type"},
+		{name: "several type parameters", cfg: config{registryType: "Views[K, T]"}, wantErr: "malformed -registry-type"},
+		{name: "missing data type", cfg: config{registryType: "Views"}, wantErr: "requires -data-type"},
+		{name: "data type of a generic type", cfg: config{registryType: "Views[T]", dataType: "PageData"}, wantErr: "cannot be combined with a generic"},
+		{name: "with types", cfg: config{registryType: "Views[T]", typesFile: "types.json"}, wantErr: "cannot be combined with -types"},
+		{name: "data type alone", cfg: config{dataType: "PageData"}, wantErr: "-data-type requires -registry-type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.packageName, tt.cfg.templatorImport, tt.cfg.extension = "views", "github.com/alesr/templator", ".html"
+			require.ErrorContains(t, tt.cfg.validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestGenerateTypedMethods(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "about.html")
+	writeTemplateFixture(t, tempDir, "users/profile.html")
+
+	manifestFile := filepath.Join(t.TempDir(), "types.json")
+	err := os.WriteFile(manifestFile, []byte(`{
+		"imports": ["github.com/acme/app/models"],
+		"templates": {
+			"index": "models.PageData",
+			"about": "models.PageData",
+			"users/profile": "ProfileData"
+		}
+	}`), 0o644)
+	require.NoError(t, err)
+
+	outputFile := filepath.Join(tempDir, "output.go")
+	cfg := config{
+		templateDir:     tempDir,
+		outputFile:      outputFile,
+		packageName:     "myapp",
+		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
+		typesFile:       manifestFile,
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	err = generateMethods(cfg, tmpl)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+
+	generatedCode := string(content)
+	assert.Contains(t, generatedCode, "package myapp")
+	assert.Contains(t, generatedCode, "\"github.com/acme/app/models\"")
+	assert.Contains(t, generatedCode, "type Templates struct")
+	assert.Contains(t, generatedCode, "func NewTemplates(group *templator.RegistryGroup) (*Templates, error)")
+	assert.Contains(t, generatedCode, "templator.NewGroupRegistry[models.PageData](group)")
+	assert.Contains(t, generatedCode, "templator.NewGroupRegistry[ProfileData](group)")
+	assert.Contains(t, generatedCode, "func (t *Templates) GetIndex() (*templator.Handler[models.PageData], error)")
+	assert.Contains(t, generatedCode, "func (t *Templates) GetAbout() (*templator.Handler[models.PageData], error)")
+	assert.Contains(t, generatedCode, "func (t *Templates) GetUsersProfile() (*templator.Handler[ProfileData], error)")
+	assert.Contains(t, generatedCode, "func (t *Templates) MustGetUsersProfile() *templator.Handler[ProfileData]")
+	assert.Equal(t, 1, strings.Count(generatedCode, "NewGroupRegistry[models.PageData]"))
+}
+
+func TestGenerateMethods_Render(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "users/profile.html")
+
+	manifestFile := filepath.Join(t.TempDir(), "types.json")
+	err := os.WriteFile(manifestFile, []byte(`{"templates": {"index": "PageData", "users/profile": "ProfileData"}}`), 0o644)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		typesFile string
+		want      []string
+	}{
+		{
+			name: "generic accessors",
+			want: []string{
+				"\"context\"",
+				"\"io\"",
+				"func (r *TemplateAccessors[T]) RenderIndex(ctx context.Context, w io.Writer, data T) error",
+				"func (r *TemplateAccessors[T]) RenderUsersProfile(ctx context.Context, w io.Writer, data T) error",
+			},
+		},
+		{
+			name:      "typed accessors",
+			typesFile: manifestFile,
+			want: []string{
+				"\"context\"",
+				"\"io\"",
+				"func (t *Templates) RenderIndex(ctx context.Context, w io.Writer, data PageData) error",
+				"func (t *Templates) RenderUsersProfile(ctx context.Context, w io.Writer, data ProfileData) error",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "output.go")
+			cfg := config{
+				templateDir:     tempDir,
+				outputFile:      outputFile,
+				packageName:     "myapp",
+				templatorImport: "github.com/alesr/templator",
+				extension:       ".html",
+				typesFile:       tt.typesFile,
+				render:          true,
+			}
+
+			tmpl, err := loadTemplateGenerator()
+			require.NoError(t, err)
+
+			err = generateMethods(cfg, tmpl)
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(outputFile)
+			require.NoError(t, err)
+
+			for _, want := range tt.want {
+				assert.Contains(t, string(content), want)
+			}
+		})
+	}
+}
+
+func TestGenerateTypedMethods_Schema(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "users/profile.html")
+
+	manifestFile := filepath.Join(t.TempDir(), "types.json")
+	err := os.WriteFile(manifestFile, []byte(`{"templates": {"index": "PageData", "users/profile": "ProfileData"}}`), 0o644)
+	require.NoError(t, err)
+
+	outputFile := filepath.Join(t.TempDir(), "output.go")
+	cfg := config{
+		templateDir:     tempDir,
+		outputFile:      outputFile,
+		packageName:     "myapp",
+		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
+		typesFile:       manifestFile,
+		schema:          true,
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	err = generateMethods(cfg, tmpl)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+
+	generatedCode := string(content)
+	assert.Contains(t, generatedCode, "func TemplateSchemas() map[string]*templator.Schema")
+	assert.Contains(t, generatedCode, "\"index\":         templator.JSONSchema[PageData](),")
+	assert.Contains(t, generatedCode, "\"users/profile\": templator.JSONSchema[ProfileData](),")
+
+	cfg.typesFile = ""
+	require.ErrorContains(t, cfg.validate(), "-schema requires -types")
+}
+
+func TestGenerateTypedMethods_MissingType(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "about.html")
+
+	manifestFile := filepath.Join(t.TempDir(), "types.json")
+	err := os.WriteFile(manifestFile, []byte(`{"templates": {"index": "PageData"}}`), 0o644)
+	require.NoError(t, err)
+
+	cfg := config{
+		templateDir:     tempDir,
+		outputFile:      filepath.Join(tempDir, "output.go"),
+		packageName:     "myapp",
+		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
+		typesFile:       manifestFile,
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	err = generateMethods(cfg, tmpl)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "template 'about' has no data type in manifest")
+}
+
+func TestGenerateRoutes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "users/profile.html")
+
+	routesFile := filepath.Join(t.TempDir(), "routes.json")
+	err := os.WriteFile(routesFile, []byte(`{
+		"imports": ["github.com/acme/app/models"],
+		"routes": [
+			{"pattern": "GET /{$}", "template": "index", "type": "models.PageData", "data": "models.LoadIndex"},
+			{"pattern": "GET /about", "template": "index", "type": "models.PageData"},
+			{"pattern": "POST /users", "template": "users/profile", "type": "ProfileData", "data": "createUser", "status": 201}
+		]
+	}`), 0o644)
+	require.NoError(t, err)
+
+	outputFile := filepath.Join(tempDir, "output.go")
+	cfg := config{
+		templateDir:     tempDir,
+		outputFile:      outputFile,
+		packageName:     "myapp",
+		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
+		routesFile:      routesFile,
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	require.NoError(t, generateMethods(cfg, tmpl))
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+
+	generatedCode := string(content)
+	assert.Contains(t, generatedCode, "package myapp")
+	assert.Contains(t, generatedCode, "\"github.com/acme/app/models\"")
+	assert.Contains(t, generatedCode, "func RegisterRoutes(mux *http.ServeMux, group *templator.RegistryGroup) error")
+	assert.Equal(t, 1, strings.Count(generatedCode, "templator.NewGroupRegistry[models.PageData](group)"))
+	assert.Contains(t, generatedCode, "templator.NewGroupRegistry[ProfileData](group)")
+	assert.Contains(t, generatedCode, `registerRoute(mux, modelsPageDataRegistry, "GET /{$}", "index", models.LoadIndex)`)
+	assert.Contains(t, generatedCode, `registerRoute(mux, modelsPageDataRegistry, "GET /about", "index", nil)`)
+	assert.Contains(t, generatedCode, `registerRoute(mux, profileDataRegistry, "POST /users", "users/profile", createUser, templator.WithStatus(201))`)
+}
+
+func TestGenerateRoutes_InvalidRoutes(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFixture(t, tempDir, "index.html")
+
+	tests := []struct {
+		name     string
+		routes   string
+		expected string
+	}{
+		{
+			name:     "missing type",
+			routes:   `{"routes": [{"pattern": "GET /", "template": "index"}]}`,
+			expected: "route 0 requires a pattern, a template, and a type",
+		},
+		{
+			name:     "unknown template",
+			routes:   `{"routes": [{"pattern": "GET /", "template": "missing", "type": "PageData"}]}`,
+			expected: "route 'GET /' renders unknown template 'missing'",
+		},
+		{
+			name:     "invalid status",
+			routes:   `{"routes": [{"pattern": "GET /", "template": "index", "type": "PageData", "status": 42}]}`,
+			expected: "route 'GET /' has invalid status 42",
+		},
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routesFile := filepath.Join(t.TempDir(), "routes.json")
+			require.NoError(t, os.WriteFile(routesFile, []byte(tt.routes), 0o644))
+
+			err := generateMethods(config{
+				templateDir:     tempDir,
+				outputFile:      filepath.Join(t.TempDir(), "output.go"),
+				packageName:     "myapp",
+				templatorImport: "github.com/alesr/templator",
+				extension:       ".html",
+				routesFile:      routesFile,
+			}, tmpl)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestGenerateStructs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	templates := map[string]string{
+		"home.html": `{{editable "hero"}}<h1>{{.Title}}</h1>{{end}}
+{{if .LoggedIn}}<p>{{.User.Name}}</p>{{end}}
+{{range .Items}}<a href="{{.URL}}">{{.Author.Name}}</a>{{end}}
+{{range $i, $tag := .Tags}}{{$i}}: {{$tag}}{{end}}
+{{with .Footer}}{{.Text}}{{end}}`,
+		"users/profile.html": `{{.Name | printf "%s"}}`,
+	}
+	for name, content := range templates {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "output.go")
+	cfg := config{
+		templateDir:     tempDir,
+		outputFile:      outputFile,
+		packageName:     "myapp",
+		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
+		genStructs:      true,
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	err = generateMethods(cfg, tmpl)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err)
+
+	generatedCode := string(content)
+	assert.Contains(t, generatedCode, "package myapp")
+	assert.Contains(t, generatedCode, `type HomeData struct {
+	Title    string
+	LoggedIn bool
+	User     HomeUser
+	Items    []HomeItem
+	Tags     []string
+	Footer   HomeFooter
+}`)
+	assert.Contains(t, generatedCode, `type HomeItem struct {
+	URL    string
+	Author HomeItemAuthor
+}`)
+	assert.Contains(t, generatedCode, "type HomeItemAuthor struct {\n\tName string\n}")
+	assert.Contains(t, generatedCode, "type HomeFooter struct {\n\tText string\n}")
+	assert.Contains(t, generatedCode, "type UsersProfileData struct {\n\tName string\n}")
+	assert.NotContains(t, generatedCode, "func ")
+
+	cfg.typesFile = "types.json"
+	require.ErrorContains(t, cfg.validate(), "-gen-structs cannot be combined with -types")
+}
+
+func TestGenerateMethods_IncludeExclude(t *testing.T) {
+	tempDir := t.TempDir()
+
+	writeTemplateFixture(t, tempDir, "index.html")
+	writeTemplateFixture(t, tempDir, "users/profile.html")
+	writeTemplateFixture(t, tempDir, "users/card.html")
+	writeTemplateFixture(t, tempDir, "partials/nav/menu.html")
+	writeTemplateFixture(t, tempDir, "layouts/base.html")
+
+	tests := []struct {
+		name    string
+		include globList
+		exclude globList
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "exclude directories",
+			exclude: globList{"partials", "layouts/*"},
+			want:    []string{"GetIndex", "GetUsersProfile", "GetUsersCard"},
+			notWant: []string{"GetPartialsNavMenu", "GetLayoutsBase"},
+		},
+		{
+			name:    "include and exclude",
+			include: globList{"users"},
+			exclude: globList{"users/card"},
+			want:    []string{"GetUsersProfile"},
+			notWant: []string{"GetIndex", "GetUsersCard", "GetPartialsNavMenu", "GetLayoutsBase"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), "output.go")
+			cfg := config{
+				templateDir:     tempDir,
+				outputFile:      outputFile,
+				packageName:     "myapp",
+				templatorImport: "github.com/alesr/templator",
+				extension:       ".html",
+				include:         tt.include,
+				exclude:         tt.exclude,
+			}
+
+			tmpl, err := loadTemplateGenerator()
+			require.NoError(t, err)
+
+			err = generateMethods(cfg, tmpl)
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(outputFile)
+			require.NoError(t, err)
+
+			for _, want := range tt.want {
+				assert.Contains(t, string(content), want+"()")
+			}
+			for _, notWant := range tt.notWant {
+				assert.NotContains(t, string(content), notWant+"()")
+			}
+		})
+	}
+}
+
+func TestGlobList(t *testing.T) {
+	var globs globList
+	require.NoError(t, globs.Set("partials, layouts/*"))
+	require.NoError(t, globs.Set("admin/**"))
+	assert.Equal(t, globList{"partials", "layouts/*", "admin/**"}, globs)
+
+	assert.True(t, globs.matches("partials/nav/menu"))
+	assert.True(t, globs.matches("layouts/base"))
+	assert.False(t, globs.matches("home"))
+	assert.False(t, globs.matches("users/partials"))
+
+	err := config{packageName: "main", templatorImport: "x", extension: ".html", exclude: globList{"["}}.validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "malformed glob '['")
+}
+
+func TestWatch(t *testing.T) {
+	tempDir := t.TempDir()
+	writeTemplateFixture(t, tempDir, "index.html")
+
+	outputFile := filepath.Join(t.TempDir(), "output.go")
+	cfg := config{
+		templateDir:     tempDir,
+		outputFile:      outputFile,
+		packageName:     "myapp",
+		templatorImport: "github.com/alesr/templator",
+		extension:       ".html",
+		watch:           true,
+		watchInterval:   5 * time.Millisecond,
+	}
+
+	tmpl, err := loadTemplateGenerator()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watch(ctx, cfg, tmpl) }()
+
+	generated := func(method string) func() bool {
+		return func() bool {
+			content, err := os.ReadFile(outputFile)
+			return err == nil && strings.Contains(string(content), method)
+		}
+	}
+
+	require.Eventually(t, generated("GetIndex()"), time.Second, 5*time.Millisecond)
+
+	writeTemplateFixture(t, tempDir, "about.html")
+	require.Eventually(t, generated("GetAbout()"), time.Second, 5*time.Millisecond)
+
+	require.NoError(t, os.Rename(filepath.Join(tempDir, "about.html"), filepath.Join(tempDir, "contact.html")))
+	require.Eventually(t, generated("GetContact()"), time.Second, 5*time.Millisecond)
+	assert.False(t, generated("GetAbout()")())
+
+	cancel()
+	require.NoError(t, <-done)
 }
 
 func TestBuildTemplateData(t *testing.T) {
@@ -145,6 +809,12 @@ func TestBuildTemplateData(t *testing.T) {
 			wantName: "GetUsersProfile",
 			wantPath: "users/profile",
 		},
+		{
+			name:     "os separated template",
+			relPath:  filepath.Join("admin", "users", "list.html"),
+			wantName: "GetAdminUsersList",
+			wantPath: "admin/users/list",
+		},
 	}
 
 	for _, tt := range tests {
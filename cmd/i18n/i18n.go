@@ -0,0 +1,311 @@
+// Package main extracts the translatable strings of a template tree, the literal keys passed to
+// the t and T functions (see templator.WithTranslator), into a PO or JSON catalog for
+// translators, and reports the keys each locale has yet to translate.
+//
+// Usage:
+//
+//	go run ./cmd/i18n [flags]
+//
+// Flags:
+//
+//	-templates string
+//	  	Directory containing template files (default "templates")
+//	-ext string
+//	  	Template file extension (default ".html")
+//	-macros
+//	  	Expand template macros
+//	-format string
+//	  	Catalog format, po or json (default "po")
+//	-out string
+//	  	File to write the catalog to, instead of standard output
+//	-locales string
+//	  	Directory of the <locale>.po or <locale>.json catalogs to check for untranslated keys
+//
+// Notes for translators are taken from i18n comments right before a message, and written to
+// PO catalogs as extracted comments:
+//
+//	{{/* i18n: Greeting shown after sign in */}}
+//	<p>{{T "home.welcome" .Name}}</p>
+//
+// With -locales, every untranslated key is printed on its own line, prefixed by its locale,
+// and the catalog is only written when -out is set. Keys are untranslated when a catalog lacks
+// them or has an empty or fuzzy translation. The exit status is 1 when keys are untranslated
+// or the extraction failed, and 0 otherwise.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/alesr/templator"
+)
+
+// errUntranslated is returned by run when locales have untranslated keys.
+var errUntranslated = errors.New("untranslated keys")
+
+type config struct {
+	templateDir string
+	ext         string
+	macros      bool
+	format      string
+	out         string
+	locales     string
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	messages, err := extract(cfg)
+	if err != nil {
+		return err
+	}
+
+	if cfg.out != "" || cfg.locales == "" {
+		if err := writeCatalog(cfg, out, messages); err != nil {
+			return err
+		}
+	}
+	if cfg.locales == "" {
+		return nil
+	}
+
+	untranslated, err := check(cfg.locales, messages)
+	if err != nil {
+		return err
+	}
+	for _, u := range untranslated {
+		fmt.Fprintf(out, "%-8s %s\n", u.Locale, u.Key)
+	}
+	if len(untranslated) > 0 {
+		return fmt.Errorf("%w: %d", errUntranslated, len(untranslated))
+	}
+	return nil
+}
+
+func parseFlags(args []string) (config, error) {
+	var cfg config
+	flags := flag.NewFlagSet("i18n", flag.ContinueOnError)
+	flags.StringVar(&cfg.templateDir, "templates", "templates", "Directory containing template files")
+	flags.StringVar(&cfg.ext, "ext", ".html", "Template file extension")
+	flags.BoolVar(&cfg.macros, "macros", false, "Expand template macros")
+	flags.StringVar(&cfg.format, "format", "po", "Catalog format, po or json")
+	flags.StringVar(&cfg.out, "out", "", "File to write the catalog to, instead of standard output")
+	flags.StringVar(&cfg.locales, "locales", "", "Directory of the <locale>.po or <locale>.json catalogs to check for untranslated keys")
+
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+	if cfg.format != "po" && cfg.format != "json" {
+		return config{}, fmt.Errorf("unknown -format %q: expected po or json", cfg.format)
+	}
+	return cfg, nil
+}
+
+// extract returns the messages of the template tree.
+func extract(cfg config) ([]templator.Message, error) {
+	abs, err := filepath.Abs(cfg.templateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []templator.Option[any]{
+		templator.WithTemplatesPath[any](filepath.Base(abs)),
+		templator.WithExtension[any](templator.Extension(cfg.ext)),
+	}
+	if cfg.macros {
+		opts = append(opts, templator.WithMacros[any]())
+	}
+
+	reg, err := templator.NewRegistry(os.DirFS(filepath.Dir(abs)), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return reg.Messages()
+}
+
+// writeCatalog writes the catalog of messages to the -out file, or to out.
+func writeCatalog(cfg config, out io.Writer, messages []templator.Message) error {
+	write := writePO
+	if cfg.format == "json" {
+		write = writeJSON
+	}
+
+	if cfg.out == "" {
+		return write(out, messages)
+	}
+
+	f, err := os.Create(cfg.out)
+	if err != nil {
+		return err
+	}
+	if err := write(f, messages); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writePO writes messages as a PO template, with empty translations.
+func writePO(w io.Writer, messages []templator.Message) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "msgid \"\"")
+	fmt.Fprintln(bw, "msgstr \"\"")
+	fmt.Fprintln(bw, `"Content-Type: text/plain; charset=UTF-8\n"`)
+
+	for _, m := range messages {
+		fmt.Fprintln(bw)
+		for _, note := range m.Notes {
+			fmt.Fprintf(bw, "#. %s\n", note)
+		}
+		fmt.Fprintf(bw, "#: %s\n", strings.Join(m.Locations, " "))
+		fmt.Fprintf(bw, "msgid %s\n", poQuote(m.Key))
+		fmt.Fprintln(bw, "msgstr \"\"")
+	}
+	return bw.Flush()
+}
+
+// writeJSON writes messages as a JSON object mapping every key to an empty translation.
+func writeJSON(w io.Writer, messages []templator.Message) error {
+	catalog := make(map[string]string, len(messages))
+	for _, m := range messages {
+		catalog[m.Key] = ""
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(catalog)
+}
+
+func poQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
+
+// Untranslated is a key a locale has yet to translate.
+type Untranslated struct {
+	Locale string
+	Key    string
+}
+
+// check returns the keys of messages the catalogs of dir leave untranslated, by locale and key.
+func check(dir string, messages []templator.Message) ([]Untranslated, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var untranslated []Untranslated
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".po" && ext != ".json") {
+			continue
+		}
+
+		catalog, err := readCatalog(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		locale := strings.TrimSuffix(entry.Name(), ext)
+		for _, m := range messages {
+			if catalog[m.Key] == "" {
+				untranslated = append(untranslated, Untranslated{Locale: locale, Key: m.Key})
+			}
+		}
+	}
+
+	slices.SortFunc(untranslated, func(a, b Untranslated) int {
+		return strings.Compare(a.Locale+"\x00"+a.Key, b.Locale+"\x00"+b.Key)
+	})
+	return untranslated, nil
+}
+
+// readCatalog returns the translations of a PO or JSON catalog by key. Fuzzy PO entries
+// are left out.
+func readCatalog(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if filepath.Ext(path) == ".json" {
+		var catalog map[string]string
+		if err := json.Unmarshal(content, &catalog); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return catalog, nil
+	}
+
+	catalog, err := parsePO(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return catalog, nil
+}
+
+// parsePO returns the translations of a PO catalog by msgid.
+func parsePO(content string) (map[string]string, error) {
+	var (
+		catalog       = make(map[string]string)
+		msgid, msgstr string
+		fuzzy         bool
+		target        *string
+	)
+	flush := func() {
+		if msgid != "" && !fuzzy {
+			catalog[msgid] = msgstr
+		}
+		msgid, msgstr, fuzzy, target = "", "", false, nil
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			fuzzy = fuzzy || (strings.HasPrefix(line, "#,") && strings.Contains(line, "fuzzy"))
+			continue
+		}
+
+		// Keywords start a string, continued by the quoted lines that follow.
+		if keyword, rest, ok := strings.Cut(line, " "); ok && !strings.HasPrefix(line, `"`) {
+			switch keyword {
+			case "msgid":
+				target = &msgid
+			case "msgstr":
+				target = &msgstr
+			default: // msgctxt and plural forms are not checked.
+				target = new(string)
+			}
+			line = rest
+		}
+
+		s, err := strconv.Unquote(line)
+		if err != nil || target == nil {
+			return nil, fmt.Errorf("line %d: invalid string %s", i+1, line)
+		}
+		*target += s
+	}
+	flush()
+	return catalog, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"templates/home.html": "{{/* i18n: Page heading */}}\n<h1>{{t \"home.title\"}}</h1>\n<p>{{T \"home.quote\"}}</p>",
+		"templates/nav.html":  `<nav>{{t "nav.home"}}</nav>`,
+		"locales/de.po": `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: home.html:2
+msgid "home.title"
+msgstr "Willkommen"
+
+#, fuzzy
+msgid "nav.home"
+msgstr "Start"
+
+msgid "home.quote"
+msgstr ""
+"Sag \"Hallo\""
+`,
+		"locales/fr.json":   `{"home.title": "Bienvenue", "home.quote": "", "nav.home": "Accueil"}`,
+		"locales/README.md": "not a catalog",
+	})
+	templates := filepath.Join(dir, "templates")
+
+	t.Run("writes a PO catalog", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.NoError(t, run([]string{"-templates", templates}, &out))
+		assert.Equal(t, `msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#: home.html:3
+msgid "home.quote"
+msgstr ""
+
+#. Page heading
+#: home.html:2
+msgid "home.title"
+msgstr ""
+
+#: nav.html:1
+msgid "nav.home"
+msgstr ""
+`, out.String())
+
+		catalog, err := parsePO(out.String())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"home.quote": "", "home.title": "", "nav.home": ""}, catalog)
+	})
+
+	t.Run("writes a JSON catalog to a file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "messages.json")
+
+		var out bytes.Buffer
+		require.NoError(t, run([]string{"-templates", templates, "-format", "json", "-out", path}, &out))
+		assert.Empty(t, out.String())
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"home.quote": "", "home.title": "", "nav.home": ""}`, string(content))
+	})
+
+	t.Run("reports untranslated keys", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := run([]string{"-templates", templates, "-locales", filepath.Join(dir, "locales")}, &out)
+		require.ErrorIs(t, err, errUntranslated)
+		assert.Equal(t, "de       nav.home\nfr       home.quote\n", out.String())
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := parseFlags([]string{"-format", "xliff"})
+		require.ErrorContains(t, err, "xliff")
+	})
+}
@@ -0,0 +1,302 @@
+// Package main serves a template library over a small HTTP API, so non-Go systems and internal
+// tools can list, inspect, validate and preview the templates of an organization.
+//
+// Usage:
+//
+//	TEMPLATOR_TOKEN=secret go run ./cmd/serve [flags]
+//
+// Flags:
+//
+//	-addr string
+//	  	Address to listen on (default ":8080")
+//	-templates string
+//	  	Directory containing template files (default "templates")
+//	-ext string
+//	  	Template file extension (default ".html")
+//	-partials string
+//	  	Comma-separated partial patterns, e.g. "components/*"
+//	-macros
+//	  	Expand template macros
+//	-fixtures string
+//	  	Directory of JSON fixture data for previews, one file per fixture
+//	-token string
+//	  	Bearer token required by the API (default $TEMPLATOR_TOKEN)
+//
+// Templates are reloaded when they change. Every endpoint but /healthz requires an
+// "Authorization: Bearer <token>" header:
+//
+//	GET  /templates              list the templates
+//	GET  /metadata/{name}        the frontmatter and pragma metadata of a template
+//	GET  /validate               parse every template, 422 with the errors when any fails
+//	POST /preview/{name}         render a template with the JSON data of the request body,
+//	                             or with the fixture named by ?fixture=, which defaults to
+//	                             the template name, when the body is empty
+//	GET  /healthz                registry health and template checksums, see Registry.HealthHandler
+//
+// Data is decoded as JSON objects, so templates access it as maps: {{.title}}.
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/alesr/templator"
+)
+
+// previewTimeout bounds the rendering of a preview.
+const previewTimeout = 10 * time.Second
+
+// Data is the data templates are rendered with: a decoded JSON object.
+type Data = map[string]any
+
+type config struct {
+	addr        string
+	templateDir string
+	ext         string
+	partials    string
+	macros      bool
+	fixtures    string
+	token       string
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	cfg, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	reg, err := newRegistry(cfg)
+	if err != nil {
+		return err
+	}
+	defer reg.Close(context.Background())
+
+	log.Printf("serving templates of %s on %s", cfg.templateDir, cfg.addr)
+	return http.ListenAndServe(cfg.addr, newServer(reg, cfg))
+}
+
+func parseFlags(args []string) (config, error) {
+	var cfg config
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	flags.StringVar(&cfg.addr, "addr", ":8080", "Address to listen on")
+	flags.StringVar(&cfg.templateDir, "templates", "templates", "Directory containing template files")
+	flags.StringVar(&cfg.ext, "ext", ".html", "Template file extension")
+	flags.StringVar(&cfg.partials, "partials", "", `Comma-separated partial patterns, e.g. "components/*"`)
+	flags.BoolVar(&cfg.macros, "macros", false, "Expand template macros")
+	flags.StringVar(&cfg.fixtures, "fixtures", "", "Directory of JSON fixture data for previews, one file per fixture")
+	flags.StringVar(&cfg.token, "token", os.Getenv("TEMPLATOR_TOKEN"), "Bearer token required by the API (default $TEMPLATOR_TOKEN)")
+
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+	if cfg.token == "" {
+		return config{}, errors.New("-token or TEMPLATOR_TOKEN is required")
+	}
+	return cfg, nil
+}
+
+// newRegistry creates a hot reloading registry over the template directory.
+func newRegistry(cfg config) (*templator.Registry[Data], error) {
+	abs, err := filepath.Abs(cfg.templateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []templator.Option[Data]{
+		templator.WithTemplatesPath[Data](filepath.Base(abs)),
+		templator.WithExtension[Data](templator.Extension(cfg.ext)),
+		templator.WithHotReload[Data](),
+	}
+	if cfg.partials != "" {
+		opts = append(opts, templator.WithPartials[Data](strings.Split(cfg.partials, ",")...))
+	}
+	if cfg.macros {
+		opts = append(opts, templator.WithMacros[Data]())
+	}
+	return templator.NewRegistry(os.DirFS(filepath.Dir(abs)), opts...)
+}
+
+// newServer returns the API handler.
+func newServer(reg *templator.Registry[Data], cfg config) http.Handler {
+	s := server{reg: reg}
+	if cfg.fixtures != "" {
+		s.fixtures = os.DirFS(cfg.fixtures)
+	}
+
+	api := http.NewServeMux()
+	api.HandleFunc("GET /templates", s.list)
+	api.HandleFunc("GET /metadata/{name...}", s.metadata)
+	api.HandleFunc("GET /validate", s.validate)
+	api.HandleFunc("POST /preview/{name...}", s.preview)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", reg.HealthHandler())
+	mux.Handle("/", authenticate(cfg.token, api))
+	return mux
+}
+
+// authenticate rejects requests without the bearer token.
+func authenticate(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type server struct {
+	reg      *templator.Registry[Data]
+	fixtures fs.FS
+}
+
+func (s server) list(w http.ResponseWriter, _ *http.Request) {
+	names, err := s.reg.ListTemplates()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"templates": names})
+}
+
+func (s server) metadata(w http.ResponseWriter, r *http.Request) {
+	md, err := s.reg.Metadata(r.PathValue("name"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"metadata": md})
+}
+
+func (s server) validate(w http.ResponseWriter, _ *http.Request) {
+	names, err := s.reg.ListTemplates()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	errs := make(map[string]string)
+	for _, name := range names {
+		if _, err := s.reg.Get(name); err != nil {
+			errs[name] = err.Error()
+		}
+	}
+
+	status := http.StatusOK
+	if len(errs) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSON(w, status, map[string]any{"valid": len(errs) == 0, "errors": errs})
+}
+
+func (s server) preview(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	h, err := s.reg.Get(name)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	data, err := s.previewData(r, name)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), previewTimeout)
+	defer cancel()
+
+	out, err := h.ExecuteToBytes(ctx, data)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(out)
+}
+
+// previewData decodes the data of a preview from the request body, or from a fixture
+// file when the body is empty.
+func (s server) previewData(r *http.Request, name string) (Data, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, 1<<20))
+	if err != nil {
+		return nil, errBadRequest{err}
+	}
+
+	if len(body) == 0 {
+		if s.fixtures == nil {
+			return nil, nil
+		}
+
+		fixture := r.URL.Query().Get("fixture")
+		if fixture == "" {
+			fixture = name
+		}
+		if body, err = fs.ReadFile(s.fixtures, fixture+".json"); err != nil {
+			if errors.Is(err, fs.ErrNotExist) && r.URL.Query().Get("fixture") == "" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("fixture '%s': %w", fixture, err)
+		}
+	}
+
+	var data Data
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, errBadRequest{fmt.Errorf("decode data: %w", err)}
+	}
+	return data, nil
+}
+
+// errBadRequest marks errors caused by the request.
+type errBadRequest struct{ err error }
+
+func (e errBadRequest) Error() string { return e.err.Error() }
+func (e errBadRequest) Unwrap() error { return e.err }
+
+// errorStatus returns the response status for an error.
+func errorStatus(err error) int {
+	switch {
+	case errors.As(err, new(errBadRequest)), errors.Is(err, fs.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, fs.ErrNotExist):
+		return http.StatusNotFound
+	case errors.As(err, new(templator.ErrTemplateParse)):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"templates/home.html":           "---\ntitle: Home\n---\n<h1>{{.title}}</h1>{{template \"components/nav.html\" .}}",
+		"templates/components/nav.html": "<nav></nav>",
+		"templates/broken.html":         "{{if}}",
+		"fixtures/home.json":            `{"title":"From fixture"}`,
+		"fixtures/other.json":           `{"title":"Other fixture"}`,
+		"fixtures/invalid.json":         `{`,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	cfg := config{
+		templateDir: filepath.Join(dir, "templates"),
+		ext:         ".html",
+		partials:    "components/*",
+		fixtures:    filepath.Join(dir, "fixtures"),
+		token:       "secret",
+	}
+	reg, err := newRegistry(cfg)
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(newServer(reg, cfg))
+	t.Cleanup(srv.Close)
+
+	do := func(t *testing.T, method, path, token, body string) (int, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		out, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(out)
+	}
+
+	t.Run("requires the token", func(t *testing.T) {
+		t.Parallel()
+
+		for _, token := range []string{"", "wrong"} {
+			status, _ := do(t, http.MethodGet, "/templates", token, "")
+			assert.Equal(t, http.StatusUnauthorized, status)
+		}
+
+		status, _ := do(t, http.MethodGet, "/healthz", "", "")
+		assert.Equal(t, http.StatusOK, status)
+	})
+
+	t.Run("lists templates", func(t *testing.T) {
+		t.Parallel()
+
+		status, body := do(t, http.MethodGet, "/templates", "secret", "")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"templates":["broken","components/nav","home"]}`, body)
+	})
+
+	t.Run("returns metadata", func(t *testing.T) {
+		t.Parallel()
+
+		status, body := do(t, http.MethodGet, "/metadata/home", "secret", "")
+		require.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"metadata":{"title":"Home"}}`, body)
+
+		status, _ = do(t, http.MethodGet, "/metadata/missing", "secret", "")
+		assert.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("validates templates", func(t *testing.T) {
+		t.Parallel()
+
+		status, body := do(t, http.MethodGet, "/validate", "secret", "")
+		require.Equal(t, http.StatusUnprocessableEntity, status)
+
+		var got struct {
+			Valid  bool
+			Errors map[string]string
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &got))
+		assert.False(t, got.Valid)
+		assert.Contains(t, got.Errors, "broken")
+		assert.Len(t, got.Errors, 1)
+	})
+
+	t.Run("renders previews", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name       string
+			path       string
+			body       string
+			wantStatus int
+			want       string
+		}{
+			{name: "request data", path: "/preview/home", body: `{"title":"From body"}`, wantStatus: http.StatusOK, want: "<h1>From body</h1><nav></nav>"},
+			{name: "default fixture", path: "/preview/home", wantStatus: http.StatusOK, want: "<h1>From fixture</h1><nav></nav>"},
+			{name: "named fixture", path: "/preview/home?fixture=other", wantStatus: http.StatusOK, want: "<h1>Other fixture</h1><nav></nav>"},
+			{name: "no fixture", path: "/preview/components/nav", wantStatus: http.StatusOK, want: "<nav></nav>"},
+			{name: "missing fixture", path: "/preview/home?fixture=nope", wantStatus: http.StatusNotFound},
+			{name: "escaping fixture", path: "/preview/home?fixture=../templates/home", wantStatus: http.StatusBadRequest},
+			{name: "invalid fixture", path: "/preview/home?fixture=invalid", wantStatus: http.StatusBadRequest},
+			{name: "invalid body", path: "/preview/home", body: `[1]`, wantStatus: http.StatusBadRequest},
+			{name: "unknown template", path: "/preview/missing", wantStatus: http.StatusNotFound},
+			{name: "template name with extension", path: "/preview/home.html", body: `{}`, wantStatus: http.StatusBadRequest},
+			{name: "broken template", path: "/preview/broken", wantStatus: http.StatusUnprocessableEntity},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				status, body := do(t, http.MethodPost, tc.path, "secret", tc.body)
+				assert.Equal(t, tc.wantStatus, status, body)
+				if tc.want != "" {
+					assert.Equal(t, tc.want, body)
+				}
+			})
+		}
+	})
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Setenv("TEMPLATOR_TOKEN", "")
+
+	_, err := parseFlags(nil)
+	require.ErrorContains(t, err, "token")
+
+	cfg, err := parseFlags([]string{"-token", "secret", "-partials", "components/*"})
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.addr)
+	assert.Equal(t, "secret", cfg.token)
+	assert.Equal(t, "components/*", cfg.partials)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"go/types"
+	"io"
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/alesr/templator"
+	"golang.org/x/tools/go/packages"
+)
+
+// errLint is returned by the lint command when templates failed.
+var errLint = errors.New("templates failed lint")
+
+// lintConfig holds the flags of the lint command.
+type lintConfig struct {
+	registryFlags
+	srcDir string
+	types  typeFlags
+	funcs  []string
+}
+
+// typeFlag is a -type flag: the Go type templates are validated against, and the template it
+// applies to, or empty for every page.
+type typeFlag struct {
+	template string
+	pkg      string
+	name     string
+}
+
+// typeFlags collects repeated -type flags.
+type typeFlags []typeFlag
+
+func (f *typeFlags) String() string {
+	return fmt.Sprint(*f)
+}
+
+// Set parses "[template=]package.Type", where package is a package pattern, such as
+// ./views or example.com/app/views, or is omitted for the package in the source directory.
+func (f *typeFlags) Set(value string) error {
+	var tf typeFlag
+	if template, typ, ok := strings.Cut(value, "="); ok {
+		tf.template, value = template, typ
+	}
+
+	tf.pkg, tf.name = ".", value
+	if i := strings.LastIndex(value, "."); i > strings.LastIndex(value, "/") {
+		tf.pkg, tf.name = value[:i], value[i+1:]
+	}
+	if tf.pkg == "" || tf.name == "" || !token.IsIdentifier(tf.name) {
+		return fmt.Errorf("invalid type %q, expected [template=]package.Type", value)
+	}
+
+	*f = append(*f, tf)
+	return nil
+}
+
+func runLint(args []string, _ io.Reader, out io.Writer) error {
+	cfg, err := parseLintFlags(args)
+	if err != nil {
+		return err
+	}
+
+	models, err := loadModels(cfg.srcDir, cfg.types)
+	if err != nil {
+		return err
+	}
+
+	funcs := make(map[string]any, len(cfg.funcs))
+	for _, name := range cfg.funcs {
+		// Stubs only let templates calling the application's functions parse.
+		funcs[name] = func(...any) (any, error) { return nil, nil }
+	}
+
+	reg, err := cfg.newRegistry(templator.WithTemplateFuncs[Data](funcs))
+	if err != nil {
+		return err
+	}
+
+	names, err := reg.ListTemplates()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, name := range names {
+		model, validate := modelFor(name, models, cfg.partials)
+		if err := lintTemplate(reg, name, model, validate); err != nil {
+			fmt.Fprintln(out, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d templates", errLint, failed, len(names))
+	}
+	return nil
+}
+
+func parseLintFlags(args []string) (lintConfig, error) {
+	var (
+		cfg   lintConfig
+		funcs string
+	)
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.StringVar(&cfg.srcDir, "src", ".", "Directory of the Go module the types are looked up from")
+	flags.Var(&cfg.types, "type", "Go type to validate templates against, [template=]package.Type, repeatable")
+	flags.StringVar(&funcs, "funcs", "", "Comma-separated names of the template functions the application registers")
+	cfg.registryFlags.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return lintConfig{}, err
+	}
+	for name := range strings.SplitSeq(funcs, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.funcs = append(cfg.funcs, name)
+		}
+	}
+	return cfg, nil
+}
+
+// models maps the templates named by -type flags to their model, with the default model,
+// for the pages, under the empty name.
+type models map[string]reflect.Value
+
+// loadModels loads the packages of the types and returns a model of each type.
+func loadModels(dir string, flags typeFlags) (models, error) {
+	var (
+		loaded  = make(map[string]*types.Package)
+		builder = newModelBuilder()
+		found   = make(models, len(flags))
+	)
+	for _, tf := range flags {
+		pkg, ok := loaded[tf.pkg]
+		if !ok {
+			var err error
+			if pkg, err = loadPackage(dir, tf.pkg); err != nil {
+				return nil, err
+			}
+			loaded[tf.pkg] = pkg
+		}
+
+		obj, ok := pkg.Scope().Lookup(tf.name).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in package %s", tf.name, pkg.Path())
+		}
+		if _, ok := found[tf.template]; ok {
+			return nil, fmt.Errorf("more than one type for template %q", tf.template)
+		}
+		found[tf.template] = reflect.New(builder.build(obj.Type())).Elem()
+	}
+	return found, nil
+}
+
+// loadMode type checks packages and their dependencies from source, which doesn't depend
+// on the export data format of the Go toolchain.
+const loadMode = packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps
+
+// loadPackage type checks the package matching pattern in dir.
+func loadPackage(dir, pattern string) (*types.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: loadMode, Dir: dir}, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("package %s: matched %d packages, expected one", pattern, len(pkgs))
+	}
+	if len(pkgs[0].Errors) > 0 {
+		return nil, fmt.Errorf("package %s: %v", pattern, pkgs[0].Errors[0])
+	}
+	return pkgs[0].Types, nil
+}
+
+// modelFor returns the model the named template is validated against: the one of its -type
+// flag, or the default one unless the template is a partial, rendered with other data.
+func modelFor(name string, models models, partials string) (reflect.Value, bool) {
+	if model, ok := models[name]; ok {
+		return model, true
+	}
+	for pattern := range strings.SplitSeq(partials, ",") {
+		if matched, _ := path.Match(strings.TrimSpace(pattern), name); matched && pattern != "" {
+			return reflect.Value{}, false
+		}
+	}
+	model, ok := models[""]
+	return model, ok
+}
+
+// lintTemplate parses the named template, and validates its fields against model when set.
+func lintTemplate(reg *templator.Registry[Data], name string, model reflect.Value, validate bool) error {
+	if _, err := reg.Get(name); err != nil {
+		return err
+	}
+	if !validate {
+		return nil
+	}
+
+	err := reg.ValidateModel(name, model.Interface())
+	var validationErr *templator.ValidationError
+	if errors.As(err, &validationErr) {
+		return fmt.Errorf("template '%s': field '%s': %w", name, validationErr.FieldPath, validationErr.Err)
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.24\n",
+		"views/views.go": `package views
+
+import "time"
+
+type Base struct {
+	Title string
+}
+
+type PageData struct {
+	Base
+	User    *User
+	Items   []Item
+	Updated time.Time
+}
+
+type User struct {
+	Name string
+	role string
+}
+
+func (u *User) IsAdmin() bool { return u.role == "admin" }
+
+type Item struct {
+	Label string
+	Tags  map[string]string
+}
+
+type CardData struct {
+	Heading string
+}
+`,
+		"templates/home.html":            `<h1>{{.Title}}</h1>{{if .User.IsAdmin}}{{.User.Name}}{{end}}{{.Updated.Year}}`,
+		"templates/list.html":            `{{range .Items}}<li>{{.Label}} {{.Tags.color}}</li>{{end}}`,
+		"templates/drifted.html":         `{{.User.Email}}{{range .Items}}{{.Name}}{{end}}`,
+		"templates/funcs.html":           `{{upper .Title}}`,
+		"templates/broken.html":          `{{if .Title}}`,
+		"templates/components/card.html": `<h2>{{.Heading}}</h2>`,
+	})
+
+	templates := filepath.Join(dir, "templates")
+	base := []string{"lint", "-src", dir, "-templates", templates, "-partials", "components/*"}
+
+	t.Run("failures", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := run(append(base,
+			"-type", "./views.PageData",
+			"-type", "components/card=example.com/app/views.CardData",
+			"-funcs", "upper",
+		), nil, &out)
+		require.ErrorIs(t, err, errLint)
+		assert.ErrorContains(t, err, "2 of 6 templates")
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.Contains(t, lines[0], "broken")
+		assert.Contains(t, lines[1], "template 'drifted': field 'User.Email'")
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		t.Parallel()
+
+		clean := t.TempDir()
+		writeFiles(t, clean, map[string]string{
+			"templates/home.html":            `<h1>{{.Title}}</h1>`,
+			"templates/components/card.html": `<h2>{{.Heading}}</h2>`,
+		})
+
+		var out bytes.Buffer
+		require.NoError(t, run([]string{
+			"lint", "-src", dir, "-templates", filepath.Join(clean, "templates"),
+			"-partials", "components/*", "-type", "./views.PageData",
+		}, nil, &out))
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("unknown function", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		require.ErrorIs(t, run(base, nil, &out), errLint)
+		assert.Contains(t, out.String(), `function "upper" not defined`)
+	})
+
+	t.Run("invalid types", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			typ     string
+			wantErr string
+		}{
+			{typ: "./views.Missing", wantErr: "type Missing not found in package example.com/app/views"},
+			{typ: "./views.", wantErr: "invalid type"},
+			{typ: "./missing.PageData", wantErr: "package ./missing"},
+		}
+
+		for _, tt := range tests {
+			err := run(append(base, "-type", tt.typ), nil, &bytes.Buffer{})
+			require.ErrorContains(t, err, tt.wantErr, tt.typ)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"go/types"
+	"reflect"
+)
+
+// anyType is the type of values the field validator does not check, such as interfaces.
+var anyType = reflect.TypeFor[any]()
+
+// basicTypes maps the basic types of go/types to their reflect types.
+var basicTypes = map[types.BasicKind]reflect.Type{
+	types.Bool:       reflect.TypeFor[bool](),
+	types.Int:        reflect.TypeFor[int](),
+	types.Int8:       reflect.TypeFor[int8](),
+	types.Int16:      reflect.TypeFor[int16](),
+	types.Int32:      reflect.TypeFor[int32](),
+	types.Int64:      reflect.TypeFor[int64](),
+	types.Uint:       reflect.TypeFor[uint](),
+	types.Uint8:      reflect.TypeFor[uint8](),
+	types.Uint16:     reflect.TypeFor[uint16](),
+	types.Uint32:     reflect.TypeFor[uint32](),
+	types.Uint64:     reflect.TypeFor[uint64](),
+	types.Uintptr:    reflect.TypeFor[uintptr](),
+	types.Float32:    reflect.TypeFor[float32](),
+	types.Float64:    reflect.TypeFor[float64](),
+	types.Complex64:  reflect.TypeFor[complex64](),
+	types.Complex128: reflect.TypeFor[complex128](),
+	types.String:     reflect.TypeFor[string](),
+}
+
+// modelBuilder builds reflect types standing in for go/types types, so the field validator of
+// templator, which walks reflect types, can check templates against types of Go sources
+// without compiling them. Reflection cannot create named types or methods, so:
+//
+//   - structs keep their exported fields, promoted fields included, and get a field per
+//     exported method, of the type of its first result, so {{.Method}} paths resolve;
+//   - other named types with exported methods, and interfaces, functions and recursive
+//     references, become any, which the validator does not check.
+type modelBuilder struct {
+	built   map[types.Type]reflect.Type
+	pending map[*types.Named]bool
+}
+
+func newModelBuilder() *modelBuilder {
+	return &modelBuilder{
+		built:   make(map[types.Type]reflect.Type),
+		pending: make(map[*types.Named]bool),
+	}
+}
+
+// build returns the reflect type standing in for t.
+func (b *modelBuilder) build(t types.Type) reflect.Type {
+	t = types.Unalias(t)
+	if typ, ok := b.built[t]; ok {
+		return typ
+	}
+
+	typ := b.convert(t)
+	b.built[t] = typ
+	return typ
+}
+
+func (b *modelBuilder) convert(t types.Type) reflect.Type {
+	switch t := t.(type) {
+	case *types.Named:
+		if b.pending[t] {
+			return anyType
+		}
+		b.pending[t] = true
+		defer delete(b.pending, t)
+
+		methods := exportedMethods(t)
+		if s, ok := t.Underlying().(*types.Struct); ok {
+			return b.structOf(s, methods)
+		}
+		if len(methods) > 0 {
+			return anyType
+		}
+		return b.build(t.Underlying())
+	case *types.Basic:
+		if typ, ok := basicTypes[t.Kind()]; ok {
+			return typ
+		}
+		return anyType
+	case *types.Pointer:
+		return reflect.PointerTo(b.build(t.Elem()))
+	case *types.Slice:
+		return reflect.SliceOf(b.build(t.Elem()))
+	case *types.Array:
+		return reflect.ArrayOf(int(t.Len()), b.build(t.Elem()))
+	case *types.Map:
+		key := b.build(t.Key())
+		if !key.Comparable() {
+			key = reflect.TypeFor[string]()
+		}
+		return reflect.MapOf(key, b.build(t.Elem()))
+	case *types.Chan:
+		return reflect.ChanOf(reflect.BothDir, b.build(t.Elem()))
+	case *types.Struct:
+		return b.structOf(t, nil)
+	default:
+		return anyType
+	}
+}
+
+// structOf returns a struct type with the exported fields of s, including promoted ones,
+// followed by a field per method. Fields shadow promoted fields and methods of the same name.
+func (b *modelBuilder) structOf(s *types.Struct, methods []*types.Func) reflect.Type {
+	var (
+		fields  []reflect.StructField
+		seen    = make(map[string]bool)
+		visited = map[*types.Struct]bool{s: true}
+	)
+	add := func(name string, typ types.Type) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		fields = append(fields, reflect.StructField{Name: name, Type: b.build(typ)})
+	}
+
+	// Breadth first, so shallower fields win, as with Go's promotion rules.
+	for level := []*types.Struct{s}; len(level) > 0; {
+		var next []*types.Struct
+		for _, s := range level {
+			for field := range s.Fields() {
+				if field.Exported() {
+					add(field.Name(), field.Type())
+				}
+				if !field.Embedded() {
+					continue
+				}
+
+				embedded := field.Type()
+				if ptr, ok := types.Unalias(embedded).(*types.Pointer); ok {
+					embedded = ptr.Elem()
+				}
+				if es, ok := embedded.Underlying().(*types.Struct); ok && !visited[es] {
+					visited[es] = true
+					next = append(next, es)
+				}
+			}
+		}
+		level = next
+	}
+
+	for _, m := range methods {
+		if seen[m.Name()] {
+			continue
+		}
+		seen[m.Name()] = true
+
+		typ := anyType
+		if results := m.Signature().Results(); results.Len() > 0 {
+			typ = b.build(results.At(0).Type())
+		}
+		fields = append(fields, reflect.StructField{Name: m.Name(), Type: typ})
+	}
+	return reflect.StructOf(fields)
+}
+
+// exportedMethods returns the exported methods of t and *t, promoted ones included.
+func exportedMethods(t *types.Named) []*types.Func {
+	var methods []*types.Func
+	set := types.NewMethodSet(types.NewPointer(t))
+	for sel := range set.Methods() {
+		if fn, ok := sel.Obj().(*types.Func); ok && fn.Exported() {
+			methods = append(methods, fn)
+		}
+	}
+	return methods
+}
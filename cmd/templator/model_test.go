@@ -0,0 +1,84 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelBuilder(t *testing.T) {
+	t.Parallel()
+
+	const src = `package views
+
+type Base struct {
+	Title string
+	ID    int
+}
+
+type Page struct {
+	Base
+	*meta
+	ID       string
+	Children []*Page
+	Links    map[string]Link
+	Status   Status
+	Name     Name
+	hidden   bool
+}
+
+type meta struct {
+	Author string
+}
+
+type Link struct {
+	URL string
+}
+
+func (l Link) Host() string { return "" }
+func (l *Link) Save()       {}
+
+type Status int
+
+func (s Status) Label() string { return "" }
+
+type Name string
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "views.go", src, 0)
+	require.NoError(t, err)
+	pkg, err := new(types.Config).Check("views", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	typ := newModelBuilder().build(pkg.Scope().Lookup("Page").Type())
+	require.Equal(t, reflect.Struct, typ.Kind())
+
+	field := func(typ reflect.Type, name string) reflect.Type {
+		t.Helper()
+		f, ok := typ.FieldByName(name)
+		require.True(t, ok, name)
+		return f.Type
+	}
+
+	assert.Equal(t, reflect.TypeFor[string](), field(typ, "Title"))
+	assert.Equal(t, reflect.TypeFor[string](), field(typ, "ID"), "fields shadow promoted fields")
+	assert.Equal(t, reflect.TypeFor[string](), field(typ, "Author"), "promoted through unexported embedded pointers")
+	assert.Equal(t, reflect.Struct, field(typ, "Base").Kind())
+	assert.Equal(t, reflect.TypeFor[[]*any](), field(typ, "Children"), "recursive references are not checked")
+	assert.Equal(t, reflect.TypeFor[any](), field(typ, "Status"), "named non-structs with methods are not checked")
+	assert.Equal(t, reflect.TypeFor[string](), field(typ, "Name"))
+
+	_, ok := typ.FieldByName("hidden")
+	assert.False(t, ok)
+
+	link := field(typ, "Links").Elem()
+	assert.Equal(t, reflect.TypeFor[string](), field(link, "URL"))
+	assert.Equal(t, reflect.TypeFor[string](), field(link, "Host"))
+	assert.Equal(t, reflect.TypeFor[any](), field(link, "Save"))
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/alesr/templator"
+	"gopkg.in/yaml.v3"
+)
+
+// renderConfig holds the flags of the render command.
+type renderConfig struct {
+	registryFlags
+	template  string
+	data      string
+	out       string
+	locale    string
+	normalize bool
+}
+
+func runRender(args []string, in io.Reader, out io.Writer) error {
+	cfg, err := parseRenderFlags(args)
+	if err != nil {
+		return err
+	}
+
+	data, err := readData(cfg.data, in)
+	if err != nil {
+		return err
+	}
+
+	var opts []templator.Option[Data]
+	if cfg.locale != "" {
+		opts = append(opts, templator.WithLocales[Data](cfg.locale))
+	}
+
+	reg, err := cfg.newRegistry(opts...)
+	if err != nil {
+		return err
+	}
+	defer reg.Close(context.Background())
+
+	ctx := context.Background()
+	var h *templator.Handler[Data]
+	if cfg.locale != "" {
+		ctx = templator.WithLocale(ctx, cfg.locale)
+		h, err = reg.GetLocalized(cfg.template, cfg.locale)
+	} else {
+		h, err = reg.Get(cfg.template)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Rendering to a buffer leaves no partial output file behind on errors.
+	var buf bytes.Buffer
+	if err := h.Execute(ctx, &buf, data); err != nil {
+		return err
+	}
+
+	output := buf.Bytes()
+	if cfg.normalize {
+		if output, err = templator.NormalizeHTML(output); err != nil {
+			return err
+		}
+	}
+
+	if cfg.out == "" {
+		_, err = out.Write(output)
+		return err
+	}
+	return os.WriteFile(cfg.out, output, 0o644)
+}
+
+func parseRenderFlags(args []string) (renderConfig, error) {
+	var cfg renderConfig
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	flags.StringVar(&cfg.template, "template", "", "Template to render")
+	flags.StringVar(&cfg.data, "data", "", `JSON or YAML file of the data, by extension, or "-" for JSON on stdin`)
+	flags.StringVar(&cfg.out, "out", "", "Output file (default stdout)")
+	flags.StringVar(&cfg.locale, "locale", "", "Render the locale variant of the template, with the locale in the context")
+	flags.BoolVar(&cfg.normalize, "normalize", false, "Normalize the output for stable diffs, see templator.NormalizeHTML")
+	cfg.registryFlags.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return renderConfig{}, err
+	}
+	if cfg.template == "" {
+		return renderConfig{}, errors.New("-template is required")
+	}
+	return cfg, nil
+}
+
+// readData decodes the data file at path, or stdin for "-". Without a path the data is empty.
+func readData(path string, in io.Reader) (Data, error) {
+	data := Data{}
+	if path == "" {
+		return data, nil
+	}
+
+	var (
+		content []byte
+		err     error
+	)
+	if path == "-" {
+		content, err = io.ReadAll(in)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(path)
+	if path == "-" {
+		ext = ".json"
+	}
+
+	switch ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &data)
+	case ".json":
+		err = json.Unmarshal(content, &data)
+	default:
+		return nil, fmt.Errorf("data file %s: unsupported extension %q, expected .json, .yaml, or .yml", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("data file %s: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,144 @@
+// Package main is the templator command line tool, which works with a template tree outside
+// Go code: previewing templates, generating snapshots in CI, and rendering for non-Go systems.
+//
+// Usage:
+//
+//	go run ./cmd/templator <command> [flags]
+//
+// Commands:
+//
+//	render  render a template with JSON or YAML data
+//	lint    parse every template and validate its fields against Go types
+//
+// Every command reads the template tree with the flags:
+//
+//	-templates string
+//	  	Directory containing template files (default "templates")
+//	-ext string
+//	  	Template file extension (default ".html")
+//	-partials string
+//	  	Comma-separated partial patterns, e.g. "components/*"
+//	-macros
+//	  	Expand template macros
+//
+// # render
+//
+//	templator render -template home -data data.json -out out.html
+//
+// Renders a template once. Flags:
+//
+//	-template string
+//	  	Template to render (required)
+//	-data string
+//	  	JSON or YAML file of the data, by extension, or "-" for JSON on stdin (optional)
+//	-out string
+//	  	Output file (default stdout)
+//	-locale string
+//	  	Render the locale variant of the template, with the locale in the context (optional)
+//	-normalize
+//	  	Normalize the output for stable diffs, see templator.NormalizeHTML (optional)
+//
+// Data is decoded as objects, so templates access it as maps: {{.title}}. Nothing is
+// written when rendering fails.
+//
+// # lint
+//
+//	templator lint -type ./views.PageData -type home=./views.HomeData -funcs upper,money
+//
+// Parses every template, and validates the fields they reference against Go types, which are
+// type checked from the sources with go/packages, so CI fails on drift between templates and
+// their models before deploying. Flags:
+//
+//	-type string
+//	  	Go type to validate templates against, as [template=]package.Type, where package is
+//	  	a package pattern such as ./views, or is omitted for the package of -src. Without a
+//	  	template it applies to every template but the partials. Repeatable (optional)
+//	-src string
+//	  	Directory of the Go module the types are looked up from (default ".")
+//	-funcs string
+//	  	Comma-separated names of the template functions the application registers (optional)
+//
+// Failures are printed one per line, and the exit status is 1 when any template failed.
+// Templates without a type are only parsed.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alesr/templator"
+)
+
+// Data is the data templates are rendered with: a decoded JSON or YAML object.
+type Data = map[string]any
+
+// errUsage is returned by run for a missing or unknown command.
+var errUsage = errors.New("usage: templator <command> [flags], with command one of: render, lint")
+
+// command runs a subcommand with its arguments.
+type command func(args []string, in io.Reader, out io.Writer) error
+
+var commands = map[string]command{
+	"render": runRender,
+	"lint":   runLint,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q: %w", args[0], errUsage)
+	}
+	return cmd(args[1:], in, out)
+}
+
+// registryFlags are the flags shared by the commands to open the template tree.
+type registryFlags struct {
+	templateDir string
+	ext         string
+	partials    string
+	macros      bool
+}
+
+// register defines the flags on flags.
+func (f *registryFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.templateDir, "templates", "templates", "Directory containing template files")
+	flags.StringVar(&f.ext, "ext", ".html", "Template file extension")
+	flags.StringVar(&f.partials, "partials", "", `Comma-separated partial patterns, e.g. "components/*"`)
+	flags.BoolVar(&f.macros, "macros", false, "Expand template macros")
+}
+
+// newRegistry creates a registry over the template directory, with extra options.
+func (f registryFlags) newRegistry(extra ...templator.Option[Data]) (*templator.Registry[Data], error) {
+	abs, err := filepath.Abs(f.templateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []templator.Option[Data]{
+		templator.WithTemplatesPath[Data](filepath.Base(abs)),
+		templator.WithExtension[Data](templator.Extension(f.ext)),
+	}
+	if f.partials != "" {
+		opts = append(opts, templator.WithPartials[Data](strings.Split(f.partials, ",")...))
+	}
+	if f.macros {
+		opts = append(opts, templator.WithMacros[Data]())
+	}
+	return templator.NewRegistry(os.DirFS(filepath.Dir(abs)), append(opts, extra...)...)
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.ErrorIs(t, run(nil, nil, &out), errUsage)
+	require.ErrorIs(t, run([]string{"deploy"}, nil, &out), errUsage)
+	assert.Empty(t, out.String())
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"templates/home.html":           `<h1>{{.title}}</h1>{{template "components/nav.html" .}}`,
+		"templates/home.pt.html":        `<h1>Olá {{.title}}</h1>`,
+		"templates/components/nav.html": `<nav>{{range .links}}{{.}} {{end}}</nav>`,
+		"templates/spaced.html":         "<p id='b' class='a'>\n    Hi\n</p>\n",
+		"templates/broken.html":         `{{.title.missing.field}}`,
+		"data/home.json":                `{"title": "Home", "links": ["a", "b"]}`,
+		"data/home.yaml":                "title: Home\nlinks: [a, b]\n",
+		"data/home.txt":                 `title=Home`,
+	})
+	templates := filepath.Join(dir, "templates")
+
+	tests := []struct {
+		name    string
+		args    []string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "json data",
+			args: []string{"-template", "home", "-data", filepath.Join(dir, "data/home.json")},
+			want: "<h1>Home</h1><nav>a b </nav>",
+		},
+		{
+			name: "yaml data",
+			args: []string{"-template", "home", "-data", filepath.Join(dir, "data/home.yaml")},
+			want: "<h1>Home</h1><nav>a b </nav>",
+		},
+		{
+			name:  "stdin data",
+			args:  []string{"-template", "home", "-data", "-"},
+			stdin: `{"title": "Stdin"}`,
+			want:  "<h1>Stdin</h1><nav></nav>",
+		},
+		{
+			name: "no data",
+			args: []string{"-template", "home"},
+			want: "<h1></h1><nav></nav>",
+		},
+		{
+			name: "locale",
+			args: []string{"-template", "home", "-locale", "pt", "-data", filepath.Join(dir, "data/home.json")},
+			want: "<h1>Olá Home</h1>",
+		},
+		{
+			name: "normalized",
+			args: []string{"-template", "spaced", "-normalize"},
+			want: "<p class=\"a\" id=\"b\">\nHi\n</p>",
+		},
+		{
+			name:    "missing template flag",
+			args:    nil,
+			wantErr: "-template is required",
+		},
+		{
+			name:    "unknown template",
+			args:    []string{"-template", "missing"},
+			wantErr: "missing",
+		},
+		{
+			name:    "unsupported data",
+			args:    []string{"-template", "home", "-data", filepath.Join(dir, "data/home.txt")},
+			wantErr: "unsupported extension",
+		},
+		{
+			name:    "invalid json",
+			args:    []string{"-template", "home", "-data", "-"},
+			stdin:   `{`,
+			wantErr: "data file -",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			args := append([]string{"render", "-templates", templates, "-partials", "components/*"}, tt.args...)
+			err := run(args, strings.NewReader(tt.stdin), &out)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+
+	t.Run("output file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "out.html")
+		var out bytes.Buffer
+		require.NoError(t, run([]string{
+			"render", "-templates", templates, "-partials", "components/*",
+			"-template", "home", "-data", filepath.Join(dir, "data/home.json"), "-out", path,
+		}, nil, &out))
+		assert.Empty(t, out.String())
+
+		got, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Home</h1><nav>a b </nav>", string(got))
+	})
+
+	t.Run("failed render writes nothing", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "out.html")
+		err := run([]string{
+			"render", "-templates", templates, "-template", "broken",
+			"-data", filepath.Join(dir, "data/home.json"), "-out", path,
+		}, nil, &bytes.Buffer{})
+		require.Error(t, err)
+		assert.NoFileExists(t, path)
+	})
+}
@@ -0,0 +1,84 @@
+package templator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ModelBreak describes a template that renders with the registry data type
+// but references fields a new model no longer provides.
+type ModelBreak struct {
+	TemplateName string
+	FieldPath    string
+	Err          error
+}
+
+func (b ModelBreak) String() string {
+	return fmt.Sprintf("template '%s' breaks at '%s': %s", b.TemplateName, b.FieldPath, b.Err)
+}
+
+// CompareModel reports the templates that would break if the registry data type T were replaced
+// by the type of next, e.g. after removing or renaming view model fields. Only templates that
+// validate against T are checked, so templates already invalid, such as partials rendered with
+// other data, are not reported. It is meant to run in tests or CI before merging a refactor:
+//
+//	breaks, err := reg.CompareModel(HomeDataV2{})
+func (r *Registry[T]) CompareModel(next any) ([]ModelBreak, error) {
+	nextType := reflect.TypeOf(next)
+	if nextType == nil {
+		return nil, errors.New("next model must not be nil")
+	}
+	current := reflect.TypeFor[T]()
+
+	names, err := r.ownTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	var breaks []ModelBreak
+	for _, name := range names {
+		content, err := r.readTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := validateFieldsOfType(name, string(content), r.config.leftDelim, r.config.rightDelim, current); err != nil {
+			continue
+		}
+
+		err = validateFieldsOfType(name, string(content), r.config.leftDelim, r.config.rightDelim, nextType)
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			breaks = append(breaks, ModelBreak{
+				TemplateName: name,
+				FieldPath:    validationErr.FieldPath,
+				Err:          validationErr.Err,
+			})
+		}
+	}
+	return breaks, nil
+}
+
+// ValidateModel validates the fields the named template references against the type of
+// model, as WithFieldValidation does against T, returning a *ValidationError for the first
+// missing field. It checks templates rendered with other data than T, such as partials, or
+// types known only at run time, e.g. by tools:
+//
+//	err := reg.ValidateModel("components/card", CardData{})
+func (r *Registry[T]) ValidateModel(name string, model any) error {
+	if sub, rest, ok := r.mounted(name); ok {
+		return sub.ValidateModel(rest, model)
+	}
+
+	typ := reflect.TypeOf(model)
+	if typ == nil {
+		return errors.New("model must not be nil")
+	}
+
+	content, err := r.readTemplate(name)
+	if err != nil {
+		return err
+	}
+	return validateFieldsOfType(name, string(content), r.config.leftDelim, r.config.rightDelim, typ)
+}
@@ -0,0 +1,86 @@
+package templator
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	profileV1 struct {
+		Name  string
+		Email string
+		Posts []struct{ Title string }
+	}
+	profileV2 struct {
+		FullName string
+		Email    string
+		Posts    []struct{ Headline string }
+	}
+)
+
+func TestRegistry_CompareModel(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/header.html":  &fstest.MapFile{Data: []byte("<h1>{{.Name}}</h1>")},
+		"templates/contact.html": &fstest.MapFile{Data: []byte("<a>{{.Email}}</a>")},
+		"templates/posts.html":   &fstest.MapFile{Data: []byte("{{range .Posts}}<li>{{.Title}}</li>{{end}}")},
+		"templates/partial.html": &fstest.MapFile{Data: []byte("{{.Unrelated}}")},
+	}
+
+	reg, err := NewRegistry[profileV1](fs)
+	require.NoError(t, err)
+
+	breaks, err := reg.CompareModel(profileV2{})
+	require.NoError(t, err)
+
+	require.Len(t, breaks, 2)
+	assert.Equal(t, "header", breaks[0].TemplateName)
+	assert.Equal(t, "Name", breaks[0].FieldPath)
+	assert.Equal(t, "posts", breaks[1].TemplateName)
+	assert.Equal(t, "Title", breaks[1].FieldPath)
+	assert.Contains(t, breaks[0].String(), "template 'header' breaks at 'Name'")
+
+	t.Run("compatible model", func(t *testing.T) {
+		t.Parallel()
+
+		breaks, err := reg.CompareModel(profileV1{})
+		require.NoError(t, err)
+		assert.Empty(t, breaks)
+	})
+
+	t.Run("nil model", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.CompareModel(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestRegistry_ValidateModel(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"templates/header.html": &fstest.MapFile{Data: []byte("<h1>{{.Name}}</h1>")},
+		"templates/posts.html":  &fstest.MapFile{Data: []byte("{{range .Posts}}<li>{{.Title}}</li>{{end}}")},
+	}
+
+	reg, err := NewRegistry[TestData](fsys)
+	require.NoError(t, err)
+
+	require.NoError(t, reg.ValidateModel("header", profileV1{}))
+	require.NoError(t, reg.ValidateModel("posts", profileV1{}))
+
+	err = reg.ValidateModel("posts", profileV2{})
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "posts", validationErr.TemplateName)
+	assert.Equal(t, "Title", validationErr.FieldPath)
+
+	require.ErrorIs(t, reg.ValidateModel("missing", profileV1{}), fs.ErrNotExist)
+	require.Error(t, reg.ValidateModel("header", nil))
+}
@@ -0,0 +1,66 @@
+package templator
+
+import (
+	"errors"
+	"strings"
+)
+
+// Compose parses the named templates into a single handler that renders the first one, with
+// every template the others define, including their own root under their file name, available
+// to it. It enables programmatic page assembly when partials and layouts are too rigid:
+//
+//	page, err := reg.Compose("pages/product", "widgets/reviews", "widgets/related")
+//
+// Each template is validated as by Get. Templates defined by several sources, other than
+// partials they share, are reported as ErrComposeConflict. Composed handlers are parsed on
+// every call and neither cached nor reloaded.
+func (r *Registry[T]) Compose(names ...string) (*Handler[T], error) {
+	if r.closed.Load() {
+		return nil, ErrRegistryClosed
+	}
+	if len(names) == 0 {
+		return nil, errors.New("compose requires at least one template")
+	}
+
+	h, err := r.parse(names[0], getConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := h.src.template()
+	files := make(map[string]string)
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			files[t.Name()] = t.Tree.ParseName
+		}
+	}
+
+	for _, name := range names[1:] {
+		other, err := r.parse(name, getConfig{})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, t := range other.src.template().Templates() {
+			if t.Tree == nil {
+				continue
+			}
+
+			if file, ok := files[t.Name()]; ok {
+				if file != t.Tree.ParseName {
+					return nil, ErrComposeConflict{
+						Name:  t.Name(),
+						Files: []string{strings.TrimSuffix(file, string(r.config.ext)), strings.TrimSuffix(t.Tree.ParseName, string(r.config.ext))},
+					}
+				}
+				continue
+			}
+
+			if _, err := tmpl.AddParseTree(t.Name(), t.Tree); err != nil {
+				return nil, err
+			}
+			files[t.Name()] = t.Tree.ParseName
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,65 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Compose(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/page.html":            &fstest.MapFile{Data: []byte(`{{template "nav.html"}}<h1>{{.Title}}</h1>{{template "reviews" .}}{{template "widgets/related.html" .}}`)},
+		"templates/widgets/reviews.html": &fstest.MapFile{Data: []byte(`{{define "reviews"}}<ul>{{.Content}}</ul>{{end}}`)},
+		"templates/widgets/related.html": &fstest.MapFile{Data: []byte(`<aside>{{template "nav.html"}}</aside>`)},
+		"templates/widgets/clash.html":   &fstest.MapFile{Data: []byte(`{{define "reviews"}}other{{end}}`)},
+		"templates/nav.html":             &fstest.MapFile{Data: []byte(`<nav></nav>`)},
+	}
+	reg := MustNewRegistry(fs, WithPartials[TestData]("nav"))
+
+	t.Run("renders composed templates", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := reg.Compose("page", "widgets/reviews", "widgets/related")
+		require.NoError(t, err)
+
+		got, err := page.ExecuteToString(context.Background(), TestData{Title: "Hi", Content: "great"})
+		require.NoError(t, err)
+		assert.Equal(t, "<nav></nav><h1>Hi</h1><ul>great</ul><aside><nav></nav></aside>", got)
+	})
+
+	t.Run("leaves registry handlers untouched", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Compose("widgets/related", "widgets/reviews")
+		require.NoError(t, err)
+
+		related := reg.MustGet("widgets/related")
+		assert.Nil(t, related.src.template().Lookup("reviews"))
+	})
+
+	t.Run("rejects conflicting defines", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Compose("page", "widgets/reviews", "widgets/clash")
+
+		var conflictErr ErrComposeConflict
+		require.ErrorAs(t, err, &conflictErr)
+		assert.Equal(t, ErrComposeConflict{Name: "reviews", Files: []string{"widgets/reviews", "widgets/clash"}}, conflictErr)
+		assert.EqualError(t, err, "template 'reviews' defined by both 'widgets/reviews' and 'widgets/clash'")
+	})
+
+	t.Run("returns errors of composed templates", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Compose("page", "widgets/missing")
+		require.ErrorIs(t, err, ErrTemplateNotFound{Name: "widgets/missing"})
+
+		_, err = reg.Compose()
+		require.Error(t, err)
+	})
+}
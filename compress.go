@@ -0,0 +1,134 @@
+package templator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var gzipPool = sync.Pool{
+	New: func() any { return gzip.NewWriter(nil) },
+}
+
+// ExecuteCompressed renders the template to w, gzip-compressed through a pooled encoder when
+// the request's Accept-Encoding allows it, and Content-Type defaults to HTML. Output is
+// buffered like Execute's, so headers are only set once the render succeeded, with the
+// template's cache policy, if any. Render errors are returned before anything is written, so
+// callers can still respond with an error page. When the error template configured with
+// WithErrorTemplate replaced the page, it responds with it and status 500, without the cache
+// policy, and returns ErrFallbackRendered.
+//
+// With WithUnbufferedWrites, output streams through the encoder and the headers go out before
+// the render's outcome is known, so the cache policy is not applied, and a render error leaves
+// a partial response.
+//
+// Only gzip is negotiated: the standard library has no Brotli encoder, and the module avoids
+// third-party dependencies.
+func (h *Handler[T]) ExecuteCompressed(ctx context.Context, w http.ResponseWriter, r *http.Request, data T) error {
+	compress := acceptsEncoding(r.Header.Get("Accept-Encoding"), "gzip")
+
+	if h.reg.config.unbufferedWrites {
+		setCompressedHeaders(w.Header(), compress)
+		if !compress {
+			return h.Execute(ctx, w, data)
+		}
+		return h.writeCompressed(w, func(gz io.Writer) error {
+			return h.Execute(ctx, gz, data)
+		})
+	}
+
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+
+	status := http.StatusOK
+	err := h.Execute(ctx, buf, data)
+	switch {
+	case errors.As(err, new(ErrFallbackRendered)):
+		// The cache policy is meant for the page, not for the error page.
+		status = http.StatusInternalServerError
+	case err != nil:
+		return err
+	default:
+		if policy, ok := h.CachePolicy(); ok {
+			policy.Apply(w.Header())
+		}
+	}
+
+	setCompressedHeaders(w.Header(), compress)
+	w.WriteHeader(status)
+
+	writeBody := func(w io.Writer) error {
+		if _, err := buf.WriteTo(w); err != nil {
+			return ErrTemplateExecution{Name: h.file, Err: err}
+		}
+		return nil
+	}
+	if compress {
+		if writeErr := h.writeCompressed(w, writeBody); writeErr != nil {
+			return writeErr
+		}
+	} else if writeErr := writeBody(w); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+// setCompressedHeaders sets the headers of a response of ExecuteCompressed.
+func setCompressedHeaders(header http.Header, compress bool) {
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "text/html; charset=utf-8")
+	}
+	header.Add("Vary", "Accept-Encoding")
+	if compress {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+	}
+}
+
+// writeCompressed runs write through a pooled gzip encoder writing to w, and closes the
+// encoder even when write fails, so the client receives a complete gzip stream.
+func (h *Handler[T]) writeCompressed(w io.Writer, write func(gz io.Writer) error) error {
+	gz := gzipPool.Get().(*gzip.Writer)
+	gz.Reset(w)
+	defer func() {
+		// Drop the response writer so the pool doesn't keep it alive.
+		gz.Reset(io.Discard)
+		gzipPool.Put(gz)
+	}()
+
+	err := write(gz)
+	if closeErr := gz.Close(); closeErr != nil && err == nil {
+		return ErrTemplateExecution{Name: h.file, Err: closeErr}
+	}
+	return err
+}
+
+// acceptsEncoding reports whether an Accept-Encoding header accepts the coding, explicitly
+// or through the * wildcard, with a non-zero quality.
+func acceptsEncoding(acceptEncoding, coding string) bool {
+	accepted := false
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			q, _ = strconv.ParseFloat(value, 64)
+		}
+
+		switch {
+		case strings.EqualFold(name, coding):
+			// An explicit entry overrides the wildcard.
+			return q > 0
+		case name == "*":
+			accepted = q > 0
+		}
+	}
+	return accepted
+}
@@ -0,0 +1,124 @@
+package templator
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ExecuteCompressed(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+	}
+	home := MustNewRegistry[TestData](fs).MustGet("home")
+
+	serve := func(t *testing.T, acceptEncoding string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		require.NoError(t, home.ExecuteCompressed(context.Background(), rec, req, TestData{Title: strings.Repeat("hi", 100)}))
+		return rec
+	}
+
+	t.Run("compresses when gzip is accepted", func(t *testing.T) {
+		t.Parallel()
+
+		for _, acceptEncoding := range []string{"gzip", "br, gzip;q=0.5", "*"} {
+			rec := serve(t, acceptEncoding)
+			assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"), acceptEncoding)
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+			assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+
+			gz, err := gzip.NewReader(rec.Body)
+			require.NoError(t, err)
+			body, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			assert.Equal(t, "<p>"+strings.Repeat("hi", 100)+"</p>", string(body))
+		}
+	})
+
+	t.Run("renders uncompressed otherwise", func(t *testing.T) {
+		t.Parallel()
+
+		for _, acceptEncoding := range []string{"", "br", "gzip;q=0", "*, gzip;q=0", "*;q=0"} {
+			rec := serve(t, acceptEncoding)
+			assert.Empty(t, rec.Header().Get("Content-Encoding"), acceptEncoding)
+			assert.Equal(t, "<p>"+strings.Repeat("hi", 100)+"</p>", rec.Body.String())
+		}
+	})
+}
+
+func TestHandler_ExecuteCompressed_Errors(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":       &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>{{.Missing}}")},
+		"templates/errors/500.html": &fstest.MapFile{Data: []byte("<p>Something went wrong</p>")},
+	}
+	policy := WithCachePolicy[TestData]("home", CachePolicy{Public: true, MaxAge: time.Hour})
+
+	serve := func(t *testing.T, reg *Registry[TestData]) (*httptest.ResponseRecorder, error) {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		rec := httptest.NewRecorder()
+		return rec, reg.MustGet("home").ExecuteCompressed(context.Background(), rec, req, TestData{Title: "Hi"})
+	}
+
+	t.Run("error template", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, MustNewRegistry(fs, policy, WithErrorTemplate[TestData]("errors/500")))
+		require.ErrorAs(t, err, new(ErrFallbackRendered))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+
+		gz, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Something went wrong</p>", string(body))
+	})
+
+	t.Run("leaves failed renders to the caller", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, MustNewRegistry(fs, policy))
+		require.Error(t, err)
+
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Zero(t, rec.Body.Len())
+		assert.False(t, rec.Flushed)
+	})
+
+	t.Run("unbuffered streams end the gzip stream", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, MustNewRegistry(fs, policy, WithUnbufferedWrites[TestData]()))
+		require.Error(t, err)
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+
+		gz, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Hi</p>", string(body))
+	})
+}
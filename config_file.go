@@ -0,0 +1,273 @@
+package templator
+
+import (
+	"fmt"
+	"html/template"
+	"maps"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvProfile is the environment variable selecting the active config profile.
+const EnvProfile = "TEMPLATOR_PROFILE"
+
+// Config holds settings loaded from a templator configuration file, shared by the
+// library and the command line tools so code options and CLI flags stay in sync.
+type Config struct {
+	// Path is the template directory.
+	Path string `yaml:"path"`
+	// Paths lists template directories in priority order, as with WithTemplatePaths.
+	// It takes precedence over Path for registries; cmd/generate reads a single
+	// directory, Generate.Templates or Path.
+	Paths []string `yaml:"paths"`
+	// Extension is the template file extension, e.g. ".html".
+	Extension Extension `yaml:"extension"`
+	// Partials lists partial template names or path.Match patterns.
+	Partials []string `yaml:"partials"`
+	// Funcs is an allowlist of function names templates may use.
+	// When empty, every function passed to Options is allowed.
+	Funcs []string `yaml:"funcs"`
+	// Cache maps template names to their cache policy.
+	Cache map[string]CachePolicy `yaml:"cache"`
+	// SLO maps template names to their expected p95 render time, e.g. "50ms".
+	SLO map[string]time.Duration `yaml:"slo"`
+	// HotReload re-parses templates on every Get.
+	HotReload bool `yaml:"hot_reload"`
+	// Minify minifies rendered HTML, as with WithMinification.
+	Minify bool `yaml:"minify"`
+	// Trace appends per-template render timings to pages, as with WithRenderTracing.
+	Trace bool `yaml:"trace"`
+	// Strict fails renders that print missing values, as with WithStrictMode.
+	Strict bool `yaml:"strict"`
+	// ErrorTemplate names the template rendered when a render fails, as with WithErrorTemplate.
+	ErrorTemplate string `yaml:"error_template"`
+	// Frozen checks the template directory at startup, as with WithStrictInit, and turns
+	// hot reload off even when a profile enables it.
+	Frozen bool `yaml:"frozen"`
+	// Profiles holds named overrides, e.g. "dev" and "prod", applied by Config.WithProfile
+	// or the WithProfile option.
+	Profiles map[string]Profile `yaml:"profiles"`
+	// Generate holds settings for cmd/generate.
+	Generate GenerateConfig `yaml:"generate"`
+}
+
+// Profile holds the settings a named profile overrides. Switches are pointers, so a profile
+// can turn off a setting the base config turns on; unset fields keep the base value.
+type Profile struct {
+	Path          string                   `yaml:"path"`
+	Paths         []string                 `yaml:"paths"`
+	Extension     Extension                `yaml:"extension"`
+	Partials      []string                 `yaml:"partials"`
+	Funcs         []string                 `yaml:"funcs"`
+	Cache         map[string]CachePolicy   `yaml:"cache"`
+	SLO           map[string]time.Duration `yaml:"slo"`
+	HotReload     *bool                    `yaml:"hot_reload"`
+	Minify        *bool                    `yaml:"minify"`
+	Trace         *bool                    `yaml:"trace"`
+	Strict        *bool                    `yaml:"strict"`
+	ErrorTemplate string                   `yaml:"error_template"`
+	Frozen        *bool                    `yaml:"frozen"`
+}
+
+// GenerateConfig holds settings for the accessor code generator.
+type GenerateConfig struct {
+	Templates       string   `yaml:"templates"`
+	Out             string   `yaml:"out"`
+	Package         string   `yaml:"package"`
+	TemplatorImport string   `yaml:"templator_import"`
+	RegistryType    string   `yaml:"registry_type"`
+	DataType        string   `yaml:"data_type"`
+	Types           string   `yaml:"types"`
+	Routes          string   `yaml:"routes"`
+	Render          bool     `yaml:"render"`
+	Include         []string `yaml:"include"`
+	Exclude         []string `yaml:"exclude"`
+	Schema          bool     `yaml:"schema"`
+}
+
+// LoadConfig reads and decodes the YAML configuration file at the given path.
+func LoadConfig(path string) (*Config, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(content, &cfg); err != nil {
+		return nil, fmt.Errorf("could not decode config file: %w", err)
+	}
+	return &cfg, nil
+}
+
+// WithProfile returns a copy of the config with the named profile applied on top.
+// Settings set in the profile replace the base ones, while cache policies and SLOs are merged.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' not found", name)
+	}
+
+	merged := *c
+	merged.Profiles = nil
+	if profile.Path != "" {
+		merged.Path = profile.Path
+		merged.Paths = nil
+	}
+	if profile.Paths != nil {
+		merged.Paths = profile.Paths
+	}
+	if profile.Extension != "" {
+		merged.Extension = profile.Extension
+	}
+	if profile.Partials != nil {
+		merged.Partials = profile.Partials
+	}
+	if profile.Funcs != nil {
+		merged.Funcs = profile.Funcs
+	}
+	if profile.Cache != nil {
+		merged.Cache = maps.Clone(c.Cache)
+		if merged.Cache == nil {
+			merged.Cache = make(map[string]CachePolicy, len(profile.Cache))
+		}
+		maps.Copy(merged.Cache, profile.Cache)
+	}
+	if profile.SLO != nil {
+		merged.SLO = maps.Clone(c.SLO)
+		if merged.SLO == nil {
+			merged.SLO = make(map[string]time.Duration, len(profile.SLO))
+		}
+		maps.Copy(merged.SLO, profile.SLO)
+	}
+	if profile.ErrorTemplate != "" {
+		merged.ErrorTemplate = profile.ErrorTemplate
+	}
+	if profile.HotReload != nil {
+		merged.HotReload = *profile.HotReload
+	}
+	if profile.Minify != nil {
+		merged.Minify = *profile.Minify
+	}
+	if profile.Trace != nil {
+		merged.Trace = *profile.Trace
+	}
+	if profile.Strict != nil {
+		merged.Strict = *profile.Strict
+	}
+	if profile.Frozen != nil {
+		merged.Frozen = *profile.Frozen
+	}
+	return &merged, nil
+}
+
+// ActiveProfile applies the profile named by the EnvProfile environment variable.
+// The config is returned unchanged when the variable is not set.
+func (c *Config) ActiveProfile() (*Config, error) {
+	name := os.Getenv(EnvProfile)
+	if name == "" {
+		return c, nil
+	}
+	return c.WithProfile(name)
+}
+
+// ConfigOptions translates a Config into registry options. Functions are taken from
+// funcMap and filtered through the config's allowlist; allowlisted names missing
+// from funcMap are reported as an error.
+func ConfigOptions[T any](cfg *Config, funcMap template.FuncMap) ([]Option[T], error) {
+	opts := []Option[T]{
+		WithTemplatesPath[T](cfg.Path),
+		WithExtension[T](cfg.Extension),
+	}
+
+	if len(cfg.Paths) > 0 {
+		opts = append(opts, WithTemplatePaths[T](cfg.Paths...))
+	}
+
+	if len(cfg.Partials) > 0 {
+		opts = append(opts, WithPartials[T](cfg.Partials...))
+	}
+
+	for name, policy := range cfg.Cache {
+		opts = append(opts, WithCachePolicy[T](name, policy))
+	}
+
+	for name, budget := range cfg.SLO {
+		opts = append(opts, WithRenderSLO[T](name, budget))
+	}
+
+	if cfg.HotReload && !cfg.Frozen {
+		opts = append(opts, WithHotReload[T]())
+	}
+
+	if cfg.Frozen {
+		opts = append(opts, WithStrictInit[T]())
+	}
+
+	if cfg.Minify {
+		opts = append(opts, WithMinification[T]())
+	}
+
+	if cfg.Trace {
+		opts = append(opts, WithRenderTracing[T]())
+	}
+
+	if cfg.Strict {
+		opts = append(opts, WithStrictMode[T]())
+	}
+
+	if cfg.ErrorTemplate != "" {
+		opts = append(opts, WithErrorTemplate[T](cfg.ErrorTemplate))
+	}
+
+	if len(cfg.Funcs) == 0 {
+		if funcMap != nil {
+			opts = append(opts, WithTemplateFuncs[T](funcMap))
+		}
+		return opts, nil
+	}
+
+	allowed := make(template.FuncMap, len(cfg.Funcs))
+	for _, name := range cfg.Funcs {
+		fn, ok := funcMap[name]
+		if !ok {
+			return nil, fmt.Errorf("allowlisted function '%s' is not provided", name)
+		}
+		allowed[name] = fn
+	}
+	return append(opts, WithTemplateFuncs[T](allowed)), nil
+}
+
+// WithProfile returns an Option configuring the registry from cfg with the named profile
+// applied, as ConfigOptions does. An empty name selects the profile named by EnvProfile,
+// or the base config when the variable is not set. An unknown profile or an allowlisted
+// function missing from funcMap is reported by NewRegistry.
+func WithProfile[T any](cfg *Config, name string, funcMap template.FuncMap) Option[T] {
+	return func(r *Registry[T]) {
+		opts, err := profileOptions[T](cfg, name, funcMap)
+		if err != nil {
+			r.config.optionErrs = append(r.config.optionErrs, ErrInvalidOption{
+				Options: []string{"WithProfile"},
+				Reason:  err.Error(),
+			})
+			return
+		}
+		for _, opt := range opts {
+			opt(r)
+		}
+	}
+}
+
+func profileOptions[T any](cfg *Config, name string, funcMap template.FuncMap) ([]Option[T], error) {
+	var err error
+	if name == "" {
+		cfg, err = cfg.ActiveProfile()
+	} else {
+		cfg, err = cfg.WithProfile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ConfigOptions[T](cfg, funcMap)
+}
@@ -0,0 +1,301 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigFile = `
+path: views
+extension: .tmpl
+partials:
+  - "components/*"
+funcs:
+  - upper
+cache:
+  home:
+    public: true
+    max_age: 1m
+slo:
+  home: 50ms
+generate:
+  package: views
+  out: ./views_gen.go
+`
+
+func writeConfigFixture(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "templator.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes config file", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := LoadConfig(writeConfigFixture(t, testConfigFile))
+		require.NoError(t, err)
+
+		assert.Equal(t, "views", cfg.Path)
+		assert.Equal(t, Extension(".tmpl"), cfg.Extension)
+		assert.Equal(t, []string{"components/*"}, cfg.Partials)
+		assert.Equal(t, []string{"upper"}, cfg.Funcs)
+		assert.Equal(t, CachePolicy{Public: true, MaxAge: time.Minute}, cfg.Cache["home"])
+		assert.Equal(t, map[string]time.Duration{"home": 50 * time.Millisecond}, cfg.SLO)
+		assert.Equal(t, GenerateConfig{Package: "views", Out: "./views_gen.go"}, cfg.Generate)
+	})
+
+	t.Run("returns error for missing file", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := LoadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+		assert.Nil(t, cfg)
+	})
+
+	t.Run("returns error for invalid yaml", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := LoadConfig(writeConfigFixture(t, "path: [unterminated"))
+		require.Error(t, err)
+		assert.Nil(t, cfg)
+	})
+}
+
+func TestConfigOptions(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfig(writeConfigFixture(t, testConfigFile))
+	require.NoError(t, err)
+
+	fs := fstest.MapFS{
+		"views/home.tmpl":            &fstest.MapFile{Data: []byte(`{{template "components/nav.tmpl"}}{{.Title | upper}}`)},
+		"views/components/nav.tmpl":  &fstest.MapFile{Data: []byte(`<nav></nav>`)},
+		"views/uses_lower.tmpl":      &fstest.MapFile{Data: []byte(`{{.Title | lower}}`)},
+		"views/components/skip.html": &fstest.MapFile{Data: []byte(`{{template "missing"}}`)},
+	}
+
+	t.Run("configures registry from file", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ConfigOptions[TestData](cfg, template.FuncMap{
+			"upper": strings.ToUpper,
+			"lower": strings.ToLower,
+		})
+		require.NoError(t, err)
+
+		reg, err := NewRegistry(fs, opts...)
+		require.NoError(t, err)
+
+		home, err := reg.Get("home")
+		require.NoError(t, err)
+
+		got, err := home.ExecuteToString(context.Background(), TestData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<nav></nav>HI", got)
+
+		policy, ok := home.CachePolicy()
+		require.True(t, ok)
+		assert.Equal(t, time.Minute, policy.MaxAge)
+
+		stats, ok := reg.RenderStats("home")
+		require.True(t, ok)
+		assert.Equal(t, 50*time.Millisecond, stats.Budget)
+
+		_, err = reg.Get("uses_lower")
+		require.Error(t, err, "lower is not in the allowlist")
+	})
+
+	t.Run("returns error for allowlisted func not provided", func(t *testing.T) {
+		t.Parallel()
+
+		opts, err := ConfigOptions[TestData](cfg, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "allowlisted function 'upper' is not provided")
+		assert.Nil(t, opts)
+	})
+}
+
+const testProfilesConfigFile = `
+path: views
+cache:
+  home:
+    public: true
+    max_age: 1m
+profiles:
+  dev:
+    hot_reload: true
+    trace: true
+    strict: true
+    cache:
+      home:
+        no_store: true
+  prod:
+    minify: true
+    frozen: true
+    error_template: error
+    cache:
+      about:
+        public: true
+        max_age: 1h
+`
+
+func TestConfig_WithProfile(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfig(writeConfigFixture(t, testProfilesConfigFile))
+	require.NoError(t, err)
+
+	t.Run("applies dev profile", func(t *testing.T) {
+		t.Parallel()
+
+		dev, err := cfg.WithProfile("dev")
+		require.NoError(t, err)
+
+		assert.Equal(t, "views", dev.Path)
+		assert.True(t, dev.HotReload)
+		assert.True(t, dev.Trace)
+		assert.True(t, dev.Strict)
+		assert.False(t, dev.Minify)
+		assert.Equal(t, CachePolicy{NoStore: true}, dev.Cache["home"])
+		assert.Nil(t, dev.Profiles)
+
+		assert.False(t, cfg.HotReload, "base config is not mutated")
+		assert.Equal(t, time.Minute, cfg.Cache["home"].MaxAge)
+	})
+
+	t.Run("merges prod cache policies", func(t *testing.T) {
+		t.Parallel()
+
+		prod, err := cfg.WithProfile("prod")
+		require.NoError(t, err)
+
+		assert.False(t, prod.HotReload)
+		assert.True(t, prod.Minify)
+		assert.True(t, prod.Frozen)
+		assert.Equal(t, "error", prod.ErrorTemplate)
+		assert.Equal(t, time.Minute, prod.Cache["home"].MaxAge)
+		assert.Equal(t, time.Hour, prod.Cache["about"].MaxAge)
+		assert.Len(t, cfg.Cache, 1, "base cache map is not mutated")
+	})
+
+	t.Run("returns error for unknown profile", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := cfg.WithProfile("staging")
+		require.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestConfig_ActiveProfile(t *testing.T) {
+	cfg, err := LoadConfig(writeConfigFixture(t, testProfilesConfigFile))
+	require.NoError(t, err)
+
+	t.Setenv(EnvProfile, "")
+	got, err := cfg.ActiveProfile()
+	require.NoError(t, err)
+	assert.Same(t, cfg, got)
+
+	t.Setenv(EnvProfile, "dev")
+	got, err = cfg.ActiveProfile()
+	require.NoError(t, err)
+	assert.True(t, got.HotReload)
+}
+
+func TestWithProfile(t *testing.T) {
+	cfg, err := LoadConfig(writeConfigFixture(t, testProfilesConfigFile))
+	require.NoError(t, err)
+
+	fs := fstest.MapFS{
+		"views/home.html":  &fstest.MapFile{Data: []byte(`<p>{{.Title}}</p>`)},
+		"views/error.html": &fstest.MapFile{Data: []byte(`<p>oops</p>`)},
+	}
+
+	t.Run("applies dev profile", func(t *testing.T) {
+		reg, err := NewRegistry(fs, WithProfile[TestData](cfg, "dev", nil))
+		require.NoError(t, err)
+
+		assert.True(t, reg.config.hotReload)
+		assert.True(t, reg.config.trace)
+		assert.True(t, reg.config.strict)
+		assert.False(t, reg.config.minify)
+	})
+
+	t.Run("applies prod profile", func(t *testing.T) {
+		reg, err := NewRegistry(fs, WithProfile[TestData](cfg, "prod", nil))
+		require.NoError(t, err)
+
+		assert.False(t, reg.config.hotReload)
+		assert.True(t, reg.config.minify)
+		assert.True(t, reg.config.strictInit)
+		assert.Equal(t, "error", reg.config.errorTemplate)
+	})
+
+	t.Run("selects profile from environment", func(t *testing.T) {
+		t.Setenv(EnvProfile, "dev")
+
+		reg, err := NewRegistry(fs, WithProfile[TestData](cfg, "", nil))
+		require.NoError(t, err)
+		assert.True(t, reg.config.hotReload)
+	})
+
+	t.Run("frozen disables hot reload", func(t *testing.T) {
+		frozen := &Config{Path: "views", HotReload: true, Frozen: true}
+
+		reg, err := NewRegistry(fs, WithProfile[TestData](frozen, "", nil))
+		require.NoError(t, err)
+		assert.False(t, reg.config.hotReload)
+		assert.True(t, reg.config.strictInit)
+	})
+
+	t.Run("returns error for unknown profile", func(t *testing.T) {
+		_, err := NewRegistry(fs, WithProfile[TestData](cfg, "staging", nil))
+
+		var invalid ErrInvalidOption
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, []string{"WithProfile"}, invalid.Options)
+		assert.Contains(t, invalid.Reason, "profile 'staging' not found")
+	})
+}
+
+func TestConfig_WithProfile_DisablesBaseSettings(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadConfig(writeConfigFixture(t, `
+path: views
+hot_reload: true
+minify: true
+trace: true
+profiles:
+  prod:
+    hot_reload: false
+    trace: false
+  staging: {}
+`))
+	require.NoError(t, err)
+
+	prod, err := cfg.WithProfile("prod")
+	require.NoError(t, err)
+	assert.False(t, prod.HotReload)
+	assert.False(t, prod.Trace)
+	assert.True(t, prod.Minify, "unset settings keep the base value")
+
+	staging, err := cfg.WithProfile("staging")
+	require.NoError(t, err)
+	assert.True(t, staging.HotReload)
+	assert.True(t, staging.Trace)
+}
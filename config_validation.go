@@ -0,0 +1,286 @@
+package templator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// htmlExtensions are the template extensions WithMinification accepts.
+var htmlExtensions = []Extension{ExtensionHTML, ".htm", ".gohtml", ".tmpl"}
+
+// validate checks the final configuration after all options have been applied
+// and returns every problem found, joined into a single error.
+func (c config[T]) validate() error {
+	errs := slices.Clone(c.optionErrs)
+
+	if len(c.paths) == 0 && !fs.ValidPath(c.path) {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithTemplatesPath"},
+			Reason:  fmt.Sprintf("'%s' is not a valid fs.FS path", c.path),
+		})
+	}
+
+	for _, dir := range c.paths {
+		if !fs.ValidPath(dir) {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithTemplatePaths"},
+				Reason:  fmt.Sprintf("'%s' is not a valid fs.FS path", dir),
+			})
+		}
+	}
+
+	if !strings.HasPrefix(string(c.ext), ".") || strings.Contains(string(c.ext), "/") {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithExtension"},
+			Reason:  fmt.Sprintf("'%s' must start with a dot and not contain a slash", c.ext),
+		})
+	}
+
+	if c.validateFields && reflect.TypeOf(c.validationModel) == nil {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithFieldValidation"},
+			Reason:  "validation model must not be nil",
+		})
+	}
+
+	packOf, packErrs := c.validateFuncPacks()
+	errs = append(errs, packErrs...)
+
+	for _, name := range slices.Sorted(maps.Keys(c.funcMap)) {
+		if err := validateFunc(c.funcMap[name]); err != nil {
+			if pack, ok := packOf[name]; ok {
+				errs = append(errs, ErrInvalidOption{
+					Options: []string{"WithFuncPacks"},
+					Reason:  fmt.Sprintf("function '%s' of func pack '%s' %s", name, pack, err),
+				})
+				continue
+			}
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithTemplateFuncs"},
+				Reason:  fmt.Sprintf("function '%s' %s", name, err),
+			})
+		}
+	}
+
+	for _, pattern := range c.partials {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithPartials"},
+				Reason:  fmt.Sprintf("malformed pattern '%s'", pattern),
+			})
+		}
+	}
+
+	if slices.Contains(c.overlays, nil) {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithOverlayFS"},
+			Reason:  "overlay filesystem must not be nil",
+		})
+	}
+
+	if slices.ContainsFunc(c.draftValidators, func(fn func(string, string) error) bool { return fn == nil }) {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithDraftValidator"},
+			Reason:  "draft validator must not be nil",
+		})
+	}
+
+	if slices.ContainsFunc(c.preprocessors, func(fn Preprocessor) bool { return fn == nil }) {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithPreprocessors"},
+			Reason:  "preprocessor must not be nil",
+		})
+	}
+
+	if c.archive != nil {
+		if c.archive.store == nil {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithArchive"},
+				Reason:  "archive store must not be nil",
+			})
+		}
+		if rate := c.archive.policy.SampleRate; rate < 0 || rate > 1 {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithArchive"},
+				Reason:  "sample rate must be between 0 and 1",
+			})
+		}
+		if c.archive.policy.QueueSize < 0 {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithArchive"},
+				Reason:  "queue size must not be negative",
+			})
+		}
+	}
+
+	if c.quota != nil {
+		p := c.quota.policy
+		if p.Rate < 0 || p.Burst < 0 || p.RenderTimeBudget < 0 || p.Window < 0 {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithQuota"},
+				Reason:  "limits must not be negative",
+			})
+		}
+	}
+
+	for _, rate := range []float64{c.chaos.ParseDelayRate, c.chaos.ExecutionErrorRate, c.chaos.CacheMissRate} {
+		if rate < 0 || rate > 1 {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithChaos"},
+				Reason:  "rates must be between 0 and 1",
+			})
+			break
+		}
+	}
+	if c.chaos.ParseDelay < 0 {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithChaos"},
+			Reason:  "parse delay must not be negative",
+		})
+	}
+
+	for _, locale := range c.locales {
+		if locale == "" || strings.ContainsAny(locale, "./\\") {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithLocales"},
+				Reason:  fmt.Sprintf("'%s' is not a valid locale", locale),
+			})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.slos)) {
+		if c.slos[name] <= 0 {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithRenderSLO"},
+				Reason:  fmt.Sprintf("budget of '%s' must be positive", name),
+			})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.cachePolicies)) {
+		policy := c.cachePolicies[name]
+		if policy.Public && policy.Private {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithCachePolicy"},
+				Reason:  fmt.Sprintf("template '%s' cache policy is both public and private", name),
+			})
+		}
+		if policy.NoStore && (policy.MaxAge > 0 || policy.SharedMaxAge > 0 || policy.SurrogateMaxAge > 0) {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithCachePolicy"},
+				Reason:  fmt.Sprintf("template '%s' cache policy sets no-store together with a max age", name),
+			})
+		}
+	}
+
+	if c.unbufferedWrites && c.errorTemplate != "" {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithUnbufferedWrites", "WithErrorTemplate"},
+			Reason:  "the error page would follow the partial output of failed renders",
+		})
+	}
+
+	if c.minify && !slices.Contains(htmlExtensions, c.ext) {
+		errs = append(errs, ErrInvalidOption{
+			Options: []string{"WithMinification", "WithExtension"},
+			Reason:  fmt.Sprintf("'%s' templates are not HTML", c.ext),
+		})
+	}
+
+	if c.hotReload {
+		for _, name := range slices.Sorted(maps.Keys(c.cachePolicies)) {
+			policy := c.cachePolicies[name]
+			if !policy.NoStore && (policy.MaxAge > 0 || policy.SharedMaxAge > 0 || policy.SurrogateMaxAge > 0) {
+				errs = append(errs, ErrInvalidOption{
+					Options: []string{"WithCachePolicy", "WithHotReload"},
+					Reason:  fmt.Sprintf("template '%s' would be served from caches after it is reloaded", name),
+				})
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.fragmentPolicies)) {
+		if mode := c.fragmentPolicies[name].Mode; mode < FragmentFail || mode > FragmentEmpty {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithFragmentPolicy"},
+				Reason:  fmt.Sprintf("template '%s' has unknown fragment mode %d", name, mode),
+			})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateFunc reports whether fn can be installed as a template function.
+func validateFunc(fn any) error {
+	typ := reflect.TypeOf(fn)
+	if typ == nil || typ.Kind() != reflect.Func {
+		return errors.New("is not a function")
+	}
+
+	switch {
+	case typ.NumOut() == 1:
+	case typ.NumOut() == 2 && typ.Out(1) == errorType:
+	default:
+		return errors.New("must return one value, or one value and an error")
+	}
+	return nil
+}
+
+// validateFuncPacks validates the installed func packs and returns the pack defining each
+// of their functions.
+func (c config[T]) validateFuncPacks() (map[string]string, []error) {
+	var (
+		errs   []error
+		packOf = make(map[string]string)
+		names  = make(map[string]bool)
+	)
+	for _, pack := range c.funcPacks {
+		if pack == nil {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithFuncPacks"},
+				Reason:  "func pack must not be nil",
+			})
+			continue
+		}
+
+		name := pack.Name()
+		switch {
+		case name == "":
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithFuncPacks"},
+				Reason:  "func pack name must not be empty",
+			})
+		case names[name]:
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithFuncPacks"},
+				Reason:  fmt.Sprintf("func pack '%s' installed twice", name),
+			})
+			continue
+		}
+		names[name] = true
+
+		if err := pack.Validate(); err != nil {
+			errs = append(errs, ErrInvalidOption{
+				Options: []string{"WithFuncPacks"},
+				Reason:  fmt.Sprintf("func pack '%s': %s", name, err),
+			})
+		}
+
+		for _, fn := range slices.Sorted(maps.Keys(pack.Funcs())) {
+			if other, ok := packOf[fn]; ok {
+				errs = append(errs, ErrInvalidOption{
+					Options: []string{"WithFuncPacks"},
+					Reason:  fmt.Sprintf("function '%s' defined by func packs '%s' and '%s'", fn, other, name),
+				})
+			}
+			packOf[fn] = name
+		}
+	}
+	return packOf, errs
+}
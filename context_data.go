@@ -0,0 +1,22 @@
+package templator
+
+import "context"
+
+// WithContextData returns an Option that passes the data of every render through enrich,
+// with the render context, before execution hooks, required field checks and caching see it.
+// It injects per-request values, such as a CSRF token, flash messages or the current user,
+// so call sites don't repeat that plumbing. With several calls, the functions run in the
+// order they were added.
+func WithContextData[T any](enrich func(ctx context.Context, data T) T) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.contextData = append(r.config.contextData, enrich)
+	}
+}
+
+// enrich returns data passed through the registry's WithContextData functions.
+func (h *Handler[T]) enrich(ctx context.Context, data T) T {
+	for _, fn := range h.reg.config.contextData {
+		data = fn(ctx, data)
+	}
+	return data
+}
@@ -0,0 +1,63 @@
+package templator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContextData(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(
+			`{{define "user"}}<span>{{.Content}}</span>{{end}}<h1>{{.Title}}</h1>{{template "user" .}}`)},
+	}
+
+	type userKey struct{}
+	withUser := WithContextData(func(ctx context.Context, data TestData) TestData {
+		if user, ok := ctx.Value(userKey{}).(string); ok {
+			data.Content = user
+		}
+		return data
+	})
+	ctx := context.WithValue(context.Background(), userKey{}, "ana")
+
+	t.Run("enriches the data before execution", func(t *testing.T) {
+		t.Parallel()
+
+		var seen string
+		reg := MustNewRegistry(fs, withUser, WithExecutionHooks(
+			func(_ context.Context, _ string, data TestData) error {
+				seen = data.Content
+				return nil
+			}, nil,
+		))
+
+		out, err := reg.MustGet("home").ExecuteToString(ctx, TestData{Title: "Home"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Home</h1><span>ana</span>", out)
+		assert.Equal(t, "ana", seen)
+
+		var b strings.Builder
+		require.NoError(t, reg.MustGet("home").ExecuteTemplate(ctx, &b, "user", TestData{}))
+		assert.Equal(t, "<span>ana</span>", b.String())
+	})
+
+	t.Run("runs in order", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, withUser, WithContextData(func(_ context.Context, data TestData) TestData {
+			data.Content = strings.ToUpper(data.Content)
+			return data
+		}))
+
+		out, err := reg.MustGet("home").ExecuteToString(ctx, TestData{Title: "Home"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Home</h1><span>ANA</span>", out)
+	})
+}
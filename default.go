@@ -0,0 +1,58 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+)
+
+// ErrNoDefaultRegistry is returned by the package-level helpers when SetDefault has not been called.
+var ErrNoDefaultRegistry = errors.New("default registry not set")
+
+var (
+	defaultMu       sync.RWMutex
+	defaultRegistry *Registry[any]
+)
+
+// SetDefault creates a registry over the provided filesystem, typically an embed.FS,
+// and installs it as the default used by the package-level Get and Execute.
+// The default registry accepts any data type, trading compile-time checks for brevity
+// in small apps and examples.
+func SetDefault(fsys fs.FS, opts ...Option[any]) error {
+	reg, err := NewRegistry(fsys, opts...)
+	if err != nil {
+		return err
+	}
+
+	defaultMu.Lock()
+	defaultRegistry = reg
+	defaultMu.Unlock()
+	return nil
+}
+
+// Default returns the default registry, or nil if SetDefault has not been called.
+func Default() *Registry[any] {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultRegistry
+}
+
+// Get retrieves a handler for the named template from the default registry.
+func Get(name string, opts ...GetOption) (*Handler[any], error) {
+	reg := Default()
+	if reg == nil {
+		return nil, ErrNoDefaultRegistry
+	}
+	return reg.Get(name, opts...)
+}
+
+// Execute renders the named template from the default registry with the provided data.
+func Execute(ctx context.Context, w io.Writer, name string, data any) error {
+	h, err := Get(name)
+	if err != nil {
+		return err
+	}
+	return h.Execute(ctx, w, data)
+}
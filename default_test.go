@@ -0,0 +1,58 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The default registry is global state, so these tests do not run in parallel.
+
+func TestDefault(t *testing.T) {
+	t.Cleanup(func() {
+		defaultMu.Lock()
+		defaultRegistry = nil
+		defaultMu.Unlock()
+	})
+
+	t.Run("returns error before SetDefault", func(t *testing.T) {
+		assert.Nil(t, Default())
+
+		h, err := Get("home")
+		require.ErrorIs(t, err, ErrNoDefaultRegistry)
+		assert.Nil(t, h)
+
+		err = Execute(context.Background(), &bytes.Buffer{}, "home", TestData{})
+		require.ErrorIs(t, err, ErrNoDefaultRegistry)
+	})
+
+	t.Run("renders from the default registry", func(t *testing.T) {
+		fs := fstest.MapFS{
+			"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		}
+
+		require.NoError(t, SetDefault(fs))
+		require.NotNil(t, Default())
+
+		var buf bytes.Buffer
+		err := Execute(context.Background(), &buf, "home", TestData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>hi</h1>", buf.String())
+
+		h, err := Get("home")
+		require.NoError(t, err)
+		assert.NotNil(t, h)
+	})
+
+	t.Run("keeps previous default on invalid options", func(t *testing.T) {
+		previous := Default()
+
+		err := SetDefault(fstest.MapFS{}, WithExtension[any]("tmpl"))
+		require.Error(t, err)
+		assert.Same(t, previous, Default())
+	})
+}
@@ -0,0 +1,191 @@
+package templator
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"text/template/parse"
+)
+
+// deprecatedKey is the metadata key marking templates and blocks as deprecated.
+const deprecatedKey = "deprecated"
+
+// Deprecation is a template, or a block it defines, marked as deprecated with a pragma:
+//
+//	{{/* templator: deprecated="use pages/home" */}}
+//
+// A pragma among the top-level comments, or a deprecated frontmatter key, deprecates the
+// whole template; a pragma leading the body of a define or block deprecates that block only:
+//
+//	{{define "legacy-card"}}{{/* templator: deprecated=true */}}...{{end}}
+type Deprecation struct {
+	// Template is the template declaring the deprecation.
+	Template string
+	// Block is the deprecated block, or empty when the whole template is deprecated.
+	Block string
+	// Reason is the value of the pragma, or empty for deprecated=true.
+	Reason string
+}
+
+func (d Deprecation) String() string {
+	s := d.Template
+	if d.Block != "" {
+		s += ": " + d.Block
+	}
+	if d.Reason != "" {
+		s += " (" + d.Reason + ")"
+	}
+	return s
+}
+
+// deprecationsOf returns the deprecations declared by the processed content of the named
+// template, keyed by the name they are parsed under: file for the whole template, and the
+// block name for blocks. md is the metadata of the template, or nil to parse it.
+func (r *Registry[T]) deprecationsOf(name, file string, content []byte, md Metadata, leftDelim, rightDelim string) (map[string]Deprecation, error) {
+	declared := bytes.Contains(content, []byte(deprecatedKey))
+	if md == nil {
+		frontmatter := r.frontmatter.get(name)
+		if _, ok := frontmatter[deprecatedKey]; !ok && !declared {
+			return nil, nil
+		}
+
+		var err error
+		if md, err = r.declaredMetadata(name, content, frontmatter, leftDelim, rightDelim); err != nil {
+			return nil, err
+		}
+	}
+
+	deprecations := make(map[string]Deprecation)
+	if reason, ok := deprecationReason(md[deprecatedKey]); ok {
+		deprecations[file] = Deprecation{Template: name, Reason: reason}
+	}
+	if !declared {
+		return deprecations, nil
+	}
+
+	trees := make(map[string]*parse.Tree)
+	tree := parse.New(file)
+	tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+	if _, err := tree.Parse(string(content), leftDelim, rightDelim, trees); err != nil {
+		// Syntax errors are left for the parser to report with their line.
+		return deprecations, nil
+	}
+
+	for block, t := range trees {
+		if block == file || t.Root == nil {
+			continue
+		}
+
+		pragmas := make(Metadata)
+		for _, node := range t.Root.Nodes {
+			if text, ok := node.(*parse.TextNode); ok && len(bytes.TrimSpace(text.Text)) == 0 {
+				continue
+			}
+			comment, ok := node.(*parse.CommentNode)
+			if !ok {
+				break
+			}
+
+			text := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(comment.Text, "/*"), "*/"))
+			if body, ok := strings.CutPrefix(text, pragmaPrefix); ok {
+				if err := parsePragma(body, pragmas); err != nil {
+					return nil, fmt.Errorf("template '%s': block '%s': %w: %w", name, block, ErrInvalidPragma, err)
+				}
+			}
+		}
+
+		if reason, ok := deprecationReason(pragmas[deprecatedKey]); ok {
+			deprecations[block] = Deprecation{Template: name, Block: block, Reason: reason}
+		}
+	}
+	return deprecations, nil
+}
+
+// deprecationReason reports whether a deprecated value marks a deprecation, and its reason.
+func deprecationReason(value any) (string, bool) {
+	switch v := value.(type) {
+	case bool:
+		return "", v
+	case string:
+		return v, true
+	default:
+		return "", false
+	}
+}
+
+// deprecationsFrom returns the deprecations of the templates reachable from the named
+// template of s: the template itself and the templates and blocks it invokes, transitively.
+func (s *source) deprecationsFrom(name string) []Deprecation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.deprecations) == 0 {
+		return nil
+	}
+
+	var (
+		found   []Deprecation
+		visited = make(map[string]bool)
+		visit   func(name string)
+	)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+
+		found = append(found, s.deprecations[name]...)
+		if t := s.tmpl.Lookup(name); t != nil && t.Tree != nil {
+			templateCalls(t.Tree.Root, visit)
+		}
+	}
+	visit(name)
+	return found
+}
+
+// warnDeprecated logs each deprecation the first time it is used, with the stack of the
+// caller rendering it, so the remaining uses of deprecated templates can be tracked down.
+func (r *Registry[T]) warnDeprecated(deprecations []Deprecation) {
+	if r.config.logger == nil {
+		return
+	}
+
+	for _, d := range deprecations {
+		if _, warned := r.deprecationsWarned.LoadOrStore(d, true); warned {
+			continue
+		}
+
+		attrs := []any{"template", d.Template}
+		if d.Block != "" {
+			attrs = append(attrs, "block", d.Block)
+		}
+		if d.Reason != "" {
+			attrs = append(attrs, "reason", d.Reason)
+		}
+		r.config.logger.Warn("deprecated template used", append(attrs, "stack", callerStack())...)
+	}
+}
+
+// packagePrefix prefixes the function names of the package, which callerStack skips.
+var packagePrefix = reflect.TypeFor[Deprecation]().PkgPath() + "."
+
+// callerStack returns the stack of the caller into the package, one "function file:line"
+// frame per line.
+func callerStack() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	var b strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			fmt.Fprintf(&b, "%s %s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,130 @@
+package templator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecation(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/old.html": &fstest.MapFile{Data: []byte(
+			`{{/* templator: deprecated="use home" */}}<p>{{.Title}}</p>`)},
+		"templates/legacy.html": &fstest.MapFile{Data: []byte("---\ndeprecated: true\n---\n<p>{{.Title}}</p>")},
+		"templates/home.html":   &fstest.MapFile{Data: []byte(`{{template "components/banner.html" .}}`)},
+		"templates/page.html":   &fstest.MapFile{Data: []byte(`{{/* templator: layout="layouts/old" */}}{{define "body"}}page{{end}}`)},
+		"templates/rows.html": &fstest.MapFile{Data: []byte(
+			`{{define "row"}}
+	{{/* templator: deprecated=true */}}<tr>{{.Title}}</tr>
+{{end}}{{define "cell"}}<td>{{template "row" .}}</td>{{end}}{{define "new"}}<tr></tr>{{end}}`)},
+		"templates/components/banner.html": &fstest.MapFile{Data: []byte(
+			`{{/* templator: deprecated="use components/alert" */}}<div>{{.Title}}</div>`)},
+		"templates/layouts/old.html": &fstest.MapFile{Data: []byte(
+			`{{/* templator: deprecated="use layouts/base" */}}<main>{{template "content" .}}</main>`)},
+	}
+
+	reg := MustNewRegistry(fs, WithPartials[TestData]("components/*"))
+
+	tests := []struct {
+		name  string
+		block string
+		want  []Deprecation
+	}{
+		{name: "old", want: []Deprecation{{Template: "old", Reason: "use home"}}},
+		{name: "legacy", want: []Deprecation{{Template: "legacy"}}},
+		{name: "home", want: []Deprecation{{Template: "components/banner", Reason: "use components/alert"}}},
+		{name: "page", want: []Deprecation{{Template: "layouts/old", Reason: "use layouts/base"}}},
+		{name: "rows", block: "cell", want: []Deprecation{{Template: "rows", Block: "row"}}},
+		{name: "rows", block: "new"},
+		{name: "rows"},
+	}
+
+	for _, tt := range tests {
+		h, err := reg.Get(tt.name)
+		require.NoError(t, err)
+
+		from := h.file
+		if tt.block != "" {
+			from = tt.block
+		}
+		assert.Equal(t, tt.want, h.src.deprecationsFrom(from), tt.name+" "+tt.block)
+	}
+}
+
+func TestDeprecation_Warnings(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/old.html": &fstest.MapFile{Data: []byte(
+			`{{/* templator: deprecated="use home" */}}<p>{{.Title}}</p>`)},
+		"templates/rows.html": &fstest.MapFile{Data: []byte(
+			`{{define "row"}}{{/* templator: deprecated=true */}}<tr>{{.Title}}</tr>{{end}}`)},
+	}
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	reg := MustNewRegistry(fs, WithLogger[TestData](logger))
+
+	for range 3 {
+		_, err := reg.Get("old")
+		require.NoError(t, err)
+	}
+
+	rows := reg.MustGet("rows")
+	for range 2 {
+		require.NoError(t, rows.ExecuteTemplate(context.Background(), io.Discard, "row", TestData{Title: "Hi"}))
+	}
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "deprecated template used", records[0]["msg"])
+	assert.Equal(t, "old", records[0]["template"])
+	assert.Equal(t, "use home", records[0]["reason"])
+	assert.Contains(t, records[0]["stack"], "testing.tRunner")
+	assert.NotContains(t, records[0]["stack"], "(*Registry[...]).Get")
+
+	assert.Equal(t, "rows", records[1]["template"])
+	assert.Equal(t, "row", records[1]["block"])
+	assert.NotContains(t, records[1], "reason")
+}
+
+func TestRegistry_Audit_Deprecated(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(`{{template "components/banner.html" .}}`)},
+		"templates/components/banner.html": &fstest.MapFile{Data: []byte(
+			`{{/* templator: deprecated="use components/alert" */}}<div></div>`)},
+		"templates/rows.html": &fstest.MapFile{Data: []byte(
+			`{{define "row"}}{{/* templator: deprecated=true */}}<tr></tr>{{end}}`)},
+		"templates/retired.html": &fstest.MapFile{Data: []byte(`{{/* templator: deprecated=true */}}gone`)},
+	}
+
+	reg := MustNewRegistry(fs, WithPartials[TestData]("components/*"))
+
+	report, err := reg.Audit(AuditScope{Templates: []string{"home", "rows"}, Blocks: []string{"row"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Deprecation{
+		{Template: "components/banner", Reason: "use components/alert"},
+		{Template: "rows", Block: "row"},
+	}, report.Deprecated)
+	assert.Equal(t, []string{"retired"}, report.Unreachable)
+}
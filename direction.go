@@ -0,0 +1,147 @@
+package templator
+
+import (
+	"bytes"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// rtlLanguages are the languages written right to left by default.
+var rtlLanguages = map[string]bool{
+	"ar": true, "arc": true, "ckb": true, "dv": true, "fa": true, "he": true, "iw": true,
+	"ks": true, "ku": true, "nqo": true, "ps": true, "sd": true, "syr": true, "ug": true,
+	"ur": true, "yi": true,
+}
+
+// rtlScripts are the scripts written right to left.
+var rtlScripts = map[string]bool{
+	"adlm": true, "arab": true, "hebr": true, "nkoo": true, "rohg": true, "syrc": true, "thaa": true,
+}
+
+// Direction returns the text direction of a locale, "rtl" for Arabic, Hebrew, Persian or Urdu,
+// for instance, and "ltr" otherwise. A script subtag takes precedence over the language,
+// so "ku-Latn" is "ltr" and "pa-Arab" is "rtl".
+func Direction(locale string) string {
+	subtags := strings.Split(strings.ToLower(strings.ReplaceAll(locale, "_", "-")), "-")
+	for _, subtag := range subtags[1:] {
+		if len(subtag) == 4 {
+			if rtlScripts[subtag] {
+				return "rtl"
+			}
+			return "ltr"
+		}
+	}
+	if rtlLanguages[subtags[0]] {
+		return "rtl"
+	}
+	return "ltr"
+}
+
+// WithDirection returns an Option that installs the template functions dir and isRTL, reporting
+// the text direction of the locale carried by the rendering context (see WithLocale), so one
+// template serves both directions:
+//
+//	<html lang="{{.Lang}}" dir="{{dir}}">
+//	<span class="{{if isRTL}}ms-2{{else}}me-2{{end}}">
+//
+// As with WithTranslator, each handler keeps one copy of its template per locale.
+func WithDirection[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.direction = true
+		WithTemplateFuncs[T](directionFuncs(""))(r)
+	}
+}
+
+// directionFuncs returns the dir and isRTL template functions bound to lang.
+func directionFuncs(lang string) template.FuncMap {
+	rtl := Direction(lang) == "rtl"
+	return template.FuncMap{
+		"dir": func() string {
+			return Direction(lang)
+		},
+		"isRTL": func() bool {
+			return rtl
+		},
+	}
+}
+
+// DefaultMirroredClasses pairs the physical CSS classes of common utility frameworks that
+// MirrorRTL swaps. Keys ending with "-" are prefixes: "ml-" swaps "ml-4" for "mr-4".
+var DefaultMirroredClasses = map[string]string{
+	"text-left":   "text-right",
+	"float-left":  "float-right",
+	"ml-":         "mr-",
+	"pl-":         "pr-",
+	"left-":       "right-",
+	"border-l-":   "border-r-",
+	"rounded-l-":  "rounded-r-",
+	"rounded-tl-": "rounded-tr-",
+	"rounded-bl-": "rounded-br-",
+}
+
+var (
+	htmlStartTag   = regexp.MustCompile(`(?i)<html\b[^>]*>`)
+	dirAttribute   = regexp.MustCompile(`(?i)\sdir\s*=`)
+	classAttribute = regexp.MustCompile(`(\sclass\s*=\s*)("[^"]*"|'[^']*')`)
+)
+
+// MirrorRTL returns a PostProcessor, for ExecuteTee sinks, adapting pages written for left to
+// right locales to a right to left one: it adds dir="rtl" to the html element, unless it
+// declares a direction, and swaps the classes paired by classes, in both directions, e.g.
+// DefaultMirroredClasses. It leaves the output of left to right locales untouched.
+func MirrorRTL(locale string, classes map[string]string) PostProcessor {
+	if Direction(locale) != "rtl" {
+		return func(out []byte) ([]byte, error) { return out, nil }
+	}
+
+	mirrored := make(map[string]string, 2*len(classes))
+	for from, to := range classes {
+		mirrored[from] = to
+		mirrored[to] = from
+	}
+
+	return func(out []byte) ([]byte, error) {
+		if loc := htmlStartTag.FindIndex(out); loc != nil && !dirAttribute.Match(out[loc[0]:loc[1]]) {
+			end := loc[1] - 1
+			out = append(out[:end], append([]byte(` dir="rtl"`), out[end:]...)...)
+		}
+
+		if len(mirrored) == 0 {
+			return out, nil
+		}
+		return classAttribute.ReplaceAllFunc(out, func(attr []byte) []byte {
+			m := classAttribute.FindSubmatchIndex(attr)
+			value := attr[m[4]+1 : m[5]-1]
+
+			classes := bytes.Fields(value)
+			for i, class := range classes {
+				classes[i] = []byte(mirrorClass(string(class), mirrored))
+			}
+
+			var b bytes.Buffer
+			b.Write(attr[:m[4]+1])
+			b.Write(bytes.Join(classes, []byte(" ")))
+			b.Write(attr[m[5]-1:])
+			return b.Bytes()
+		}), nil
+	}
+}
+
+// mirrorClass returns the class paired with class, exactly or by prefix, or class itself.
+func mirrorClass(class string, mirrored map[string]string) string {
+	if to, ok := mirrored[class]; ok {
+		return to
+	}
+
+	var best string
+	for from := range mirrored {
+		if strings.HasSuffix(from, "-") && strings.HasPrefix(class, from) && len(from) > len(best) {
+			best = from
+		}
+	}
+	if best == "" {
+		return class
+	}
+	return mirrored[best] + class[len(best):]
+}
@@ -0,0 +1,99 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirection(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":        "ltr",
+		"en":      "ltr",
+		"pt-BR":   "ltr",
+		"ar":      "rtl",
+		"ar-EG":   "rtl",
+		"he_IL":   "rtl",
+		"FA":      "rtl",
+		"ku-Latn": "ltr",
+		"pa-Arab": "rtl",
+		"ckb-IQ":  "rtl",
+	}
+	for locale, want := range tests {
+		assert.Equal(t, want, Direction(locale), locale)
+	}
+}
+
+func TestWithDirection(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(`<html dir="{{dir}}">{{if isRTL}}rtl{{else}}ltr{{end}}</html>`)},
+	}
+	reg := MustNewRegistry(fs, WithLocales[TestData]("en", "ar"), WithDirection[TestData]())
+	home := reg.MustGet("home")
+
+	tests := map[string]string{
+		"ar-EG": `<html dir="rtl">rtl</html>`,
+		"en":    `<html dir="ltr">ltr</html>`,
+		"he":    `<html dir="ltr">ltr</html>`, // undeclared
+	}
+	for locale, want := range tests {
+		var buf bytes.Buffer
+		require.NoError(t, home.Execute(WithLocale(context.Background(), locale), &buf, TestData{}))
+		assert.Equal(t, want, buf.String(), locale)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, home.Execute(context.Background(), &buf, TestData{}))
+	assert.Equal(t, `<html dir="ltr">ltr</html>`, buf.String())
+}
+
+func TestMirrorRTL(t *testing.T) {
+	t.Parallel()
+
+	page := `<html lang="ar"><body class="text-left ml-4 rounded-tl-lg"><p class='mr-2 pl-1 left-0 grid'>x</p></body></html>`
+
+	t.Run("mirrors right to left locales", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := MirrorRTL("ar", DefaultMirroredClasses)([]byte(page))
+		require.NoError(t, err)
+		assert.Equal(t, `<html lang="ar" dir="rtl"><body class="text-right mr-4 rounded-tr-lg"><p class='ml-2 pr-1 right-0 grid'>x</p></body></html>`, string(out))
+	})
+
+	t.Run("keeps declared directions", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := MirrorRTL("he", nil)([]byte(`<HTML DIR="ltr"><p class="ml-4">`))
+		require.NoError(t, err)
+		assert.Equal(t, `<HTML DIR="ltr"><p class="ml-4">`, string(out))
+	})
+
+	t.Run("leaves left to right locales untouched", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := MirrorRTL("en", DefaultMirroredClasses)([]byte(page))
+		require.NoError(t, err)
+		assert.Equal(t, page, string(out))
+	})
+
+	t.Run("works as a sink post-processor", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fstest.MapFS{
+			"templates/home.html": &fstest.MapFile{Data: []byte(`<html><p class="ml-2">{{.Title}}</p></html>`)},
+		})
+
+		var buf bytes.Buffer
+		err := reg.MustGet("home").ExecuteTee(context.Background(), TestData{Title: "مرحبا"}, NewSink(&buf, MirrorRTL("ar", DefaultMirroredClasses)))
+		require.NoError(t, err)
+		assert.Equal(t, `<html dir="rtl"><p class="mr-2">مرحبا</p></html>`, buf.String())
+	})
+}
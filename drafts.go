@@ -0,0 +1,207 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ErrDraftNotFound is returned when no draft is saved for a template.
+var ErrDraftNotFound = errors.New("draft not found")
+
+// WithDraftValidator returns an Option adding a check every draft must pass before it is saved
+// or published, on top of parsing and the registry's field and function validation. It lets
+// products enforce their own rules on user-edited templates, such as size limits or banned
+// constructs. Validators run in the order they are added.
+func WithDraftValidator[T any](fn func(name, content string) error) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.draftValidators = append(r.config.draftValidators, fn)
+	}
+}
+
+// drafts holds edited template content: saved drafts waiting to be published,
+// and published content overriding the registry filesystem.
+type drafts struct {
+	mu      sync.RWMutex
+	pending map[string][]byte
+	live    map[string][]byte
+}
+
+func (d *drafts) published(name string) ([]byte, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	content, ok := d.live[name]
+	return content, ok
+}
+
+// SaveDraft validates content as a new version of the named template and saves it as a draft,
+// replacing any previous draft. Drafts are invisible to Get until published. The template
+// does not need to exist yet, so drafts can also introduce new templates.
+func (r *Registry[T]) SaveDraft(name, content string) error {
+	if r.closed.Load() {
+		return ErrRegistryClosed
+	}
+
+	if err := r.validateName(name); err != nil {
+		return err
+	}
+
+	if err := r.validateDraft(name, []byte(content)); err != nil {
+		return err
+	}
+
+	r.drafts.mu.Lock()
+	defer r.drafts.mu.Unlock()
+
+	if r.drafts.pending == nil {
+		r.drafts.pending = make(map[string][]byte)
+	}
+	r.drafts.pending[name] = []byte(content)
+	return nil
+}
+
+// Draft returns the content of the draft saved for the named template.
+func (r *Registry[T]) Draft(name string) (string, bool) {
+	r.drafts.mu.RLock()
+	defer r.drafts.mu.RUnlock()
+
+	content, ok := r.drafts.pending[name]
+	return string(content), ok
+}
+
+// DiscardDraft deletes the draft saved for the named template, if any.
+func (r *Registry[T]) DiscardDraft(name string) {
+	r.drafts.mu.Lock()
+	delete(r.drafts.pending, name)
+	r.drafts.mu.Unlock()
+}
+
+// Preview renders the draft saved for the named template with data to w, as Execute would
+// once it is published, with editable region markers (see WithEditableMarkersOnce). Partials
+// are the published ones. Previews don't count toward render SLOs.
+func (r *Registry[T]) Preview(ctx context.Context, w io.Writer, name string, data T) error {
+	if ctx == nil {
+		return ErrTemplateExecution{Name: name + string(r.config.ext), Err: ErrNilContext}
+	}
+
+	if err := checkBudget(ctx); err != nil {
+		return ErrTemplateExecution{Name: name + string(r.config.ext), Err: err}
+	}
+
+	content, ok := r.draftContent(name)
+	if !ok {
+		return fmt.Errorf("template '%s': %w", name, ErrDraftNotFound)
+	}
+
+	if r.closed.Load() {
+		return ErrRegistryClosed
+	}
+
+	content, src, err := r.processDraft(name, content)
+	if err != nil {
+		return err
+	}
+
+	h, err := r.parseContent(name, content, getConfig{editableMarkers: true, source: src})
+	if err != nil {
+		return err
+	}
+	return h.execute(ctx, w, data)
+}
+
+// Publish validates the draft saved for the named template again, since partials and validators
+// may have changed since it was saved, and makes it the live version of the template.
+// Published content takes precedence over the registry filesystem, including overlays.
+// Handlers already handed out switch to it as with Reload, dropping the output of cached
+// handlers, and the next Get picks it up. The draft is removed on success.
+func (r *Registry[T]) Publish(name string) error {
+	if r.closed.Load() {
+		return ErrRegistryClosed
+	}
+
+	if err := r.validateName(name); err != nil {
+		return err
+	}
+
+	content, ok := r.draftContent(name)
+	if !ok {
+		return fmt.Errorf("template '%s': %w", name, ErrDraftNotFound)
+	}
+
+	if err := r.validateDraft(name, content); err != nil {
+		return err
+	}
+
+	r.drafts.mu.Lock()
+	if r.drafts.live == nil {
+		r.drafts.live = make(map[string][]byte)
+	}
+	r.drafts.live[name] = content
+	delete(r.drafts.pending, name)
+	r.drafts.mu.Unlock()
+
+	// Handlers failing to re-parse keep serving their version, as with Reload; the
+	// published template itself was just validated.
+	if _, err := r.Reload(context.Background()); err != nil && r.config.logger != nil {
+		r.config.logger.Warn("reloading templates after publishing a draft", "template", name, "error", err)
+	}
+
+	// Published templates may be partials or locale variants of other templates,
+	// so every cached handler and lookup is dropped rather than only this one.
+	r.mu.Lock()
+	clear(r.templates)
+	clear(r.localized)
+	clear(r.metadata)
+	r.mu.Unlock()
+
+	r.logInvalidation("draft published", name)
+	return nil
+}
+
+func (r *Registry[T]) draftContent(name string) ([]byte, bool) {
+	r.drafts.mu.RLock()
+	defer r.drafts.mu.RUnlock()
+
+	content, ok := r.drafts.pending[name]
+	return content, ok
+}
+
+// validateDraft runs the draft validators, then processes and parses content as the named
+// template with the registry's validation, partials, and metadata pragmas.
+func (r *Registry[T]) validateDraft(name string, content []byte) error {
+	for _, validate := range r.config.draftValidators {
+		if err := validate(name, string(content)); err != nil {
+			return fmt.Errorf("template '%s': invalid draft: %w", name, err)
+		}
+	}
+
+	content, src, err := r.processDraft(name, content)
+	if err != nil {
+		return err
+	}
+
+	if _, err := parseMetadata(name, string(content), r.config.leftDelim, r.config.rightDelim); err != nil {
+		return err
+	}
+
+	_, err = r.parseContent(name, content, getConfig{source: src})
+	return err
+}
+
+// processDraft is readSource for the content of a draft. What processing learns about the
+// draft is returned rather than recorded, so the live template keeps its frontmatter and
+// source maps, and its preprocessed source stays memoized.
+func (r *Registry[T]) processDraft(name string, content []byte) ([]byte, *sourceInfo, error) {
+	content, src, err := r.expand(name, content, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if content, err = r.runPreprocessors(name, content); err != nil {
+		return nil, nil, err
+	}
+	return content, &src, nil
+}
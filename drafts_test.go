@@ -0,0 +1,206 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Drafts(t *testing.T) {
+	t.Parallel()
+
+	newDraftRegistry := func(t *testing.T, opts ...Option[TestData]) *Registry[TestData] {
+		t.Helper()
+
+		fs := fstest.MapFS{
+			"templates/home.html":              &fstest.MapFile{Data: []byte(`{{template "components/header.html" .}}<p>{{.Content}}</p>`)},
+			"templates/components/header.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		}
+
+		reg, err := NewRegistry(fs, append([]Option[TestData]{WithPartials[TestData]("components/*")}, opts...)...)
+		require.NoError(t, err)
+		return reg
+	}
+
+	t.Run("previews and publishes a draft", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newDraftRegistry(t)
+		data := TestData{Title: "Hi", Content: "body"}
+
+		live, err := reg.MustGet("home").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><p>body</p>", live)
+
+		require.NoError(t, reg.SaveDraft("home", `{{template "components/header.html" .}}<main>{{.Content}}</main>`))
+
+		draft, ok := reg.Draft("home")
+		require.True(t, ok)
+		assert.Contains(t, draft, "<main>")
+
+		var buf bytes.Buffer
+		require.NoError(t, reg.Preview(context.Background(), &buf, "home", data))
+		assert.Equal(t, "<h1>Hi</h1><main>body</main>", buf.String())
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, live, got, "drafts are invisible until published")
+
+		require.NoError(t, reg.Publish("home"))
+
+		got, err = reg.MustGet("home").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><main>body</main>", got)
+
+		_, ok = reg.Draft("home")
+		assert.False(t, ok, "published draft is removed")
+	})
+
+	t.Run("publishing a partial updates templates using it", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newDraftRegistry(t)
+		data := TestData{Title: "Hi", Content: "body"}
+
+		_, err := reg.MustGet("home").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+
+		require.NoError(t, reg.SaveDraft("components/header", `<h2>{{.Title}}</h2>`))
+		require.NoError(t, reg.Publish("components/header"))
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h2>Hi</h2><p>body</p>", got)
+	})
+
+	t.Run("rejects invalid drafts", func(t *testing.T) {
+		t.Parallel()
+
+		errTooLong := errors.New("too long")
+		reg := newDraftRegistry(t,
+			WithFieldValidation(TestData{}),
+			WithDraftValidator[TestData](func(_, content string) error {
+				if len(content) > 100 {
+					return errTooLong
+				}
+				return nil
+			}),
+		)
+
+		tests := []struct {
+			name    string
+			content string
+			wantErr string
+		}{
+			{name: "syntax", content: "{{.Title", wantErr: "unclosed action"},
+			{name: "unknown field", content: "{{.Missing}}", wantErr: "Missing"},
+			{name: "unknown function", content: "{{shout .Title}}", wantErr: "shout"},
+			{name: "invalid pragma", content: `{{/* templator: title="open */}}`, wantErr: "invalid pragma"},
+			{name: "validator", content: strings.Repeat("x", 101), wantErr: "too long"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := reg.SaveDraft("home", tt.content)
+				require.ErrorContains(t, err, tt.wantErr)
+
+				_, ok := reg.Draft("home")
+				assert.False(t, ok)
+			})
+		}
+	})
+
+	t.Run("publishing drops cached output", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newDraftRegistry(t)
+		data := TestData{Title: "Hi", Content: "body"}
+		cached := reg.MustGet("home").WithCache(time.Hour, func(d TestData) string { return d.Title })
+
+		got, err := cached.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><p>body</p>", got)
+
+		require.NoError(t, reg.SaveDraft("components/header", `<h2>{{.Title}}</h2>`))
+		require.NoError(t, reg.Publish("components/header"))
+
+		got, err = cached.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h2>Hi</h2><p>body</p>", got)
+	})
+
+	t.Run("rejects invalid names", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newDraftRegistry(t)
+
+		for _, name := range []string{"../x", "/etc/passwd", "home.html", ""} {
+			require.ErrorAs(t, reg.SaveDraft(name, "<p>draft</p>"), new(ErrInvalidTemplateName), name)
+			require.ErrorAs(t, reg.Publish(name), new(ErrInvalidTemplateName), name)
+		}
+	})
+
+	t.Run("returns error without a draft", func(t *testing.T) {
+		t.Parallel()
+
+		reg := newDraftRegistry(t)
+
+		require.NoError(t, reg.SaveDraft("home", "<p>draft</p>"))
+		reg.DiscardDraft("home")
+
+		require.ErrorIs(t, reg.Publish("home"), ErrDraftNotFound)
+		require.ErrorIs(t, reg.Preview(context.Background(), &bytes.Buffer{}, "home", TestData{}), ErrDraftNotFound)
+	})
+
+	t.Run("rejects nil validator", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(fstest.MapFS{}, WithDraftValidator[TestData](nil))
+		require.ErrorContains(t, err, "draft validator must not be nil")
+		assert.Nil(t, reg)
+	})
+}
+
+func TestRegistry_Drafts_LeaveLiveTemplate(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("---\ntitle: Live\n---\n<p>{{.User.Name}}</p>")},
+	}
+	reg := MustNewRegistry(fs, WithMacros[locationData]())
+
+	location := func() ExecLocation {
+		err := reg.MustGet("home").Execute(context.Background(), new(bytes.Buffer), locationData{})
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		return execErr.Location
+	}
+
+	want := ExecLocation{File: "home.html", Line: 4, Column: 10, Expr: ".User.Name", Snippet: "<p>{{.User.Name}}</p>"}
+	require.Equal(t, want, location())
+	md, err := reg.Metadata("home")
+	require.NoError(t, err)
+	require.Equal(t, Metadata{"title": "Live"}, md)
+
+	draft := "---\ntitle: Draft\nauthor: Ada\nsection: news\n---\n<div>\n<p>{{.User.Name}}</p>\n</div>"
+	require.NoError(t, reg.SaveDraft("home", draft))
+	err = reg.Preview(context.Background(), new(bytes.Buffer), "home", locationData{User: &struct{ Name string }{"Ada"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, want, location())
+	reg.mu.Lock()
+	clear(reg.metadata)
+	reg.mu.Unlock()
+	md, err = reg.Metadata("home")
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{"title": "Live"}, md)
+	assert.Equal(t, Metadata{"title": "Live"}, reg.frontmatter.get("home"))
+}
@@ -0,0 +1,40 @@
+// Package echoadapter exposes a templator Registry as an Echo renderer.
+//
+// Renderer implements echo.Renderer:
+//
+//	e := echo.New()
+//	e.Renderer = echoadapter.New(reg)
+//
+//	e.GET("/", func(c echo.Context) error {
+//		return c.Render(http.StatusOK, "home", HomeData{Title: "Welcome"})
+//	})
+//
+// It lives in its own module so the root templator module does not depend on Echo.
+package echoadapter
+
+import (
+	"io"
+
+	"github.com/alesr/templator"
+	"github.com/labstack/echo/v4"
+)
+
+// Renderer renders Echo responses from a templator Registry.
+type Renderer[T any] struct {
+	reg *templator.Registry[T]
+}
+
+// New creates a Renderer backed by the given registry.
+func New[T any](reg *templator.Registry[T]) *Renderer[T] {
+	return &Renderer[T]{reg: reg}
+}
+
+// Render renders the named template with data, which must be a T.
+// The request context is propagated to template execution.
+func (r *Renderer[T]) Render(w io.Writer, name string, data any, c echo.Context) error {
+	h, err := r.reg.Get(name)
+	if err != nil {
+		return err
+	}
+	return h.ExecuteAny(c.Request().Context(), w, data)
+}
@@ -0,0 +1,54 @@
+package echoadapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alesr/templator"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageData struct {
+	Title string
+}
+
+func TestRenderer(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}
+
+	reg, err := templator.NewRegistry[pageData](fs)
+	require.NoError(t, err)
+
+	e := echo.New()
+	e.Renderer = New(reg)
+
+	t.Run("renders template", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		require.NoError(t, c.Render(http.StatusOK, "home", pageData{Title: "hi"}))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "<h1>hi</h1>", rec.Body.String())
+	})
+
+	t.Run("rejects wrong data type", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		err := c.Render(http.StatusOK, "home", "not page data")
+
+		var dataErr templator.ErrDataType
+		require.ErrorAs(t, err, &dataErr)
+	})
+}
@@ -0,0 +1,139 @@
+package templator
+
+import (
+	"errors"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+	"text/template/parse"
+)
+
+const (
+	editableFunc    = "editable"
+	endEditableFunc = "endeditable"
+)
+
+// editableAction matches the opening action of an editable region with the default delimiters.
+var editableAction = editableActionPattern("{{")
+
+// editableFuncs render the markers of editable regions. They are only called by handlers
+// created with WithEditableMarkersOnce; other handlers have their regions stripped at parse time.
+var editableFuncs = template.FuncMap{
+	editableFunc: func(region string) template.HTML {
+		return template.HTML(`<div data-templator-editable="` + html.EscapeString(region) + `" style="display:contents">`)
+	},
+	endEditableFunc: func() template.HTML {
+		return "</div>"
+	},
+}
+
+// WithEditableMarkersOnce returns a GetOption that renders editable regions of a single handler
+// wrapped in an element carrying a data-templator-editable attribute, for visual editors to
+// target. Regions are declared with {{editable "hero"}}...{{end}} and render only their content
+// otherwise. Registry.Preview always renders the markers.
+func WithEditableMarkersOnce() GetOption {
+	return func(c *getConfig) {
+		c.editableMarkers = true
+	}
+}
+
+func editableActionPattern(leftDelim string) *regexp.Regexp {
+	return regexp.MustCompile(regexp.QuoteMeta(leftDelim) + `(-?\s*)` + editableFunc + `\b`)
+}
+
+// expandEditable turns {{editable "name"}} actions into {{if editable "name"}}, so the
+// region parses as a block closed by {{end}}. The blocks are rewritten by rewriteEditable.
+func expandEditable(content, leftDelim string) string {
+	if !strings.Contains(content, editableFunc) {
+		return content
+	}
+
+	pattern := editableAction
+	if leftDelim != "" && leftDelim != "{{" {
+		pattern = editableActionPattern(leftDelim)
+	}
+	return pattern.ReplaceAllStringFunc(content, func(action string) string {
+		return strings.Replace(action, editableFunc, "if "+editableFunc, 1)
+	})
+}
+
+// rewriteEditable replaces the editable region blocks of the tree with their content,
+// surrounded by marker actions when markers is set.
+func rewriteEditable(name string, list *parse.ListNode, markers bool) error {
+	if list == nil {
+		return nil
+	}
+
+	nodes := make([]parse.Node, 0, len(list.Nodes))
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.IfNode:
+			if !isEditable(n.Pipe) {
+				if err := rewriteEditableBranch(name, &n.BranchNode, markers); err != nil {
+					return err
+				}
+				break
+			}
+
+			if err := checkEditable(name, n); err != nil {
+				return err
+			}
+			if err := rewriteEditable(name, n.List, markers); err != nil {
+				return err
+			}
+
+			if !markers {
+				nodes = append(nodes, n.List.Nodes...)
+				continue
+			}
+
+			end := n.Pipe.CopyPipe()
+			end.Cmds[0].Args = []parse.Node{parse.NewIdentifier(endEditableFunc).SetPos(n.Position())}
+
+			nodes = append(nodes, &parse.ActionNode{NodeType: parse.NodeAction, Pos: n.Pos, Line: n.Line, Pipe: n.Pipe})
+			nodes = append(nodes, n.List.Nodes...)
+			nodes = append(nodes, &parse.ActionNode{NodeType: parse.NodeAction, Pos: n.Pos, Line: n.Line, Pipe: end})
+			continue
+		case *parse.WithNode:
+			if err := rewriteEditableBranch(name, &n.BranchNode, markers); err != nil {
+				return err
+			}
+		case *parse.RangeNode:
+			if err := rewriteEditableBranch(name, &n.BranchNode, markers); err != nil {
+				return err
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	list.Nodes = nodes
+	return nil
+}
+
+func rewriteEditableBranch(name string, n *parse.BranchNode, markers bool) error {
+	if err := rewriteEditable(name, n.List, markers); err != nil {
+		return err
+	}
+	return rewriteEditable(name, n.ElseList, markers)
+}
+
+func isEditable(pipe *parse.PipeNode) bool {
+	if len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) == 0 {
+		return false
+	}
+	ident, ok := pipe.Cmds[0].Args[0].(*parse.IdentifierNode)
+	return ok && ident.Ident == editableFunc
+}
+
+// checkEditable reports an editable region that is not a plain {{editable "name"}}...{{end}} block.
+func checkEditable(name string, n *parse.IfNode) error {
+	args := n.Pipe.Cmds[0].Args
+	if _, ok := args[len(args)-1].(*parse.StringNode); !ok || len(args) != 2 || len(n.Pipe.Decl) > 0 {
+		return ErrTemplateParse{Name: name, Line: n.Line, Err: errors.New("editable expects a single region name string")}
+	}
+	if n.ElseList != nil {
+		return ErrTemplateParse{Name: name, Line: n.Line, Err: errors.New("editable region cannot have an else branch")}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditableRegions(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(
+			`{{editable "hero"}}<h1>{{.Title}}</h1>{{end}}{{if .Content}}{{- editable "body" -}} <p>{{.Content}}</p> {{- end}}{{end}}{{template "components/footer.html" .}}`,
+		)},
+		"templates/components/footer.html": &fstest.MapFile{Data: []byte(`{{editable "footer"}}<footer>&copy;</footer>{{end}}`)},
+		"templates/else.html":              &fstest.MapFile{Data: []byte(`{{editable "hero"}}a{{else}}b{{end}}`)},
+		"templates/unnamed.html":           &fstest.MapFile{Data: []byte(`{{editable .Title}}a{{end}}`)},
+		"templates/delims.html":            &fstest.MapFile{Data: []byte(`[[editable "hero"]]<h1>[[.Title]]</h1>[[end]]`)},
+	}
+
+	reg, err := NewRegistry(fs,
+		WithPartials[TestData]("components/*"),
+		WithFieldValidation(TestData{}),
+		WithFuncValidation[TestData](),
+	)
+	require.NoError(t, err)
+
+	data := TestData{Title: "Hi", Content: "body"}
+
+	t.Run("renders only content in production", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><p>body</p><footer>&copy;</footer>", got)
+	})
+
+	t.Run("renders markers on request", func(t *testing.T) {
+		t.Parallel()
+
+		h, err := reg.Get("home", WithEditableMarkersOnce())
+		require.NoError(t, err)
+
+		got, err := h.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t,
+			`<div data-templator-editable="hero" style="display:contents"><h1>Hi</h1></div>`+
+				`<div data-templator-editable="body" style="display:contents"><p>body</p></div>`+
+				`<div data-templator-editable="footer" style="display:contents"><footer>&copy;</footer></div>`,
+			got,
+		)
+	})
+
+	t.Run("supports custom delimiters", func(t *testing.T) {
+		t.Parallel()
+
+		h, err := reg.Get("delims", WithDelimsOnce("[[", "]]"))
+		require.NoError(t, err)
+
+		got, err := h.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1>", got)
+	})
+
+	t.Run("previews drafts with markers", func(t *testing.T) {
+		t.Parallel()
+
+		draftReg := MustNewRegistry[TestData](fs)
+		require.NoError(t, draftReg.SaveDraft("about", `{{editable "intro"}}{{.Title}}{{end}}`))
+
+		var buf bytes.Buffer
+		require.NoError(t, draftReg.Preview(context.Background(), &buf, "about", data))
+		assert.Equal(t, `<div data-templator-editable="intro" style="display:contents">Hi</div>`, buf.String())
+	})
+
+	t.Run("reads metadata of templates with regions", func(t *testing.T) {
+		t.Parallel()
+
+		md, err := reg.Metadata("home")
+		require.NoError(t, err)
+		assert.Empty(t, md)
+	})
+
+	t.Run("rejects malformed regions", func(t *testing.T) {
+		t.Parallel()
+
+		for name, want := range map[string]string{
+			"else":    "cannot have an else branch",
+			"unnamed": "expects a single region name string",
+		} {
+			_, err := reg.Get(name)
+
+			var parseErr ErrTemplateParse
+			require.ErrorAs(t, err, &parseErr, name)
+			assert.Contains(t, parseErr.Error(), want)
+			assert.Equal(t, 1, parseErr.Line)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package templator
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// NewRegistryFromEmbed creates a registry for the templates embedded under root, the directory
+// named by the go:embed directive, e.g. "templates" or "web/templates":
+//
+//	//go:embed web/templates
+//	var templates embed.FS
+//
+//	reg, err := templator.NewRegistryFromEmbed[PageData](templates, "web/templates")
+//
+// Leading "./" and "/" and trailing slashes of root are ignored, and root replaces
+// WithTemplatesPath. Unlike a wrong WithTemplatesPath, which only surfaces as ErrTemplateNotFound
+// at Get time, a root that isn't an embedded directory returns ErrInvalidOption.
+func NewRegistryFromEmbed[T any](efs embed.FS, root string, opts ...Option[T]) (*Registry[T], error) {
+	dir := strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(root, `\`, "/")), "/")
+	if dir == "" {
+		return nil, ErrInvalidOption{
+			Options: []string{"NewRegistryFromEmbed"},
+			Reason:  "root must name the embedded directory holding the templates",
+		}
+	}
+
+	info, err := fs.Stat(efs, dir)
+	if err != nil {
+		return nil, ErrInvalidOption{
+			Options: []string{"NewRegistryFromEmbed"},
+			Reason:  fmt.Sprintf("root '%s' is not embedded", root),
+		}
+	}
+	if !info.IsDir() {
+		return nil, ErrInvalidOption{
+			Options: []string{"NewRegistryFromEmbed"},
+			Reason:  fmt.Sprintf("root '%s' is not a directory", root),
+		}
+	}
+
+	var fsys fs.FS = efs
+	if parent := path.Dir(dir); parent != "." {
+		if fsys, err = fs.Sub(efs, parent); err != nil {
+			return nil, err
+		}
+	}
+	return NewRegistry(fsys, append(slices.Clip(opts), WithTemplatesPath[T](path.Base(dir)))...)
+}
@@ -0,0 +1,59 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed templates
+var embedded embed.FS
+
+func TestNewRegistryFromEmbed(t *testing.T) {
+	t.Parallel()
+
+	t.Run("normalizes the root", func(t *testing.T) {
+		t.Parallel()
+
+		for _, root := range []string{"templates", "./templates/", "/templates", `templates\`} {
+			reg, err := NewRegistryFromEmbed[TestData](embedded, root)
+			require.NoError(t, err, root)
+
+			var buf bytes.Buffer
+			require.NoError(t, reg.MustGet("home").Execute(context.Background(), &buf, TestData{}), root)
+			assert.Contains(t, buf.String(), "This is the home page.")
+		}
+	})
+
+	t.Run("sub-roots nested directories", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistryFromEmbed(embedded, "templates/components", WithTemplatesPath[TestData]("ignored"))
+		require.NoError(t, err)
+
+		names, err := reg.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"menu"}, names)
+	})
+
+	t.Run("rejects invalid roots", func(t *testing.T) {
+		t.Parallel()
+
+		for root, reason := range map[string]string{
+			"":                    "must name",
+			".":                   "must name",
+			"views":               "not embedded",
+			"templates/home.html": "not a directory",
+		} {
+			_, err := NewRegistryFromEmbed[TestData](embedded, root)
+
+			var optErr ErrInvalidOption
+			require.ErrorAs(t, err, &optErr, root)
+			assert.Contains(t, optErr.Reason, reason, root)
+		}
+	})
+}
@@ -1,8 +1,14 @@
 package templator
 
-import "fmt"
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
 
 // ErrTemplateNotFound is returned when a template cannot be found.
+// It matches fs.ErrNotExist with errors.Is.
 type ErrTemplateNotFound struct {
 	Name string
 }
@@ -11,16 +17,129 @@ func (e ErrTemplateNotFound) Error() string {
 	return fmt.Sprintf("template '%s' not found", e.Name)
 }
 
-// ErrTemplateExecution is returned when a template fails to execute.
-type ErrTemplateExecution struct {
+func (e ErrTemplateNotFound) Unwrap() error {
+	return fs.ErrNotExist
+}
+
+// ErrInvalidTemplateName is returned when a template name is empty, absolute,
+// climbs out of the template directory or carries the template file extension.
+// It matches fs.ErrInvalid with errors.Is.
+type ErrInvalidTemplateName struct {
+	Name   string
+	Reason string
+}
+
+func (e ErrInvalidTemplateName) Error() string {
+	return fmt.Sprintf("invalid template name '%s': %s", e.Name, e.Reason)
+}
+
+func (e ErrInvalidTemplateName) Unwrap() error {
+	return fs.ErrInvalid
+}
+
+// ErrTemplateParse is returned when a template or one of its partials fails to parse.
+// Line is the line of the syntax error, or 0 when unknown.
+type ErrTemplateParse struct {
 	Name string
+	Line int
 	Err  error
 }
 
+func (e ErrTemplateParse) Error() string {
+	return fmt.Sprintf("failed to parse template '%s': '%v'", e.Name, e.Err)
+}
+
+func (e ErrTemplateParse) Unwrap() error {
+	return e.Err
+}
+
+// newParseError wraps a parse error of the named template file, extracting the line
+// from the "template: name:line: ..." prefix of text/template errors.
+func newParseError(name, file string, err error) ErrTemplateParse {
+	parseErr := ErrTemplateParse{Name: name, Err: err}
+
+	rest, ok := strings.CutPrefix(err.Error(), "template: "+file+":")
+	if !ok {
+		return parseErr
+	}
+	if line, _, ok := strings.Cut(rest, ":"); ok {
+		parseErr.Line, _ = strconv.Atoi(line)
+	}
+	return parseErr
+}
+
+// ErrComposeConflict is returned by Registry.Compose when two of the composed templates
+// define a template of the same name.
+type ErrComposeConflict struct {
+	Name  string
+	Files []string
+}
+
+func (e ErrComposeConflict) Error() string {
+	return fmt.Sprintf("template '%s' defined by both '%s'", e.Name, strings.Join(e.Files, "' and '"))
+}
+
+// ErrMissingFields is returned, wrapped in ErrTemplateExecution, when data leaves fields
+// the template declares as required at their zero value.
+type ErrMissingFields struct {
+	Fields []string
+}
+
+func (e ErrMissingFields) Error() string {
+	return fmt.Sprintf("required fields not set: %s", strings.Join(e.Fields, ", "))
+}
+
+// ErrTemplateExecution is returned when a template fails to execute.
+// Location is set when an action of the template failed, and zero otherwise.
+type ErrTemplateExecution struct {
+	Name     string
+	Err      error
+	Location ExecLocation
+}
+
 func (e ErrTemplateExecution) Error() string {
+	if e.Location.Line > 0 {
+		return fmt.Sprintf("failed to execute template '%s' at %s: '%v'", e.Name, e.Location, e.Err)
+	}
 	return fmt.Sprintf("failed to execute template '%s': '%v'", e.Name, e.Err)
 }
 
 func (e ErrTemplateExecution) Unwrap() error {
 	return e.Err
 }
+
+// ErrInvalidOption is returned by NewRegistry when options are invalid
+// or conflict with each other.
+type ErrInvalidOption struct {
+	Options []string
+	Reason  string
+}
+
+func (e ErrInvalidOption) Error() string {
+	return fmt.Sprintf("invalid option %s: %s", strings.Join(e.Options, " + "), e.Reason)
+}
+
+// ErrHTTPStatus lets data functions passed to Handler.HTTP choose the response status for an error.
+type ErrHTTPStatus struct {
+	Status int
+	Err    error
+}
+
+func (e ErrHTTPStatus) Error() string {
+	return fmt.Sprintf("http status %d: '%v'", e.Status, e.Err)
+}
+
+func (e ErrHTTPStatus) Unwrap() error {
+	return e.Err
+}
+
+// ErrDataType is returned when data passed as any is not of the handler's data type.
+type ErrDataType struct {
+	Name string
+	Want string
+	Got  string
+}
+
+func (e ErrDataType) Error() string {
+	return fmt.Sprintf("template '%s' expects data of type %s, got %s", e.Name, e.Want, e.Got)
+}
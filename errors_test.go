@@ -2,6 +2,7 @@ package templator
 
 import (
 	"errors"
+	"io/fs"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,6 +15,21 @@ func TestErrTemplateNotFound_Error(t *testing.T) {
 
 	got := e.Error()
 	assert.Equal(t, "template 'foo' not found", got)
+	assert.ErrorIs(t, e, fs.ErrNotExist)
+}
+
+func TestErrTemplateParse_Error(t *testing.T) {
+	t.Parallel()
+
+	e := newParseError("foo", "foo.html", errors.New("template: foo.html:3: unexpected {{end}}"))
+
+	got := e.Error()
+	assert.Equal(t, "failed to parse template 'foo': 'template: foo.html:3: unexpected {{end}}'", got)
+	assert.Equal(t, 3, e.Line)
+	assert.ErrorIs(t, e, e.Err)
+
+	e = newParseError("foo", "foo.html", errors.New("boom"))
+	assert.Zero(t, e.Line)
 }
 
 func TestErrTemplateExecution_Error(t *testing.T) {
@@ -26,4 +42,41 @@ func TestErrTemplateExecution_Error(t *testing.T) {
 
 	got := e.Error()
 	assert.Equal(t, "failed to execute template 'foo': 'bar'", got)
+
+	e.Location = ExecLocation{File: "foo.html", Line: 3, Column: 2, Snippet: "{{.Bar}}"}
+	assert.Equal(t, `failed to execute template 'foo' at foo.html:3:2 near "{{.Bar}}": 'bar'`, e.Error())
+}
+
+func TestErrInvalidOption_Error(t *testing.T) {
+	t.Parallel()
+
+	e := ErrInvalidOption{
+		Options: []string{"WithFoo", "WithBar"},
+		Reason:  "baz",
+	}
+
+	got := e.Error()
+	assert.Equal(t, "invalid option WithFoo + WithBar: baz", got)
+}
+
+func TestErrHTTPStatus_Error(t *testing.T) {
+	t.Parallel()
+
+	e := ErrHTTPStatus{
+		Status: 404,
+		Err:    errors.New("bar"),
+	}
+
+	got := e.Error()
+	assert.Equal(t, "http status 404: 'bar'", got)
+	assert.ErrorIs(t, e, e.Err)
+}
+
+func TestErrDataType_Error(t *testing.T) {
+	t.Parallel()
+
+	e := ErrDataType{Name: "foo", Want: "main.Foo", Got: "string"}
+
+	got := e.Error()
+	assert.Equal(t, "template 'foo' expects data of type main.Foo, got string", got)
 }
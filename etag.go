@@ -0,0 +1,64 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ExecuteWithETag renders the template into a pooled buffer and responds with it, setting a
+// strong ETag computed from the output. When the request's If-None-Match header matches the
+// ETag, it responds 304 Not Modified without a body instead. The template's cache policy, if
+// any, is applied to the response headers, and Content-Type defaults to HTML. Render errors
+// are returned before anything is written, so callers can still respond with an error page.
+func (h *Handler[T]) ExecuteWithETag(ctx context.Context, w http.ResponseWriter, r *http.Request, data T) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+
+	if err := h.Execute(ctx, buf, data); err != nil {
+		return err
+	}
+
+	etag := outputETag(buf.Bytes())
+
+	header := w.Header()
+	if policy, ok := h.CachePolicy(); ok {
+		policy.Apply(header)
+	}
+	header.Set("ETag", etag)
+
+	if etagMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "text/html; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(w); err != nil {
+		return ErrTemplateExecution{Name: h.file, Err: err}
+	}
+	return nil
+}
+
+// outputETag returns a strong ETag for rendered output.
+func outputETag(out []byte) string {
+	sum := sha256.Sum256(out)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatch reports whether an If-None-Match header matches etag, using the weak
+// comparison the header calls for.
+func etagMatch(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package templator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ExecuteWithETag(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":   &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("<h1>{{.Missing}}</h1>")},
+	}
+
+	reg := MustNewRegistry(fs, WithCachePolicy[TestData]("home", CachePolicy{Public: true, MaxAge: time.Minute}))
+	home := reg.MustGet("home")
+
+	serve := func(t *testing.T, h *Handler[TestData], ifNoneMatch string, data TestData) (*httptest.ResponseRecorder, error) {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		return rec, h.ExecuteWithETag(context.Background(), rec, req, data)
+	}
+
+	first, err := serve(t, home, "", TestData{Title: "hi"})
+	require.NoError(t, err)
+	etag := first.Header().Get("ETag")
+
+	t.Run("responds with output and strong ETag", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Equal(t, "<h1>hi</h1>", first.Body.String())
+		assert.Equal(t, "text/html; charset=utf-8", first.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=60", first.Header().Get("Cache-Control"))
+		assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	})
+
+	t.Run("responds not modified when ETag matches", func(t *testing.T) {
+		t.Parallel()
+
+		for _, ifNoneMatch := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+			rec, err := serve(t, home, ifNoneMatch, TestData{Title: "hi"})
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusNotModified, rec.Code, ifNoneMatch)
+			assert.Empty(t, rec.Body.String())
+			assert.Equal(t, etag, rec.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("responds with new output when it changed", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, home, etag, TestData{Title: "bye"})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "<h1>bye</h1>", rec.Body.String())
+		assert.NotEqual(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("writes nothing on render error", func(t *testing.T) {
+		t.Parallel()
+
+		rec, err := serve(t, reg.MustGet("broken"), "", TestData{})
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		assert.Empty(t, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Body.String())
+	})
+}
@@ -0,0 +1,68 @@
+package templator
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ExecLocation is where in its source file a template failed to execute.
+type ExecLocation struct {
+	// File is the template file, a partial or a layout possibly, e.g. "components/card.html".
+	File string
+	// Line is the line of the file, counting frontmatter and before macro expansion. Column is
+	// the byte offset of the failing node in the line, approximate when a macro rewrote it.
+	Line   int
+	Column int
+	// Expr is the failing expression, e.g. ".User.Name".
+	Expr string
+	// Snippet is the source line of the failing action, trimmed.
+	Snippet string
+}
+
+func (l ExecLocation) String() string {
+	s := l.File + ":" + strconv.Itoa(l.Line) + ":" + strconv.Itoa(l.Column)
+	if l.Snippet != "" {
+		s += " near " + strconv.Quote(l.Snippet)
+	}
+	return s
+}
+
+// execErrorLocation matches the "template: file:line:col: executing "name" at <expr>" prefix
+// of execution errors.
+var execErrorLocation = regexp.MustCompile(`^template: (.+?):(\d+):(\d+): executing ".*?" at <(.*?)>: `)
+
+// execLocation returns the location of an execution error raised by an action of a template,
+// mapped back to the line of the file it came from.
+func (r *Registry[T]) execLocation(err error) (ExecLocation, bool) {
+	var execErr template.ExecError
+	if !errors.As(err, &execErr) {
+		return ExecLocation{}, false
+	}
+
+	m := execErrorLocation.FindStringSubmatch(execErr.Error())
+	if m == nil {
+		return ExecLocation{}, false
+	}
+
+	line, _ := strconv.Atoi(m[2])
+	column, _ := strconv.Atoi(m[3])
+	name := strings.TrimSuffix(m[1], string(r.config.ext))
+
+	parseErr := r.locate(ErrTemplateParse{Name: name, Line: line})
+	loc := ExecLocation{
+		File:   parseErr.Name + string(r.config.ext),
+		Line:   parseErr.Line,
+		Column: column,
+		Expr:   m[4],
+	}
+
+	if content, err := r.readRaw(parseErr.Name, false); err == nil {
+		if lines := strings.Split(string(content), "\n"); loc.Line <= len(lines) {
+			loc.Snippet = strings.TrimSpace(lines[loc.Line-1])
+		}
+	}
+	return loc, true
+}
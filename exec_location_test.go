@@ -0,0 +1,81 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type locationData struct {
+	User  *struct{ Name string }
+	Count map[string]int
+}
+
+func TestHandler_Execute_ErrorLocation(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/page.html": &fstest.MapFile{Data: []byte("---\ntitle: Page\n---\n<main>\n  <p>{{.User.Name}}</p>\n</main>")},
+		"templates/list.html": &fstest.MapFile{Data: []byte("<ul>\n{{template \"components/item.html\" .}}\n</ul>")},
+		"templates/macro.html": &fstest.MapFile{
+			Data: []byte("@include \"components/header\"\n<p>{{.User.Name}}</p>"),
+		},
+		"templates/components/item.html":   &fstest.MapFile{Data: []byte("<li>\n  {{index .Count 1}}\n</li>")},
+		"templates/components/header.html": &fstest.MapFile{Data: []byte("<header>\n</header>")},
+	}
+
+	reg := MustNewRegistry(fs, WithPartials[locationData]("components/*"), WithMacros[locationData]())
+
+	tests := []struct {
+		name     string
+		template string
+		want     ExecLocation
+	}{
+		{
+			name:     "counts frontmatter lines",
+			template: "page",
+			want:     ExecLocation{File: "page.html", Line: 5, Column: 12, Expr: ".User.Name", Snippet: "<p>{{.User.Name}}</p>"},
+		},
+		{
+			name:     "points into partials",
+			template: "list",
+			want:     ExecLocation{File: "components/item.html", Line: 2, Column: 4, Expr: "index .Count 1", Snippet: "{{index .Count 1}}"},
+		},
+		{
+			name:     "maps macro expanded lines",
+			template: "macro",
+			want:     ExecLocation{File: "macro.html", Line: 2, Column: 10, Expr: ".User.Name", Snippet: "<p>{{.User.Name}}</p>"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := reg.MustGet(tc.template).Execute(context.Background(), new(bytes.Buffer), locationData{})
+
+			var execErr ErrTemplateExecution
+			require.ErrorAs(t, err, &execErr)
+			assert.Equal(t, tc.want, execErr.Location)
+			assert.Contains(t, err.Error(), tc.want.String())
+		})
+	}
+
+	t.Run("zero for errors outside actions", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := reg.execLocation(errors.New("write failed"))
+		assert.False(t, ok)
+
+		err := reg.MustGet("page").Execute(context.Background(), failingWriter{err: errors.New("disk full")}, locationData{User: &struct{ Name string }{"Ada"}})
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		assert.Zero(t, execErr.Location)
+	})
+}
@@ -0,0 +1,116 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrFallbackRendered is returned by Execute when the template failed and the error template
+// was rendered into the writer in its place. Err is the error of the failed render.
+type ErrFallbackRendered struct {
+	Name     string
+	Fallback string
+	Err      error
+}
+
+func (e ErrFallbackRendered) Error() string {
+	return fmt.Sprintf("rendered error template '%s' in place of '%s': %v", e.Fallback, e.Name, e.Err)
+}
+
+func (e ErrFallbackRendered) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorTemplate returns an Option that renders the named template, e.g. "errors/500",
+// with the same data when Execute fails, so the writer receives an error page instead of a
+// half-written one. Execute then returns ErrFallbackRendered, and Handler.HTTP responds
+// with the error page and status 500. It cannot be combined with WithUnbufferedWrites;
+// streamed renders, which are never buffered, follow their partial output with the error page.
+func WithErrorTemplate[T any](name string) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.errorTemplate = name
+	}
+}
+
+// WithBufferedWrites returns an Option that renders Execute output into a pooled buffer and
+// copies it to the writer only when rendering succeeds, so failed renders write nothing.
+// This is the default; the option undoes WithUnbufferedWrites.
+func WithBufferedWrites[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.unbufferedWrites = false
+	}
+}
+
+// WithUnbufferedWrites returns an Option that makes Execute write output to the writer as it
+// renders, saving the copy of buffered writes at the cost of leaving partial output behind
+// when rendering fails. It cannot be combined with WithErrorTemplate.
+func WithUnbufferedWrites[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.unbufferedWrites = true
+	}
+}
+
+// renderOrFallback runs render into w, through a pooled buffer when buffered is set, and
+// renders the error template in its place when it fails. Renders into a bytes.Buffer write
+// to it directly, since what they wrote can be taken back.
+func (h *Handler[T]) renderOrFallback(ctx context.Context, w io.Writer, data T, buffered bool, render func(io.Writer) error) error {
+	cfg := &h.reg.config
+	if !buffered && cfg.errorTemplate == "" {
+		return render(w)
+	}
+
+	target := w
+	buf, direct := w.(*bytes.Buffer)
+	if buffered && !direct {
+		buf = bufferPool.Get().(*bytes.Buffer)
+		defer putBuffer(buf)
+		target = buf
+	}
+
+	var mark int
+	if buf != nil {
+		mark = buf.Len()
+	}
+
+	err := render(target)
+	if err != nil {
+		if buffered {
+			buf.Truncate(mark)
+		}
+		// Canceled renders have no client left to show an error page to.
+		if cfg.errorTemplate == "" || h.name == cfg.errorTemplate || ctx.Err() != nil {
+			return err
+		}
+		if buf != nil {
+			buf.Truncate(mark)
+		}
+		if fallbackErr := h.renderFallback(ctx, target, data); fallbackErr != nil {
+			return errors.Join(err, fallbackErr)
+		}
+		err = ErrFallbackRendered{Name: h.name, Fallback: cfg.errorTemplate, Err: err}
+	}
+
+	if target != w {
+		_, writeErr := buf.WriteTo(w)
+		// As for unbuffered renders, cancellation during the write takes precedence.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ErrTemplateExecution{Name: h.file, Err: ctxErr}
+		}
+		if writeErr != nil {
+			return ErrTemplateExecution{Name: h.file, Err: writeErr}
+		}
+	}
+	return err
+}
+
+// renderFallback renders the error template into w. It skips hooks and caching, like Replay.
+func (h *Handler[T]) renderFallback(ctx context.Context, w io.Writer, data T) error {
+	fallback, err := h.reg.Get(h.reg.config.errorTemplate)
+	if err != nil {
+		return err
+	}
+	return fallback.execute(ctx, w, data)
+}
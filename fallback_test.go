@@ -0,0 +1,180 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithErrorTemplate(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":       &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>{{.Missing}}")},
+		"templates/ok.html":         &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		"templates/errors/500.html": &fstest.MapFile{Data: []byte("<p>Sorry</p>")},
+		"templates/errors/bad.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+	}
+
+	t.Run("replaces the failed render in buffers", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithErrorTemplate[TestData]("errors/500"))
+
+		buf := bytes.NewBufferString("<!-- head -->")
+		err := reg.MustGet("home").Execute(context.Background(), buf, TestData{Title: "hi"})
+
+		var fallbackErr ErrFallbackRendered
+		require.ErrorAs(t, err, &fallbackErr)
+		assert.Equal(t, "home", fallbackErr.Name)
+		assert.Equal(t, "errors/500", fallbackErr.Fallback)
+		assert.ErrorAs(t, err, new(ErrTemplateExecution))
+		assert.Equal(t, "<!-- head --><p>Sorry</p>", buf.String())
+	})
+
+	t.Run("follows partial output of streams", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithErrorTemplate[TestData]("errors/500"))
+
+		rec := httptest.NewRecorder()
+		err := reg.MustGet("home").ExecuteStream(context.Background(), rec, TestData{Title: "hi"})
+		require.ErrorAs(t, err, new(ErrFallbackRendered))
+		assert.Equal(t, "<h1>hi</h1><p>Sorry</p>", rec.Body.String())
+	})
+
+	t.Run("replaces the page with buffered writes", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithErrorTemplate[TestData]("errors/500"), WithBufferedWrites[TestData]())
+
+		rec := httptest.NewRecorder()
+		err := reg.MustGet("home").Execute(context.Background(), rec, TestData{Title: "hi"})
+		require.ErrorAs(t, err, new(ErrFallbackRendered))
+		assert.Equal(t, "<p>Sorry</p>", rec.Body.String())
+
+		rec = httptest.NewRecorder()
+		require.NoError(t, reg.MustGet("ok").Execute(context.Background(), rec, TestData{Title: "hi"}))
+		assert.Equal(t, "<h1>hi</h1>", rec.Body.String())
+	})
+
+	t.Run("returns both errors when the error template fails", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithErrorTemplate[TestData]("errors/bad"), WithBufferedWrites[TestData]())
+
+		rec := httptest.NewRecorder()
+		err := reg.MustGet("home").Execute(context.Background(), rec, TestData{Title: "hi"})
+		require.Error(t, err)
+		assert.NotErrorAs(t, err, new(ErrFallbackRendered))
+		assert.Contains(t, err.Error(), "errors/bad")
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("skips canceled renders", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithErrorTemplate[TestData]("errors/500"))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var buf bytes.Buffer
+		err := reg.MustGet("home").Execute(ctx, &buf, TestData{Title: "hi"})
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("responds with the error page over HTTP", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs,
+			WithErrorTemplate[TestData]("errors/500"),
+			WithCachePolicy[TestData]("home", CachePolicy{Public: true, MaxAge: time.Minute}),
+		)
+
+		rec := httptest.NewRecorder()
+		reg.MustGet("home").HTTP(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "<p>Sorry</p>", rec.Body.String())
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+	})
+}
+
+func TestBufferedWrites(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>{{.Missing}}")},
+		"templates/ok.html":   &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}
+
+	t.Run("are the default", func(t *testing.T) {
+		t.Parallel()
+
+		for _, reg := range []*Registry[TestData]{
+			MustNewRegistry[TestData](fs),
+			MustNewRegistry(fs, WithUnbufferedWrites[TestData](), WithBufferedWrites[TestData]()),
+		} {
+			rec := httptest.NewRecorder()
+			err := reg.MustGet("home").Execute(context.Background(), rec, TestData{Title: "hi"})
+			require.ErrorAs(t, err, new(ErrTemplateExecution))
+			assert.Empty(t, rec.Body.String(), "failed renders write nothing")
+
+			rec = httptest.NewRecorder()
+			require.NoError(t, reg.MustGet("ok").Execute(context.Background(), rec, TestData{Title: "hi"}))
+			assert.Equal(t, "<h1>hi</h1>", rec.Body.String())
+		}
+	})
+
+	t.Run("take failed output back from buffers", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+
+		buf := bytes.NewBufferString("<!-- head -->")
+		err := reg.MustGet("home").Execute(context.Background(), buf, TestData{Title: "hi"})
+		require.Error(t, err)
+		assert.Equal(t, "<!-- head -->", buf.String())
+	})
+
+	t.Run("report writer errors", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+
+		err := reg.MustGet("ok").Execute(context.Background(), failingWriter{err: errors.New("broken pipe")}, TestData{Title: "hi"})
+		require.ErrorContains(t, err, "broken pipe")
+	})
+
+	t.Run("can be disabled", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithUnbufferedWrites[TestData]())
+
+		rec := httptest.NewRecorder()
+		err := reg.MustGet("home").Execute(context.Background(), rec, TestData{Title: "hi"})
+		require.Error(t, err)
+		assert.Equal(t, "<h1>hi</h1>", rec.Body.String())
+	})
+
+	t.Run("are skipped by streams", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+
+		rec := httptest.NewRecorder()
+		err := reg.MustGet("home").ExecuteStream(context.Background(), rec, TestData{Title: "hi"})
+		require.Error(t, err)
+		assert.Equal(t, "<h1>hi</h1>", rec.Body.String())
+	})
+}
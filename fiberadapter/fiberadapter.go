@@ -0,0 +1,65 @@
+// Package fiberadapter exposes a templator Registry as a Fiber template engine.
+//
+// Engine implements Fiber's Views interface:
+//
+//	app := fiber.New(fiber.Config{
+//		Views: fiberadapter.New(reg),
+//	})
+//
+//	app.Get("/", func(c *fiber.Ctx) error {
+//		return c.Render("home", HomeData{Title: "Welcome"})
+//	})
+package fiberadapter
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/alesr/templator"
+	"github.com/gofiber/fiber/v2"
+)
+
+var _ fiber.Views = (*Engine[any])(nil)
+
+// ErrLayoutsNotSupported is returned by Render when Fiber passes layout names.
+// Declare layouts in the templates instead, with a layout pragma or frontmatter key.
+var ErrLayoutsNotSupported = errors.New("fiberadapter: layouts are not supported")
+
+// Engine renders Fiber views from a templator Registry.
+type Engine[T any] struct {
+	reg *templator.Registry[T]
+}
+
+// New creates an Engine backed by the given registry.
+func New[T any](reg *templator.Registry[T]) *Engine[T] {
+	return &Engine[T]{reg: reg}
+}
+
+// Load parses every template of the registry so errors surface when Fiber starts.
+func (e *Engine[T]) Load() error {
+	names, err := e.reg.ListTemplates()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if _, err := e.reg.Get(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render renders the named template with data, which must be a T.
+func (e *Engine[T]) Render(w io.Writer, name string, data any, layouts ...string) error {
+	if len(layouts) > 0 {
+		return ErrLayoutsNotSupported
+	}
+
+	h, err := e.reg.Get(name)
+	if err != nil {
+		return err
+	}
+	return h.ExecuteAny(context.Background(), w, data)
+}
@@ -0,0 +1,67 @@
+package fiberadapter
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageData struct {
+	Title string
+}
+
+func TestEngine(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}
+
+	reg, err := templator.NewRegistry[pageData](fs)
+	require.NoError(t, err)
+
+	engine := New(reg)
+	require.NoError(t, engine.Load())
+
+	t.Run("renders template", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := engine.Render(&buf, "home", pageData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>hi</h1>", buf.String())
+	})
+
+	t.Run("rejects wrong data type", func(t *testing.T) {
+		t.Parallel()
+
+		err := engine.Render(&bytes.Buffer{}, "home", map[string]string{"Title": "hi"})
+
+		var dataErr templator.ErrDataType
+		require.ErrorAs(t, err, &dataErr)
+	})
+
+	t.Run("rejects layouts", func(t *testing.T) {
+		t.Parallel()
+
+		err := engine.Render(&bytes.Buffer{}, "home", pageData{}, "layouts/main")
+		require.ErrorIs(t, err, ErrLayoutsNotSupported)
+	})
+}
+
+func TestEngine_LoadError(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/broken.html": &fstest.MapFile{Data: []byte("{{end}}")},
+	}
+
+	reg, err := templator.NewRegistry[pageData](fs)
+	require.NoError(t, err)
+
+	require.Error(t, New(reg).Load())
+}
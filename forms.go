@@ -0,0 +1,200 @@
+package templator
+
+import (
+	"fmt"
+	"html/template"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocaleFormat describes how a locale writes the values of form inputs.
+type LocaleFormat struct {
+	// Decimal and Group are the decimal and digit group separators, e.g. "," and "." in German.
+	Decimal string
+	Group   string
+	// DateLayout is the time layout of dates, e.g. "02.01.2006" in German.
+	DateLayout string
+	// CurrencyAfter writes currency symbols after amounts, as in "1.234,56 €".
+	CurrencyAfter bool
+}
+
+// DefaultLocaleFormats are the formats WithLocaleFormats starts from, by locale. Locales
+// without a format of their own use their language's, and then "en"'s.
+var DefaultLocaleFormats = map[string]LocaleFormat{
+	"en":    {Decimal: ".", Group: ",", DateLayout: "01/02/2006"},
+	"en-GB": {Decimal: ".", Group: ",", DateLayout: "02/01/2006"},
+	"de":    {Decimal: ",", Group: ".", DateLayout: "02.01.2006", CurrencyAfter: true},
+	"es":    {Decimal: ",", Group: ".", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"fr":    {Decimal: ",", Group: "\u202f", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"it":    {Decimal: ",", Group: ".", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"nl":    {Decimal: ",", Group: ".", DateLayout: "02-01-2006"},
+	"pt":    {Decimal: ",", Group: "\u00a0", DateLayout: "02/01/2006", CurrencyAfter: true},
+	"pt-BR": {Decimal: ",", Group: ".", DateLayout: "02/01/2006"},
+	"ja":    {Decimal: ".", Group: ",", DateLayout: "2006/01/02"},
+}
+
+// currencySymbols are the symbols FormatCurrency writes instead of currency codes.
+var currencySymbols = map[string]string{
+	"BRL": "R$", "EUR": "€", "GBP": "£", "JPY": "¥", "USD": "$",
+}
+
+// zeroDecimalCurrencies are the currencies without minor units.
+var zeroDecimalCurrencies = map[string]bool{"JPY": true, "KRW": true}
+
+// FormatDecimal formats v with places decimals, e.g. "1.234,50" in German.
+func (f LocaleFormat) FormatDecimal(v float64, places int) string {
+	s := strconv.FormatFloat(v, 'f', places, 64)
+
+	sign := ""
+	if s[0] == '-' {
+		sign, s = "-", s[1:]
+	}
+
+	digits, fraction, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(f.Group)
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(f.Decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// ParseDecimal parses a number written as FormatDecimal writes it, with or without group
+// separators. Spaces, including no-break ones, are accepted as group separators in every locale.
+func (f LocaleFormat) ParseDecimal(s string) (float64, error) {
+	cleaned := strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(strings.TrimSpace(s))
+	if f.Group != "" {
+		cleaned = strings.ReplaceAll(cleaned, f.Group, "")
+	}
+	cleaned = strings.Replace(cleaned, f.Decimal, ".", 1)
+
+	v, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid decimal '%s'", s)
+	}
+	return v, nil
+}
+
+// FormatDate formats the date of t, e.g. "31.12.2025" in German.
+func (f LocaleFormat) FormatDate(t time.Time) string {
+	return t.Format(f.DateLayout)
+}
+
+// ParseDate parses a date written as FormatDate writes it.
+func (f LocaleFormat) ParseDate(s string) (time.Time, error) {
+	t, err := time.Parse(f.DateLayout, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s': expected %s", s, f.datePattern())
+	}
+	return t, nil
+}
+
+// FormatCurrency formats an amount of the currency with the given ISO 4217 code, e.g.
+// "1.234,50 €" in German. Currencies without a known symbol are written with their code.
+func (f LocaleFormat) FormatCurrency(amount float64, code string) string {
+	places := 2
+	if zeroDecimalCurrencies[code] {
+		places = 0
+	}
+
+	symbol, ok := currencySymbols[code]
+	if !ok {
+		symbol = code + "\u00a0"
+	}
+
+	number := f.FormatDecimal(amount, places)
+	if f.CurrencyAfter {
+		return number + "\u00a0" + strings.TrimSpace(symbol)
+	}
+	return symbol + number
+}
+
+// datePattern returns the date layout in the notation of date pickers, e.g. "DD.MM.YYYY".
+func (f LocaleFormat) datePattern() string {
+	return strings.NewReplacer("2006", "YYYY", "01", "MM", "02", "DD").Replace(f.DateLayout)
+}
+
+// InputHints returns the attributes of a form input of the given kind, "decimal", "currency"
+// or "date", telling scripts how the locale writes its value:
+//
+//	inputmode="decimal" data-decimal-separator="," data-group-separator="."
+//	data-date-format="DD.MM.YYYY"
+func (f LocaleFormat) InputHints(kind string) (template.HTMLAttr, error) {
+	var attrs []string
+	switch kind {
+	case "decimal", "currency":
+		attrs = append(attrs,
+			`inputmode="decimal"`,
+			`data-decimal-separator="`+template.HTMLEscapeString(f.Decimal)+`"`,
+			`data-group-separator="`+template.HTMLEscapeString(f.Group)+`"`,
+		)
+	case "date":
+		attrs = append(attrs, `data-date-format="`+template.HTMLEscapeString(f.datePattern())+`"`)
+	default:
+		return "", fmt.Errorf("unknown input kind '%s': expected decimal, currency, or date", kind)
+	}
+	return template.HTMLAttr(strings.Join(attrs, " ")), nil
+}
+
+// WithLocaleFormats returns an Option that installs template functions formatting form input
+// values in the locale carried by the rendering context (see WithLocale), and the hints
+// scripts need to parse them back:
+//
+//	<input name="price" value="{{formatDecimal .Price 2}}" {{inputHints "decimal"}}>
+//	<input name="due" value="{{formatDate .Due}}" {{inputHints "date"}}>
+//	<span>{{formatCurrency .Total "EUR"}}</span>
+//
+// formats add to or replace DefaultLocaleFormats. Parse submitted values with the same
+// format, from Registry.LocaleFormat. As with WithTranslator, each handler keeps one copy
+// of its template per locale.
+func WithLocaleFormats[T any](formats map[string]LocaleFormat) Option[T] {
+	return func(r *Registry[T]) {
+		if r.config.localeFormats == nil {
+			r.config.localeFormats = maps.Clone(DefaultLocaleFormats)
+		}
+		maps.Copy(r.config.localeFormats, formats)
+		WithTemplateFuncs[T](localeFormatFuncs(DefaultLocaleFormats["en"]))(r)
+	}
+}
+
+// LocaleFormat returns the format of locale: its own, its language's, or else "en"'s.
+func (r *Registry[T]) LocaleFormat(locale string) LocaleFormat {
+	formats := r.config.localeFormats
+	if formats == nil {
+		formats = DefaultLocaleFormats
+	}
+
+	locale = strings.ReplaceAll(locale, "_", "-")
+	for {
+		for name, f := range formats {
+			if strings.EqualFold(name, locale) {
+				return f
+			}
+		}
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			return formats["en"]
+		}
+		locale = locale[:i]
+	}
+}
+
+// localeFormatFuncs returns the form input template functions of f.
+func localeFormatFuncs(f LocaleFormat) template.FuncMap {
+	return template.FuncMap{
+		"formatDecimal":  f.FormatDecimal,
+		"formatDate":     f.FormatDate,
+		"formatCurrency": f.FormatCurrency,
+		"inputHints":     f.InputHints,
+	}
+}
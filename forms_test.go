@@ -0,0 +1,133 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleFormat(t *testing.T) {
+	t.Parallel()
+
+	de := DefaultLocaleFormats["de"]
+	en := DefaultLocaleFormats["en"]
+	fr := DefaultLocaleFormats["fr"]
+
+	t.Run("formats and parses decimals", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			format LocaleFormat
+			v      float64
+			places int
+			want   string
+		}{
+			{en, 1234567.891, 2, "1,234,567.89"},
+			{de, 1234.5, 2, "1.234,50"},
+			{de, -1234.5, 0, "-1.234"},
+			{fr, 1234.5, 1, "1 234,5"},
+			{en, 12, 0, "12"},
+			{en, 0.5, 3, "0.500"},
+		}
+		for _, tc := range tests {
+			got := tc.format.FormatDecimal(tc.v, tc.places)
+			assert.Equal(t, tc.want, got)
+
+			parsed, err := tc.format.ParseDecimal(got)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.v, parsed, 0.5)
+		}
+
+		v, err := de.ParseDecimal(" 1\u00a0234,5 ")
+		require.NoError(t, err)
+		assert.InDelta(t, 1234.5, v, 1e-9)
+
+		_, err = de.ParseDecimal("1,2,3")
+		assert.ErrorContains(t, err, "invalid decimal '1,2,3'")
+	})
+
+	t.Run("formats and parses dates", func(t *testing.T) {
+		t.Parallel()
+
+		date := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+		assert.Equal(t, "31.12.2025", de.FormatDate(date))
+		assert.Equal(t, "12/31/2025", en.FormatDate(date))
+
+		parsed, err := de.ParseDate("31.12.2025")
+		require.NoError(t, err)
+		assert.Equal(t, date, parsed)
+
+		_, err = de.ParseDate("12/31/2025")
+		assert.ErrorContains(t, err, "expected DD.MM.YYYY")
+	})
+
+	t.Run("formats currencies", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "1.234,50 €", de.FormatCurrency(1234.5, "EUR"))
+		assert.Equal(t, "$1,234.50", en.FormatCurrency(1234.5, "USD"))
+		assert.Equal(t, "¥1,235", en.FormatCurrency(1234.6, "JPY"))
+		assert.Equal(t, "CHF 1,234.50", en.FormatCurrency(1234.5, "CHF"))
+		assert.Equal(t, "1.234,50 CHF", de.FormatCurrency(1234.5, "CHF"))
+	})
+
+	t.Run("returns input hints", func(t *testing.T) {
+		t.Parallel()
+
+		hints, err := de.InputHints("currency")
+		require.NoError(t, err)
+		assert.Equal(t, template.HTMLAttr(`inputmode="decimal" data-decimal-separator="," data-group-separator="."`), hints)
+
+		hints, err = de.InputHints("date")
+		require.NoError(t, err)
+		assert.Equal(t, template.HTMLAttr(`data-date-format="DD.MM.YYYY"`), hints)
+
+		_, err = de.InputHints("color")
+		assert.ErrorContains(t, err, "unknown input kind 'color'")
+	})
+}
+
+func TestWithLocaleFormats(t *testing.T) {
+	t.Parallel()
+
+	type invoice struct {
+		Total float64
+		Due   time.Time
+	}
+
+	fs := fstest.MapFS{
+		"templates/form.html": &fstest.MapFile{Data: []byte(
+			`<input value="{{formatDecimal .Total 2}}" {{inputHints "decimal"}}>` +
+				`<input value="{{formatDate .Due}}" {{inputHints "date"}}>{{formatCurrency .Total "EUR"}}`,
+		)},
+	}
+	reg := MustNewRegistry(fs, WithLocaleFormats[invoice](map[string]LocaleFormat{
+		"de-CH": {Decimal: ".", Group: "'", DateLayout: "02.01.2006", CurrencyAfter: true},
+	}))
+	form := reg.MustGet("form")
+	data := invoice{Total: 1234.5, Due: time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)}
+
+	tests := map[string]string{
+		"de-AT": `<input value="1.234,50" inputmode="decimal" data-decimal-separator="," data-group-separator=".">` +
+			`<input value="31.12.2025" data-date-format="DD.MM.YYYY">` + "1.234,50\u00a0€",
+		"de-CH": `<input value="1&#39;234.50" inputmode="decimal" data-decimal-separator="." data-group-separator="&#39;">` +
+			`<input value="31.12.2025" data-date-format="DD.MM.YYYY">` + "1&#39;234.50\u00a0€",
+		"xx": `<input value="1,234.50" inputmode="decimal" data-decimal-separator="." data-group-separator=",">` +
+			`<input value="12/31/2025" data-date-format="MM/DD/YYYY">€1,234.50`,
+	}
+	for locale, want := range tests {
+		var buf bytes.Buffer
+		require.NoError(t, form.Execute(WithLocale(context.Background(), locale), &buf, data), locale)
+		assert.Equal(t, want, buf.String(), locale)
+	}
+
+	v, err := reg.LocaleFormat("de_CH").ParseDecimal("1'234.50")
+	require.NoError(t, err)
+	assert.InDelta(t, 1234.5, v, 1e-9)
+}
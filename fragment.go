@@ -0,0 +1,74 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+)
+
+// FragmentMode selects what ExecuteFragment does when a fragment fails to render.
+type FragmentMode int
+
+const (
+	// FragmentFail returns the error, failing the page. It is the default.
+	FragmentFail FragmentMode = iota
+	// FragmentPlaceholder renders the policy's placeholder instead of the fragment.
+	FragmentPlaceholder
+	// FragmentEmpty renders nothing in place of the fragment.
+	FragmentEmpty
+)
+
+// FragmentPolicy describes how a non-critical fragment, such as an ads or
+// recommendations widget, degrades when it fails to render.
+type FragmentPolicy struct {
+	Mode        FragmentMode
+	Placeholder template.HTML
+	// OnError, if set, is called with every error the policy swallows,
+	// so failures stay observable.
+	OnError func(name string, err error)
+}
+
+// WithFragmentPolicy returns an Option that sets the error policy applied by
+// ExecuteFragment to the named template.
+func WithFragmentPolicy[T any](name string, policy FragmentPolicy) Option[T] {
+	return func(r *Registry[T]) {
+		if r.config.fragmentPolicies == nil {
+			r.config.fragmentPolicies = make(map[string]FragmentPolicy)
+		}
+		r.config.fragmentPolicies[name] = policy
+	}
+}
+
+// ExecuteFragment renders the template as a fragment of a larger page and returns the output
+// as template.HTML, ready to be embedded in the page's data. Rendering stops with
+// ErrBudgetExceeded once the context's render budget runs out. When rendering fails, the
+// fragment policy configured for the template decides whether the error is returned or
+// replaced by a placeholder or by empty output.
+func (h *Handler[T]) ExecuteFragment(ctx context.Context, data T) (template.HTML, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+
+	if h.reg.config.trace && ctx != nil {
+		ctx = context.WithValue(ctx, traceFragmentKey{}, true)
+	}
+	if err := h.Execute(ctx, buf, data); err != nil {
+		return h.fragmentFallback(err)
+	}
+	return template.HTML(buf.String()), nil
+}
+
+func (h *Handler[T]) fragmentFallback(err error) (template.HTML, error) {
+	policy := h.reg.config.fragmentPolicies[h.name]
+	if policy.Mode == FragmentFail {
+		return "", err
+	}
+
+	if policy.OnError != nil {
+		policy.OnError(h.name, err)
+	}
+
+	if policy.Mode == FragmentPlaceholder {
+		return policy.Placeholder, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,67 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ExecuteFragment(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/widgets/ok.html":          &fstest.MapFile{Data: []byte("<aside>{{.Title}}</aside>")},
+		"templates/widgets/critical.html":    &fstest.MapFile{Data: []byte("{{.Missing}}")},
+		"templates/widgets/ads.html":         &fstest.MapFile{Data: []byte("{{.Missing}}")},
+		"templates/widgets/recommended.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+	}
+
+	var swallowed []string
+	reg, err := NewRegistry(fs,
+		WithFragmentPolicy[TestData]("widgets/ads", FragmentPolicy{
+			Mode:        FragmentPlaceholder,
+			Placeholder: `<div class="ads-placeholder"></div>`,
+			OnError: func(name string, err error) {
+				swallowed = append(swallowed, name)
+			},
+		}),
+		WithFragmentPolicy[TestData]("widgets/recommended", FragmentPolicy{Mode: FragmentEmpty}),
+	)
+	require.NoError(t, err)
+
+	execute := func(name string) (template.HTML, error) {
+		h, err := reg.Get(name)
+		require.NoError(t, err)
+		return h.ExecuteFragment(context.Background(), TestData{Title: "hi"})
+	}
+
+	got, err := execute("widgets/ok")
+	require.NoError(t, err)
+	assert.Equal(t, template.HTML("<aside>hi</aside>"), got)
+
+	got, err = execute("widgets/critical")
+	require.Error(t, err, "fragments fail the page by default")
+	assert.Empty(t, got)
+
+	got, err = execute("widgets/ads")
+	require.NoError(t, err)
+	assert.Equal(t, template.HTML(`<div class="ads-placeholder"></div>`), got)
+	assert.Equal(t, []string{"widgets/ads"}, swallowed)
+
+	got, err = execute("widgets/recommended")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestWithFragmentPolicy_InvalidMode(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry(fstest.MapFS{}, WithFragmentPolicy[TestData]("ads", FragmentPolicy{Mode: 42}))
+	require.Error(t, err)
+	assert.Nil(t, reg)
+	assert.Contains(t, err.Error(), "unknown fragment mode 42")
+}
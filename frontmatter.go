@@ -0,0 +1,216 @@
+package templator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	yamlFence = "---"
+	tomlFence = "+++"
+)
+
+// ErrInvalidFrontmatter is returned when the frontmatter of a template cannot be parsed.
+var ErrInvalidFrontmatter = errors.New("invalid frontmatter")
+
+// splitFrontmatter splits the YAML frontmatter fenced by --- lines, or the TOML frontmatter
+// fenced by +++ lines, from the top of a template source. It returns the frontmatter values,
+// the remaining source, and the number of lines removed. Sources without frontmatter are
+// returned as is.
+func splitFrontmatter(name string, content []byte) (Metadata, []byte, int, error) {
+	var fence string
+	switch first, _, _ := bytes.Cut(content, []byte("\n")); string(bytes.TrimRight(first, " \t\r")) {
+	case yamlFence:
+		fence = yamlFence
+	case tomlFence:
+		fence = tomlFence
+	default:
+		return nil, content, 0, nil
+	}
+
+	var (
+		lines = strings.SplitAfter(string(content), "\n")
+		end   = -1
+	)
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t\r\n") == fence {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, nil, 0, fmt.Errorf("template '%s': %w: missing closing %s", name, ErrInvalidFrontmatter, fence)
+	}
+
+	var (
+		md   = make(Metadata)
+		body = strings.Join(lines[1:end], "")
+		err  error
+	)
+	if fence == yamlFence {
+		// Decoded into a plain map, so nested mappings are map[string]any rather than Metadata.
+		var values map[string]any
+		err = yaml.Unmarshal([]byte(body), &values)
+		maps.Copy(md, values)
+	} else {
+		err = parseTOML(body, md)
+	}
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("template '%s': %w: %w", name, ErrInvalidFrontmatter, err)
+	}
+
+	for key, value := range md {
+		md[key] = normalizeValue(value)
+	}
+	return md, []byte(strings.Join(lines[end+1:], "")), end + 1, nil
+}
+
+// frontmatters holds the frontmatter of the last source read for each template.
+type frontmatters struct {
+	mu     sync.RWMutex
+	values map[string]Metadata
+}
+
+func (f *frontmatters) get(name string) Metadata {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.values[name]
+}
+
+func (f *frontmatters) set(name string, md Metadata) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.values == nil {
+		f.values = make(map[string]Metadata)
+	}
+	f.values[name] = md
+}
+
+// sourceInfo is what processing learnt about a template source: its frontmatter, the number
+// of frontmatter lines removed from the top of the file, and with macros, the origin of every
+// line of the expanded source.
+type sourceInfo struct {
+	frontmatter Metadata
+	offset      int
+	lines       []sourceLine
+}
+
+// storeSource records what processing learnt about the live source of the named template,
+// for metadata lookups and to map parse and execution errors back to the file.
+func (r *Registry[T]) storeSource(name string, src sourceInfo) {
+	r.frontmatter.set(name, src.frontmatter)
+
+	r.sourceMaps.mu.Lock()
+	defer r.sourceMaps.mu.Unlock()
+
+	if r.sourceMaps.offsets == nil {
+		r.sourceMaps.offsets = make(map[string]int)
+	}
+	r.sourceMaps.offsets[name] = src.offset
+
+	if r.config.macros {
+		if r.sourceMaps.lines == nil {
+			r.sourceMaps.lines = make(map[string][]sourceLine)
+		}
+		r.sourceMaps.lines[name] = src.lines
+	}
+}
+
+// normalizeValue converts the integers decoded from YAML to int64, matching pragma values.
+func normalizeValue(value any) any {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case []any:
+		for i := range v {
+			v[i] = normalizeValue(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = normalizeValue(v[key])
+		}
+	}
+	return value
+}
+
+// parseTOML adds the key = value pairs of a TOML document to md. It supports the subset of
+// TOML used for frontmatter: strings, booleans, numbers, and single-line arrays of those.
+func parseTOML(doc string, md Metadata) error {
+	for i, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			return fmt.Errorf("line %d: tables are not supported", i+1)
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		key = strings.Trim(strings.TrimSpace(key), `"`)
+		if !ok || key == "" {
+			return fmt.Errorf("line %d: expected key = value", i+1)
+		}
+
+		value, rest, err := tomlValue(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if rest = strings.TrimSpace(rest); rest != "" && rest[0] != '#' {
+			return fmt.Errorf("line %d: unexpected '%s'", i+1, rest)
+		}
+		md[key] = value
+	}
+	return nil
+}
+
+// tomlValue parses the TOML value at the start of s, returning it and the rest of s.
+func tomlValue(s string) (any, string, error) {
+	switch {
+	case s == "":
+		return nil, "", errors.New("missing value")
+	case s[0] == '"':
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return nil, "", errors.New("unterminated string")
+		}
+		value, _ := strconv.Unquote(quoted)
+		return value, s[len(quoted):], nil
+	case s[0] == '\'':
+		value, rest, ok := strings.Cut(s[1:], "'")
+		if !ok {
+			return nil, "", errors.New("unterminated string")
+		}
+		return value, rest, nil
+	case s[0] == '[':
+		values := []any{}
+		s = strings.TrimSpace(s[1:])
+		for !strings.HasPrefix(s, "]") {
+			value, rest, err := tomlValue(s)
+			if err != nil {
+				return nil, "", err
+			}
+			values = append(values, value)
+
+			s = strings.TrimSpace(rest)
+			if after, ok := strings.CutPrefix(s, ","); ok {
+				s = strings.TrimSpace(after)
+			} else if !strings.HasPrefix(s, "]") {
+				return nil, "", errors.New("unterminated array")
+			}
+		}
+		return values, s[1:], nil
+	}
+
+	end := strings.IndexAny(s, " \t,]#")
+	if end < 0 {
+		end = len(s)
+	}
+	return pragmaValue(s[:end]), s[end:], nil
+}
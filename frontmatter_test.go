@@ -0,0 +1,165 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrontmatter(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(
+			"---\ntitle: Home page\ncache:\n  max_age: 60\nrequired: [Title]\n---\n" +
+				"{{/* templator: title=\"Overridden\" */}}<h1>{{.Title}}</h1>",
+		)},
+		"templates/about.html": &fstest.MapFile{Data: []byte(
+			"+++\n# page settings\ntitle = \"About\"\nsection = 'docs'\nweight = 3 # sidebar order\nrequired = [\"Title\", \"Content\"]\n+++\n<p>{{.Content}}</p>",
+		)},
+		"templates/plain.html":    &fstest.MapFile{Data: []byte("---- not frontmatter\n<p>{{.Title}}</p>")},
+		"templates/broken.html":   &fstest.MapFile{Data: []byte("---\ntitle: Broken\n---\n<p>\n{{.Title}\n</p>")},
+		"templates/unclosed.html": &fstest.MapFile{Data: []byte("---\ntitle: Unclosed\n<p></p>")},
+		"templates/bad_yaml.html": &fstest.MapFile{Data: []byte("---\ntitle: [\n---\n<p></p>")},
+		"templates/bad_toml.html": &fstest.MapFile{Data: []byte("+++\n[params]\n+++\n<p></p>")},
+		"templates/includes.html": &fstest.MapFile{Data: []byte("<main>\n@include \"partial\"\n</main>")},
+		"templates/partial.html":  &fstest.MapFile{Data: []byte("---\ntitle: Partial\n---\n<p>\n{{.Title}</p>")},
+		"templates/included.html": &fstest.MapFile{Data: []byte("<main>\n@include \"snippet\"\n</main>")},
+		"templates/snippet.html":  &fstest.MapFile{Data: []byte("---\ntitle: Snippet\n---\n<p>{{.Title}}</p>")},
+	}
+
+	reg := MustNewRegistry(fs, WithMacros[TestData]())
+	data := TestData{Title: "Hi", Content: "body"}
+
+	t.Run("strips frontmatter before parsing", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1>", got)
+
+		got, err = reg.MustGet("included").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<main>\n<p>Hi</p>\n</main>", got)
+	})
+
+	t.Run("exposes YAML frontmatter as metadata", func(t *testing.T) {
+		t.Parallel()
+
+		md, err := reg.Metadata("home")
+		require.NoError(t, err)
+		assert.Equal(t, Metadata{
+			"title":    "Overridden",
+			"cache":    map[string]any{"max_age": int64(60)},
+			"required": []any{"Title"},
+		}, md)
+	})
+
+	t.Run("exposes TOML frontmatter as metadata", func(t *testing.T) {
+		t.Parallel()
+
+		md, err := reg.Metadata("about")
+		require.NoError(t, err)
+		assert.Equal(t, Metadata{
+			"title":    "About",
+			"section":  "docs",
+			"weight":   int64(3),
+			"required": []any{"Title", "Content"},
+		}, md)
+
+		got, err := reg.MustGet("about").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<p>body</p>", got)
+	})
+
+	t.Run("ignores lines that only look like fences", func(t *testing.T) {
+		t.Parallel()
+
+		md, err := reg.Metadata("plain")
+		require.NoError(t, err)
+		assert.Empty(t, md)
+
+		got, err := reg.MustGet("plain").ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "---- not frontmatter\n<p>Hi</p>", got)
+	})
+
+	t.Run("reports parse errors at their line in the file", func(t *testing.T) {
+		t.Parallel()
+
+		for name, want := range map[string]ErrTemplateParse{
+			"broken":   {Name: "broken", Line: 5},
+			"includes": {Name: "partial", Line: 5},
+		} {
+			_, err := reg.Get(name)
+
+			var parseErr ErrTemplateParse
+			require.ErrorAs(t, err, &parseErr, name)
+			assert.Equal(t, want.Name, parseErr.Name)
+			assert.Equal(t, want.Line, parseErr.Line, name)
+		}
+	})
+
+	t.Run("rejects invalid frontmatter", func(t *testing.T) {
+		t.Parallel()
+
+		for name, msg := range map[string]string{
+			"unclosed": "template 'unclosed': invalid frontmatter: missing closing ---",
+			"bad_yaml": "template 'bad_yaml': invalid frontmatter: yaml:",
+			"bad_toml": "template 'bad_toml': invalid frontmatter: line 1: tables are not supported",
+		} {
+			_, err := reg.Get(name)
+			require.ErrorIs(t, err, ErrInvalidFrontmatter, name)
+			assert.Contains(t, err.Error(), msg)
+
+			_, err = reg.Metadata(name)
+			require.ErrorIs(t, err, ErrInvalidFrontmatter, name)
+		}
+	})
+
+	t.Run("strips frontmatter from drafts", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fstest.MapFS{})
+		require.NoError(t, reg.SaveDraft("news", "---\ntitle: News\n---\n<p>{{.Title}}</p>"))
+
+		var buf bytes.Buffer
+		require.NoError(t, reg.Preview(context.Background(), &buf, "news", data))
+		assert.Equal(t, "<p>Hi</p>", buf.String())
+
+		require.NoError(t, reg.Publish("news"))
+
+		md, err := reg.Metadata("news")
+		require.NoError(t, err)
+		assert.Equal(t, Metadata{"title": "News"}, md)
+	})
+}
+
+func TestParseTOML(t *testing.T) {
+	t.Parallel()
+
+	md := make(Metadata)
+	require.NoError(t, parseTOML("a = \"x # y\"\nb = true\nc = 1.5\nd = []\ne = [1, 'two', false]", md))
+	assert.Equal(t, Metadata{
+		"a": "x # y",
+		"b": true,
+		"c": 1.5,
+		"d": []any{},
+		"e": []any{int64(1), "two", false},
+	}, md)
+
+	for doc, msg := range map[string]string{
+		"a":         "line 1: expected key = value",
+		"a =":       "line 1: missing value",
+		"a = \"x":   "line 1: unterminated string",
+		"a = [1, 2": "line 1: unterminated array",
+		"a = [1 2]": "line 1: unterminated array",
+		"a = 1 2":   "line 1: unexpected '2'",
+	} {
+		require.EqualError(t, parseTOML(doc, make(Metadata)), msg, doc)
+	}
+}
@@ -0,0 +1,30 @@
+package templator
+
+import "html/template"
+
+// FuncPack is a cohesive bundle of template functions, such as an i18n, asset, or form pack,
+// published by a package for registries to install with WithFuncPacks.
+type FuncPack interface {
+	// Name identifies the pack in errors. It must be unique among the packs of a registry.
+	Name() string
+	// Funcs returns the functions of the pack. Every call must return the same functions.
+	Funcs() template.FuncMap
+	// Validate reports a misconfigured pack, such as a missing asset manifest,
+	// failing NewRegistry rather than the first render.
+	Validate() error
+}
+
+// WithFuncPacks returns an Option that installs the functions of every pack, as with
+// WithTemplateFuncs, so they are known to function validation. NewRegistry fails with
+// ErrInvalidOption when a pack does not validate, when packs share a name or define the
+// same function, or when a pack function cannot be installed.
+func WithFuncPacks[T any](packs ...FuncPack) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.funcPacks = append(r.config.funcPacks, packs...)
+		for _, pack := range packs {
+			if pack != nil {
+				WithTemplateFuncs[T](pack.Funcs())(r)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPack is a FuncPack with fixed functions and validation error.
+type testPack struct {
+	name  string
+	funcs template.FuncMap
+	err   error
+}
+
+func (p testPack) Name() string            { return p.name }
+func (p testPack) Funcs() template.FuncMap { return p.funcs }
+func (p testPack) Validate() error         { return p.err }
+
+func TestWithFuncPacks(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(`{{upper .Title}} {{asset "app.css"}}`)},
+	}
+
+	text := testPack{name: "text", funcs: template.FuncMap{"upper": strings.ToUpper}}
+	assets := testPack{name: "assets", funcs: template.FuncMap{
+		"asset": func(name string) string { return "/static/" + name },
+	}}
+
+	t.Run("installs pack functions", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(fs, WithFuncPacks[TestData](text, assets), WithFuncValidation[TestData]())
+		require.NoError(t, err)
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "HI /static/app.css", got)
+	})
+
+	t.Run("validator knows pack functions", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(fs, WithFuncPacks[TestData](text), WithFuncValidation[TestData]())
+		require.NoError(t, err)
+
+		_, err = reg.Get("home")
+		require.ErrorContains(t, err, "asset")
+	})
+
+	tests := []struct {
+		name  string
+		packs []FuncPack
+		err   string
+	}{
+		{
+			name:  "nil pack",
+			packs: []FuncPack{nil},
+			err:   "func pack must not be nil",
+		},
+		{
+			name:  "unnamed pack",
+			packs: []FuncPack{testPack{}},
+			err:   "func pack name must not be empty",
+		},
+		{
+			name:  "pack installed twice",
+			packs: []FuncPack{text, text},
+			err:   "func pack 'text' installed twice",
+		},
+		{
+			name:  "invalid pack",
+			packs: []FuncPack{testPack{name: "assets", err: errors.New("missing manifest")}},
+			err:   "func pack 'assets': missing manifest",
+		},
+		{
+			name:  "conflicting packs",
+			packs: []FuncPack{text, testPack{name: "shout", funcs: template.FuncMap{"upper": strings.ToUpper}}},
+			err:   "function 'upper' defined by func packs 'text' and 'shout'",
+		},
+		{
+			name:  "invalid pack function",
+			packs: []FuncPack{testPack{name: "broken", funcs: template.FuncMap{"bad": 42}}},
+			err:   "invalid option WithFuncPacks: function 'bad' of func pack 'broken' is not a function",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewRegistry(fs, WithFuncPacks[TestData](tt.packs...))
+			require.ErrorAs(t, err, new(ErrInvalidOption))
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Package ginadapter exposes a templator Registry as a Gin HTML renderer.
+//
+// HTMLRender implements render.HTMLRender:
+//
+//	r := gin.New()
+//	r.HTMLRender = ginadapter.New(reg)
+//
+//	r.GET("/", func(c *gin.Context) {
+//		c.HTML(http.StatusOK, "home", HomeData{Title: "Welcome"})
+//	})
+//
+// It lives in its own module so the root templator module does not depend on Gin.
+package ginadapter
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/alesr/templator"
+	"github.com/gin-gonic/gin/render"
+)
+
+var htmlContentType = []string{"text/html; charset=utf-8"}
+
+// HTMLRender renders Gin responses from a templator Registry.
+type HTMLRender[T any] struct {
+	reg *templator.Registry[T]
+}
+
+// New creates an HTMLRender backed by the given registry.
+func New[T any](reg *templator.Registry[T]) *HTMLRender[T] {
+	return &HTMLRender[T]{reg: reg}
+}
+
+// Instance returns a render.Render for the named template and data, which must be a T.
+func (r *HTMLRender[T]) Instance(name string, data any) render.Render {
+	return htmlRender[T]{reg: r.reg, name: name, data: data}
+}
+
+type htmlRender[T any] struct {
+	reg  *templator.Registry[T]
+	name string
+	data any
+}
+
+// Render executes the template into a buffer before writing, so errors never
+// produce partial responses. Gin does not expose the request context to
+// renderers, so execution is not bound to it.
+func (r htmlRender[T]) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	h, err := r.reg.Get(r.name)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := h.ExecuteAny(context.Background(), &buf, r.data); err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func (r htmlRender[T]) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = htmlContentType
+	}
+}
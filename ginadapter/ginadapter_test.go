@@ -0,0 +1,48 @@
+package ginadapter
+
+import (
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageData struct {
+	Title string
+}
+
+func TestHTMLRender(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}
+
+	reg, err := templator.NewRegistry[pageData](fs)
+	require.NoError(t, err)
+
+	renderer := New(reg)
+
+	t.Run("renders template", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		require.NoError(t, renderer.Instance("home", pageData{Title: "hi"}).Render(rec))
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "<h1>hi</h1>", rec.Body.String())
+	})
+
+	t.Run("does not write partial output on error", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		err := renderer.Instance("home", "not page data").Render(rec)
+
+		var dataErr templator.ErrDataType
+		require.ErrorAs(t, err, &dataErr)
+		assert.Empty(t, rec.Body.String())
+	})
+}
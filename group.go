@@ -0,0 +1,148 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"io/fs"
+	"maps"
+	"sync"
+	"time"
+)
+
+// GroupOption configures a RegistryGroup instance.
+type GroupOption func(*RegistryGroup)
+
+// WithGroupTemplatesPath returns a GroupOption that sets the template directory path
+// shared by every registry derived from the group.
+// If an empty path is provided, the default path will be used.
+func WithGroupTemplatesPath(path string) GroupOption {
+	return func(g *RegistryGroup) {
+		if path != "" {
+			g.path = path
+		}
+	}
+}
+
+// WithGroupFuncs returns a GroupOption that registers template functions shared by every
+// registry derived from the group. Multiple calls are merged, later ones taking precedence,
+// and functions set on a registry take precedence over the group's.
+func WithGroupFuncs(funcMap template.FuncMap) GroupOption {
+	return func(g *RegistryGroup) {
+		if g.funcMap == nil {
+			g.funcMap = make(template.FuncMap, len(funcMap))
+		}
+		maps.Copy(g.funcMap, funcMap)
+	}
+}
+
+// WithGroupHooks returns a GroupOption that runs pre before and post after every render of
+// the registries derived from the group, as WithExecutionHooks does for one registry, with
+// the data passed as any. Group hooks wrap those of the registries: their pre hooks run
+// first and their post hooks last.
+func WithGroupHooks(
+	pre func(ctx context.Context, name string, data any) error,
+	post func(ctx context.Context, name string, dur time.Duration, err error),
+) GroupOption {
+	return func(g *RegistryGroup) {
+		g.hooks = append(g.hooks, executionHooks[any]{pre: pre, post: post})
+	}
+}
+
+// WithGroupPartials returns a GroupOption that sets partial templates parsed alongside
+// every template of the derived registries. Partials are referenced by name, like Get,
+// and patterns may use path.Match syntax. Templates invoke them by file name,
+// e.g. {{template "components/menu.html" .}}.
+func WithGroupPartials(patterns ...string) GroupOption {
+	return func(g *RegistryGroup) {
+		g.partials = append(g.partials, patterns...)
+	}
+}
+
+// RegistryGroup owns configuration and caches shared by registries of different data types
+// over the same filesystem. Template sources are read once per group, and partials parsed
+// once per set of function names, then reused by every derived registry.
+type RegistryGroup struct {
+	fs       fs.FS
+	path     string
+	funcMap  template.FuncMap
+	partials []string
+	hooks    []executionHooks[any]
+
+	parsed parsedPartials
+
+	mu      sync.RWMutex
+	sources map[string][]byte
+}
+
+// NewRegistryGroup creates a new registry group with the provided filesystem and options.
+func NewRegistryGroup(fsys fs.FS, opts ...GroupOption) *RegistryGroup {
+	g := &RegistryGroup{
+		fs:      fsys,
+		path:    DefaultTemplateDir,
+		sources: make(map[string][]byte),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// NewGroupRegistry derives a typed registry from the group. The registry inherits the
+// group's filesystem, path, functions, partials and hooks, and shares its source and partial
+// caches. Options are applied on top of the inherited configuration, which they are
+// validated with.
+func NewGroupRegistry[T any](g *RegistryGroup, opts ...Option[T]) (*Registry[T], error) {
+	inherited := []Option[T]{WithTemplatesPath[T](g.path)}
+	if len(g.funcMap) > 0 {
+		inherited = append(inherited, WithTemplateFuncs[T](g.funcMap))
+	}
+	if len(g.partials) > 0 {
+		inherited = append(inherited, WithPartials[T](g.partials...))
+	}
+	for _, hook := range g.hooks {
+		inherited = append(inherited, groupHooks[T](hook))
+	}
+
+	reg, err := NewRegistry(g.fs, append(inherited, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	reg.partials = &g.parsed
+	reg.group = g
+	return reg, nil
+}
+
+// groupHooks returns an Option adding the group hooks to a derived registry.
+func groupHooks[T any](hooks executionHooks[any]) Option[T] {
+	var pre func(ctx context.Context, name string, data T) error
+	if hooks.pre != nil {
+		pre = func(ctx context.Context, name string, data T) error {
+			return hooks.pre(ctx, name, data)
+		}
+	}
+	return WithExecutionHooks(pre, hooks.post)
+}
+
+// readSource returns the content of the file at the given path, reading it from the
+// filesystem only the first time it is requested, or again when fresh is set.
+func (g *RegistryGroup) readSource(path string, fresh bool) ([]byte, error) {
+	if !fresh {
+		g.mu.RLock()
+		content, ok := g.sources[path]
+		g.mu.RUnlock()
+		if ok {
+			return content, nil
+		}
+	}
+
+	content, err := fs.ReadFile(g.fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	g.sources[path] = content
+	g.mu.Unlock()
+	return content, nil
+}
@@ -0,0 +1,223 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingFS struct {
+	fs.FS
+	opens atomic.Int64
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.opens.Add(1)
+	return c.FS.Open(name)
+}
+
+type AboutData struct {
+	Company string
+}
+
+func TestRegistryGroup(t *testing.T) {
+	t.Parallel()
+
+	fsys := &countingFS{FS: fstest.MapFS{
+		"views/home.html":            &fstest.MapFile{Data: []byte(`{{template "components/menu.html" .}}<h1>{{.Title | upper}}</h1>`)},
+		"views/about.html":           &fstest.MapFile{Data: []byte(`{{template "components/menu.html" .}}<p>{{.Company | shout}}</p>`)},
+		"views/components/menu.html": &fstest.MapFile{Data: []byte(`<nav></nav>`)},
+	}}
+
+	group := NewRegistryGroup(
+		fsys,
+		WithGroupTemplatesPath("views"),
+		WithGroupFuncs(template.FuncMap{"upper": strings.ToUpper, "shout": strings.ToUpper}),
+		WithGroupPartials("components/menu"),
+	)
+
+	homeReg, err := NewGroupRegistry[TestData](group)
+	require.NoError(t, err)
+
+	aboutReg, err := NewGroupRegistry(group, WithTemplateFuncs[AboutData](template.FuncMap{
+		"shout": func(s string) string { return strings.ToUpper(s) + "!" },
+	}))
+	require.NoError(t, err)
+
+	home, err := homeReg.Get("home")
+	require.NoError(t, err)
+
+	got, err := home.ExecuteToString(context.Background(), TestData{Title: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "<nav></nav><h1>HELLO</h1>", got)
+
+	about, err := aboutReg.Get("about")
+	require.NoError(t, err)
+
+	got, err = about.ExecuteToString(context.Background(), AboutData{Company: "acme"})
+	require.NoError(t, err)
+	assert.Equal(t, "<nav></nav><p>ACME!</p>", got)
+
+	// home, about and the shared partial are each read once
+	assert.Equal(t, int64(3), fsys.opens.Load())
+
+	menu, err := homeReg.Get("components/menu")
+	require.NoError(t, err)
+
+	got, err = menu.ExecuteToString(context.Background(), TestData{})
+	require.NoError(t, err)
+	assert.Equal(t, "<nav></nav>", got)
+	assert.Equal(t, int64(3), fsys.opens.Load())
+}
+
+func TestRegistryGroup_MissingPartial(t *testing.T) {
+	t.Parallel()
+
+	group := NewRegistryGroup(
+		fstest.MapFS{"templates/home.html": &fstest.MapFile{Data: []byte(testHTMLTemplate)}},
+		WithGroupPartials("missing"),
+	)
+
+	reg, err := NewGroupRegistry[TestData](group)
+	require.NoError(t, err)
+
+	handler, err := reg.Get("home")
+	require.Error(t, err)
+	assert.Nil(t, handler)
+}
+
+func TestRegistryGroup_Shared(t *testing.T) {
+	t.Parallel()
+
+	newGroup := func(opts ...GroupOption) *RegistryGroup {
+		return NewRegistryGroup(fstest.MapFS{
+			"templates/home.html":            &fstest.MapFile{Data: []byte(`{{template "components/menu.html" .}}<h1>{{.Title | upper}}</h1>`)},
+			"templates/about.html":           &fstest.MapFile{Data: []byte(`{{template "components/menu.html" .}}<p>{{.Company}}</p>`)},
+			"templates/components/menu.html": &fstest.MapFile{Data: []byte(`<nav>{{"menu" | lower}}</nav>`)},
+		}, append([]GroupOption{WithGroupPartials("components/menu")}, opts...)...)
+	}
+
+	t.Run("merges group funcs", func(t *testing.T) {
+		t.Parallel()
+
+		group := newGroup(
+			WithGroupFuncs(template.FuncMap{"upper": strings.ToUpper}),
+			WithGroupFuncs(template.FuncMap{"lower": strings.ToLower}),
+		)
+
+		reg, err := NewGroupRegistry(group, WithFuncValidation[TestData]())
+		require.NoError(t, err)
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<nav>menu</nav><h1>HI</h1>", got)
+	})
+
+	t.Run("validates the inherited configuration", func(t *testing.T) {
+		t.Parallel()
+
+		group := newGroup(WithGroupFuncs(template.FuncMap{"upper": "not a function"}), WithGroupPartials("[bad"))
+
+		reg, err := NewGroupRegistry[TestData](group)
+		require.ErrorContains(t, err, "function 'upper'")
+		require.ErrorContains(t, err, "malformed pattern '[bad'")
+		assert.Nil(t, reg)
+	})
+
+	t.Run("runs group hooks around registry hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		group := newGroup(
+			WithGroupFuncs(template.FuncMap{"upper": strings.ToUpper, "lower": strings.ToLower}),
+			WithGroupHooks(
+				func(_ context.Context, name string, data any) error {
+					calls = append(calls, fmt.Sprintf("group pre %s %T", name, data))
+					return nil
+				},
+				func(_ context.Context, name string, _ time.Duration, _ error) {
+					calls = append(calls, "group post "+name)
+				},
+			),
+		)
+
+		homeReg, err := NewGroupRegistry(group, WithExecutionHooks(
+			func(_ context.Context, name string, _ TestData) error {
+				calls = append(calls, "registry pre "+name)
+				return nil
+			},
+			func(_ context.Context, name string, _ time.Duration, _ error) {
+				calls = append(calls, "registry post "+name)
+			},
+		))
+		require.NoError(t, err)
+		aboutReg, err := NewGroupRegistry[AboutData](group)
+		require.NoError(t, err)
+
+		_, err = homeReg.MustGet("home").ExecuteToString(context.Background(), TestData{})
+		require.NoError(t, err)
+		_, err = aboutReg.MustGet("about").ExecuteToString(context.Background(), AboutData{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"group pre home templator.TestData",
+			"registry pre home",
+			"registry post home",
+			"group post home",
+			"group pre about templator.AboutData",
+			"group post about",
+		}, calls)
+	})
+
+	t.Run("group hooks abort renders", func(t *testing.T) {
+		t.Parallel()
+
+		errDenied := errors.New("denied")
+		group := newGroup(
+			WithGroupFuncs(template.FuncMap{"lower": strings.ToLower}),
+			WithGroupHooks(func(context.Context, string, any) error { return errDenied }, nil),
+		)
+
+		reg, err := NewGroupRegistry[AboutData](group)
+		require.NoError(t, err)
+
+		_, err = reg.MustGet("about").ExecuteToString(context.Background(), AboutData{})
+		require.ErrorIs(t, err, errDenied)
+	})
+
+	t.Run("shares parsed partials", func(t *testing.T) {
+		t.Parallel()
+
+		group := newGroup(WithGroupFuncs(template.FuncMap{"upper": strings.ToUpper, "lower": strings.ToLower}))
+
+		homeReg, err := NewGroupRegistry[TestData](group)
+		require.NoError(t, err)
+		aboutReg, err := NewGroupRegistry[AboutData](group)
+		require.NoError(t, err)
+
+		_, err = homeReg.Get("home")
+		require.NoError(t, err)
+		_, err = aboutReg.Get("about")
+		require.NoError(t, err)
+
+		assert.Same(t, homeReg.partials, aboutReg.partials)
+		assert.Len(t, group.parsed.partials, 1)
+
+		// Registries with other functions parse partials of their own.
+		otherReg, err := NewGroupRegistry(group, WithTemplateFuncs[AboutData](template.FuncMap{"shout": strings.ToUpper}))
+		require.NoError(t, err)
+		_, err = otherReg.Get("about")
+		require.NoError(t, err)
+		assert.Len(t, group.parsed.partials, 2)
+	})
+}
@@ -0,0 +1,55 @@
+package templator
+
+import (
+	"context"
+	"time"
+)
+
+// executionHooks are the functions set by one call to WithExecutionHooks.
+type executionHooks[T any] struct {
+	pre  func(ctx context.Context, name string, data T) error
+	post func(ctx context.Context, name string, dur time.Duration, err error)
+}
+
+// WithExecutionHooks returns an Option that runs pre before and post after every render,
+// for logging, metrics, or audit trails. A non-nil error from pre aborts the render and is
+// returned wrapped in ErrTemplateExecution. post receives the render duration and its error,
+// including one returned by pre. Either function may be nil. With several calls, pre hooks run
+// in the order they were added and post hooks in reverse order, like middleware.
+func WithExecutionHooks[T any](
+	pre func(ctx context.Context, name string, data T) error,
+	post func(ctx context.Context, name string, dur time.Duration, err error),
+) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.hooks = append(r.config.hooks, executionHooks[T]{pre: pre, post: post})
+	}
+}
+
+// hooked runs render between the registry's execution hooks.
+func (h *Handler[T]) hooked(ctx context.Context, data T, render func() error) (err error) {
+	hooks := h.reg.config.hooks
+	if len(hooks) == 0 {
+		return render()
+	}
+
+	start := time.Now()
+	ran := 0
+	defer func() {
+		for i := ran - 1; i >= 0; i-- {
+			if post := hooks[i].post; post != nil {
+				post(ctx, h.name, time.Since(start), err)
+			}
+		}
+	}()
+
+	for _, hook := range hooks {
+		ran++
+		if hook.pre == nil {
+			continue
+		}
+		if err := hook.pre(ctx, h.name, data); err != nil {
+			return ErrTemplateExecution{Name: h.file, Err: err}
+		}
+	}
+	return render()
+}
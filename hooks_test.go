@@ -0,0 +1,98 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithExecutionHooks(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+		"templates/fail.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+	}
+
+	recorder := func(calls *[]string, id string, preErr error) Option[TestData] {
+		return WithExecutionHooks(
+			func(_ context.Context, name string, data TestData) error {
+				*calls = append(*calls, fmt.Sprintf("%s pre %s %s", id, name, data.Title))
+				return preErr
+			},
+			func(_ context.Context, name string, dur time.Duration, err error) {
+				*calls = append(*calls, fmt.Sprintf("%s post %s %t", id, name, err != nil))
+			},
+		)
+	}
+
+	t.Run("runs hooks around renders", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		reg := MustNewRegistry(fs, recorder(&calls, "a", nil), recorder(&calls, "b", nil))
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Hi</p>", got)
+
+		assert.Equal(t, []string{
+			"a pre home Hi",
+			"b pre home Hi",
+			"b post home false",
+			"a post home false",
+		}, calls)
+	})
+
+	t.Run("passes render errors to post hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+		reg := MustNewRegistry(fs, recorder(&calls, "a", nil))
+
+		_, err := reg.MustGet("fail").ExecuteToString(context.Background(), TestData{})
+		require.Error(t, err)
+		assert.Equal(t, []string{"a pre fail ", "a post fail true"}, calls)
+	})
+
+	t.Run("pre hook error aborts render", func(t *testing.T) {
+		t.Parallel()
+
+		errDenied := errors.New("denied")
+
+		var calls []string
+		reg := MustNewRegistry(fs,
+			recorder(&calls, "a", errDenied),
+			recorder(&calls, "b", nil),
+		)
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.ErrorIs(t, err, errDenied)
+		assert.Empty(t, got)
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		assert.Equal(t, []string{"a pre home Hi", "a post home true"}, calls)
+	})
+
+	t.Run("allows nil hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var rendered []string
+		reg := MustNewRegistry(fs,
+			WithExecutionHooks[TestData](nil, func(_ context.Context, name string, _ time.Duration, _ error) {
+				rendered = append(rendered, name)
+			}),
+		)
+
+		_, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home"}, rendered)
+	})
+}
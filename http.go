@@ -0,0 +1,96 @@
+package templator
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+)
+
+// HTTPOption configures the http.Handler returned by Handler.HTTP.
+type HTTPOption func(*httpConfig)
+
+// WithStatus returns an HTTPOption that sets the status code of successful responses.
+func WithStatus(code int) HTTPOption {
+	return func(c *httpConfig) {
+		c.status = code
+	}
+}
+
+// WithContentType returns an HTTPOption that sets the Content-Type of successful responses.
+func WithContentType(contentType string) HTTPOption {
+	return func(c *httpConfig) {
+		c.contentType = contentType
+	}
+}
+
+// WithErrorHandler returns an HTTPOption that replaces the default error handling,
+// which replies with the status of an ErrHTTPStatus, or 500, and its status text.
+func WithErrorHandler(fn func(http.ResponseWriter, *http.Request, error)) HTTPOption {
+	return func(c *httpConfig) {
+		c.errorHandler = fn
+	}
+}
+
+type httpConfig struct {
+	status       int
+	contentType  string
+	errorHandler func(http.ResponseWriter, *http.Request, error)
+}
+
+// HTTP returns an http.Handler that renders the template with the data returned by dataFunc.
+// The template is rendered into a buffer before anything is written, so errors never produce
+// partial responses. The template's cache policy, if any, is applied to the response headers.
+// When the error template configured with WithErrorTemplate replaced the page, it responds
+// with it and status 500 instead of calling the error handler. A nil dataFunc renders the zero value of T.
+func (h *Handler[T]) HTTP(dataFunc func(*http.Request) (T, error), opts ...HTTPOption) http.Handler {
+	cfg := httpConfig{
+		status:       http.StatusOK,
+		contentType:  "text/html; charset=utf-8",
+		errorHandler: defaultHTTPErrorHandler,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data T
+		if dataFunc != nil {
+			var err error
+			if data, err = dataFunc(r); err != nil {
+				cfg.errorHandler(w, r, err)
+				return
+			}
+		}
+
+		buf := bufferPool.Get().(*bytes.Buffer)
+		defer putBuffer(buf)
+
+		status := cfg.status
+		err := h.Execute(r.Context(), buf, data)
+		switch {
+		case errors.As(err, new(ErrFallbackRendered)):
+			// The cache policy is meant for the page, not for the error page.
+			status = http.StatusInternalServerError
+		case err != nil:
+			cfg.errorHandler(w, r, err)
+			return
+		default:
+			if policy, ok := h.CachePolicy(); ok {
+				policy.Apply(w.Header())
+			}
+		}
+		w.Header().Set("Content-Type", cfg.contentType)
+		w.WriteHeader(status)
+		buf.WriteTo(w)
+	})
+}
+
+func defaultHTTPErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	status := http.StatusInternalServerError
+
+	var statusErr ErrHTTPStatus
+	if errors.As(err, &statusErr) {
+		status = statusErr.Status
+	}
+	http.Error(w, http.StatusText(status), status)
+}
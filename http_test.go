@@ -0,0 +1,98 @@
+package templator
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_HTTP(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":   &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("<h1>{{.Missing}}</h1>")},
+	}
+
+	reg, err := NewRegistry(fs, WithCachePolicy[TestData]("home", CachePolicy{Public: true, MaxAge: time.Minute}))
+	require.NoError(t, err)
+
+	home, err := reg.Get("home")
+	require.NoError(t, err)
+
+	broken, err := reg.Get("broken")
+	require.NoError(t, err)
+
+	titleFromQuery := func(r *http.Request) (TestData, error) {
+		return TestData{Title: r.URL.Query().Get("title")}, nil
+	}
+
+	serve := func(h http.Handler) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?title=hi", nil))
+		return rec
+	}
+
+	t.Run("renders template", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(home.HTTP(titleFromQuery))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "<h1>hi</h1>", rec.Body.String())
+	})
+
+	t.Run("applies options", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(home.HTTP(nil, WithStatus(http.StatusAccepted), WithContentType("text/plain")))
+
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "<h1></h1>", rec.Body.String())
+	})
+
+	t.Run("uses status from data error", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(home.HTTP(func(*http.Request) (TestData, error) {
+			return TestData{}, ErrHTTPStatus{Status: http.StatusNotFound, Err: errors.New("no such page")}
+		}))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Header().Get("Cache-Control"))
+		assert.Contains(t, rec.Body.String(), "Not Found")
+	})
+
+	t.Run("does not write partial output on execution error", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(broken.HTTP(titleFromQuery))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "<h1>")
+	})
+
+	t.Run("uses custom error handler", func(t *testing.T) {
+		t.Parallel()
+
+		var got error
+		rec := serve(broken.HTTP(titleFromQuery, WithErrorHandler(func(w http.ResponseWriter, _ *http.Request, err error) {
+			got = err
+			w.WriteHeader(http.StatusTeapot)
+		})))
+
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, got, &execErr)
+	})
+}
@@ -0,0 +1,45 @@
+package templator
+
+import (
+	"fmt"
+	"html/template"
+)
+
+const (
+	// layoutKey is the metadata key naming the layout a template renders in.
+	layoutKey = "layout"
+
+	// contentTemplate is the name under which a layout invokes the body of its page.
+	contentTemplate = "content"
+)
+
+// layoutOf returns the layout declared in the metadata of the named template.
+// Templates without a layout return an empty name.
+func layoutOf(name string, md Metadata) (string, error) {
+	value, ok := md[layoutKey]
+	if !ok {
+		return "", nil
+	}
+
+	layout, ok := value.(string)
+	if !ok || layout == "" || layout == name {
+		return "", fmt.Errorf("template '%s': %w: layout must name another template", name, ErrInvalidPragma)
+	}
+	return layout, nil
+}
+
+// useLayout returns the template of the page's name executing the layout parsed into the
+// set of tmpl, with the page's body available to the layout as the content template.
+func useLayout(tmpl *template.Template, layout string) (*template.Template, error) {
+	if _, err := tmpl.AddParseTree(contentTemplate, tmpl.Tree); err != nil {
+		return nil, err
+	}
+
+	// The layout tree is copied, so the layout's own template and the page never share
+	// a tree that html/template escapes in place. Copy drops the file name Reload tracks.
+	tree := tmpl.Lookup(layout).Tree
+	root := tree.Copy()
+	root.ParseName = tree.ParseName
+
+	return tmpl.AddParseTree(tmpl.Name(), root)
+}
@@ -0,0 +1,89 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayouts(t *testing.T) {
+	t.Parallel()
+
+	newFS := func() fstest.MapFS {
+		return fstest.MapFS{
+			"templates/layouts/base.html": &fstest.MapFile{Data: []byte(
+				`<title>{{block "title" .}}Site{{end}}</title><main>{{template "content" .}}</main>`,
+			)},
+			"templates/home.html": &fstest.MapFile{Data: []byte(
+				`{{/* templator: layout="layouts/base" */}}<h1>{{.Title}}</h1>{{define "title"}}{{.Title}} | Site{{end}}`,
+			)},
+			"templates/about.html": &fstest.MapFile{Data: []byte(
+				"---\nlayout: layouts/base\n---\n<p>{{.Content}}</p>",
+			)},
+			"templates/plain.html":          &fstest.MapFile{Data: []byte(`<p>{{.Title}}</p>`)},
+			"templates/missing.html":        &fstest.MapFile{Data: []byte(`{{/* templator: layout="layouts/none" */}}`)},
+			"templates/self.html":           &fstest.MapFile{Data: []byte(`{{/* templator: layout="self" */}}`)},
+			"templates/numeric.html":        &fstest.MapFile{Data: []byte(`{{/* templator: layout=1 */}}`)},
+			"templates/unknown.html":        &fstest.MapFile{Data: []byte(`{{/* templator: layout="layouts/fields" */}}`)},
+			"templates/components/nav.html": &fstest.MapFile{Data: []byte(`<nav></nav>`)},
+			"templates/layouts/fields.html": &fstest.MapFile{Data: []byte(`{{.Missing}}{{template "content" .}}`)},
+		}
+	}
+	data := TestData{Title: "Hi", Content: "body"}
+
+	t.Run("renders pages in their layout", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(newFS(), WithPartials[TestData]("components/*", "layouts/*"))
+
+		for name, want := range map[string]string{
+			"home":  "<title>Hi | Site</title><main><h1>Hi</h1></main>",
+			"about": "<title>Site</title><main><p>body</p></main>",
+			"plain": "<p>Hi</p>",
+		} {
+			got, err := reg.MustGet(name).ExecuteToString(context.Background(), data)
+			require.NoError(t, err, name)
+			assert.Equal(t, want, got, name)
+		}
+	})
+
+	t.Run("rejects invalid layouts", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(newFS(), WithFieldValidation(TestData{}))
+
+		_, err := reg.Get("missing")
+		require.ErrorIs(t, err, ErrTemplateNotFound{Name: "layouts/none"})
+
+		for _, name := range []string{"self", "numeric"} {
+			_, err = reg.Get(name)
+			require.ErrorIs(t, err, ErrInvalidPragma, name)
+			assert.Contains(t, err.Error(), "layout must name another template")
+		}
+
+		_, err = reg.Get("unknown")
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("reloads pages when their layout changes", func(t *testing.T) {
+		t.Parallel()
+
+		fs := newFS()
+		reg := MustNewRegistry[TestData](fs)
+		home := reg.MustGet("home")
+
+		fs["templates/layouts/base.html"] = &fstest.MapFile{Data: []byte(`<body>{{template "content" .}}</body>`)}
+
+		reloaded, err := reg.Reload(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home"}, reloaded)
+
+		got, err := home.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<body><h1>Hi</h1></body>", got)
+	})
+}
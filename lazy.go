@@ -0,0 +1,294 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"reflect"
+	"sync"
+	"text/template/parse"
+	"time"
+)
+
+// Lazy is a value of template data loaded only if the template uses it, so pages only pay for
+// the data they render. Templates read it with Get, which fails the render on errors:
+//
+//	type PageData struct {
+//		Title   string
+//		Reviews *templator.Lazy[[]Review]
+//	}
+//
+//	data := PageData{Title: "Home", Reviews: templator.NewLazy(loadReviews)}
+//
+//	{{range .Reviews.Get}}<p>{{.Text}}</p>{{end}}
+//
+// When a render starts, the Lazy values held by fields the template references by name start
+// loading concurrently, with the render context; the others load when Get is called. They are
+// found through the structs, pointers, slices, arrays and maps of T, not behind interfaces.
+// A Lazy loads once, so it belongs to the data of a single render. Loads depending on another
+// Lazy can call its Get, and only wait for that one.
+//
+// How a failing load degrades is set per field with WithLazyPolicy; with WithRenderTracing,
+// the page's trace times every load. A zero Lazy, without a load function, holds the zero value.
+type Lazy[V any] struct {
+	load func(ctx context.Context) (V, error)
+	once sync.Once
+	done chan struct{}
+
+	mu     sync.Mutex
+	ctx    context.Context
+	field  string
+	policy LazyPolicy
+
+	value V
+	err   error
+}
+
+// LazyMode selects what Get returns when a Lazy fails to load.
+type LazyMode int
+
+const (
+	// LazyFail returns the error, failing the render. It is the default.
+	LazyFail LazyMode = iota
+	// LazyPlaceholder returns the policy's placeholder instead, or the zero value without one.
+	LazyPlaceholder
+)
+
+// LazyPolicy describes how the Lazy values of a field degrade when they fail to load, such as
+// reviews a product page can render without.
+type LazyPolicy struct {
+	Mode LazyMode
+	// Placeholder is the value Get returns with LazyPlaceholder. It must be of the type of the
+	// Lazy value; the load error is returned otherwise.
+	Placeholder any
+	// OnError, if set, is called with the field and every error the policy swallows,
+	// so failures stay observable.
+	OnError func(field string, err error)
+}
+
+// WithLazyPolicy returns an Option that sets the policy applied to failed loads of the Lazy
+// values held by fields of the given name, such as "Reviews", wherever they are in the data.
+func WithLazyPolicy[T any](field string, policy LazyPolicy) Option[T] {
+	return func(r *Registry[T]) {
+		if r.config.lazyPolicies == nil {
+			r.config.lazyPolicies = make(map[string]LazyPolicy)
+		}
+		r.config.lazyPolicies[field] = policy
+	}
+}
+
+// NewLazy returns a Lazy loading its value with load.
+func NewLazy[V any](load func(ctx context.Context) (V, error)) *Lazy[V] {
+	return &Lazy[V]{load: load}
+}
+
+// Get returns the value, waiting for it to load, and starting to load it when it hasn't
+// already. A nil Lazy returns the zero value.
+func (l *Lazy[V]) Get() (V, error) {
+	if l == nil {
+		var zero V
+		return zero, nil
+	}
+
+	l.mu.Lock()
+	ctx := l.ctx
+	l.mu.Unlock()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	l.start(ctx)
+	<-l.done
+	return l.value, l.err
+}
+
+// start starts loading the value with ctx, unless it already started. The done channel is
+// made here, so zero Lazy values work too; Once makes it visible to every caller.
+func (l *Lazy[V]) start(ctx context.Context) {
+	l.once.Do(func() {
+		l.done = make(chan struct{})
+		if l.load == nil {
+			close(l.done)
+			return
+		}
+
+		l.mu.Lock()
+		field, policy := l.field, l.policy
+		l.mu.Unlock()
+
+		go func() {
+			defer close(l.done)
+
+			start := time.Now()
+			l.value, l.err = l.loadSafely(ctx)
+			if l.err != nil && field != "" {
+				l.err = fmt.Errorf("loading %s: %w", field, l.err)
+			}
+			traceFrom(ctx).addLoad(field, time.Since(start), l.err, policy.Mode)
+
+			if l.err == nil || policy.Mode != LazyPlaceholder {
+				return
+			}
+			placeholder, ok := policy.Placeholder.(V)
+			if !ok && policy.Placeholder != nil {
+				l.err = fmt.Errorf("%w (placeholder %T is not a %T)", l.err, policy.Placeholder, l.value)
+				return
+			}
+			if policy.OnError != nil {
+				policy.OnError(field, l.err)
+			}
+			l.value, l.err = placeholder, nil
+		}()
+	})
+}
+
+// loadSafely calls load, turning panics into errors.
+func (l *Lazy[V]) loadSafely(ctx context.Context) (value V, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return l.load(ctx)
+}
+
+// bind sets the context Get loads the value with, the field holding it and the policy of the
+// field, unless they are set.
+func (l *Lazy[V]) bind(ctx context.Context, field string, policy LazyPolicy) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ctx == nil {
+		l.ctx, l.field, l.policy = ctx, field, policy
+	}
+}
+
+// lazyValue is implemented by every *Lazy.
+type lazyValue interface {
+	start(ctx context.Context)
+	bind(ctx context.Context, field string, policy LazyPolicy)
+}
+
+var lazyValueType = reflect.TypeFor[lazyValue]()
+
+// lazyTypes caches whether types can hold Lazy values.
+var lazyTypes sync.Map
+
+// holdsLazy reports whether values of typ can hold Lazy values outside of interfaces.
+func holdsLazy(typ reflect.Type) bool {
+	if cached, ok := lazyTypes.Load(typ); ok {
+		return cached.(bool)
+	}
+
+	held := holdsLazyVisiting(typ, make(map[reflect.Type]bool))
+	lazyTypes.Store(typ, held)
+	return held
+}
+
+func holdsLazyVisiting(typ reflect.Type, visiting map[reflect.Type]bool) bool {
+	if typ == nil || visiting[typ] {
+		return false
+	}
+	if typ.Implements(lazyValueType) {
+		return true
+	}
+	visiting[typ] = true
+
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return holdsLazyVisiting(typ.Elem(), visiting)
+	case reflect.Map:
+		return holdsLazyVisiting(typ.Elem(), visiting)
+	case reflect.Struct:
+		for i := range typ.NumField() {
+			if field := typ.Field(i); field.IsExported() && holdsLazyVisiting(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// referencedFields returns the names of the fields and methods the templates of tmpl
+// reference, such as Author and Name for {{.Author.Name}}.
+func referencedFields(tmpl *template.Template) map[string]bool {
+	fields := make(map[string]bool)
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		walkPipes(t.Tree.Root, func(pipe *parse.PipeNode) error {
+			for _, cmd := range pipe.Cmds {
+				for _, arg := range cmd.Args {
+					var idents []string
+					switch arg := arg.(type) {
+					case *parse.FieldNode:
+						idents = arg.Ident
+					case *parse.ChainNode:
+						idents = arg.Field
+					case *parse.VariableNode:
+						idents = arg.Ident[1:]
+					}
+					for _, ident := range idents {
+						fields[ident] = true
+					}
+				}
+			}
+			return nil
+		})
+	}
+	return fields
+}
+
+// startLazy binds the Lazy values of v to ctx and to the policies of their fields, and starts
+// loading those held by the referenced fields.
+func startLazy(ctx context.Context, v reflect.Value, fields map[string]bool, policies map[string]LazyPolicy) {
+	visited := make(map[uintptr]bool)
+
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		switch v.Kind() {
+		case reflect.Pointer:
+			if v.IsNil() || visited[v.Pointer()] {
+				return
+			}
+			visited[v.Pointer()] = true
+			walk(v.Elem())
+		case reflect.Slice, reflect.Array:
+			if !holdsLazy(v.Type().Elem()) {
+				return
+			}
+			for i := range v.Len() {
+				walk(v.Index(i))
+			}
+		case reflect.Map:
+			if !holdsLazy(v.Type().Elem()) {
+				return
+			}
+			for iter := v.MapRange(); iter.Next(); {
+				walk(iter.Value())
+			}
+		case reflect.Struct:
+			for i := range v.NumField() {
+				field, fv := v.Type().Field(i), v.Field(i)
+				if !field.IsExported() || !holdsLazy(field.Type) {
+					continue
+				}
+
+				lazy, ok := fv.Interface().(lazyValue)
+				if !ok {
+					walk(fv)
+					continue
+				}
+				if fv.Kind() == reflect.Pointer && fv.IsNil() {
+					continue
+				}
+				lazy.bind(ctx, field.Name, policies[field.Name])
+				if fields[field.Name] {
+					lazy.start(ctx)
+				}
+			}
+		}
+	}
+	walk(v)
+}
@@ -0,0 +1,280 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lazyAuthor struct {
+	Name string
+}
+
+type lazyData struct {
+	Title    string
+	Author   *Lazy[lazyAuthor]
+	Comments *Lazy[[]string]
+	Sections []lazySection
+}
+
+type lazySection struct {
+	Heading string
+	Body    *Lazy[string]
+}
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/article.html": &fstest.MapFile{Data: []byte(
+			`<h1>{{.Title}}</h1><p>{{.Author.Get.Name}}</p>{{range .Comments.Get}}<li>{{.}}</li>{{end}}`)},
+		"templates/title.html":    &fstest.MapFile{Data: []byte(`<h1>{{.Title}}</h1>`)},
+		"templates/sections.html": &fstest.MapFile{Data: []byte(`{{range .Sections}}<h2>{{.Heading}}</h2>{{.Body.Get}}{{end}}`)},
+	}
+	reg := MustNewRegistry(fs, WithFieldValidation(lazyData{}))
+
+	t.Run("loads the referenced values concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		// Each load waits for the other to start, so loading them one after the other deadlocks.
+		var started sync.WaitGroup
+		started.Add(2)
+		wait := func() {
+			started.Done()
+			started.Wait()
+		}
+
+		data := lazyData{
+			Title: "Hi",
+			Author: NewLazy(func(context.Context) (lazyAuthor, error) {
+				wait()
+				return lazyAuthor{Name: "Ana"}, nil
+			}),
+			Comments: NewLazy(func(context.Context) ([]string, error) {
+				wait()
+				return []string{"a", "b"}, nil
+			}),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		out, err := reg.MustGet("article").ExecuteToString(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><p>Ana</p><li>a</li><li>b</li>", out)
+	})
+
+	t.Run("skips unreferenced values", func(t *testing.T) {
+		t.Parallel()
+
+		var loads atomic.Int32
+		load := func(context.Context) (lazyAuthor, error) {
+			loads.Add(1)
+			return lazyAuthor{}, nil
+		}
+
+		out, err := reg.MustGet("title").ExecuteToString(context.Background(), lazyData{Title: "Hi", Author: NewLazy(load)})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1>", out)
+		assert.Zero(t, loads.Load())
+	})
+
+	t.Run("errors fail the render", func(t *testing.T) {
+		t.Parallel()
+
+		errLoad := errors.New("author service down")
+		data := lazyData{Author: NewLazy(func(context.Context) (lazyAuthor, error) {
+			return lazyAuthor{}, errLoad
+		})}
+
+		_, err := reg.MustGet("article").ExecuteToString(context.Background(), data)
+		require.ErrorIs(t, err, errLoad)
+	})
+
+	t.Run("nested values load with the render context", func(t *testing.T) {
+		t.Parallel()
+
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "request")
+
+		body := func(text string) *Lazy[string] {
+			return NewLazy(func(ctx context.Context) (string, error) {
+				return text + ":" + ctx.Value(key{}).(string), nil
+			})
+		}
+		data := lazyData{Sections: []lazySection{
+			{Heading: "One", Body: body("1")},
+			{Heading: "Two", Body: body("2")},
+		}}
+
+		out, err := reg.MustGet("sections").ExecuteToString(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h2>One</h2>1:request<h2>Two</h2>2:request", out)
+	})
+
+	t.Run("nil values", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := reg.MustGet("article").ExecuteToString(context.Background(), lazyData{Title: "Hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><p></p>", out)
+	})
+
+	t.Run("zero values", func(t *testing.T) {
+		t.Parallel()
+
+		out, err := reg.MustGet("article").ExecuteToString(context.Background(), lazyData{
+			Title:    "Hi",
+			Author:   &Lazy[lazyAuthor]{},
+			Comments: &Lazy[[]string]{},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><p></p>", out)
+	})
+}
+
+func TestWithLazyPolicy(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/article.html": &fstest.MapFile{Data: []byte(
+			`<p>{{.Author.Get.Name}}</p>{{range .Comments.Get}}<li>{{.}}</li>{{else}}<li>No comments</li>{{end}}`)},
+	}
+	errLoad := errors.New("comments service down")
+	failing := func() lazyData {
+		return lazyData{
+			Author:   NewLazy(func(context.Context) (lazyAuthor, error) { return lazyAuthor{Name: "Ana"}, nil }),
+			Comments: NewLazy(func(context.Context) ([]string, error) { return nil, errLoad }),
+		}
+	}
+
+	t.Run("renders the placeholder", func(t *testing.T) {
+		t.Parallel()
+
+		var fields []string
+		var errs []error
+		reg := MustNewRegistry(fs, WithLazyPolicy[lazyData]("Comments", LazyPolicy{
+			Mode:        LazyPlaceholder,
+			Placeholder: []string{"Comments are unavailable"},
+			OnError: func(field string, err error) {
+				fields = append(fields, field)
+				errs = append(errs, err)
+			},
+		}))
+
+		out, err := reg.MustGet("article").ExecuteToString(context.Background(), failing())
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Ana</p><li>Comments are unavailable</li>", out)
+		assert.Equal(t, []string{"Comments"}, fields)
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], errLoad)
+	})
+
+	t.Run("zero value without a placeholder", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithLazyPolicy[lazyData]("Comments", LazyPolicy{Mode: LazyPlaceholder}))
+
+		out, err := reg.MustGet("article").ExecuteToString(context.Background(), failing())
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Ana</p><li>No comments</li>", out)
+	})
+
+	t.Run("fails the render by default", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithLazyPolicy[lazyData]("Author", LazyPolicy{Mode: LazyPlaceholder}))
+
+		_, err := reg.MustGet("article").ExecuteToString(context.Background(), failing())
+		require.ErrorIs(t, err, errLoad)
+		assert.ErrorContains(t, err, "loading Comments")
+	})
+
+	t.Run("placeholder of the wrong type", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithLazyPolicy[lazyData]("Comments", LazyPolicy{Mode: LazyPlaceholder, Placeholder: "none"}))
+
+		_, err := reg.MustGet("article").ExecuteToString(context.Background(), failing())
+		require.ErrorIs(t, err, errLoad)
+		assert.ErrorContains(t, err, "placeholder string is not a []string")
+	})
+
+	t.Run("traces the loads", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs,
+			WithLazyPolicy[lazyData]("Comments", LazyPolicy{Mode: LazyPlaceholder}),
+			WithRenderTracing[lazyData](),
+		)
+
+		out, err := reg.MustGet("article").ExecuteToString(context.Background(), failing())
+		require.NoError(t, err)
+
+		before, lines := traceLines(t, out)
+		assert.Equal(t, "<p>Ana</p><li>No comments</li>", before)
+		assert.Equal(t, "kind name calls time notes", lines[1])
+		assert.Contains(t, lines, "load Author 1 T")
+		assert.Contains(t, lines, "load Comments 1 T 1 failed, placeholder rendered")
+	})
+}
+
+func TestLazy_Get(t *testing.T) {
+	t.Parallel()
+
+	var loads atomic.Int32
+	l := NewLazy(func(context.Context) (int, error) {
+		loads.Add(1)
+		return 42, nil
+	})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := l.Get()
+			assert.NoError(t, err)
+			assert.Equal(t, 42, v)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), loads.Load())
+
+	panicking := NewLazy(func(context.Context) (int, error) { panic("boom") })
+	_, err := panicking.Get()
+	require.ErrorContains(t, err, "panic: boom")
+
+	var zero Lazy[int]
+	v, err := zero.Get()
+	require.NoError(t, err)
+	assert.Zero(t, v)
+}
+
+func TestHoldsLazy(t *testing.T) {
+	t.Parallel()
+
+	type recursive struct {
+		Next *recursive
+		Tags map[string][]*Lazy[int]
+	}
+	type plain struct {
+		Name  string
+		Any   any
+		inner *Lazy[int]
+	}
+
+	assert.True(t, holdsLazy(reflect.TypeFor[lazyData]()))
+	assert.True(t, holdsLazy(reflect.TypeFor[recursive]()))
+	assert.True(t, holdsLazy(reflect.TypeFor[*Lazy[string]]()))
+	assert.False(t, holdsLazy(reflect.TypeFor[plain]()))
+	assert.False(t, holdsLazy(reflect.TypeFor[TestData]()))
+}
@@ -0,0 +1,92 @@
+package templator
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// WithLocales returns an Option declaring the locales templates are translated to, e.g. "de" or
+// "pt-BR". Only declared locales are considered by GetLocalized, so locales taken from requests
+// never reach the filesystem unchecked.
+func WithLocales[T any](locales ...string) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.locales = append(r.config.locales, locales...)
+	}
+}
+
+// GetLocalized retrieves the handler for the locale-specific variant of a template.
+// For name "home" and locale "pt-BR", it tries "home.pt-BR", then "home.pt", and finally
+// falls back to "home". Locales are matched case-insensitively against those declared with
+// WithLocales, treating "_" as "-"; undeclared locales resolve to the base template.
+func (r *Registry[T]) GetLocalized(name, locale string) (*Handler[T], error) {
+	if sub, rest, ok := r.mounted(name); ok {
+		return sub.GetLocalized(rest, locale)
+	}
+
+	candidates := r.localeCandidates(locale)
+	if len(candidates) == 0 {
+		return r.Get(name)
+	}
+
+	// Lookups are keyed by the most specific declared locale, never by the caller's input,
+	// so locales taken from requests can't grow the map.
+	key := name + "\x00" + candidates[0]
+
+	r.mu.RLock()
+	resolved, ok := r.localized[key]
+	r.mu.RUnlock()
+	if ok {
+		return r.Get(resolved)
+	}
+
+	for _, candidate := range candidates {
+		h, err := r.Get(name + "." + candidate)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			r.remember(key, h.name)
+		}
+		return h, err
+	}
+
+	h, err := r.Get(name)
+	if err == nil {
+		r.remember(key, name)
+	}
+	return h, err
+}
+
+// remember caches the template a localized lookup resolved to. With hot reload,
+// lookups are resolved every time so new translations show up.
+func (r *Registry[T]) remember(key, resolved string) {
+	if r.config.hotReload {
+		return
+	}
+
+	r.mu.Lock()
+	r.localized[key] = resolved
+	r.mu.Unlock()
+}
+
+// localeCandidates returns the declared locales matching locale, most specific first.
+func (r *Registry[T]) localeCandidates(locale string) []string {
+	locale = strings.ReplaceAll(locale, "_", "-")
+
+	var candidates []string
+	for {
+		for _, declared := range r.config.locales {
+			if strings.EqualFold(declared, locale) {
+				candidates = append(candidates, declared)
+				break
+			}
+		}
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			return candidates
+		}
+		locale = locale[:i]
+	}
+}
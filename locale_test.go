@@ -0,0 +1,92 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_GetLocalized(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":       &fstest.MapFile{Data: []byte("Hello {{.Title}}")},
+		"templates/home.de.html":    &fstest.MapFile{Data: []byte("Hallo {{.Title}}")},
+		"templates/home.pt.html":    &fstest.MapFile{Data: []byte("Olá {{.Title}}")},
+		"templates/home.pt-BR.html": &fstest.MapFile{Data: []byte("Oi {{.Title}}")},
+		"templates/home.fr.html":    &fstest.MapFile{Data: []byte("Bonjour {{.Title}}")},
+	}
+
+	reg, err := NewRegistry[TestData](fs, WithLocales[TestData]("de", "pt", "pt-BR", "es"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		locale string
+		want   string
+	}{
+		{name: "exact locale", locale: "de", want: "Hallo Ana"},
+		{name: "region variant", locale: "pt-BR", want: "Oi Ana"},
+		{name: "normalized region variant", locale: "pt_br", want: "Oi Ana"},
+		{name: "falls back to language", locale: "pt-PT", want: "Olá Ana"},
+		{name: "declared without translation", locale: "es", want: "Hello Ana"},
+		{name: "undeclared locale", locale: "fr", want: "Hello Ana"},
+		{name: "path in locale", locale: "../home", want: "Hello Ana"},
+		{name: "empty locale", locale: "", want: "Hello Ana"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for range 2 {
+				handler, err := reg.GetLocalized("home", tt.locale)
+				require.NoError(t, err)
+
+				out, err := handler.ExecuteToString(context.Background(), TestData{Title: "Ana"})
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, out)
+			}
+		})
+	}
+
+	t.Run("missing base template", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.GetLocalized("missing", "de")
+		require.Error(t, err)
+	})
+
+	t.Run("does not remember request locales", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry[TestData](fs, WithLocales[TestData]("de", "pt", "pt-BR"))
+		require.NoError(t, err)
+
+		_, err = reg.GetLocalized("home", "pt-BR")
+		require.NoError(t, err)
+		require.Len(t, reg.localized, 1)
+
+		for i := range 1000 {
+			_, err := reg.GetLocalized("home", fmt.Sprintf("xx-%d", i))
+			require.NoError(t, err)
+
+			h, err := reg.GetLocalized("home", fmt.Sprintf("PT_br-x%d", i))
+			require.NoError(t, err)
+			assert.Equal(t, "home.pt-BR", h.name)
+		}
+		assert.Len(t, reg.localized, 1)
+	})
+
+	t.Run("rejects invalid locales", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry[TestData](fs, WithLocales[TestData]("de", "../x", ""))
+		require.ErrorContains(t, err, "invalid option WithLocales: '../x' is not a valid locale")
+		require.ErrorContains(t, err, "invalid option WithLocales: '' is not a valid locale")
+	})
+}
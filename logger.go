@@ -0,0 +1,43 @@
+package templator
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithLogger returns an Option that logs registry activity to logger: template loads at debug
+// level, reloads and published drafts, which invalidate cached handlers and output, at info
+// level, templates failing to load or validate at warn level, and failed renders at error
+// level. A nil logger disables logging.
+func WithLogger[T any](logger *slog.Logger) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.logger = logger
+		if logger == nil {
+			return
+		}
+		r.config.hooks = append(r.config.hooks, executionHooks[T]{
+			post: func(ctx context.Context, name string, dur time.Duration, err error) {
+				if err != nil {
+					logger.ErrorContext(ctx, "template render failed", "template", name, "duration", dur, "error", err)
+				}
+			},
+		})
+	}
+}
+
+func (r *Registry[T]) logLoad(name string, err error) {
+	switch logger := r.config.logger; {
+	case logger == nil:
+	case err != nil:
+		logger.Warn("template load failed", "template", name, "error", err)
+	default:
+		logger.Debug("template loaded", "template", name)
+	}
+}
+
+func (r *Registry[T]) logInvalidation(msg, name string) {
+	if r.config.logger != nil {
+		r.config.logger.Info(msg, "template", name)
+	}
+}
@@ -0,0 +1,96 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of a logger.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSpace(b.buf.String()), "\n")
+}
+
+func TestWithLogger(t *testing.T) {
+	t.Parallel()
+
+	newLogger := func(out *syncBuffer) *slog.Logger {
+		return slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey || a.Key == "duration" {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+	}
+
+	t.Run("logs loads, failures, and invalidations", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"templates/home.html":   &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+			"templates/fail.html":   &fstest.MapFile{Data: []byte("{{.Missing}}")},
+			"templates/broken.html": &fstest.MapFile{Data: []byte("{{.Title")},
+		}
+
+		var out syncBuffer
+		reg := MustNewRegistry(fs, WithLogger[TestData](newLogger(&out)))
+
+		_, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		_, err = reg.MustGet("fail").ExecuteToString(context.Background(), TestData{})
+		require.Error(t, err)
+		_, err = reg.Get("broken")
+		require.Error(t, err)
+
+		fs["templates/home.html"] = &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")}
+		_, err = reg.Reload(context.Background())
+		require.NoError(t, err)
+
+		require.NoError(t, reg.SaveDraft("about", "<p>about</p>"))
+		require.NoError(t, reg.Publish("about"))
+
+		lines := out.lines()
+		require.Len(t, lines, 7)
+		assert.Equal(t, "level=DEBUG msg=\"template loaded\" template=home", lines[0])
+		assert.Equal(t, "level=DEBUG msg=\"template loaded\" template=fail", lines[1])
+		assert.True(t, strings.HasPrefix(lines[2], "level=ERROR msg=\"template render failed\" template=fail error="), lines[2])
+		assert.True(t, strings.HasPrefix(lines[3], "level=WARN msg=\"template load failed\" template=broken error="), lines[3])
+		assert.Equal(t, "level=DEBUG msg=\"template loaded\" template=home", lines[4])
+		assert.Equal(t, "level=INFO msg=\"template reloaded\" template=home", lines[5])
+		assert.Equal(t, "level=INFO msg=\"draft published\" template=about", lines[6])
+	})
+
+	t.Run("allows nil logger", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fstest.MapFS{
+			"templates/home.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+		}, WithLogger[TestData](nil))
+
+		_, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{})
+		require.Error(t, err)
+	})
+}
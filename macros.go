@@ -0,0 +1,166 @@
+package templator
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	includeMacro   = "@include"
+	componentMacro = "@component"
+
+	// componentDir is the directory of the partials invoked by @component.
+	componentDir = "components/"
+)
+
+// ErrInvalidMacro is returned when a macro directive cannot be expanded.
+var ErrInvalidMacro = errors.New("invalid macro")
+
+// WithMacros returns an Option that expands macro directives, each on a line of its own,
+// in template sources before they are parsed and before any preprocessor runs:
+//
+//	@include "legal/disclaimer"
+//	@component "card" .Product
+//
+// @include inlines the source of another template, expanding its macros too. @component
+// invokes the partial of the same name under components/ with the given data, dot by default,
+// and requires that partial to be registered with WithPartials. Parse errors in expanded
+// sources report the file and line the failing text came from.
+func WithMacros[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.macros = true
+	}
+}
+
+// sourceLine is the origin of a line of an expanded template source.
+type sourceLine struct {
+	name string
+	line int
+}
+
+// sourceMaps holds the origin of every line of the expanded source of each template,
+// and the number of frontmatter lines removed from the top of each template file.
+type sourceMaps struct {
+	mu      sync.RWMutex
+	lines   map[string][]sourceLine
+	offsets map[string]int
+}
+
+// expandMacros expands the macros of the named template source, whose frontmatter took offset
+// lines, and returns its source map.
+func (r *Registry[T]) expandMacros(name string, src []byte, offset int, fresh bool) ([]byte, []sourceLine, error) {
+	var (
+		out   bytes.Buffer
+		lines []sourceLine
+	)
+	if err := r.expandInto(&out, &lines, name, src, offset, fresh, nil); err != nil {
+		return nil, nil, err
+	}
+	return out.Bytes(), lines, nil
+}
+
+// expandInto writes the expanded source of the named template to out, appending the origin
+// of every line written to lines, counting the offset lines of frontmatter. including holds
+// the templates being included, to detect cycles.
+func (r *Registry[T]) expandInto(out *bytes.Buffer, lines *[]sourceLine, name string, src []byte, offset int, fresh bool, including []string) error {
+	if slices.Contains(including, name) {
+		return fmt.Errorf("template '%s': %w: include cycle %s", name, ErrInvalidMacro, strings.Join(append(including, name), " -> "))
+	}
+	including = append(including, name)
+
+	for i, line := range strings.SplitAfter(string(src), "\n") {
+		if line == "" {
+			continue
+		}
+
+		directive := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(directive, includeMacro+" "):
+			target, _, err := macroArgs(directive, includeMacro)
+			if err != nil {
+				return fmt.Errorf("template '%s' line %d: %w", name, i+1, err)
+			}
+
+			raw, err := r.readRaw(target, fresh)
+			if err != nil {
+				return err
+			}
+			_, included, includedOffset, err := splitFrontmatter(target, raw)
+			if err != nil {
+				return err
+			}
+			if err := r.expandInto(out, lines, target, included, includedOffset, fresh, including); err != nil {
+				return err
+			}
+
+			// Keep the line break of the directive, ending the last included line.
+			if strings.HasSuffix(line, "\n") && !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+				out.WriteString("\n")
+			}
+			continue
+		case strings.HasPrefix(directive, componentMacro+" "):
+			target, data, err := macroArgs(directive, componentMacro)
+			if err != nil {
+				return fmt.Errorf("template '%s' line %d: %w", name, i+1, err)
+			}
+
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			file := strconv.Quote(componentDir + target + string(r.config.ext))
+			left, right := cmp.Or(r.config.leftDelim, "{{"), cmp.Or(r.config.rightDelim, "}}")
+			line = indent + left + "template " + file + " " + data + right + line[len(strings.TrimRight(line, "\r\n")):]
+		}
+
+		out.WriteString(line)
+		*lines = append(*lines, sourceLine{name: name, line: offset + i + 1})
+	}
+	return nil
+}
+
+// macroArgs returns the quoted template name and the rest of a macro directive,
+// which defaults to dot.
+func macroArgs(directive, macro string) (string, string, error) {
+	args := strings.TrimSpace(strings.TrimPrefix(directive, macro))
+
+	quoted, err := strconv.QuotedPrefix(args)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: %s expects a quoted template name", ErrInvalidMacro, macro)
+	}
+
+	target, _ := strconv.Unquote(quoted)
+	data := strings.TrimSpace(args[len(quoted):])
+	if macro == includeMacro && data != "" {
+		return "", "", fmt.Errorf("%w: %s takes only a template name", ErrInvalidMacro, macro)
+	}
+	if data == "" {
+		data = "."
+	}
+	return target, data, nil
+}
+
+// locate maps a parse error in the expanded source of a template to the file and line it came from.
+func (r *Registry[T]) locate(err ErrTemplateParse) ErrTemplateParse {
+	r.sourceMaps.mu.RLock()
+	src := sourceInfo{offset: r.sourceMaps.offsets[err.Name], lines: r.sourceMaps.lines[err.Name]}
+	r.sourceMaps.mu.RUnlock()
+	return src.locate(err)
+}
+
+// locate maps a parse error in the expanded source s describes to the file and line it came from.
+func (s sourceInfo) locate(err ErrTemplateParse) ErrTemplateParse {
+	if err.Line < 1 {
+		return err
+	}
+	if err.Line <= len(s.lines) {
+		origin := s.lines[err.Line-1]
+		err.Name, err.Line = origin.name, origin.line
+		return err
+	}
+	err.Line += s.offset
+	return err
+}
@@ -0,0 +1,83 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMacros(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(
+			"<main>\n  @include \"legal/intro\"\n  @component \"card\" .Title\n</main>\n",
+		)},
+		"templates/legal/intro.html":      &fstest.MapFile{Data: []byte("<p>{{.Content}}</p>\n@include \"legal/footnote\"")},
+		"templates/legal/footnote.html":   &fstest.MapFile{Data: []byte("<small>*</small>")},
+		"templates/components/card.html":  &fstest.MapFile{Data: []byte("<div>{{.}}</div>")},
+		"templates/broken.html":           &fstest.MapFile{Data: []byte("<main>\n@include \"legal/broken\"\n</main>\n")},
+		"templates/legal/broken.html":     &fstest.MapFile{Data: []byte("<p>\n{{.Title}\n</p>")},
+		"templates/cycle.html":            &fstest.MapFile{Data: []byte("@include \"legal/cycle\"\n")},
+		"templates/legal/cycle.html":      &fstest.MapFile{Data: []byte("@include \"cycle\"\n")},
+		"templates/bad_args.html":         &fstest.MapFile{Data: []byte("@include legal/intro\n")},
+		"templates/components/other.html": &fstest.MapFile{Data: []byte("")},
+	}
+
+	reg := MustNewRegistry(fs, WithMacros[TestData](), WithPartials[TestData]("components/*"))
+
+	t.Run("expands includes and components", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "Hi", Content: "body"})
+		require.NoError(t, err)
+		assert.Equal(t, "<main>\n<p>body</p>\n<small>*</small>\n  <div>Hi</div>\n</main>\n", got)
+	})
+
+	t.Run("reports parse errors at their origin", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Get("broken")
+
+		var parseErr ErrTemplateParse
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, "legal/broken", parseErr.Name)
+		assert.Equal(t, 2, parseErr.Line)
+	})
+
+	t.Run("rejects invalid macros", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Get("cycle")
+		require.ErrorIs(t, err, ErrInvalidMacro)
+		assert.Contains(t, err.Error(), "include cycle cycle -> legal/cycle -> cycle")
+
+		_, err = reg.Get("bad_args")
+		require.ErrorIs(t, err, ErrInvalidMacro)
+		assert.Contains(t, err.Error(), "template 'bad_args' line 1")
+	})
+
+	t.Run("reloads templates when an include changes", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"templates/page.html":        &fstest.MapFile{Data: []byte("@include \"legal/intro\"\n")},
+			"templates/legal/intro.html": &fstest.MapFile{Data: []byte("old")},
+		}
+		reg := MustNewRegistry(fs, WithMacros[TestData]())
+		page := reg.MustGet("page")
+
+		fs["templates/legal/intro.html"] = &fstest.MapFile{Data: []byte("new")}
+
+		reloaded, err := reg.Reload(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"page"}, reloaded)
+
+		got, err := page.ExecuteToString(context.Background(), TestData{})
+		require.NoError(t, err)
+		assert.Equal(t, "new\n", got)
+	})
+}
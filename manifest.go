@@ -0,0 +1,71 @@
+package templator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+// Manifest lists the templates of a registry with the checksums of their sources,
+// to compare what a running service serves with a template tree.
+type Manifest struct {
+	// Templates maps template names to the checksums of their sources, as returned by Checksum.
+	Templates map[string]string `json:"templates"`
+}
+
+// Checksum returns the checksum of a template source reported in manifests.
+func Checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Manifest returns the checksums of the sources of every template in the registry, as read
+// from its filesystem, or from published drafts. Frontmatter, macros, and preprocessors are
+// not applied, so checksums match the files of the template tree.
+func (r *Registry[T]) Manifest() (Manifest, error) {
+	names, err := r.ownTemplates()
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	m := Manifest{Templates: make(map[string]string, len(names))}
+	for _, name := range names {
+		content, err := r.readRaw(name, false)
+		if err != nil {
+			return Manifest{}, err
+		}
+		m.Templates[name] = Checksum(content)
+	}
+	return m, nil
+}
+
+// health is the response of HealthHandler.
+type health struct {
+	Status string `json:"status"`
+	Manifest
+}
+
+// HealthHandler returns an http.Handler reporting the health of the registry as JSON,
+// with its manifest, for cmd/drift to compare deployed templates with a template tree:
+//
+//	{"status": "ok", "templates": {"home": "sha256:..."}}
+//
+// It replies 503 with status "closed" once the registry is closed, and 500 with
+// status "error" when the templates cannot be read.
+func (r *Registry[T]) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		res, code := health{Status: "ok"}, http.StatusOK
+		if r.closed.Load() {
+			res.Status, code = "closed", http.StatusServiceUnavailable
+		} else if m, err := r.Manifest(); err != nil {
+			res.Status, code = "error", http.StatusInternalServerError
+		} else {
+			res.Manifest = m
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(res)
+	})
+}
@@ -0,0 +1,60 @@
+package templator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Manifest(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":            &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		"templates/components/menu.html": &fstest.MapFile{Data: []byte("<nav></nav>")},
+	}
+
+	reg := MustNewRegistry[TestData](fs)
+	require.NoError(t, reg.SaveDraft("home", "<h2>{{.Title}}</h2>"))
+	require.NoError(t, reg.Publish("home"))
+
+	m, err := reg.Manifest()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"home":            Checksum([]byte("<h2>{{.Title}}</h2>")),
+		"components/menu": Checksum([]byte("<nav></nav>")),
+	}, m.Templates)
+	assert.Equal(t, "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Checksum([]byte("hello")))
+
+	t.Run("health handler", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		reg.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var got health
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, "ok", got.Status)
+		assert.Equal(t, m, got.Manifest)
+	})
+
+	t.Run("closed registry", func(t *testing.T) {
+		t.Parallel()
+
+		closed := MustNewRegistry[TestData](fs)
+		require.NoError(t, closed.Close(context.Background()))
+
+		rec := httptest.NewRecorder()
+		closed.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.JSONEq(t, `{"status": "closed", "templates": null}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,136 @@
+package templator
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// translatorNotePrefix starts template comments holding a note for translators.
+const translatorNotePrefix = "i18n:"
+
+// Message is a translatable string the templates pass to the t and T functions
+// (see WithTranslator).
+type Message struct {
+	Key string
+	// Notes are left for translators in i18n comments right before the message:
+	//
+	//	{{/* i18n: Greeting shown after sign in, %s is the user name */}}
+	//	<p>{{T "home.welcome" .Name}}</p>
+	Notes []string
+	// Locations are the "file:line" of every use of the message.
+	Locations []string
+}
+
+// Messages extracts the translatable strings of every template, sorted by key, to build the
+// catalogs of a Translator. Only literal keys are found: messages whose key is computed at
+// render time must be listed by hand.
+func (r *Registry[T]) Messages() ([]Message, error) {
+	names, err := r.ownTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*Message)
+	for _, name := range names {
+		content, err := r.readTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+
+		trees := make(map[string]*parse.Tree)
+		tree := parse.New(name)
+		tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+		text := expandEditable(string(content), r.config.leftDelim)
+		if _, err := tree.Parse(text, r.config.leftDelim, r.config.rightDelim, trees); err != nil {
+			return nil, r.locate(newParseError(name, name, err))
+		}
+
+		// The tree set holds the template itself, unless it only defines blocks, and its blocks.
+		for _, block := range slices.Sorted(maps.Keys(trees)) {
+			c := messageCollector{add: func(key, note string, pos parse.Pos) {
+				line := r.locate(ErrTemplateParse{Name: name, Line: 1 + strings.Count(text[:pos], "\n")})
+
+				m, ok := byKey[key]
+				if !ok {
+					m = &Message{Key: key}
+					byKey[key] = m
+				}
+				if note != "" && !slices.Contains(m.Notes, note) {
+					m.Notes = append(m.Notes, note)
+				}
+				m.Locations = append(m.Locations, fmt.Sprintf("%s%s:%d", line.Name, r.config.ext, line.Line))
+			}}
+			c.walk(trees[block].Root)
+		}
+	}
+
+	messages := make([]Message, 0, len(byKey))
+	for _, m := range byKey {
+		messages = append(messages, *m)
+	}
+	slices.SortFunc(messages, func(a, b Message) int { return cmp.Compare(a.Key, b.Key) })
+	return messages, nil
+}
+
+// messageCollector finds the t and T calls of a template tree, in source order, with the
+// translator note preceding each.
+type messageCollector struct {
+	add  func(key, note string, pos parse.Pos)
+	note string
+}
+
+func (c *messageCollector) walk(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			c.walk(child)
+		}
+	case *parse.CommentNode:
+		text := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(n.Text, "/*"), "*/"))
+		if note, ok := strings.CutPrefix(text, translatorNotePrefix); ok {
+			c.note = strings.TrimSpace(note)
+		}
+	case *parse.ActionNode:
+		c.pipe(n.Pipe)
+	case *parse.TemplateNode:
+		c.pipe(n.Pipe)
+	case *parse.IfNode:
+		c.branch(&n.BranchNode)
+	case *parse.WithNode:
+		c.branch(&n.BranchNode)
+	case *parse.RangeNode:
+		c.branch(&n.BranchNode)
+	}
+}
+
+func (c *messageCollector) branch(n *parse.BranchNode) {
+	c.pipe(n.Pipe)
+	c.walk(n.List)
+	c.walk(n.ElseList)
+}
+
+func (c *messageCollector) pipe(pipe *parse.PipeNode) {
+	if pipe == nil {
+		return
+	}
+	walkPipes(pipe, func(p *parse.PipeNode) error {
+		for _, cmd := range p.Cmds {
+			ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+			if !ok || (ident.Ident != "t" && ident.Ident != "T") || len(cmd.Args) < 2 {
+				continue
+			}
+			if key, ok := cmd.Args[1].(*parse.StringNode); ok {
+				c.add(key.Text, c.note, key.Pos)
+				c.note = ""
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,56 @@
+package templator
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Messages(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(
+			"---\ntitle: Home\n---\n" +
+				"{{/* i18n: Page heading */}}\n" +
+				"<h1>{{t \"home.title\"}}</h1>\n" +
+				"{{if .Title}}<p>{{T \"home.welcome\" .Title | printf \"%s\"}}</p>{{end}}\n" +
+				"<p>{{t .Content}}</p>",
+		)},
+		"templates/components/nav.html": &fstest.MapFile{Data: []byte(
+			"{{define \"links\"}}{{range .}}<a title=\"{{t \"nav.link\"}}\"></a>{{end}}{{end}}\n" +
+				"{{/* i18n: Page heading */}}{{/* i18n: Shown in the menu */}}<nav>{{t \"home.title\"}}</nav>",
+		)},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("{{if}}")},
+	}
+
+	t.Run("extracts messages with notes and locations", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fstest.MapFS{
+			"templates/home.html":           fs["templates/home.html"],
+			"templates/components/nav.html": fs["templates/components/nav.html"],
+		})
+
+		messages, err := reg.Messages()
+		require.NoError(t, err)
+		assert.Equal(t, []Message{
+			{Key: "home.title", Notes: []string{"Shown in the menu", "Page heading"}, Locations: []string{"components/nav.html:2", "home.html:5"}},
+			{Key: "home.welcome", Locations: []string{"home.html:6"}},
+			{Key: "nav.link", Locations: []string{"components/nav.html:1"}},
+		}, messages)
+	})
+
+	t.Run("reports syntax errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := MustNewRegistry[TestData](fs).Messages()
+
+		var parseErr ErrTemplateParse
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, "broken", parseErr.Name)
+		assert.Equal(t, 1, parseErr.Line)
+	})
+}
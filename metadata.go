@@ -0,0 +1,171 @@
+package templator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// pragmaPrefix starts template comments holding metadata.
+const pragmaPrefix = "templator:"
+
+// ErrInvalidPragma is returned when a metadata pragma cannot be parsed.
+var ErrInvalidPragma = errors.New("invalid pragma")
+
+// Metadata holds the values declared by a template's pragmas. Quoted values are strings,
+// true and false are booleans, numbers are int64 or float64, and other words are strings.
+type Metadata map[string]any
+
+// Metadata returns the metadata declared by the named template in its frontmatter and in
+// top-level comment pragmas:
+//
+//	---
+//	title: Home page
+//	required: [Title, Products]
+//	---
+//	{{/* templator: category="marketing" editable=true */}}
+//
+// Pragmas override frontmatter keys, and later pragmas override earlier ones.
+// Templates without frontmatter or pragmas have empty metadata.
+func (r *Registry[T]) Metadata(name string) (Metadata, error) {
+	if sub, rest, ok := r.mounted(name); ok {
+		return sub.Metadata(rest)
+	}
+
+	r.mu.RLock()
+	md, ok := r.metadata[name]
+	r.mu.RUnlock()
+	if ok {
+		return md, nil
+	}
+
+	raw, err := r.readRaw(name, false)
+	if err != nil {
+		return nil, err
+	}
+
+	frontmatter, _, _, err := splitFrontmatter(name, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := r.readTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	md, err = parseMetadata(name, string(content), r.config.leftDelim, r.config.rightDelim)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range frontmatter {
+		if _, ok := md[key]; !ok {
+			md[key] = value
+		}
+	}
+
+	if !r.config.hotReload {
+		r.mu.Lock()
+		r.metadata[name] = md
+		r.mu.Unlock()
+	}
+	return md, nil
+}
+
+// declaredMetadata returns the metadata of the named template while it is parsed: the pragmas
+// of its processed content over the frontmatter stripped from it. Syntax errors are left for
+// the parser to report with their line.
+func (r *Registry[T]) declaredMetadata(name string, content []byte, frontmatter Metadata, leftDelim, rightDelim string) (Metadata, error) {
+	md, err := parseMetadata(name, string(content), leftDelim, rightDelim)
+	if errors.Is(err, ErrInvalidPragma) {
+		return nil, err
+	}
+	if md == nil {
+		md = make(Metadata)
+	}
+
+	for key, value := range frontmatter {
+		if _, ok := md[key]; !ok {
+			md[key] = value
+		}
+	}
+	return md, nil
+}
+
+// parseMetadata collects the pragmas among the top-level comments of the template content.
+func parseMetadata(name, content, leftDelim, rightDelim string) (Metadata, error) {
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck | parse.ParseComments
+	if _, err := tree.Parse(expandEditable(content, leftDelim), leftDelim, rightDelim, make(map[string]*parse.Tree)); err != nil {
+		return nil, err
+	}
+
+	md := make(Metadata)
+	for _, node := range tree.Root.Nodes {
+		comment, ok := node.(*parse.CommentNode)
+		if !ok {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(comment.Text, "/*"), "*/"))
+		body, ok := strings.CutPrefix(text, pragmaPrefix)
+		if !ok {
+			continue
+		}
+
+		if err := parsePragma(body, md); err != nil {
+			return nil, fmt.Errorf("template '%s': %w: %w", name, ErrInvalidPragma, err)
+		}
+	}
+	return md, nil
+}
+
+// parsePragma adds the key=value pairs of a pragma body to md.
+func parsePragma(body string, md Metadata) error {
+	for {
+		body = strings.TrimSpace(body)
+		if body == "" {
+			return nil
+		}
+
+		key, rest, ok := strings.Cut(body, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\n") {
+			return fmt.Errorf("expected key=value at '%s'", body)
+		}
+
+		if rest != "" && (rest[0] == '"' || rest[0] == '`') {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return fmt.Errorf("unterminated value of '%s'", key)
+			}
+			unquoted, _ := strconv.Unquote(quoted)
+			md[key] = unquoted
+			body = rest[len(quoted):]
+			continue
+		}
+
+		raw, remaining, _ := strings.Cut(rest, " ")
+		if raw == "" {
+			return fmt.Errorf("missing value of '%s'", key)
+		}
+		md[key] = pragmaValue(raw)
+		body = remaining
+	}
+}
+
+// pragmaValue converts an unquoted pragma value to a boolean, number, or string.
+func pragmaValue(raw string) any {
+	if raw == "true" || raw == "false" {
+		return raw == "true"
+	}
+	if i, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f
+	}
+	return raw
+}
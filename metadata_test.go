@@ -0,0 +1,96 @@
+package templator
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Metadata(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(`{{/* templator: title="Home page" category="marketing" editable=true */}}
+{{- /* templator: order=3 weight=0.5 layout=wide title="Landing page" */ -}}
+{{/* an ordinary comment */}}
+<h1>{{.Title}}</h1>`)},
+		"templates/plain.html":   &fstest.MapFile{Data: []byte(`<p>{{.Content}}</p>`)},
+		"templates/broken.html":  &fstest.MapFile{Data: []byte(`{{/* templator: title="unterminated */}}`)},
+		"templates/nokey.html":   &fstest.MapFile{Data: []byte(`{{/* templator: =value */}}`)},
+		"templates/nested.html":  &fstest.MapFile{Data: []byte(`{{if .Title}}{{/* templator: hidden=true */}}{{end}}`)},
+		"templates/escaped.html": &fstest.MapFile{Data: []byte("{{/* templator: title=\"Say \\\"hi\\\"\" note=`raw text` */}}")},
+	}
+
+	reg, err := NewRegistry[TestData](fs)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		template string
+		want     Metadata
+		wantErr  string
+	}{
+		{
+			name:     "pragmas merged in order",
+			template: "home",
+			want: Metadata{
+				"title":    "Landing page",
+				"category": "marketing",
+				"editable": true,
+				"order":    int64(3),
+				"weight":   0.5,
+				"layout":   "wide",
+			},
+		},
+		{
+			name:     "no pragmas",
+			template: "plain",
+			want:     Metadata{},
+		},
+		{
+			name:     "only top-level pragmas",
+			template: "nested",
+			want:     Metadata{},
+		},
+		{
+			name:     "quoted values",
+			template: "escaped",
+			want:     Metadata{"title": `Say "hi"`, "note": "raw text"},
+		},
+		{
+			name:     "unterminated value",
+			template: "broken",
+			wantErr:  "template 'broken': invalid pragma: unterminated value of 'title'",
+		},
+		{
+			name:     "missing key",
+			template: "nokey",
+			wantErr:  "invalid pragma: expected key=value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			md, err := reg.Metadata(tt.template)
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, ErrInvalidPragma)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, md)
+		})
+	}
+
+	t.Run("missing template", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Metadata("missing")
+		require.Error(t, err)
+	})
+}
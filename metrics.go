@@ -0,0 +1,52 @@
+package templator
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics receives instrumentation events from a registry. Implementations must be safe
+// for concurrent use. The promadapter module provides one backed by Prometheus.
+type Metrics interface {
+	// RenderObserved is called after every render with its duration and error.
+	RenderObserved(name string, dur time.Duration, err error)
+	// ParseFailed is called when a template is found but fails to parse or validate.
+	ParseFailed(name string)
+	// CacheHit is called when a cached handler serves output from its cache.
+	CacheHit(name string)
+	// CacheMiss is called when a cached handler has to render the output.
+	CacheMiss(name string)
+}
+
+// WithMetrics returns an Option that reports renders, parse failures, and render cache
+// lookups to m. Renders are observed by an execution hook, so their durations include the
+// time spent in pre hooks. A nil m disables metrics.
+func WithMetrics[T any](m Metrics) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.metrics = m
+		if m == nil {
+			return
+		}
+		r.config.hooks = append(r.config.hooks, executionHooks[T]{
+			post: func(_ context.Context, name string, dur time.Duration, err error) {
+				m.RenderObserved(name, dur, err)
+			},
+		})
+	}
+}
+
+func (r *Registry[T]) observeParseFailure(name string) {
+	if r.config.metrics != nil {
+		r.config.metrics.ParseFailed(name)
+	}
+}
+
+func (h *Handler[T]) observeCache(hit bool) {
+	switch m := h.reg.config.metrics; {
+	case m == nil:
+	case hit:
+		m.CacheHit(h.name)
+	default:
+		m.CacheMiss(h.name)
+	}
+}
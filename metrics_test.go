@@ -0,0 +1,96 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics records the events it receives.
+type recordingMetrics struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (m *recordingMetrics) record(format string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, fmt.Sprintf(format, args...))
+}
+
+func (m *recordingMetrics) RenderObserved(name string, dur time.Duration, err error) {
+	m.record("render %s %t", name, err != nil)
+}
+
+func (m *recordingMetrics) ParseFailed(name string) { m.record("parse failed %s", name) }
+func (m *recordingMetrics) CacheHit(name string)    { m.record("cache hit %s", name) }
+func (m *recordingMetrics) CacheMiss(name string)   { m.record("cache miss %s", name) }
+
+func TestWithMetrics(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":   &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+		"templates/fail.html":   &fstest.MapFile{Data: []byte("{{.Missing}}")},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("{{.Title")},
+	}
+
+	t.Run("reports renders and parse failures", func(t *testing.T) {
+		t.Parallel()
+
+		var m recordingMetrics
+		reg := MustNewRegistry(fs, WithMetrics[TestData](&m))
+
+		_, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		_, err = reg.MustGet("fail").ExecuteToString(context.Background(), TestData{})
+		require.Error(t, err)
+		_, err = reg.Get("broken")
+		require.Error(t, err)
+		_, err = reg.Get("missing")
+		require.Error(t, err)
+
+		assert.Equal(t, []string{
+			"render home false",
+			"render fail true",
+			"parse failed broken",
+		}, m.events)
+	})
+
+	t.Run("reports cache lookups", func(t *testing.T) {
+		t.Parallel()
+
+		var m recordingMetrics
+		reg := MustNewRegistry(fs, WithMetrics[TestData](&m))
+		t.Cleanup(func() { reg.Close(context.Background()) })
+
+		home := reg.MustGet("home").WithCache(time.Minute, func(d TestData) string { return d.Title })
+		for range 2 {
+			_, err := home.ExecuteToString(context.Background(), TestData{Title: "Hi"})
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, []string{
+			"cache miss home",
+			"render home false",
+			"cache hit home",
+			"render home false",
+		}, m.events)
+	})
+
+	t.Run("allows nil metrics", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithMetrics[TestData](nil))
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Hi</p>", got)
+	})
+}
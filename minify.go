@@ -0,0 +1,137 @@
+package templator
+
+import (
+	"bytes"
+	"text/template/parse"
+)
+
+// rawElements are the elements whose content is left untouched by minification,
+// since whitespace is significant in them or belongs to scripts and styles.
+var rawElements = []string{"pre", "textarea", "script", "style"}
+
+// WithMinification returns an Option that minifies the HTML of templates when they are
+// parsed, stripping comments and collapsing runs of whitespace to a single space outside
+// pre, textarea, script, and style elements. Only the template text is minified, so renders
+// cost nothing extra; use MinifyHTML to minify rendered output instead. NewRegistry rejects
+// the option for extensions other than .html, .htm, .gohtml, and .tmpl.
+func WithMinification[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.minify = true
+	}
+}
+
+// MinifyHTML is a PostProcessor that minifies rendered HTML like WithMinification,
+// also trimming leading and trailing whitespace.
+func MinifyHTML(out []byte) ([]byte, error) {
+	var m minifier
+	return bytes.TrimSpace(m.minify(make([]byte, 0, len(out)), out)), nil
+}
+
+// minifyTree minifies the text of a parse tree in document order. The elements opened in one
+// branch of a conditional are assumed to be closed in the same branch.
+func minifyTree(node parse.Node, m *minifier) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			minifyTree(child, m)
+		}
+	case *parse.TextNode:
+		n.Text = m.minify(nil, n.Text)
+	case *parse.ActionNode, *parse.TemplateNode:
+		// Their output separates the whitespace around them.
+		m.space = false
+	case *parse.IfNode:
+		minifyTree(n.List, m)
+		minifyTree(n.ElseList, m)
+	case *parse.RangeNode:
+		minifyTree(n.List, m)
+		minifyTree(n.ElseList, m)
+	case *parse.WithNode:
+		minifyTree(n.List, m)
+		minifyTree(n.ElseList, m)
+	}
+}
+
+// minifier minifies HTML split into consecutive chunks, such as the text nodes of a template.
+type minifier struct {
+	// closing is the closing tag of the raw element being copied, or empty outside of one.
+	closing []byte
+	// comment is set while inside a comment that spans chunks.
+	comment bool
+	// space is set when the output ends with a collapsed run of whitespace.
+	space bool
+}
+
+// minify appends the minified src to dst.
+func (m *minifier) minify(dst, src []byte) []byte {
+	for len(src) > 0 {
+		switch {
+		case m.comment:
+			end := bytes.Index(src, []byte("-->"))
+			if end < 0 {
+				return dst
+			}
+			m.comment = false
+			src = src[end+len("-->"):]
+		case m.closing != nil:
+			m.space = false
+			end := indexFold(src, m.closing)
+			if end < 0 {
+				return append(dst, src...)
+			}
+			dst = append(dst, src[:end]...)
+			src = src[end:]
+			m.closing = nil
+		case isSpace(src[0]):
+			if !m.space {
+				dst = append(dst, ' ')
+				m.space = true
+			}
+			src = bytes.TrimLeft(src, " \t\r\n\f")
+		case bytes.HasPrefix(src, []byte("<!--")) && !bytes.HasPrefix(src, []byte("<!--[if")):
+			m.comment = true
+			src = src[len("<!--"):]
+		case src[0] == '<':
+			m.closing = rawClosingTag(src)
+			m.space = false
+			dst = append(dst, '<')
+			src = src[1:]
+		default:
+			m.space = false
+			dst = append(dst, src[0])
+			src = src[1:]
+		}
+	}
+	return dst
+}
+
+// rawClosingTag returns the closing tag of the raw element opened at the start of src, or nil.
+func rawClosingTag(src []byte) []byte {
+	for _, name := range rawElements {
+		if len(src) <= len(name)+1 || !bytes.EqualFold(src[1:len(name)+1], []byte(name)) {
+			continue
+		}
+		switch src[len(name)+1] {
+		case '>', ' ', '\t', '\r', '\n', '\f', '/':
+			return []byte("</" + name)
+		}
+	}
+	return nil
+}
+
+// indexFold is bytes.Index ignoring ASCII case.
+func indexFold(s, sep []byte) int {
+	for i := 0; i+len(sep) <= len(s); i++ {
+		if bytes.EqualFold(s[i:i+len(sep)], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f'
+}
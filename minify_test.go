@@ -0,0 +1,64 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMinification(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(`<ul>
+  {{range .}}
+    <li>  {{.}}  </li>
+  {{end}}
+</ul>
+<PRE>
+  keep   {{len .}}
+</pre>
+<script>
+  var x = 1
+  var y = 2
+</script>`)},
+	}
+
+	reg := MustNewRegistry(fs, WithMinification[[]string]())
+
+	got, err := reg.MustGet("home").ExecuteToString(context.Background(), []string{"a  b", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, "<ul> <li> a  b </li> <li> c </li> </ul> <PRE>\n  keep   2\n</pre> <script>\n  var x = 1\n  var y = 2\n</script>", got)
+}
+
+func TestMinifyHTML(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{
+		"\n<p>\n  a   b\n</p>\n":                 "<p> a b </p>",
+		"<p>a<!-- note -->b</p>":                 "<p>ab</p>",
+		"<!--[if IE]><p>ie</p><![endif]-->":      "<!--[if IE]><p>ie</p><![endif]-->",
+		"<textarea>  a\n b</textarea>  <p> </p>": "<textarea>  a\n b</textarea> <p> </p>",
+		"<style>a  { }</style><stylish>  x":      "<style>a  { }</style><stylish> x",
+		"<p>unterminated <!-- comment":           "<p>unterminated",
+	} {
+		got, err := MinifyHTML([]byte(in))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(got), in)
+	}
+}
+
+func TestMinifier_AcrossChunks(t *testing.T) {
+	t.Parallel()
+
+	var m minifier
+
+	var out []byte
+	for _, chunk := range []string{"<pre>  a", "  b</pre>  <p>x<!-- a", "b -->  y</p>"} {
+		out = m.minify(out, []byte(chunk))
+	}
+	assert.Equal(t, "<pre>  a  b</pre> <p>x y</p>", string(out))
+}
@@ -0,0 +1,118 @@
+package templator
+
+import (
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidMount is returned by Registry.Mount when a registry cannot be mounted.
+type ErrInvalidMount struct {
+	Prefix string
+	Reason string
+}
+
+func (e ErrInvalidMount) Error() string {
+	return fmt.Sprintf("cannot mount registry at '%s': %s", e.Prefix, e.Reason)
+}
+
+// Mount makes the templates of other available through r under prefix, so registries built
+// by separate packages, such as auth, admin, and marketing pages, share one lookup namespace:
+// after Mount("admin", adminRegistry), Get("admin/users") returns adminRegistry.Get("users").
+//
+// The mounted registry keeps its own file system, options, locales, and lifecycle. Get,
+// GetLocalized, Metadata, and ListTemplates go through mounts; registry-wide operations such as Reload,
+// Preload, Audit, and Close apply to the registry's own templates only. Templates under a
+// mounted prefix shadow those of r's own directory, and templates of r cannot invoke those
+// of a mounted registry, which are parsed separately.
+//
+// Prefixes must be valid fs.FS paths and must not nest within each other. Mounting a registry
+// that already mounts r, directly or not, is rejected.
+func (r *Registry[T]) Mount(prefix string, other *Registry[T]) error {
+	if r.closed.Load() {
+		return ErrRegistryClosed
+	}
+
+	prefix = fsPath(prefix)
+	switch {
+	case prefix == "." || !fs.ValidPath(prefix):
+		return ErrInvalidMount{Prefix: prefix, Reason: "prefix must be a non-empty fs.FS path"}
+	case other == nil:
+		return ErrInvalidMount{Prefix: prefix, Reason: "registry must not be nil"}
+	case other == r || other.reaches(r):
+		return ErrInvalidMount{Prefix: prefix, Reason: "registry would mount itself"}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for existing := range r.mounts {
+		if existing == prefix || strings.HasPrefix(existing, prefix+"/") || strings.HasPrefix(prefix, existing+"/") {
+			return ErrInvalidMount{Prefix: prefix, Reason: fmt.Sprintf("overlaps the registry mounted at '%s'", existing)}
+		}
+	}
+
+	if r.mounts == nil {
+		r.mounts = make(map[string]*Registry[T])
+	}
+	r.mounts[prefix] = other
+	return nil
+}
+
+// mounted returns the mounted registry serving the named template, and the name of the
+// template within it.
+func (r *Registry[T]) mounted(name string) (*Registry[T], string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for prefix, sub := range r.mounts {
+		if rest, ok := strings.CutPrefix(name, prefix+"/"); ok {
+			return sub, rest, true
+		}
+	}
+	return nil, "", false
+}
+
+// reaches reports whether r mounts target, directly or through mounted registries.
+func (r *Registry[T]) reaches(target *Registry[T]) bool {
+	r.mu.RLock()
+	subs := slices.Collect(maps.Values(r.mounts))
+	r.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub == target || sub.reaches(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendMounted appends the templates of the mounted registries, under their prefix, to the
+// names of r's own templates, dropping own templates shadowed by a mount.
+func (r *Registry[T]) appendMounted(names []string) ([]string, error) {
+	r.mu.RLock()
+	mounts := maps.Clone(r.mounts)
+	r.mu.RUnlock()
+
+	if len(mounts) == 0 {
+		return names, nil
+	}
+
+	names = slices.DeleteFunc(names, func(name string) bool {
+		_, _, shadowed := r.mounted(name)
+		return shadowed
+	})
+	for prefix, sub := range mounts {
+		subNames, err := sub.ListTemplates()
+		if err != nil {
+			return nil, fmt.Errorf("mounted registry '%s': %w", prefix, err)
+		}
+		for _, name := range subNames {
+			names = append(names, prefix+"/"+name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
@@ -0,0 +1,141 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Mount(t *testing.T) {
+	t.Parallel()
+
+	app := fstest.MapFS{
+		"templates/home.html":        &fstest.MapFile{Data: []byte("<p>home {{.Title}}</p>")},
+		"templates/admin/stale.html": &fstest.MapFile{Data: []byte("<p>shadowed</p>")},
+	}
+	admin := fstest.MapFS{
+		"views/users.tmpl":       &fstest.MapFile{Data: []byte(`{{/* templator: section="admin" */}}{{template "nav.tmpl"}}<p>users {{.Title}}</p>`)},
+		"views/users.pt.tmpl":    &fstest.MapFile{Data: []byte("<p>utilizadores</p>")},
+		"views/nav.tmpl":         &fstest.MapFile{Data: []byte("<nav></nav>")},
+		"views/reports/q1.tmpl":  &fstest.MapFile{Data: []byte("<p>q1</p>")},
+		"templates/ignored.html": &fstest.MapFile{Data: []byte("<p>ignored</p>")},
+	}
+
+	newRegistries := func(t *testing.T) (*Registry[TestData], *Registry[TestData]) {
+		t.Helper()
+
+		root := MustNewRegistry[TestData](app)
+		sub := MustNewRegistry(admin,
+			WithTemplatesPath[TestData]("views"),
+			WithExtension[TestData](".tmpl"),
+			WithPartials[TestData]("nav"),
+			WithLocales[TestData]("pt"),
+		)
+		require.NoError(t, root.Mount("admin", sub))
+		return root, sub
+	}
+
+	t.Run("resolves templates through the mount", func(t *testing.T) {
+		t.Parallel()
+
+		root, sub := newRegistries(t)
+
+		h, err := root.Get("admin/users")
+		require.NoError(t, err)
+		got, err := h.ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<nav></nav><p>users Hi</p>", got)
+
+		direct, err := sub.Get("users")
+		require.NoError(t, err)
+		assert.Same(t, direct, h, "the mounted registry caches the handler")
+
+		h, err = root.Get("admin/reports/q1")
+		require.NoError(t, err)
+		got, err = h.ExecuteToString(context.Background(), TestData{})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>q1</p>", got)
+
+		h, err = root.Get("home")
+		require.NoError(t, err)
+		got, err = h.ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>home Hi</p>", got)
+	})
+
+	t.Run("resolves localized templates and metadata", func(t *testing.T) {
+		t.Parallel()
+
+		root, _ := newRegistries(t)
+
+		h, err := root.GetLocalized("admin/users", "pt-BR")
+		require.NoError(t, err)
+		got, err := h.ExecuteToString(context.Background(), TestData{})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>utilizadores</p>", got)
+
+		md, err := root.Metadata("admin/users")
+		require.NoError(t, err)
+		assert.Equal(t, "admin", md["section"])
+	})
+
+	t.Run("lists mounted templates under their prefix", func(t *testing.T) {
+		t.Parallel()
+
+		root, _ := newRegistries(t)
+
+		names, err := root.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"admin/nav", "admin/reports/q1", "admin/users", "admin/users.pt", "home"}, names)
+	})
+
+	t.Run("reports missing mounted templates", func(t *testing.T) {
+		t.Parallel()
+
+		root, _ := newRegistries(t)
+
+		_, err := root.Get("admin/stale")
+		assert.ErrorAs(t, err, &ErrTemplateNotFound{}, "the mount shadows the registry's own templates")
+
+		_, err = root.Get("admin/../home")
+		assert.ErrorAs(t, err, &ErrInvalidTemplateName{})
+	})
+
+	t.Run("rejects invalid mounts", func(t *testing.T) {
+		t.Parallel()
+
+		root, sub := newRegistries(t)
+		other := MustNewRegistry[TestData](app)
+
+		tests := []struct {
+			name     string
+			prefix   string
+			registry *Registry[TestData]
+		}{
+			{name: "empty prefix", prefix: "", registry: other},
+			{name: "escaping prefix", prefix: "../admin", registry: other},
+			{name: "nil registry", prefix: "other", registry: nil},
+			{name: "itself", prefix: "self", registry: root},
+			{name: "same prefix", prefix: "admin", registry: other},
+			{name: "nested prefix", prefix: "admin/reports", registry: other},
+			{name: "dot prefix", prefix: "admin/../", registry: other},
+		}
+
+		for _, tt := range tests {
+			err := root.Mount(tt.prefix, tt.registry)
+			assert.ErrorAs(t, err, &ErrInvalidMount{}, tt.name)
+		}
+
+		require.NoError(t, root.Mount("marketing/en", other))
+		assert.ErrorAs(t, root.Mount("marketing", MustNewRegistry[TestData](app)), &ErrInvalidMount{}, "enclosing prefix")
+
+		err := sub.Mount("app", root)
+		assert.ErrorAs(t, err, &ErrInvalidMount{}, "cycles are rejected")
+
+		require.NoError(t, root.Close(context.Background()))
+		assert.ErrorIs(t, root.Mount("other", other), ErrRegistryClosed)
+	})
+}
@@ -0,0 +1,168 @@
+package templator
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+)
+
+// WithOutputNormalization returns an Option making Replay compare archived and current output
+// normalized with NormalizeHTML, so renders that differ only in attribute order, quoting, or
+// whitespace are not reported as changed. Renders themselves are not normalized.
+func WithOutputNormalization[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.normalize = true
+	}
+}
+
+// NormalizeHTML is a PostProcessor rewriting rendered HTML to a canonical form for comparing
+// renders, such as golden files: the attributes of start tags are sorted by name, lowercased and
+// double quoted, and runs of whitespace collapse to a newline when they contain one, or to a
+// single space otherwise. Comments and the content of pre, textarea, script, and style elements
+// are left untouched, and leading and trailing whitespace is trimmed. The result is meant for
+// comparison, not for serving.
+func NormalizeHTML(out []byte) ([]byte, error) {
+	dst := make([]byte, 0, len(out))
+	for src := out; len(src) > 0; {
+		switch {
+		case bytes.HasPrefix(src, []byte("<!--")):
+			end := bytes.Index(src, []byte("-->"))
+			if end < 0 {
+				return append(dst, src...), nil
+			}
+			dst = append(dst, src[:end+len("-->")]...)
+			src = src[end+len("-->"):]
+		case isSpace(src[0]):
+			rest := bytes.TrimLeft(src, " \t\r\n\f")
+			if bytes.ContainsRune(src[:len(src)-len(rest)], '\n') {
+				dst = append(dst, '\n')
+			} else {
+				dst = append(dst, ' ')
+			}
+			src = rest
+		case src[0] == '<' && len(src) > 1 && isTagStart(src[1]):
+			closing := rawClosingTag(src)
+			tag, rest := normalizeTag(src)
+			dst = append(dst, tag...)
+			src = rest
+			if closing == nil {
+				continue
+			}
+
+			end := indexFold(src, closing)
+			if end < 0 {
+				return append(dst, src...), nil
+			}
+			dst = append(dst, src[:end]...)
+			src = src[end:]
+		default:
+			dst = append(dst, src[0])
+			src = src[1:]
+		}
+	}
+	return bytes.TrimSpace(dst), nil
+}
+
+// isTagStart reports whether c, following '<', starts a start or end tag.
+func isTagStart(c byte) bool {
+	return c == '/' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// attribute is an attribute of a start tag; value is nil for attributes without one.
+type attribute struct {
+	name  string
+	value []byte
+}
+
+// normalizeTag returns the normalized tag at the start of src and the input following it.
+// Malformed tags, running to the end of src, are returned unchanged.
+func normalizeTag(src []byte) ([]byte, []byte) {
+	i := 1
+	if src[i] == '/' {
+		i++
+	}
+	for i < len(src) && !isSpace(src[i]) && src[i] != '>' && src[i] != '/' {
+		i++
+	}
+	name := src[:i]
+
+	var (
+		attrs       []attribute
+		selfClosing bool
+	)
+	for {
+		for i < len(src) && isSpace(src[i]) {
+			i++
+		}
+		if i == len(src) {
+			return src, nil
+		}
+
+		switch src[i] {
+		case '>':
+			tag := append([]byte(nil), name...)
+			slices.SortStableFunc(attrs, func(a, b attribute) int { return strings.Compare(a.name, b.name) })
+			for _, attr := range attrs {
+				tag = append(tag, ' ')
+				tag = append(tag, attr.name...)
+				if attr.value != nil {
+					tag = appendQuoted(append(tag, '='), attr.value)
+				}
+			}
+			if selfClosing {
+				tag = append(tag, " /"...)
+			}
+			return append(tag, '>'), src[i+1:]
+		case '/':
+			selfClosing = true
+			i++
+			continue
+		}
+		selfClosing = false
+
+		start := i
+		for i < len(src) && !isSpace(src[i]) && src[i] != '>' && src[i] != '=' && src[i] != '/' {
+			i++
+		}
+		attr := attribute{name: strings.ToLower(string(src[start:i]))}
+
+		j := i
+		for j < len(src) && isSpace(src[j]) {
+			j++
+		}
+		if j < len(src) && src[j] == '=' {
+			for j++; j < len(src) && isSpace(src[j]); j++ {
+			}
+			if j == len(src) {
+				return src, nil
+			}
+
+			if quote := src[j]; quote == '"' || quote == '\'' {
+				end := bytes.IndexByte(src[j+1:], quote)
+				if end < 0 {
+					return src, nil
+				}
+				attr.value = src[j+1 : j+1+end]
+				i = j + end + 2
+			} else {
+				i = j
+				for i < len(src) && !isSpace(src[i]) && src[i] != '>' {
+					i++
+				}
+				attr.value = src[j:i]
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+}
+
+// appendQuoted appends value in double quotes, or in single quotes when it holds a double quote.
+func appendQuoted(dst, value []byte) []byte {
+	quote := byte('"')
+	if bytes.IndexByte(value, '"') >= 0 {
+		quote = '\''
+	}
+	dst = append(dst, quote)
+	dst = append(dst, value...)
+	return append(dst, quote)
+}
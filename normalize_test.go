@@ -0,0 +1,110 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeHTML(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "attribute order",
+			in:   `<a href="/" class="nav" id=home>Home</a>`,
+			want: `<a class="nav" href="/" id="home">Home</a>`,
+		},
+		{
+			name: "attribute quoting and case",
+			in:   `<input TYPE='text' value = 'say "hi"' disabled>`,
+			want: `<input disabled type="text" value='say "hi"'>`,
+		},
+		{
+			name: "self-closing and end tags",
+			in:   `<br/><img alt="" src="x.png" /></p >`,
+			want: `<br /><img alt="" src="x.png" /></p>`,
+		},
+		{
+			name: "whitespace",
+			in:   "\n  <ul>\n    <li>a   b</li>\t<li>c</li>\n  </ul>\n",
+			want: "<ul>\n<li>a b</li> <li>c</li>\n</ul>",
+		},
+		{
+			name: "raw elements and comments",
+			in:   "<pre class=x>  a\n   b</pre>  <!-- <b  c=1 a=2> -->  <script>if (a  <b) {}</script>",
+			want: "<pre class=\"x\">  a\n   b</pre> <!-- <b  c=1 a=2> --> <script>if (a  <b) {}</script>",
+		},
+		{
+			name: "text",
+			in:   `1 < 2 and 3 > 2`,
+			want: `1 < 2 and 3 > 2`,
+		},
+		{
+			name: "malformed tag",
+			in:   `<a href="x`,
+			want: `<a href="x`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NormalizeHTML([]byte(tt.in))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+
+	t.Run("equivalent renders", func(t *testing.T) {
+		t.Parallel()
+
+		a, err := NormalizeHTML([]byte("<div id=\"a\" class=\"b\">\n    <p>Hi</p>\n</div>"))
+		require.NoError(t, err)
+		b, err := NormalizeHTML([]byte("<div class='b' id='a'>\n<p>Hi</p>\n</div>\n"))
+		require.NoError(t, err)
+		assert.Equal(t, string(a), string(b))
+	})
+}
+
+func TestRegistry_Replay_Normalized(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<p id=\"t\" class=\"title\">\n  {{.Title}}\n</p>")},
+	}
+	archived := Archived[TestData]{
+		Template: "home",
+		Data:     TestData{Title: "Hi"},
+		Output:   []byte("<p class=\"title\" id=\"t\">\nHi\n</p>\n"),
+	}
+
+	tests := []struct {
+		name        string
+		opts        []Option[TestData]
+		wantChanged bool
+	}{
+		{name: "exact", wantChanged: true},
+		{name: "normalized", opts: []Option[TestData]{WithOutputNormalization[TestData]()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reg := MustNewRegistry(fs, tt.opts...)
+			result, err := reg.Replay(context.Background(), archived)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanged, result.Changed)
+			assert.Equal(t, tt.wantChanged, result.Diff != "")
+		})
+	}
+}
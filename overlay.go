@@ -0,0 +1,88 @@
+package templator
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// overlayFS resolves files through a stack of filesystems, the first layer holding a file winning.
+// Directory listings are merged across layers.
+type overlayFS struct {
+	layers []fs.FS
+}
+
+// newOverlayFS returns a filesystem consulting overlays from last to first, then base.
+func newOverlayFS(base fs.FS, overlays []fs.FS) *overlayFS {
+	layers := slices.Clone(overlays)
+	slices.Reverse(layers)
+	return &overlayFS{layers: append(layers, base)}
+}
+
+// newRootsFS returns a filesystem resolving files under the directories of base in
+// priority order, the first directory holding a file winning.
+func newRootsFS(base fs.FS, dirs []string) *overlayFS {
+	layers := make([]fs.FS, 0, len(dirs))
+	for _, dir := range dirs {
+		// dirs are validated fs.FS paths, for which fs.Sub cannot fail.
+		sub, _ := fs.Sub(base, dir)
+		layers = append(layers, sub)
+	}
+	return &overlayFS{layers: layers}
+}
+
+func (o *overlayFS) Open(name string) (fs.File, error) {
+	for _, layer := range o.layers {
+		f, err := layer.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return f, err
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o *overlayFS) ReadFile(name string) ([]byte, error) {
+	for _, layer := range o.layers {
+		content, err := fs.ReadFile(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return content, err
+	}
+	return nil, &fs.PathError{Op: "readfile", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o *overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var (
+		merged []fs.DirEntry
+		seen   = make(map[string]bool)
+		found  bool
+	)
+	for _, layer := range o.layers {
+		entries, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		found = true
+		for _, entry := range entries {
+			if !seen[entry.Name()] {
+				seen[entry.Name()] = true
+				merged = append(merged, entry)
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	slices.SortFunc(merged, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return merged, nil
+}
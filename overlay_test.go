@@ -0,0 +1,184 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithOverlayFS(t *testing.T) {
+	t.Parallel()
+
+	base := fstest.MapFS{
+		"templates/home.html":              &fstest.MapFile{Data: []byte(`{{template "components/header.html" .}}<p>{{.Content}}</p>`)},
+		"templates/about.html":             &fstest.MapFile{Data: []byte("<p>about</p>")},
+		"templates/components/header.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}
+	theme := fstest.MapFS{
+		"templates/components/header.html": &fstest.MapFile{Data: []byte("<h1 class=\"theme\">{{.Title}}</h1>")},
+		"templates/pricing.html":           &fstest.MapFile{Data: []byte("<p>pricing</p>")},
+	}
+	tenant := fstest.MapFS{
+		"templates/about.html":             &fstest.MapFile{Data: []byte("<p>tenant about</p>")},
+		"templates/components/header.html": &fstest.MapFile{Data: []byte("<h1 class=\"tenant\">{{.Title}}</h1>")},
+	}
+
+	reg, err := NewRegistry[TestData](
+		base,
+		WithPartials[TestData]("components/*"),
+		WithOverlayFS[TestData](theme),
+		WithOverlayFS[TestData](tenant),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "partial from latest overlay",
+			template: "home",
+			want:     `<h1 class="tenant">Hi</h1><p>body</p>`,
+		},
+		{
+			name:     "template from overlay",
+			template: "about",
+			want:     "<p>tenant about</p>",
+		},
+		{
+			name:     "template only in lower overlay",
+			template: "pricing",
+			want:     "<p>pricing</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler, err := reg.Get(tt.template)
+			require.NoError(t, err)
+
+			out, err := handler.ExecuteToString(context.Background(), TestData{Title: "Hi", Content: "body"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out)
+		})
+	}
+
+	t.Run("lists merged templates", func(t *testing.T) {
+		t.Parallel()
+
+		names, err := reg.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"about", "components/header", "home", "pricing"}, names)
+	})
+
+	t.Run("missing everywhere", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Get("missing")
+		require.Error(t, err)
+	})
+
+	t.Run("rejects nil overlay", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry[TestData](base, WithOverlayFS[TestData](nil))
+		require.ErrorContains(t, err, "invalid option WithOverlayFS: overlay filesystem must not be nil")
+	})
+}
+
+func TestWithTemplatePaths(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"app/templates/home.html":                &fstest.MapFile{Data: []byte(`{{template "components/header.html" .}}<p>app home</p>`)},
+		"app/templates/components/header.html":   &fstest.MapFile{Data: []byte("<h1 class=\"app\">{{.Title}}</h1>")},
+		"lib/templates/home.html":                &fstest.MapFile{Data: []byte("<p>lib home</p>")},
+		"lib/templates/about.html":               &fstest.MapFile{Data: []byte(`{{template "components/header.html" .}}<p>lib about</p>`)},
+		"lib/templates/components/header.html":   &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		"lib/templates/components/footer.html":   &fstest.MapFile{Data: []byte("<footer></footer>")},
+		"vendor/templates/pricing.html":          &fstest.MapFile{Data: []byte("<p>pricing</p>")},
+		"vendor/templates/components/ignored.go": &fstest.MapFile{Data: []byte("package ignored")},
+	}
+
+	reg, err := NewRegistry(
+		fs,
+		WithTemplatePaths[TestData]("app/templates", "lib/templates", "vendor/templates"),
+		WithPartials[TestData]("components/*"),
+		WithStrictInit[TestData](),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "template from the first path", template: "home", want: `<h1 class="app">Hi</h1><p>app home</p>`},
+		{name: "partial overridden by the first path", template: "about", want: `<h1 class="app">Hi</h1><p>lib about</p>`},
+		{name: "template only in the last path", template: "pricing", want: "<p>pricing</p>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := reg.Get(tt.template)
+			require.NoError(t, err)
+
+			got, err := h.ExecuteToString(context.Background(), TestData{Title: "Hi"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("lists templates across paths", func(t *testing.T) {
+		t.Parallel()
+
+		names, err := reg.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"about", "components/footer", "components/header", "home", "pricing"}, names)
+	})
+
+	t.Run("later WithTemplatesPath replaces the paths", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(fs,
+			WithTemplatePaths[TestData]("app/templates", "lib/templates"),
+			WithTemplatesPath[TestData]("vendor/templates"),
+		)
+		require.NoError(t, err)
+
+		names, err := reg.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pricing"}, names)
+	})
+
+	t.Run("strict init reports missing paths", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fs,
+			WithTemplatePaths[TestData]("app/templates", "shared/templates"),
+			WithStrictInit[TestData](),
+		)
+
+		var dirErr ErrInvalidTemplateDir
+		require.ErrorAs(t, err, &dirErr)
+		assert.Equal(t, "shared/templates", dirErr.Path)
+	})
+
+	t.Run("rejects invalid paths", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fs, WithTemplatePaths[TestData]("app/templates", "../lib"))
+
+		var optErr ErrInvalidOption
+		require.ErrorAs(t, err, &optErr)
+		assert.Equal(t, []string{"WithTemplatePaths"}, optErr.Options)
+	})
+}
@@ -0,0 +1,104 @@
+package templator
+
+import (
+	"html/template"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/template/parse"
+)
+
+// parsedPartials caches the parse trees of partials, so a partial shared by many templates is
+// parsed once rather than once per template. Each template receives copies of the trees, as
+// html/template rewrites the trees of a template set when escaping it.
+//
+// Every template still gets its own template set rather than being a view onto a single set
+// of the whole tree: pages define the blocks of their layout, such as "content", under the
+// same names, and per-call options, drafts, and reloads apply to one template at a time.
+//
+// Registries of a RegistryGroup share the cache. Parsing only checks that the functions a
+// template calls are defined, so trees are keyed by the names of the functions they were
+// parsed with, and shared between registries defining the same ones.
+type parsedPartials struct {
+	mu       sync.Mutex
+	partials map[partialKey]parsedPartial
+}
+
+// partialKey identifies the trees of a partial file parsed with a set of function names.
+type partialKey struct {
+	file, funcs string
+}
+
+// parsedPartial holds the trees parsed from the source of a partial with the given hash and
+// delimiters, by template name.
+type parsedPartial struct {
+	hash        uint64
+	left, right string
+	trees       map[string]*parse.Tree
+}
+
+// addPartial parses the partial source into tmpl as the template named file. Unless the
+// overrides install functions, which affect parsing, the trees are parsed once per source.
+func (r *Registry[T]) addPartial(tmpl *template.Template, file string, content []byte, overrides getConfig) error {
+	if overrides.funcMap != nil {
+		_, err := tmpl.New(file).Parse(string(content))
+		return err
+	}
+
+	hash := hashSource(content)
+	key := partialKey{file: file, funcs: r.funcNames()}
+	r.partials.mu.Lock()
+	cached, ok := r.partials.partials[key]
+	r.partials.mu.Unlock()
+
+	if !ok || cached.hash != hash || cached.left != overrides.leftDelim || cached.right != overrides.rightDelim {
+		set := template.New(file).
+			Delims(overrides.leftDelim, overrides.rightDelim).
+			Funcs(editableFuncs).
+			Funcs(r.config.funcMap)
+		if r.config.strict {
+			set.Funcs(strictFuncs)
+		}
+		if _, err := set.Parse(string(content)); err != nil {
+			return err
+		}
+
+		cached = parsedPartial{hash: hash, left: overrides.leftDelim, right: overrides.rightDelim, trees: make(map[string]*parse.Tree)}
+		for _, t := range set.Templates() {
+			if t.Tree != nil {
+				cached.trees[t.Name()] = t.Tree
+			}
+		}
+
+		r.partials.mu.Lock()
+		if r.partials.partials == nil {
+			r.partials.partials = make(map[partialKey]parsedPartial)
+		}
+		r.partials.partials[key] = cached
+		r.partials.mu.Unlock()
+	}
+
+	for name, tree := range cached.trees {
+		tree := tree.Copy()
+		tree.ParseName = file
+		if _, err := tmpl.AddParseTree(name, tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// funcNames returns the sorted names of the functions the registry parses templates with,
+// joined by commas.
+func (r *Registry[T]) funcNames() string {
+	r.funcNamesOnce.Do(func() {
+		names := slices.Collect(maps.Keys(r.config.funcMap))
+		if r.config.strict {
+			names = slices.AppendSeq(names, maps.Keys(strictFuncs))
+		}
+		slices.Sort(names)
+		r.funcNamesKey = strings.Join(slices.Compact(names), ",")
+	})
+	return r.funcNamesKey
+}
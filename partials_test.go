@@ -0,0 +1,102 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_ParsedPartials(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":             &fstest.MapFile{Data: []byte(`{{template "header" .}}<main>home</main>`)},
+		"templates/about.html":            &fstest.MapFile{Data: []byte(`{{template "header" .}}<main>about</main>`)},
+		"templates/partials/header.html":  &fstest.MapFile{Data: []byte(`{{define "header"}}<h1>{{.Title}}</h1>{{end}}`)},
+		"templates/partials/scripts.html": &fstest.MapFile{Data: []byte(`<script>var title = {{.Title}};</script>`)},
+	}
+
+	reg := MustNewRegistry(fs, WithPartials[TestData]("partials/*"))
+
+	for _, name := range []string{"home", "about"} {
+		got, err := reg.MustGet(name).ExecuteToString(context.Background(), TestData{Title: "<b>"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>&lt;b&gt;</h1><main>"+name+"</main>", got)
+	}
+
+	require.Len(t, reg.partials.partials, 2, "partials are parsed once")
+	header := reg.partials.partials[partialKey{file: "partials/header.html"}]
+	assert.Equal(t, `<h1>{{.Title}}</h1>`, header.trees["header"].Root.String(), "escaping leaves cached trees untouched")
+
+	got, err := reg.MustGet("partials/scripts").ExecuteToString(context.Background(), TestData{Title: "x"})
+	require.NoError(t, err)
+	assert.Equal(t, `<script>var title = "x";</script>`, got)
+
+	t.Run("reparses changed partials", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"templates/home.html":            &fstest.MapFile{Data: []byte(`{{template "header" .}}`)},
+			"templates/partials/header.html": &fstest.MapFile{Data: []byte(`{{define "header"}}v1{{end}}`)},
+		}
+		reg := MustNewRegistry(fs, WithPartials[TestData]("partials/*"))
+		reg.MustGet("home")
+
+		fs["templates/partials/header.html"] = &fstest.MapFile{Data: []byte(`{{define "header"}}v2{{end}}`)}
+		_, err := reg.Reload(context.Background())
+		require.NoError(t, err)
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{})
+		require.NoError(t, err)
+		assert.Equal(t, "v2", got)
+	})
+}
+
+func benchmarkFS(pages, partials int) fstest.MapFS {
+	fs := fstest.MapFS{}
+	for i := range partials {
+		fs[fmt.Sprintf("templates/partials/p%d.html", i)] = &fstest.MapFile{
+			Data: []byte(fmt.Sprintf(`{{define "p%d"}}<div class="p%d">{{range .}}<span>{{.}}</span>{{end}}</div>{{end}}`, i, i)),
+		}
+	}
+	for i := range pages {
+		fs[fmt.Sprintf("templates/page%d.html", i)] = &fstest.MapFile{
+			Data: []byte(fmt.Sprintf(`<h1>{{.Title}}</h1>{{template "p%d" .Content}}`, i%partials)),
+		}
+	}
+	return fs
+}
+
+func BenchmarkRegistry_Get(b *testing.B) {
+	fs := benchmarkFS(100, 20)
+	reg := MustNewRegistry(fs, WithPartials[TestData]("partials/*"))
+	require.NoError(b, reg.Preload(context.Background()))
+
+	for b.Loop() {
+		reg.MustGet("page42")
+	}
+}
+
+func BenchmarkRegistry_Preload(b *testing.B) {
+	fs := benchmarkFS(100, 20)
+
+	for b.Loop() {
+		reg := MustNewRegistry(fs, WithPartials[TestData]("partials/*"))
+		require.NoError(b, reg.Preload(context.Background()))
+	}
+}
+
+func BenchmarkRegistry_ParseWithPartials(b *testing.B) {
+	fs := benchmarkFS(1, 20)
+	reg := MustNewRegistry(fs, WithPartials[TestData]("partials/*"))
+
+	for b.Loop() {
+		if _, err := reg.parse("page0", getConfig{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
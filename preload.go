@@ -0,0 +1,87 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// Preload parses the named templates, or every template when no name is given, and stores
+// their handlers for Get, so the first requests after a deploy don't pay for parsing. Unlike
+// Get, which parses one template at a time, templates are parsed concurrently by up to
+// GOMAXPROCS workers. Templates already loaded are skipped. Parse errors are joined; the
+// templates that parsed are stored regardless. Cancelling ctx stops parsing templates not
+// yet started. With hot reload, templates are parsed to check them but not stored.
+func (r *Registry[T]) Preload(ctx context.Context, names ...string) error {
+	if r.closed.Load() {
+		return ErrRegistryClosed
+	}
+
+	if len(names) == 0 {
+		var err error
+		if names, err = r.ownTemplates(); err != nil {
+			return err
+		}
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		todo = make(chan string)
+	)
+	for range min(runtime.GOMAXPROCS(0), len(names)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range todo {
+				if err := r.preload(name); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	var err error
+dispatch:
+	for _, name := range names {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		select {
+		case todo <- name:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break dispatch
+		}
+	}
+	close(todo)
+	wg.Wait()
+
+	return errors.Join(append(errs, err)...)
+}
+
+// preload parses the named template, unless already loaded, and stores its handler.
+func (r *Registry[T]) preload(name string) error {
+	r.mu.RLock()
+	_, ok := r.templates[name]
+	r.mu.RUnlock()
+	if ok {
+		return nil
+	}
+
+	h, err := r.parse(name, getConfig{})
+	if err != nil || r.config.hotReload {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.templates[name]; !ok {
+		r.templates[name] = h
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Preload(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{}
+	for i := range 20 {
+		fs[fmt.Sprintf("templates/page%d.html", i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf("<p>%d {{.Title}}</p>", i))}
+	}
+
+	t.Run("loads every template", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+		loaded := reg.MustGet("page3")
+
+		require.NoError(t, reg.Preload(context.Background()))
+		assert.Len(t, reg.templates, 20)
+		assert.Same(t, loaded, reg.MustGet("page3"), "loaded templates are kept")
+
+		got, err := reg.MustGet("page7").ExecuteToString(context.Background(), TestData{Title: "a"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>7 a</p>", got)
+	})
+
+	t.Run("loads named templates and joins errors", func(t *testing.T) {
+		t.Parallel()
+
+		broken := fstest.MapFS{
+			"templates/home.html":   &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+			"templates/broken.html": &fstest.MapFile{Data: []byte("{{.Title")},
+		}
+		reg := MustNewRegistry[TestData](broken)
+
+		err := reg.Preload(context.Background(), "home", "broken", "missing")
+		require.ErrorAs(t, err, new(ErrTemplateParse))
+		require.ErrorAs(t, err, new(ErrTemplateNotFound))
+		assert.Contains(t, reg.templates, "home")
+		assert.Len(t, reg.templates, 1)
+	})
+
+	t.Run("stops on cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		reg := MustNewRegistry[TestData](fs)
+		require.ErrorIs(t, reg.Preload(ctx), context.Canceled)
+	})
+
+	t.Run("closed registry", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+		require.NoError(t, reg.Close(context.Background()))
+		require.ErrorIs(t, reg.Preload(context.Background()), ErrRegistryClosed)
+	})
+}
@@ -0,0 +1,72 @@
+package templator
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Preprocessor rewrites the source of the named template before it is parsed.
+type Preprocessor func(name string, src []byte) ([]byte, error)
+
+// WithPreprocessors returns an Option that runs fns, in order, on the source of every template
+// and partial before it is parsed, e.g. to expand custom shorthands, inline include directives,
+// or strip design tool artifacts. Validation, metadata pragmas, and Reload all see the processed
+// source. Preprocessors run once per distinct source, and a change that processes to the same
+// output doesn't cause Reload to re-parse anything.
+func WithPreprocessors[T any](fns ...Preprocessor) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.preprocessors = append(r.config.preprocessors, fns...)
+	}
+}
+
+// preprocessed memoizes the processed source of each template, keyed on the hash of its raw source.
+type preprocessed struct {
+	mu      sync.Mutex
+	sources map[string]processedSource
+}
+
+type processedSource struct {
+	raw uint64
+	out []byte
+}
+
+// preprocess runs the registry's preprocessors on the source of the named template.
+func (r *Registry[T]) preprocess(name string, src []byte) ([]byte, error) {
+	if len(r.config.preprocessors) == 0 {
+		return src, nil
+	}
+
+	raw := hashSource(src)
+
+	r.processed.mu.Lock()
+	cached, ok := r.processed.sources[name]
+	r.processed.mu.Unlock()
+	if ok && cached.raw == raw {
+		return cached.out, nil
+	}
+
+	out, err := r.runPreprocessors(name, src)
+	if err != nil {
+		return nil, err
+	}
+
+	r.processed.mu.Lock()
+	if r.processed.sources == nil {
+		r.processed.sources = make(map[string]processedSource)
+	}
+	r.processed.sources[name] = processedSource{raw: raw, out: out}
+	r.processed.mu.Unlock()
+	return out, nil
+}
+
+// runPreprocessors runs the preprocessors on the named template source, without memoizing.
+func (r *Registry[T]) runPreprocessors(name string, src []byte) ([]byte, error) {
+	out := src
+	for _, fn := range r.config.preprocessors {
+		var err error
+		if out, err = fn(name, out); err != nil {
+			return nil, fmt.Errorf("template '%s': preprocessing failed: %w", name, err)
+		}
+	}
+	return out, nil
+}
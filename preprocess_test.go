@@ -0,0 +1,133 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPreprocessors(t *testing.T) {
+	t.Parallel()
+
+	// expandTitle turns the @title shorthand into a field reference.
+	expandTitle := func(_ string, src []byte) ([]byte, error) {
+		return bytes.ReplaceAll(src, []byte("@title"), []byte("{{.Title}}")), nil
+	}
+
+	// stripDesignIDs removes attributes left behind by a design tool.
+	designID := regexp.MustCompile(` data-figma-id="[^"]*"`)
+	stripDesignIDs := func(_ string, src []byte) ([]byte, error) {
+		return designID.ReplaceAll(src, nil), nil
+	}
+
+	newFS := func() fstest.MapFS {
+		return fstest.MapFS{
+			"templates/home.html":              &fstest.MapFile{Data: []byte(`<h1 data-figma-id="1:2">@title</h1>{{template "components/nav.html" .}}`)},
+			"templates/components/nav.html":    &fstest.MapFile{Data: []byte(`<nav>@title</nav>`)},
+			"templates/components/broken.html": &fstest.MapFile{Data: []byte(`ok`)},
+		}
+	}
+
+	t.Run("processes templates and partials before parsing", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(newFS(),
+			WithPartials[TestData]("components/*"),
+			WithFieldValidation(TestData{}),
+			WithPreprocessors[TestData](expandTitle, stripDesignIDs),
+		)
+
+		got, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1><nav>Hi</nav>", got)
+	})
+
+	t.Run("runs once per distinct source", func(t *testing.T) {
+		t.Parallel()
+
+		var runs atomic.Int64
+		counting := func(_ string, src []byte) ([]byte, error) {
+			runs.Add(1)
+			return src, nil
+		}
+
+		fs := newFS()
+		reg := MustNewRegistry(fs, WithHotReload[TestData](), WithPreprocessors[TestData](counting))
+
+		for range 3 {
+			_, err := reg.Get("components/nav")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int64(1), runs.Load())
+
+		fs["templates/components/nav.html"] = &fstest.MapFile{Data: []byte(`<nav></nav>`)}
+		_, err := reg.Get("components/nav")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), runs.Load())
+	})
+
+	t.Run("reload compares processed sources", func(t *testing.T) {
+		t.Parallel()
+
+		fs := newFS()
+		reg := MustNewRegistry(fs,
+			WithPartials[TestData]("components/*"),
+			WithPreprocessors[TestData](stripDesignIDs),
+		)
+		reg.MustGet("home")
+
+		fs["templates/home.html"] = &fstest.MapFile{Data: []byte(`<h1 data-figma-id="9:9">@title</h1>{{template "components/nav.html" .}}`)}
+
+		reloaded, err := reg.Reload(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, reloaded)
+	})
+
+	t.Run("processes drafts", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(newFS(), WithPreprocessors[TestData](expandTitle))
+		require.NoError(t, reg.SaveDraft("about", "<p>@title</p>"))
+
+		var buf bytes.Buffer
+		require.NoError(t, reg.Preview(context.Background(), &buf, "about", TestData{Title: "Hi"}))
+		assert.Equal(t, "<p>Hi</p>", buf.String())
+
+		require.NoError(t, reg.Publish("about"))
+
+		got, err := reg.MustGet("about").ExecuteToString(context.Background(), TestData{Title: "Hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Hi</p>", got)
+	})
+
+	t.Run("returns preprocessor errors", func(t *testing.T) {
+		t.Parallel()
+
+		errBroken := errors.New("broken")
+		reg := MustNewRegistry(newFS(), WithPreprocessors[TestData](func(name string, src []byte) ([]byte, error) {
+			if name == "components/broken" {
+				return nil, errBroken
+			}
+			return src, nil
+		}))
+
+		_, err := reg.Get("components/broken")
+		require.ErrorIs(t, err, errBroken)
+		assert.Contains(t, err.Error(), "template 'components/broken': preprocessing failed")
+	})
+
+	t.Run("rejects nil preprocessor", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(newFS(), WithPreprocessors[TestData](nil))
+		require.ErrorContains(t, err, "preprocessor must not be nil")
+		assert.Nil(t, reg)
+	})
+}
@@ -0,0 +1,96 @@
+// Package promadapter exposes templator render metrics as Prometheus collectors.
+//
+// Metrics implements templator.Metrics and templator.QuotaMetrics:
+//
+//	m := promadapter.New(prometheus.DefaultRegisterer)
+//	reg, err := templator.NewRegistry[PageData](fs, templator.WithMetrics[PageData](m))
+//
+// It lives in its own module so the root templator module does not depend on Prometheus.
+package promadapter
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics records templator events in Prometheus counters and a histogram,
+// all labeled by template name:
+//
+//   - templator_renders_total, also labeled by status, "ok" or "error"
+//   - templator_render_duration_seconds
+//   - templator_parse_errors_total
+//   - templator_cache_hits_total and templator_cache_misses_total
+//   - templator_quota_exceeded_total, also labeled by tenant and limit
+type Metrics struct {
+	renders     *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	parseErrors *prometheus.CounterVec
+	cacheHits   *prometheus.CounterVec
+	cacheMisses *prometheus.CounterVec
+	quotas      *prometheus.CounterVec
+}
+
+// New creates Metrics and registers its collectors with reg. It panics if a collector
+// is already registered, like prometheus.MustRegister.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		renders: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "templator_renders_total",
+			Help: "Number of template renders by template and status.",
+		}, []string{"template", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "templator_render_duration_seconds",
+			Help:    "Duration of template renders by template.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"template"}),
+		parseErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "templator_parse_errors_total",
+			Help: "Number of templates that failed to parse or validate.",
+		}, []string{"template"}),
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "templator_cache_hits_total",
+			Help: "Number of renders served from the render cache.",
+		}, []string{"template"}),
+		cacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "templator_cache_misses_total",
+			Help: "Number of cached renders that had to execute the template.",
+		}, []string{"template"}),
+		quotas: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "templator_quota_exceeded_total",
+			Help: "Number of renders rejected by tenant quotas.",
+		}, []string{"tenant", "template", "limit"}),
+	}
+	reg.MustRegister(m.renders, m.duration, m.parseErrors, m.cacheHits, m.cacheMisses, m.quotas)
+	return m
+}
+
+// RenderObserved counts the render and observes its duration.
+func (m *Metrics) RenderObserved(name string, dur time.Duration, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	m.renders.WithLabelValues(name, status).Inc()
+	m.duration.WithLabelValues(name).Observe(dur.Seconds())
+}
+
+// ParseFailed counts a parse error.
+func (m *Metrics) ParseFailed(name string) {
+	m.parseErrors.WithLabelValues(name).Inc()
+}
+
+// CacheHit counts a render cache hit.
+func (m *Metrics) CacheHit(name string) {
+	m.cacheHits.WithLabelValues(name).Inc()
+}
+
+// CacheMiss counts a render cache miss.
+func (m *Metrics) CacheMiss(name string) {
+	m.cacheMisses.WithLabelValues(name).Inc()
+}
+
+// QuotaExceeded counts a render rejected by a tenant quota.
+func (m *Metrics) QuotaExceeded(tenant, name, limit string) {
+	m.quotas.WithLabelValues(tenant, name, limit).Inc()
+}
@@ -0,0 +1,55 @@
+package promadapter
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/alesr/templator"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageData struct {
+	Title string
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":   &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		"templates/fail.html":   &fstest.MapFile{Data: []byte("{{.Missing}}")},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("{{.Title")},
+	}
+
+	m := New(prometheus.NewRegistry())
+	reg, err := templator.NewRegistry(fs, templator.WithMetrics[pageData](m))
+	require.NoError(t, err)
+	t.Cleanup(func() { reg.Close(context.Background()) })
+
+	home := reg.MustGet("home").WithCache(time.Minute, func(d pageData) string { return d.Title })
+	for range 2 {
+		_, err := home.ExecuteToString(context.Background(), pageData{Title: "hi"})
+		require.NoError(t, err)
+	}
+
+	_, err = reg.MustGet("fail").ExecuteToString(context.Background(), pageData{})
+	require.Error(t, err)
+
+	_, err = reg.Get("broken")
+	require.Error(t, err)
+
+	assert.InDelta(t, 2, testutil.ToFloat64(m.renders.WithLabelValues("home", "ok")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.renders.WithLabelValues("fail", "error")), 0)
+	assert.Equal(t, 2, testutil.CollectAndCount(m.duration))
+	assert.InDelta(t, 1, testutil.ToFloat64(m.parseErrors.WithLabelValues("broken")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.cacheHits.WithLabelValues("home")), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.cacheMisses.WithLabelValues("home")), 0)
+
+	m.QuotaExceeded("acme", "home", templator.QuotaRate)
+	assert.InDelta(t, 1, testutil.ToFloat64(m.quotas.WithLabelValues("acme", "home", "rate")), 0)
+}
@@ -0,0 +1,178 @@
+package templator
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultQuotaWindow is the period over which render time is budgeted when
+// QuotaPolicy.Window is not set.
+const DefaultQuotaWindow = time.Minute
+
+// QuotaPolicy limits the renders of each tenant. Zero limits are unlimited.
+type QuotaPolicy struct {
+	// Rate is the number of renders per second allowed to each tenant.
+	Rate float64
+	// Burst is the number of renders a tenant may make at once above Rate, 1 by default.
+	Burst int
+	// RenderTimeBudget is the cumulative render time each tenant may spend per Window.
+	// Render time is wall-clock time, so it includes time spent waiting, such as on Lazy
+	// values, and is not a measure of CPU time.
+	RenderTimeBudget time.Duration
+	// Window is the period RenderTimeBudget is budgeted over, DefaultQuotaWindow by default.
+	Window time.Duration
+}
+
+// Quota limits reported by ErrQuotaExceeded.
+const (
+	QuotaRate       = "rate"
+	QuotaRenderTime = "render time"
+)
+
+// ErrQuotaExceeded is returned, wrapped in ErrTemplateExecution, when a tenant
+// exceeds a limit set with WithQuota.
+type ErrQuotaExceeded struct {
+	Tenant string
+	// Limit is QuotaRate or QuotaRenderTime.
+	Limit string
+}
+
+func (e ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("tenant '%s' exceeded its %s quota", e.Tenant, e.Limit)
+}
+
+// QuotaMetrics is implemented by Metrics that also count renders rejected by WithQuota.
+type QuotaMetrics interface {
+	QuotaExceeded(tenant, name, limit string)
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context attributing renders to tenant, for quotas set with WithQuota.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by ctx and whether it carries one.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok
+}
+
+// WithQuota returns an Option that enforces policy on the renders of each tenant, as set on
+// the context with WithTenant, protecting shared infrastructure from abusive or broken
+// tenant-editable templates. Renders of contexts without a tenant are not limited. Rejected
+// renders fail with ErrQuotaExceeded and are counted by metrics implementing QuotaMetrics.
+// Tenants idle for a window, with a full rate quota, are forgotten.
+func WithQuota[T any](policy QuotaPolicy) Option[T] {
+	return func(r *Registry[T]) {
+		q := &quotas{policy: policy, tenants: make(map[string]*tenantUsage), now: time.Now}
+		r.config.quota = q
+		r.config.hooks = append(r.config.hooks, executionHooks[T]{
+			pre: func(ctx context.Context, name string, _ T) error {
+				tenant, ok := TenantFromContext(ctx)
+				if !ok {
+					return nil
+				}
+				if limit := q.admit(tenant); limit != "" {
+					if m, ok := r.config.metrics.(QuotaMetrics); ok {
+						m.QuotaExceeded(tenant, name, limit)
+					}
+					return ErrQuotaExceeded{Tenant: tenant, Limit: limit}
+				}
+				return nil
+			},
+			post: func(ctx context.Context, _ string, dur time.Duration, _ error) {
+				if tenant, ok := TenantFromContext(ctx); ok {
+					q.spend(tenant, dur)
+				}
+			},
+		})
+	}
+}
+
+// quotas tracks the usage of every tenant against the policy.
+type quotas struct {
+	policy QuotaPolicy
+	now    func() time.Time
+
+	mu      sync.Mutex
+	tenants map[string]*tenantUsage
+	swept   time.Time
+}
+
+// tenantUsage is a token bucket for the render rate and the render time spent in the current window.
+type tenantUsage struct {
+	tokens      float64
+	refilled    time.Time
+	windowStart time.Time
+	spent       time.Duration
+}
+
+// usage returns the usage of tenant, refilled and rolled over to now. q.mu must be held.
+func (q *quotas) usage(tenant string, now time.Time) *tenantUsage {
+	u, ok := q.tenants[tenant]
+	if !ok {
+		u = &tenantUsage{tokens: q.burst(), refilled: now, windowStart: now}
+		q.tenants[tenant] = u
+	}
+
+	u.tokens = min(q.burst(), u.tokens+now.Sub(u.refilled).Seconds()*q.policy.Rate)
+	u.refilled = now
+	if now.Sub(u.windowStart) >= cmp.Or(q.policy.Window, DefaultQuotaWindow) {
+		u.windowStart, u.spent = now, 0
+	}
+	return u
+}
+
+func (q *quotas) burst() float64 {
+	return float64(max(q.policy.Burst, 1))
+}
+
+// admit takes a render from the quota of tenant, returning the exceeded limit if any.
+func (q *quotas) admit(tenant string) string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.now()
+	q.evictIdle(now)
+
+	u := q.usage(tenant, now)
+	switch {
+	case q.policy.RenderTimeBudget > 0 && u.spent >= q.policy.RenderTimeBudget:
+		return QuotaRenderTime
+	case q.policy.Rate > 0 && u.tokens < 1:
+		return QuotaRate
+	}
+	if q.policy.Rate > 0 {
+		u.tokens--
+	}
+	return ""
+}
+
+// spend adds the duration of a render to the render time spent by tenant.
+func (q *quotas) spend(tenant string, dur time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.usage(tenant, q.now()).spent += dur
+}
+
+// evictIdle forgets, at most once per window, the tenants whose usage has returned to that of
+// a new tenant, so the map only holds recently active tenants. q.mu must be held.
+func (q *quotas) evictIdle(now time.Time) {
+	window := cmp.Or(q.policy.Window, DefaultQuotaWindow)
+	if now.Sub(q.swept) < window {
+		return
+	}
+	q.swept = now
+
+	for tenant, u := range q.tenants {
+		idle := now.Sub(u.refilled)
+		if idle >= window && (q.policy.Rate <= 0 || idle.Seconds()*q.policy.Rate >= q.burst()) {
+			delete(q.tenants, tenant)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package templator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaMetrics counts rejected renders by tenant and limit.
+type quotaMetrics struct {
+	recordingMetrics
+
+	mu       sync.Mutex
+	exceeded []string
+}
+
+func (m *quotaMetrics) QuotaExceeded(tenant, name, limit string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exceeded = append(m.exceeded, tenant+" "+name+" "+limit)
+}
+
+func TestWithQuota(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+	}
+
+	newRegistry := func(policy QuotaPolicy, opts ...Option[TestData]) (*Registry[TestData], *time.Time) {
+		reg := MustNewRegistry(fs, append(opts, WithQuota[TestData](policy))...)
+		now := time.Now()
+		reg.config.quota.now = func() time.Time { return now }
+		return reg, &now
+	}
+
+	render := func(reg *Registry[TestData], tenant string) error {
+		ctx := context.Background()
+		if tenant != "" {
+			ctx = WithTenant(ctx, tenant)
+		}
+		_, err := reg.MustGet("home").ExecuteToString(ctx, TestData{Title: "a"})
+		return err
+	}
+
+	t.Run("limits the render rate per tenant", func(t *testing.T) {
+		t.Parallel()
+
+		var m quotaMetrics
+		reg, now := newRegistry(QuotaPolicy{Rate: 1, Burst: 2}, WithMetrics[TestData](&m))
+
+		require.NoError(t, render(reg, "acme"))
+		require.NoError(t, render(reg, "acme"))
+
+		err := render(reg, "acme")
+		require.ErrorAs(t, err, new(ErrTemplateExecution))
+		assert.ErrorIs(t, err, ErrQuotaExceeded{Tenant: "acme", Limit: QuotaRate})
+		assert.EqualError(t, ErrQuotaExceeded{Tenant: "acme", Limit: QuotaRate}, "tenant 'acme' exceeded its rate quota")
+
+		require.NoError(t, render(reg, "globex"), "tenants have their own quota")
+		for range 5 {
+			require.NoError(t, render(reg, ""), "renders without a tenant are not limited")
+		}
+
+		*now = now.Add(time.Second)
+		require.NoError(t, render(reg, "acme"))
+		require.Error(t, render(reg, "acme"))
+
+		assert.Equal(t, []string{"acme home rate", "acme home rate"}, m.exceeded)
+	})
+
+	t.Run("limits the render time per window", func(t *testing.T) {
+		t.Parallel()
+
+		reg, now := newRegistry(QuotaPolicy{RenderTimeBudget: time.Second, Window: time.Minute})
+		reg.config.quota.spend("acme", time.Second)
+
+		err := render(reg, "acme")
+		assert.ErrorIs(t, err, ErrQuotaExceeded{Tenant: "acme", Limit: QuotaRenderTime})
+		require.NoError(t, render(reg, "globex"))
+
+		*now = now.Add(time.Minute)
+		require.NoError(t, render(reg, "acme"))
+	})
+
+	t.Run("forgets idle tenants", func(t *testing.T) {
+		t.Parallel()
+
+		reg, now := newRegistry(QuotaPolicy{Rate: 1, Burst: 2, Window: time.Minute})
+		q := reg.config.quota
+
+		require.NoError(t, render(reg, "acme"))
+		require.NoError(t, render(reg, "acme"))
+		require.NoError(t, render(reg, "globex"))
+
+		*now = now.Add(30 * time.Second)
+		require.NoError(t, render(reg, "globex"))
+		assert.Len(t, q.tenants, 2, "tenants are swept once per window")
+
+		*now = now.Add(31 * time.Second)
+		require.NoError(t, render(reg, "globex"))
+		assert.Len(t, q.tenants, 1)
+		assert.Contains(t, q.tenants, "globex")
+
+		require.NoError(t, render(reg, "acme"))
+		require.NoError(t, render(reg, "acme"), "forgotten tenants start with a full quota")
+	})
+
+	t.Run("rejects negative limits", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fs, WithQuota[TestData](QuotaPolicy{Rate: -1}))
+		require.ErrorContains(t, err, "limits must not be negative")
+	})
+}
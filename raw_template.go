@@ -0,0 +1,17 @@
+package templator
+
+import "html/template"
+
+// Template returns a copy of the parsed html/template behind the handler, with its partials,
+// layout and blocks, for libraries that require a raw *template.Template. The copy is taken from
+// a version of the template that is never executed, so it can be taken at any time, and changing
+// it leaves the handler untouched. It reflects the template as of the call: later reloads are not
+// applied to it, and it has no locale-bound functions.
+func (h *Handler[T]) Template() *template.Template {
+	clone, err := h.src.pristineTemplate().Clone()
+	if err != nil {
+		// Clone only fails on executed templates, and the pristine copy never executes.
+		panic(err)
+	}
+	return clone
+}
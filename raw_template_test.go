@@ -0,0 +1,96 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Template(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":              &fstest.MapFile{Data: []byte(`{{define "title"}}<h1>{{.Title}}</h1>{{end}}{{template "components/card.html" .}}`)},
+		"templates/components/card.html":   &fstest.MapFile{Data: []byte(`<div>{{.Content}}</div>`)},
+		"templates/components/footer.html": &fstest.MapFile{Data: []byte(`<footer></footer>`)},
+	}
+	data := TestData{Title: "Hi", Content: "<b>body</b>"}
+
+	t.Run("returns a copy before the handler renders", func(t *testing.T) {
+		t.Parallel()
+
+		h := MustNewRegistry(fs, WithPartials[TestData]("components/*")).MustGet("home")
+
+		tmpl := h.Template()
+		assert.Equal(t, "home.html", tmpl.Name())
+		assert.NotNil(t, tmpl.Lookup("title"))
+		assert.NotNil(t, tmpl.Lookup("components/footer.html"))
+
+		_, err := tmpl.New("components/card.html").Parse(`<p>changed</p>`)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.ExecuteTemplate(&buf, "title", data))
+		assert.Equal(t, "<h1>Hi</h1>", buf.String())
+
+		got, err := h.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<div>&lt;b&gt;body&lt;/b&gt;</div>", got, "the copy leaves the handler untouched")
+	})
+
+	t.Run("returns a copy after the handler renders", func(t *testing.T) {
+		t.Parallel()
+
+		h := MustNewRegistry(fs, WithPartials[TestData]("components/*")).MustGet("home")
+		_, err := h.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+
+		tmpl := h.Template()
+		assert.NotSame(t, h.src.template(), tmpl)
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, data))
+		assert.Equal(t, "<div>&lt;b&gt;body&lt;/b&gt;</div>", buf.String())
+
+		got, err := h.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<div>&lt;b&gt;body&lt;/b&gt;</div>", got)
+	})
+
+	t.Run("reflects reloads", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{"templates/home.html": &fstest.MapFile{Data: []byte(`<p>{{.Title}}</p>`)}}
+		reg := MustNewRegistry[TestData](fs)
+		h := reg.MustGet("home")
+		_, err := h.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+
+		fs["templates/home.html"] = &fstest.MapFile{Data: []byte(`<h1>{{.Title}}</h1>`)}
+		_, err = reg.Reload(context.Background())
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, h.Template().Execute(&buf, data))
+		assert.Equal(t, "<h1>Hi</h1>", buf.String())
+	})
+
+	t.Run("returns a copy for localized handlers", func(t *testing.T) {
+		t.Parallel()
+
+		h := MustNewRegistry(fs, WithPartials[TestData]("components/*"), WithDirection[TestData]()).MustGet("home")
+		_, err := h.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+
+		tmpl := h.Template()
+		assert.NotSame(t, h.src.template(), tmpl)
+
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, data))
+		assert.Equal(t, "<div>&lt;b&gt;body&lt;/b&gt;</div>", buf.String())
+	})
+}
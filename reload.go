@@ -0,0 +1,235 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"weak"
+)
+
+// hashSource hashes template sources compared by Reload. The hash is stable across
+// processes, so the versions recorded by archived renders can be compared later.
+func hashSource(content []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(content)
+	return h.Sum64()
+}
+
+// version identifies the content of a template and of every template it depends on.
+func (s *source) version() string {
+	deps := s.dependencies()
+
+	h := fnv.New64a()
+	for _, dep := range slices.Sorted(maps.Keys(deps)) {
+		fmt.Fprintf(h, "%s=%x;", dep, deps[dep])
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// source is the parsed template of a handler, with the content hashes of the templates it
+// depends on and the data fields it requires. It is shared by a handler and the cached handlers derived from it, so Reload
+// swaps the template and purges the render caches of all of them at once.
+type source struct {
+	mu   sync.RWMutex
+	tmpl *template.Template
+	// pristine is a copy of tmpl that is never executed, so Handler.Template can copy it.
+	pristine *template.Template
+	deps     map[string]uint64
+	required []string
+	// caches are held weakly, so they are collected with their cached handlers.
+	caches []weak.Pointer[renderCache]
+
+	// deprecations are the deprecations of the parsed templates, keyed by template name.
+	deprecations map[string][]Deprecation
+	// fields are the fields the templates reference, to start loading Lazy values, or nil
+	// when the data type holds none.
+	fields map[string]bool
+	// cache is the policy of the cache block of the template's frontmatter, if any.
+	cache *CachePolicy
+}
+
+func (s *source) template() *template.Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tmpl
+}
+
+func (s *source) pristineTemplate() *template.Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pristine
+}
+
+func (s *source) lazyFields() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fields
+}
+
+func (s *source) dependencies() map[string]uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.deps
+}
+
+func (s *source) cachePolicy() *CachePolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cache
+}
+
+func (s *source) requiredFields() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.required
+}
+
+// track registers a render cache to purge when the template is replaced.
+func (s *source) track(c *renderCache) {
+	s.mu.Lock()
+	s.caches = append(slices.DeleteFunc(s.caches, func(p weak.Pointer[renderCache]) bool {
+		return p.Value() == nil
+	}), weak.Make(c))
+	s.mu.Unlock()
+}
+
+// replace swaps in the template and dependencies of next and purges the render caches.
+func (s *source) replace(next *source) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tmpl, s.pristine, s.deps, s.required, s.deprecations, s.fields = next.tmpl, next.pristine, next.deps, next.required, next.deprecations, next.fields
+	s.cache = next.cache
+	for _, p := range s.caches {
+		if c := p.Value(); c != nil {
+			c.purge()
+		}
+	}
+}
+
+// Reload re-reads the templates the registry has handed out handlers for and re-parses those
+// whose content, or the content of a partial they invoke, changed since they were parsed.
+// Handlers already handed out switch to the new version, including cached handlers created
+// with WithCache, which drop their rendered output. Handlers of unchanged templates keep their
+// cache. It returns the names of the reloaded templates.
+//
+// A template that fails to re-parse keeps serving its previous version; its error is joined
+// into the returned error. Handlers created with GetOptions are not reloaded.
+func (r *Registry[T]) Reload(ctx context.Context) ([]string, error) {
+	if r.closed.Load() {
+		return nil, ErrRegistryClosed
+	}
+
+	r.mu.RLock()
+	handlers := maps.Clone(r.templates)
+	r.mu.RUnlock()
+
+	var (
+		current  = make(map[string]uint64)
+		missing  = make(map[string]bool)
+		reloaded []string
+		errs     []error
+	)
+	changed := func(deps map[string]uint64) bool {
+		for dep, hash := range deps {
+			if _, ok := current[dep]; !ok && !missing[dep] {
+				content, err := r.readSource(dep, true)
+				if err != nil {
+					missing[dep] = true
+				} else {
+					current[dep] = hashSource(content)
+				}
+			}
+			if missing[dep] || current[dep] != hash {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(handlers)) {
+		if err := ctx.Err(); err != nil {
+			return reloaded, err
+		}
+
+		h := handlers[name]
+		if !changed(h.src.dependencies()) {
+			continue
+		}
+
+		next, err := r.parse(name, getConfig{})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		h.src.replace(next.src)
+		reloaded = append(reloaded, name)
+		r.logInvalidation("template reloaded", name)
+	}
+
+	// Metadata and locale variants are cheap to resolve again and may come from
+	// templates no handler was created for, so they are always dropped.
+	r.mu.Lock()
+	clear(r.localized)
+	clear(r.metadata)
+	r.mu.Unlock()
+
+	return reloaded, errors.Join(errs...)
+}
+
+// dependencies returns the names of the templates tmpl was parsed from that its execution
+// can reach: its own file, and the files defining the templates it invokes, transitively.
+func dependencies(tmpl *template.Template, ext Extension) []string {
+	var (
+		deps    []string
+		visited = make(map[string]bool)
+		visit   func(t *template.Template)
+	)
+	visit = func(t *template.Template) {
+		if t == nil || t.Tree == nil || visited[t.Name()] {
+			return
+		}
+		visited[t.Name()] = true
+
+		if dep := strings.TrimSuffix(t.Tree.ParseName, string(ext)); !slices.Contains(deps, dep) {
+			deps = append(deps, dep)
+		}
+		templateCalls(t.Tree.Root, func(name string) {
+			visit(tmpl.Lookup(name))
+		})
+	}
+	visit(tmpl)
+	return deps
+}
+
+// templateCalls calls fn with the name of every template invoked under node.
+func templateCalls(node parse.Node, fn func(string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			templateCalls(child, fn)
+		}
+	case *parse.IfNode:
+		templateCalls(n.List, fn)
+		templateCalls(n.ElseList, fn)
+	case *parse.WithNode:
+		templateCalls(n.List, fn)
+		templateCalls(n.ElseList, fn)
+	case *parse.RangeNode:
+		templateCalls(n.List, fn)
+		templateCalls(n.ElseList, fn)
+	case *parse.TemplateNode:
+		fn(n.Name)
+	}
+}
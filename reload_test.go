@@ -0,0 +1,169 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Reload(t *testing.T) {
+	t.Parallel()
+
+	newReloadFS := func() fstest.MapFS {
+		return fstest.MapFS{
+			"templates/home.html":              &fstest.MapFile{Data: []byte(`{{template "components/header.html" .}}<p>{{.Content}}</p>`)},
+			"templates/about.html":             &fstest.MapFile{Data: []byte(`<p>about</p>`)},
+			"templates/components/header.html": &fstest.MapFile{Data: []byte(`<h1>{{.Title}}</h1>`)},
+			"templates/components/footer.html": &fstest.MapFile{Data: []byte(`<footer></footer>`)},
+		}
+	}
+	data := TestData{Title: "Hi", Content: "body"}
+
+	t.Run("reloads templates depending on changed partials", func(t *testing.T) {
+		t.Parallel()
+
+		fs := newReloadFS()
+		reg := MustNewRegistry(fs, WithPartials[TestData]("components/*"))
+
+		home, about := reg.MustGet("home"), reg.MustGet("about")
+
+		fs["templates/components/header.html"] = &fstest.MapFile{Data: []byte(`<h2>{{.Title}}</h2>`)}
+		fs["templates/components/footer.html"] = &fstest.MapFile{Data: []byte(`<footer>new</footer>`)}
+
+		reloaded, err := reg.Reload(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home"}, reloaded, "about invokes no partial")
+
+		got, err := home.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<h2>Hi</h2><p>body</p>", got, "handed out handlers are updated")
+		assert.Same(t, home, reg.MustGet("home"))
+		assert.Same(t, about, reg.MustGet("about"))
+
+		reloaded, err = reg.Reload(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, reloaded)
+	})
+
+	t.Run("purges only caches of reloaded templates", func(t *testing.T) {
+		t.Parallel()
+
+		fs := newReloadFS()
+		reg := MustNewRegistry(fs, WithPartials[TestData]("components/*"))
+		t.Cleanup(func() { reg.Close(context.Background()) })
+
+		byTitle := func(d TestData) string { return d.Title }
+		home := reg.MustGet("home").WithCache(time.Minute, byTitle)
+		about := reg.MustGet("about").WithCache(time.Minute, byTitle)
+
+		for _, h := range []*Handler[TestData]{home, about} {
+			_, err := h.ExecuteToString(context.Background(), data)
+			require.NoError(t, err)
+		}
+
+		fs["templates/home.html"] = &fstest.MapFile{Data: []byte(`<main>{{.Content}}</main>`)}
+
+		reloaded, err := reg.Reload(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home"}, reloaded)
+
+		_, _, ok := home.cache.lookup("Hi")
+		assert.False(t, ok)
+		_, _, ok = about.cache.lookup("Hi")
+		assert.True(t, ok)
+
+		got, err := home.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<main>body</main>", got)
+	})
+
+	t.Run("keeps previous version when parsing fails", func(t *testing.T) {
+		t.Parallel()
+
+		fs := newReloadFS()
+		reg := MustNewRegistry[TestData](fs)
+		about := reg.MustGet("about")
+
+		fs["templates/about.html"] = &fstest.MapFile{Data: []byte(`{{.Title`)}
+
+		reloaded, err := reg.Reload(context.Background())
+		var parseErr ErrTemplateParse
+		require.ErrorAs(t, err, &parseErr)
+		assert.Empty(t, reloaded)
+
+		got, err := about.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<p>about</p>", got)
+	})
+
+	t.Run("refreshes group sources", func(t *testing.T) {
+		t.Parallel()
+
+		fs := newReloadFS()
+		reg, err := NewGroupRegistry[TestData](NewRegistryGroup(fs))
+		require.NoError(t, err)
+		about := reg.MustGet("about")
+
+		fs["templates/about.html"] = &fstest.MapFile{Data: []byte(`<p>new about</p>`)}
+
+		reloaded, err := reg.Reload(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"about"}, reloaded)
+
+		got, err := about.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<p>new about</p>", got)
+	})
+
+	t.Run("drops translated copies", func(t *testing.T) {
+		t.Parallel()
+
+		fs := newReloadFS()
+		fs["templates/greet.html"] = &fstest.MapFile{Data: []byte(`{{t "hello"}}`)}
+
+		reg := MustNewRegistry(fs, WithTranslator[TestData](func(lang, key string, _ ...any) string {
+			return lang + ":" + key
+		}), WithLocales[TestData]("de"))
+		greet := reg.MustGet("greet")
+
+		ctx := WithLocale(context.Background(), "de")
+		got, err := greet.ExecuteToString(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, "de:hello", got)
+
+		fs["templates/greet.html"] = &fstest.MapFile{Data: []byte(`<b>{{t "bye"}}</b>`)}
+		_, err = reg.Reload(context.Background())
+		require.NoError(t, err)
+
+		got, err = greet.ExecuteToString(ctx, data)
+		require.NoError(t, err)
+		assert.Equal(t, "<b>de:bye</b>", got)
+	})
+
+	t.Run("returns error when closed", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](newReloadFS())
+		require.NoError(t, reg.Close(context.Background()))
+
+		_, err := reg.Reload(context.Background())
+		require.ErrorIs(t, err, ErrRegistryClosed)
+	})
+}
+
+func TestDependencies(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("home.html").Parse(`{{template "layout"}}{{if .}}{{template "a.html"}}{{end}}`))
+	template.Must(tmpl.New("a.html").Parse(`{{template "b.html"}}`))
+	template.Must(tmpl.New("b.html").Parse(`b`))
+	template.Must(tmpl.New("c.html").Parse(`c{{define "layout"}}layout{{end}}`))
+	template.Must(tmpl.New("unused.html").Parse(`unused`))
+
+	assert.Equal(t, []string{"home", "c", "a", "b"}, dependencies(tmpl, ExtensionHTML))
+}
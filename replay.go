@@ -0,0 +1,190 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReplayResult compares an archived render with a render of the same data by the current templates.
+type ReplayResult struct {
+	Template string
+	// ArchivedVersion and Version identify the templates of the archived and current renders.
+	ArchivedVersion string
+	Version         string
+	// Changed reports whether the output differs from the archived output.
+	Changed bool
+	// Diff is a line diff from the archived to the current output, empty when unchanged.
+	Diff string
+}
+
+// VersionChanged reports whether the templates changed since the render was archived.
+func (r ReplayResult) VersionChanged() bool {
+	return r.ArchivedVersion != r.Version
+}
+
+// Replay re-renders the data of an archived render with the current version of its template
+// and diffs the output against the archived one, to verify that a template fix changes only
+// what was intended. Replays are neither archived nor cached, and skip execution hooks.
+// With WithOutputNormalization, outputs are compared and diffed normalized.
+func (r *Registry[T]) Replay(ctx context.Context, a Archived[T]) (ReplayResult, error) {
+	h, err := r.Get(a.Template)
+	if err != nil {
+		return ReplayResult{}, err
+	}
+
+	if err := h.checkRequired(a.Data); err != nil {
+		return ReplayResult{}, err
+	}
+
+	var buf bytes.Buffer
+	if err := h.execute(ctx, &buf, a.Data); err != nil {
+		return ReplayResult{}, err
+	}
+
+	archived, current := a.Output, buf.Bytes()
+	if r.config.normalize {
+		// NormalizeHTML doesn't fail.
+		archived, _ = NormalizeHTML(archived)
+		current, _ = NormalizeHTML(current)
+	}
+
+	result := ReplayResult{
+		Template:        a.Template,
+		ArchivedVersion: a.Version,
+		Version:         h.src.version(),
+		Changed:         !bytes.Equal(archived, current),
+	}
+	if result.Changed {
+		result.Diff = lineDiff(string(archived), string(current))
+	}
+	return result, nil
+}
+
+// ReplayAll replays every archived render, e.g. those loaded with DirArchive.All. It returns
+// the results of the renders that could be replayed and the errors of the others, joined.
+func (r *Registry[T]) ReplayAll(ctx context.Context, archives iter.Seq2[Archived[T], error]) ([]ReplayResult, error) {
+	var (
+		results []ReplayResult
+		errs    []error
+	)
+	for a, err := range archives {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, errors.Join(append(errs, err)...)
+		}
+
+		result, err := r.Replay(ctx, a)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+// All returns the archived renders, oldest first. Files that cannot be read or decoded
+// are yielded as errors.
+func (d *DirArchive[T]) All() iter.Seq2[Archived[T], error] {
+	return func(yield func(Archived[T], error) bool) {
+		files, err := filepath.Glob(filepath.Join(d.dir, "*.json"))
+		if err != nil {
+			yield(Archived[T]{}, err)
+			return
+		}
+
+		for _, file := range files {
+			var a Archived[T]
+			content, err := os.ReadFile(file)
+			if err == nil {
+				err = json.Unmarshal(content, &a)
+			}
+			if err != nil {
+				err = fmt.Errorf("archived render %s: %w", filepath.Base(file), err)
+			}
+			if !yield(a, err) {
+				return
+			}
+		}
+	}
+}
+
+// maxDiffCells caps the size of the table lineDiff builds, which grows with the product of
+// the numbers of lines that differ.
+const maxDiffCells = 1 << 20
+
+// lineDiff returns the lines removed from and added to old to get to new, each hunk
+// introduced by the 1-based line numbers it starts at in old and new. Outputs differing
+// over too many lines for maxDiffCells are reported by their first differing line.
+func lineDiff(old, new string) string {
+	a, b := strings.Split(old, "\n"), strings.Split(new, "\n")
+
+	// Common leading and trailing lines are left out of the table.
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	a, b = a[start:], b[start:]
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1] == b[len(b)-1] {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+
+	if len(a) > 0 && len(b) > 0 && (len(a)+1)*(len(b)+1) > maxDiffCells {
+		return fmt.Sprintf("@@ -%d +%d @@\n-%s\n+%s\n... %d lines removed and %d added, too many to diff\n",
+			start+1, start+1, a[0], b[0], len(a), len(b))
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var (
+		sb     strings.Builder
+		inHunk bool
+		i, j   int
+	)
+	hunk := func() {
+		if !inHunk {
+			fmt.Fprintf(&sb, "@@ -%d +%d @@\n", start+i+1, start+j+1)
+			inHunk = true
+		}
+	}
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			inHunk = false
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			hunk()
+			fmt.Fprintf(&sb, "-%s\n", a[i])
+			i++
+		default:
+			hunk()
+			fmt.Fprintf(&sb, "+%s\n", b[j])
+			j++
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,128 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Replay(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>\n<p>{{.Content}}</p>\n<footer>v1</footer>")},
+	}
+
+	store, err := NewDirArchive[TestData](t.TempDir())
+	require.NoError(t, err)
+
+	reg := MustNewRegistry(fs, WithArchive[TestData](store, ArchivePolicy{SampleRate: 1}))
+	for _, title := range []string{"first", "second"} {
+		_, err := reg.MustGet("home").ExecuteToString(context.Background(), TestData{Title: title, Content: "body"})
+		require.NoError(t, err)
+	}
+	require.NoError(t, reg.Close(context.Background()))
+
+	t.Run("unchanged templates", func(t *testing.T) {
+		t.Parallel()
+
+		results, err := MustNewRegistry[TestData](fs).ReplayAll(context.Background(), store.All())
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.Equal(t, "home", result.Template)
+			assert.False(t, result.Changed)
+			assert.False(t, result.VersionChanged())
+			assert.Empty(t, result.Diff)
+		}
+	})
+
+	t.Run("changed templates", func(t *testing.T) {
+		t.Parallel()
+
+		fixed := fstest.MapFS{
+			"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>\n<p>{{.Content}}</p>\n<footer>v2</footer>")},
+		}
+		results, err := MustNewRegistry[TestData](fixed).ReplayAll(context.Background(), store.All())
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.True(t, result.Changed)
+			assert.True(t, result.VersionChanged())
+			assert.Equal(t, "@@ -3 +3 @@\n-<footer>v1</footer>\n+<footer>v2</footer>\n", result.Diff)
+		}
+	})
+
+	t.Run("reports unreadable archives and missing templates", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644))
+		broken, err := NewDirArchive[TestData](dir)
+		require.NoError(t, err)
+
+		results, err := MustNewRegistry[TestData](fs).ReplayAll(context.Background(), broken.All())
+		require.ErrorContains(t, err, "archived render broken.json")
+		assert.Empty(t, results)
+
+		_, err = MustNewRegistry[TestData](fs).Replay(context.Background(), Archived[TestData]{Template: "missing"})
+		require.ErrorAs(t, err, new(ErrTemplateNotFound))
+	})
+}
+
+func TestLineDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		old, new string
+		expected string
+	}{
+		{name: "equal", old: "a\nb", new: "a\nb", expected: ""},
+		{name: "added", old: "a\nc", new: "a\nb\nc", expected: "@@ -2 +2 @@\n+b\n"},
+		{name: "removed", old: "a\nb\nc", new: "a\nc", expected: "@@ -2 +2 @@\n-b\n"},
+		{
+			name:     "several hunks",
+			old:      "a\nb\nc\nd",
+			new:      "x\nb\nc\ny",
+			expected: "@@ -1 +1 @@\n-a\n+x\n@@ -4 +4 @@\n-d\n+y\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, lineDiff(tt.old, tt.new))
+		})
+	}
+
+	lines := func(n int, format string) string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprintf(format, i)
+		}
+		return strings.Join(out, "\n")
+	}
+
+	t.Run("leaves common lines out of the table", func(t *testing.T) {
+		t.Parallel()
+
+		common := lines(5000, "line %d")
+		got := lineDiff(common+"\nold\n"+common, common+"\nnew\n"+common)
+		assert.Equal(t, "@@ -5001 +5001 @@\n-old\n+new\n", got)
+	})
+
+	t.Run("reports the first differing line of large diffs", func(t *testing.T) {
+		t.Parallel()
+
+		got := lineDiff("head\n"+lines(2000, "old %d"), "head\n"+lines(2000, "new %d"))
+		assert.Equal(t, "@@ -2 +2 @@\n-old 0\n+new 0\n... 2000 lines removed and 2000 added, too many to diff\n", got)
+	})
+}
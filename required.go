@@ -0,0 +1,137 @@
+package templator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// requiredKey is the metadata key listing the data fields a template requires.
+const requiredKey = "required"
+
+// requiredOf returns the required fields declared in the metadata of the named template,
+// either as a list or as a comma-separated string. Each field is a dotted path, such as
+// User.Name, that must exist in T unless the path goes through an interface.
+func requiredOf[T any](name string, md Metadata) ([]string, error) {
+	fields, err := metadataList(name, requiredKey, "field names", md)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := reflect.TypeFor[T]()
+	for _, field := range fields {
+		if !fieldExists(typ, field) {
+			return nil, fmt.Errorf("template '%s': %w: required field '%s' does not exist in %s", name, ErrInvalidPragma, field, typ)
+		}
+	}
+	return fields, nil
+}
+
+// metadataList returns the strings listed under key in the metadata of the named template,
+// either as a list or as a comma-separated string. what describes the strings in errors.
+func metadataList(name, key, what string, md Metadata) ([]string, error) {
+	var list []string
+	switch value := md[key].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		for item := range strings.SplitSeq(value, ",") {
+			list = append(list, strings.TrimSpace(item))
+		}
+	case []any:
+		for _, item := range value {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("template '%s': %w: %s must be strings", name, ErrInvalidPragma, what)
+			}
+			list = append(list, s)
+		}
+	default:
+		return nil, fmt.Errorf("template '%s': %w: %s must list %s", name, ErrInvalidPragma, key, what)
+	}
+	return list, nil
+}
+
+// fieldExists reports whether the dotted path can be resolved in typ.
+func fieldExists(typ reflect.Type, path string) bool {
+	for part := range strings.SplitSeq(path, ".") {
+		if part == "" {
+			return false
+		}
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Interface:
+			return true
+		case reflect.Map:
+			if typ.Key().Kind() != reflect.String {
+				return false
+			}
+			typ = typ.Elem()
+		case reflect.Struct:
+			field, ok := typ.FieldByName(part)
+			if !ok || !field.IsExported() {
+				return false
+			}
+			typ = field.Type
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// missingFields returns the fields of data that are unset: zero-valued, or unreachable
+// through a nil pointer, interface, or missing map key.
+func missingFields(data any, fields []string) []string {
+	var missing []string
+	for _, field := range fields {
+		if v := fieldValue(reflect.ValueOf(data), field); !v.IsValid() || v.IsZero() {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// fieldValue resolves the dotted path in v, returning the zero Value when it cannot.
+func fieldValue(v reflect.Value, path string) reflect.Value {
+	for part := range strings.SplitSeq(path, ".") {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return reflect.Value{}
+			}
+			v = v.MapIndex(reflect.ValueOf(part).Convert(v.Type().Key()))
+		case reflect.Struct:
+			v = v.FieldByName(part)
+		default:
+			return reflect.Value{}
+		}
+		if !v.IsValid() {
+			return reflect.Value{}
+		}
+	}
+	return v
+}
+
+// checkRequired returns ErrMissingFields when data leaves required fields of the template unset.
+func (h *Handler[T]) checkRequired(data T) error {
+	fields := h.src.requiredFields()
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if missing := missingFields(data, fields); len(missing) > 0 {
+		return ErrTemplateExecution{Name: h.file, Err: ErrMissingFields{Fields: missing}}
+	}
+	return nil
+}
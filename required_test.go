@@ -0,0 +1,101 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/pragma.html":      &fstest.MapFile{Data: []byte(`{{/* templator: required="Title, Content" */}}<h1>{{.Title}}</h1>{{define "body"}}{{.Content}}{{end}}`)},
+		"templates/frontmatter.html": &fstest.MapFile{Data: []byte("---\nrequired: [Title]\n---\n<h1>{{.Title}}</h1>")},
+		"templates/unknown.html":     &fstest.MapFile{Data: []byte(`{{/* templator: required="Author" */}}`)},
+		"templates/invalid.html":     &fstest.MapFile{Data: []byte("---\nrequired: 3\n---\n")},
+	}
+	reg := MustNewRegistry[TestData](fs)
+
+	t.Run("renders when required fields are set", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := reg.MustGet("pragma").ExecuteToString(context.Background(), TestData{Title: "Hi", Content: "body"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hi</h1>", got)
+	})
+
+	t.Run("fails fast when required fields are zero", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := reg.MustGet("pragma").Execute(context.Background(), &buf, TestData{Content: "body"})
+
+		var missingErr ErrMissingFields
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, []string{"Title"}, missingErr.Fields)
+		assert.EqualError(t, err, "failed to execute template 'pragma.html': 'required fields not set: Title'")
+		assert.Empty(t, buf.String())
+
+		err = reg.MustGet("pragma").ExecuteTemplate(context.Background(), &buf, "body", TestData{})
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, []string{"Title", "Content"}, missingErr.Fields)
+
+		_, err = reg.MustGet("frontmatter").ExecuteToString(context.Background(), TestData{})
+		require.ErrorAs(t, err, &missingErr)
+		assert.Equal(t, []string{"Title"}, missingErr.Fields)
+	})
+
+	t.Run("rejects invalid declarations", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.Get("unknown")
+		require.ErrorIs(t, err, ErrInvalidPragma)
+		assert.Contains(t, err.Error(), "required field 'Author' does not exist in templator.TestData")
+
+		_, err = reg.Get("invalid")
+		require.ErrorIs(t, err, ErrInvalidPragma)
+		assert.Contains(t, err.Error(), "required must list field names")
+	})
+}
+
+func TestMissingFields(t *testing.T) {
+	t.Parallel()
+
+	type user struct {
+		Name string
+		Tags []string
+	}
+	type page struct {
+		User  *user
+		Meta  map[string]any
+		Extra any
+		Count int
+	}
+
+	fields := []string{"User.Name", "User.Tags", "Meta.lang", "Extra", "Count"}
+
+	assert.Equal(t, fields, missingFields(page{}, fields))
+	assert.Equal(t, []string{"User.Tags", "Count"}, missingFields(&page{
+		User:  &user{Name: "ana"},
+		Meta:  map[string]any{"lang": "en"},
+		Extra: 1,
+	}, fields))
+
+	for path, want := range map[string]bool{
+		"User.Name":   true,
+		"Meta.any":    true,
+		"Extra.Field": true,
+		"User.Age":    false,
+		"Count.Value": false,
+		"user":        false,
+		"User.":       false,
+	} {
+		assert.Equal(t, want, fieldExists(reflect.TypeFor[page](), path), path)
+	}
+}
@@ -0,0 +1,176 @@
+package templator
+
+import (
+	"errors"
+	"io/fs"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrLoadTimeout is returned when a single attempt to load from a RetryFS exceeds the policy timeout.
+var ErrLoadTimeout = errors.New("template load timed out")
+
+// RetryPolicy configures how a RetryFS recovers from transient storage failures.
+type RetryPolicy struct {
+	// Attempts is the total number of tries per operation. Values below 1 mean a single try.
+	Attempts int
+	// Timeout bounds each attempt. Zero disables the timeout.
+	Timeout time.Duration
+	// BaseDelay is the backoff before the first retry, doubled on every subsequent retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. Zero means no cap.
+	MaxDelay time.Duration
+	// ServeStale returns the last successfully loaded copy when every attempt fails.
+	ServeStale bool
+}
+
+// RetryFS wraps a filesystem backed by remote storage, such as HTTP or S3, retrying transient
+// failures with jittered exponential backoff. Missing files, permission and invalid path errors
+// are returned without retrying.
+type RetryFS struct {
+	fsys   fs.FS
+	policy RetryPolicy
+
+	mu    sync.RWMutex
+	files map[string][]byte
+	dirs  map[string][]fs.DirEntry
+}
+
+// NewRetryFS returns a RetryFS loading from fsys according to policy.
+func NewRetryFS(fsys fs.FS, policy RetryPolicy) *RetryFS {
+	if policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+	return &RetryFS{
+		fsys:   fsys,
+		policy: policy,
+		files:  make(map[string][]byte),
+		dirs:   make(map[string][]fs.DirEntry),
+	}
+}
+
+// Open opens the named file, retrying transient failures. Stale copies are not served by Open.
+func (r *RetryFS) Open(name string) (fs.File, error) {
+	return retry(r, func() (fs.File, error) {
+		return r.fsys.Open(name)
+	})
+}
+
+// ReadFile reads the named file, retrying transient failures and falling back
+// to the last good copy when ServeStale is set.
+func (r *RetryFS) ReadFile(name string) ([]byte, error) {
+	content, err := retry(r, func() ([]byte, error) {
+		return fs.ReadFile(r.fsys, name)
+	})
+	if err == nil {
+		r.mu.Lock()
+		r.files[name] = content
+		r.mu.Unlock()
+		return content, nil
+	}
+
+	if r.policy.ServeStale && !permanent(err) {
+		r.mu.RLock()
+		stale, ok := r.files[name]
+		r.mu.RUnlock()
+		if ok {
+			return stale, nil
+		}
+	}
+	return nil, err
+}
+
+// ReadDir reads the named directory, retrying transient failures and falling back
+// to the last good listing when ServeStale is set.
+func (r *RetryFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := retry(r, func() ([]fs.DirEntry, error) {
+		return fs.ReadDir(r.fsys, name)
+	})
+	if err == nil {
+		r.mu.Lock()
+		r.dirs[name] = entries
+		r.mu.Unlock()
+		return entries, nil
+	}
+
+	if r.policy.ServeStale && !permanent(err) {
+		r.mu.RLock()
+		stale, ok := r.dirs[name]
+		r.mu.RUnlock()
+		if ok {
+			return stale, nil
+		}
+	}
+	return nil, err
+}
+
+// retry runs op until it succeeds, fails permanently, or the policy runs out of attempts.
+func retry[V any](r *RetryFS, op func() (V, error)) (V, error) {
+	var (
+		v   V
+		err error
+	)
+	for attempt := range r.policy.Attempts {
+		if attempt > 0 {
+			time.Sleep(r.backoff(attempt))
+		}
+
+		v, err = withTimeout(r.policy.Timeout, op)
+		if err == nil || permanent(err) {
+			return v, err
+		}
+	}
+	return v, err
+}
+
+// withTimeout runs op, giving up after timeout. A timed out op keeps running
+// in the background until the underlying filesystem returns.
+func withTimeout[V any](timeout time.Duration, op func() (V, error)) (V, error) {
+	if timeout <= 0 {
+		return op()
+	}
+
+	type result struct {
+		v   V
+		err error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		v, err := op()
+		done <- result{v, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case res := <-done:
+		return res.v, res.err
+	case <-timer.C:
+		var zero V
+		return zero, ErrLoadTimeout
+	}
+}
+
+// backoff returns a random delay up to the exponential backoff for the given retry ("full jitter").
+func (r *RetryFS) backoff(attempt int) time.Duration {
+	if r.policy.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := r.policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || (r.policy.MaxDelay > 0 && delay > r.policy.MaxDelay) {
+		delay = r.policy.MaxDelay
+	}
+	if delay <= 0 {
+		return r.policy.BaseDelay
+	}
+	return rand.N(delay)
+}
+
+// permanent reports whether err will not go away by retrying.
+func permanent(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) || errors.Is(err, fs.ErrInvalid)
+}
@@ -0,0 +1,120 @@
+package templator
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("storage unavailable")
+
+// flakyFS fails every Open while failures is positive, decrementing it on each call.
+type flakyFS struct {
+	fs.FS
+	failures atomic.Int32
+	calls    atomic.Int32
+	delay    time.Duration
+}
+
+func (f *flakyFS) Open(name string) (fs.File, error) {
+	f.calls.Add(1)
+	time.Sleep(f.delay)
+	if f.failures.Add(-1) >= 0 {
+		return nil, errUnavailable
+	}
+	return f.FS.Open(name)
+}
+
+func newFlakyFS(failures int32) *flakyFS {
+	f := &flakyFS{FS: fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}}
+	f.failures.Store(failures)
+	return f
+}
+
+func TestRetryFS_ReadFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyFS(2)
+		rfs := NewRetryFS(flaky, RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond})
+
+		content, err := rfs.ReadFile("templates/home.html")
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>{{.Title}}</h1>", string(content))
+		assert.EqualValues(t, 3, flaky.calls.Load())
+	})
+
+	t.Run("gives up after attempts", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyFS(5)
+		rfs := NewRetryFS(flaky, RetryPolicy{Attempts: 2})
+
+		_, err := rfs.ReadFile("templates/home.html")
+		require.ErrorIs(t, err, errUnavailable)
+		assert.EqualValues(t, 2, flaky.calls.Load())
+	})
+
+	t.Run("does not retry missing files", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyFS(0)
+		rfs := NewRetryFS(flaky, RetryPolicy{Attempts: 3})
+
+		_, err := rfs.ReadFile("templates/missing.html")
+		require.ErrorIs(t, err, fs.ErrNotExist)
+		assert.EqualValues(t, 1, flaky.calls.Load())
+	})
+
+	t.Run("times out slow attempts", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyFS(0)
+		flaky.delay = 50 * time.Millisecond
+		rfs := NewRetryFS(flaky, RetryPolicy{Attempts: 2, Timeout: time.Millisecond})
+
+		_, err := rfs.ReadFile("templates/home.html")
+		require.ErrorIs(t, err, ErrLoadTimeout)
+	})
+
+	t.Run("serves stale copy", func(t *testing.T) {
+		t.Parallel()
+
+		flaky := newFlakyFS(0)
+		rfs := NewRetryFS(flaky, RetryPolicy{Attempts: 2, ServeStale: true})
+
+		_, err := rfs.ReadFile("templates/home.html")
+		require.NoError(t, err)
+
+		flaky.failures.Store(10)
+		content, err := rfs.ReadFile("templates/home.html")
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>{{.Title}}</h1>", string(content))
+	})
+}
+
+func TestRetryFS_Registry(t *testing.T) {
+	t.Parallel()
+
+	flaky := newFlakyFS(1)
+	reg, err := NewRegistry[TestData](NewRetryFS(flaky, RetryPolicy{Attempts: 2}))
+	require.NoError(t, err)
+
+	handler, err := reg.Get("home")
+	require.NoError(t, err)
+
+	out, err := handler.ExecuteToString(context.Background(), TestData{Title: "Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>Hello</h1>", out)
+}
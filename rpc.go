@@ -0,0 +1,84 @@
+package templator
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// RPCFragment is a rendered template ready to travel in a bytes field of an RPC response,
+// for architectures where an edge gateway fetches server-rendered fragments from backend
+// services over gRPC or Connect.
+type RPCFragment struct {
+	Body []byte
+	// ContentType is inferred from the template file extension and defaults to HTML.
+	ContentType string
+	// CacheControl and SurrogateControl are the values of the template's cache policy, if any.
+	CacheControl     string
+	SurrogateControl string
+	// ETag is a strong ETag of Body, as set by Handler.ExecuteWithETag.
+	ETag string
+	// Version identifies the template version that rendered Body.
+	Version string
+}
+
+// RenderRPC renders the template into an RPCFragment. Copy the fields the gateway needs into
+// the response message:
+//
+//	frag, err := h.RenderRPC(ctx, data)
+//	if err != nil {
+//		return nil, status.Error(codes.Internal, err.Error())
+//	}
+//	grpc.SetHeader(ctx, metadata.MD(frag.Metadata()))
+//	return &pb.RenderResponse{Html: frag.Body, ContentType: frag.ContentType}, nil
+func (h *Handler[T]) RenderRPC(ctx context.Context, data T) (RPCFragment, error) {
+	body, err := h.ExecuteToBytes(ctx, data)
+	if err != nil {
+		return RPCFragment{}, err
+	}
+
+	frag := RPCFragment{
+		Body:        body,
+		ContentType: "text/html; charset=utf-8",
+		ETag:        outputETag(body),
+		Version:     h.src.version(),
+	}
+	if contentType := mime.TypeByExtension(path.Ext(h.file)); contentType != "" {
+		frag.ContentType = contentType
+	}
+	if policy, ok := h.CachePolicy(); ok {
+		frag.CacheControl = policy.CacheControl()
+		frag.SurrogateControl = policy.SurrogateControl()
+	}
+	return frag, nil
+}
+
+// Header returns the cache metadata of the fragment as response headers, e.g. for
+// connect.Response.Header. Content-Type is left out: the RPC protocol owns that header,
+// so the fragment's content type belongs in the response message.
+func (f RPCFragment) Header() http.Header {
+	h := make(http.Header)
+	for key, value := range map[string]string{
+		"Cache-Control":      f.CacheControl,
+		"Surrogate-Control":  f.SurrogateControl,
+		"ETag":               f.ETag,
+		"X-Template-Version": f.Version,
+	} {
+		if value != "" {
+			h.Set(key, value)
+		}
+	}
+	return h
+}
+
+// Metadata returns the headers of Header with the lowercase keys gRPC metadata requires,
+// convertible to metadata.MD.
+func (f RPCFragment) Metadata() map[string][]string {
+	md := make(map[string][]string)
+	for key, values := range f.Header() {
+		md[strings.ToLower(key)] = values
+	}
+	return md
+}
@@ -0,0 +1,74 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_RenderRPC(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/card.html":   &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+	}
+
+	reg := MustNewRegistry(fs,
+		WithCachePolicy[TestData]("card", CachePolicy{Public: true, MaxAge: time.Minute, SurrogateMaxAge: time.Hour}),
+	)
+
+	t.Run("renders fragment with cache metadata", func(t *testing.T) {
+		t.Parallel()
+
+		frag, err := reg.MustGet("card").RenderRPC(context.Background(), TestData{Title: "hi"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "<h1>hi</h1>", string(frag.Body))
+		assert.Equal(t, "text/html; charset=utf-8", frag.ContentType)
+		assert.Equal(t, "public, max-age=60", frag.CacheControl)
+		assert.Equal(t, "max-age=3600", frag.SurrogateControl)
+		assert.Equal(t, outputETag(frag.Body), frag.ETag)
+		assert.NotEmpty(t, frag.Version)
+
+		header := frag.Header()
+		assert.Equal(t, frag.CacheControl, header.Get("Cache-Control"))
+		assert.Equal(t, frag.ETag, header.Get("ETag"))
+		assert.Equal(t, frag.Version, header.Get("X-Template-Version"))
+		assert.Empty(t, header.Get("Content-Type"))
+
+		assert.Equal(t, map[string][]string{
+			"cache-control":      {frag.CacheControl},
+			"surrogate-control":  {frag.SurrogateControl},
+			"etag":               {frag.ETag},
+			"x-template-version": {frag.Version},
+		}, frag.Metadata())
+	})
+
+	t.Run("infers content type and omits missing policy", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"templates/plain.txt": &fstest.MapFile{Data: []byte("{{.Title}}")},
+		}
+		reg := MustNewRegistry(fs, WithExtension[TestData](".txt"))
+
+		frag, err := reg.MustGet("plain").RenderRPC(context.Background(), TestData{Title: "hi"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "hi", string(frag.Body))
+		assert.Equal(t, "text/plain; charset=utf-8", frag.ContentType)
+		assert.NotContains(t, frag.Metadata(), "cache-control")
+	})
+
+	t.Run("returns render errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := reg.MustGet("broken").RenderRPC(context.Background(), TestData{Title: "hi"})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,26 @@
+package templator
+
+import "html/template"
+
+// WithSanitizer returns an Option that installs the template function sanitize, rendering
+// untrusted HTML, such as user-generated content, as markup filtered by fn:
+//
+//	<div class="comment">{{sanitize .Comment}}</div>
+//
+// fn is typically backed by an HTML sanitizer such as bluemonday:
+//
+//	policy := bluemonday.UGCPolicy()
+//	templator.WithSanitizer[Page](func(s string) template.HTML {
+//		return template.HTML(policy.Sanitize(s))
+//	})
+//
+// Output of fn is trusted as is, so it must never return unfiltered input. With a nil fn,
+// sanitize escapes its input like any other string.
+func WithSanitizer[T any](fn func(string) template.HTML) Option[T] {
+	if fn == nil {
+		fn = func(s string) template.HTML {
+			return template.HTML(template.HTMLEscapeString(s))
+		}
+	}
+	return WithTemplateFuncs[T](template.FuncMap{"sanitize": fn})
+}
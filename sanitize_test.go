@@ -0,0 +1,53 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithSanitizer(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/comment.html": &fstest.MapFile{Data: []byte("<div>{{sanitize .Content}}</div><p>{{.Content}}</p>")},
+	}
+	scripts := regexp.MustCompile(`(?s)<script.*?</script>`)
+	content := `<b>hi</b><script>alert(1)</script>`
+
+	tests := []struct {
+		name     string
+		fn       func(string) template.HTML
+		expected string
+	}{
+		{
+			name: "renders sanitized markup",
+			fn: func(s string) template.HTML {
+				return template.HTML(scripts.ReplaceAllString(s, ""))
+			},
+			expected: "<div><b>hi</b></div><p>&lt;b&gt;hi&lt;/b&gt;&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		},
+		{
+			name:     "nil sanitizer escapes",
+			expected: "<div>&lt;b&gt;hi&lt;/b&gt;&lt;script&gt;alert(1)&lt;/script&gt;</div><p>&lt;b&gt;hi&lt;/b&gt;&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reg, err := NewRegistry(fs, WithSanitizer[TestData](tt.fn), WithFuncValidation[TestData]())
+			require.NoError(t, err)
+
+			got, err := reg.MustGet("comment").ExecuteToString(context.Background(), TestData{Content: content})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
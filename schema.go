@@ -0,0 +1,124 @@
+package templator
+
+import (
+	"encoding"
+	"html/template"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaDraft is the JSON Schema dialect produced by JSONSchema.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document describing template data, for consumers such as
+// headless CMS editors and frontend previews.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	ContentEncoding      string             `json:"contentEncoding,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	htmlType          = reflect.TypeFor[template.HTML]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// JSONSchema returns the JSON Schema of T as it is decoded from JSON, following encoding/json
+// rules: exported fields are named by their json tag, fields without omitempty are required,
+// and embedded structs are flattened. Recursive types are cut at the first repetition with
+// an unconstrained schema.
+func JSONSchema[T any]() *Schema {
+	t := reflect.TypeFor[T]()
+
+	s := schemaOf(t, make(map[reflect.Type]bool))
+	s.Schema = SchemaDraft
+	s.Title = t.Name()
+	return s
+}
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == htmlType:
+		return &Schema{Type: "string"}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", ContentEncoding: "base64"}
+		}
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Schema{}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		addFields(s, t, visiting)
+		return s
+	default:
+		return &Schema{}
+	}
+}
+
+// addFields adds the JSON-visible fields of struct type t to s, flattening embedded structs.
+func addFields(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			addFields(s, ft, visiting)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = schemaOf(field.Type, visiting)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") && field.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package templator
+
+import (
+	"encoding/json"
+	"html/template"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	schemaMeta struct {
+		Published time.Time `json:"published"`
+	}
+	schemaNode struct {
+		Name     string        `json:"name"`
+		Children []*schemaNode `json:"children,omitempty"`
+	}
+	schemaPage struct {
+		schemaMeta
+		Title   string            `json:"title"`
+		Body    template.HTML     `json:"body"`
+		Views   int               `json:"views,omitempty"`
+		Rating  float64           `json:"rating"`
+		Draft   bool              `json:"draft"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Author  *schemaNode       `json:"author"`
+		Avatar  []byte            `json:"avatar,omitempty"`
+		Secret  string            `json:"-"`
+		private string
+	}
+)
+
+func TestJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	s := JSONSchema[schemaPage]()
+
+	assert.Equal(t, SchemaDraft, s.Schema)
+	assert.Equal(t, "schemaPage", s.Title)
+	assert.Equal(t, "object", s.Type)
+	assert.ElementsMatch(t, []string{"published", "title", "body", "rating", "draft", "tags"}, s.Required)
+
+	props := s.Properties
+	require.Len(t, props, 10)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, props["published"])
+	assert.Equal(t, &Schema{Type: "string"}, props["body"])
+	assert.Equal(t, &Schema{Type: "integer"}, props["views"])
+	assert.Equal(t, &Schema{Type: "number"}, props["rating"])
+	assert.Equal(t, &Schema{Type: "boolean"}, props["draft"])
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{Type: "string"}}, props["tags"])
+	assert.Equal(t, &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}, props["labels"])
+	assert.Equal(t, &Schema{Type: "string", ContentEncoding: "base64"}, props["avatar"])
+	assert.NotContains(t, props, "Secret")
+	assert.NotContains(t, props, "private")
+
+	author := props["author"]
+	assert.Equal(t, "object", author.Type)
+	assert.Equal(t, []string{"name"}, author.Required)
+	assert.Equal(t, &Schema{Type: "array", Items: &Schema{}}, author.Properties["children"], "recursion is cut")
+
+	out, err := json.Marshal(s)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"$schema":"https://json-schema.org/draft/2020-12/schema"`)
+}
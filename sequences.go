@@ -0,0 +1,160 @@
+package templator
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"iter"
+	"reflect"
+)
+
+// WithSequenceFuncs returns an Option that installs template functions shaping slices, arrays,
+// and Go iterators, iter.Seq and iter.Seq2, for {{range}}:
+//
+//	{{range .Products | chunk 3}}<div class="row">{{range .}}{{.Name}}{{end}}</div>{{end}}
+//	{{range .Latest | take 5}}<li>{{.Title}}</li>{{end}}
+//	{{range .ByID | values}}<li>{{.Name}}</li>{{end}}
+//	{{len (collect .Results)}} results
+//
+// chunk and take consume their input lazily, so they can shape unbounded iterators and
+// stop pulling from them early; collect reads the whole input into a slice. For an
+// iter.Seq2, values yields the values, and the other functions work on the values too.
+// Templates range over iterators directly since Go 1.24; the field validation of
+// WithFieldValidation follows the element types of iter.Seq and iter.Seq2 fields.
+func WithSequenceFuncs[T any]() Option[T] {
+	return WithTemplateFuncs[T](template.FuncMap{
+		"chunk":   chunkSeq,
+		"take":    takeSeq,
+		"collect": collectSeq,
+		"values":  valuesSeq,
+	})
+}
+
+// errNotSequence is returned by the sequence functions for values they cannot range over.
+var errNotSequence = errors.New("not a slice, array, iter.Seq, or iter.Seq2")
+
+// chunkSeq yields the elements of seq in slices of n, the last one holding the remainder.
+// Each slice has the element type of seq.
+func chunkSeq(n int, seq any) (iter.Seq[any], error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("chunk size must be positive, got %d", n)
+	}
+
+	elems, typ, err := elements(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(any) bool) {
+		chunk := reflect.MakeSlice(reflect.SliceOf(typ), 0, n)
+		for elem := range elems {
+			if chunk = reflect.Append(chunk, elem); chunk.Len() < n {
+				continue
+			}
+			if !yield(chunk.Interface()) {
+				return
+			}
+			chunk = reflect.MakeSlice(reflect.SliceOf(typ), 0, n)
+		}
+		if chunk.Len() > 0 {
+			yield(chunk.Interface())
+		}
+	}, nil
+}
+
+// takeSeq yields the first n elements of seq.
+func takeSeq(n int, seq any) (iter.Seq[any], error) {
+	elems, _, err := elements(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(any) bool) {
+		if n <= 0 {
+			return
+		}
+
+		taken := 0
+		for elem := range elems {
+			if !yield(elem.Interface()) {
+				return
+			}
+			if taken++; taken == n {
+				return
+			}
+		}
+	}, nil
+}
+
+// collectSeq returns the elements of seq as a slice of their element type.
+func collectSeq(seq any) (any, error) {
+	elems, typ, err := elements(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	slice := reflect.MakeSlice(reflect.SliceOf(typ), 0, 0)
+	for elem := range elems {
+		slice = reflect.Append(slice, elem)
+	}
+	return slice.Interface(), nil
+}
+
+// valuesSeq yields the elements of seq, which are the values of an iter.Seq2.
+func valuesSeq(seq any) (iter.Seq[any], error) {
+	elems, _, err := elements(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(any) bool) {
+		for elem := range elems {
+			if !yield(elem.Interface()) {
+				return
+			}
+		}
+	}, nil
+}
+
+// elements returns an iterator over the elements of a slice, an array, or the values of
+// an iter.Seq or iter.Seq2, along with their type. Nil sequences have no elements.
+func elements(seq any) (iter.Seq[reflect.Value], reflect.Type, error) {
+	if seq == nil {
+		return func(func(reflect.Value) bool) {}, reflect.TypeFor[any](), nil
+	}
+
+	val := reflect.ValueOf(seq)
+	switch typ := val.Type(); {
+	case typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array:
+		return func(yield func(reflect.Value) bool) {
+			for i := range val.Len() {
+				if !yield(val.Index(i)) {
+					return
+				}
+			}
+		}, typ.Elem(), nil
+	case typ.Kind() == reflect.Func && typ.CanSeq2():
+		return func(yield func(reflect.Value) bool) {
+			if val.IsNil() {
+				return
+			}
+			for _, v := range val.Seq2() {
+				if !yield(v) {
+					return
+				}
+			}
+		}, typ.In(0).In(1), nil
+	case typ.Kind() == reflect.Func && typ.CanSeq() && typ.In(0).NumIn() == 1:
+		return func(yield func(reflect.Value) bool) {
+			if val.IsNil() {
+				return
+			}
+			for v := range val.Seq() {
+				if !yield(v) {
+					return
+				}
+			}
+		}, typ.In(0).In(0), nil
+	}
+	return nil, nil, fmt.Errorf("cannot range over %T: %w", seq, errNotSequence)
+}
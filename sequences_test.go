@@ -0,0 +1,171 @@
+package templator
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sequenceItem struct {
+	Name string
+}
+
+type sequenceData struct {
+	Items []sequenceItem
+	Seq   iter.Seq[sequenceItem]
+	ByID  iter.Seq2[int, sequenceItem]
+	Nums  iter.Seq[int]
+}
+
+func TestWithSequenceFuncs(t *testing.T) {
+	t.Parallel()
+
+	items := []sequenceItem{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}}
+	data := sequenceData{
+		Items: items,
+		Seq:   slices.Values(items),
+		ByID:  slices.All(items),
+		Nums: func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				if !yield(i) {
+					return
+				}
+			}
+		},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		data    *sequenceData
+		want    string
+	}{
+		{
+			name:    "chunks a slice",
+			content: `{{range .Items | chunk 2}}[{{range .}}{{.Name}}{{end}}]{{end}}`,
+			want:    "[ab][cd][e]",
+		},
+		{
+			name:    "chunks an iter.Seq",
+			content: `{{range .Seq | chunk 3}}[{{range .}}{{.Name}}{{end}}]{{end}}`,
+			want:    "[abc][de]",
+		},
+		{
+			name:    "chunks the values of an iter.Seq2",
+			content: `{{range chunk 5 .ByID}}{{len .}}{{end}}`,
+			want:    "5",
+		},
+		{
+			name:    "takes from an unbounded iter.Seq",
+			content: `{{range .Nums | take 3}}{{.}}{{end}}`,
+			want:    "012",
+		},
+		{
+			name:    "takes more than available",
+			content: `{{range .Seq | take 10}}{{.Name}}{{end}}`,
+			want:    "abcde",
+		},
+		{
+			name:    "collects an iter.Seq",
+			content: `{{$all := collect .Seq}}{{len $all}} {{(index $all 1).Name}}`,
+			want:    "5 b",
+		},
+		{
+			name:    "ranges over the values of an iter.Seq2",
+			content: `{{range .ByID | values}}{{.Name}}{{end}}`,
+			want:    "abcde",
+		},
+		{
+			name:    "ranges over iterators directly",
+			content: `{{range $i, $item := .ByID}}{{$i}}{{$item.Name}}{{end}}`,
+			want:    "0a1b2c3d4e",
+		},
+		{
+			name:    "nil iterators are empty",
+			content: `{{range chunk 2 .Seq}}x{{else}}empty{{end}}`,
+			data:    &sequenceData{},
+			want:    "empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fs := fstest.MapFS{"templates/page.html": &fstest.MapFile{Data: []byte(tt.content)}}
+			reg := MustNewRegistry(fs, WithSequenceFuncs[sequenceData](), WithFieldValidation(sequenceData{}))
+
+			h, err := reg.Get("page")
+			require.NoError(t, err)
+
+			input := data
+			if tt.data != nil {
+				input = *tt.data
+			}
+
+			got, err := h.ExecuteToString(context.Background(), input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSequenceFuncs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stops pulling once done", func(t *testing.T) {
+		t.Parallel()
+
+		pulled := 0
+		nums := func(yield func(int) bool) {
+			for i := 0; ; i++ {
+				pulled++
+				if !yield(i) {
+					return
+				}
+			}
+		}
+
+		seq, err := takeSeq(3, iter.Seq[int](nums))
+		require.NoError(t, err)
+		assert.Equal(t, []any{0, 1, 2}, slices.Collect(seq))
+		assert.Equal(t, 3, pulled)
+
+		chunks, err := chunkSeq(2, iter.Seq[int](nums))
+		require.NoError(t, err)
+		for chunk := range chunks {
+			assert.Equal(t, []int{0, 1}, chunk)
+			break
+		}
+	})
+
+	t.Run("keeps the element type", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := collectSeq(maps.Values(map[string]int{"a": 1}))
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, got)
+
+		got, err = collectSeq([2]string{"a", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, got)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := chunkSeq(0, []int{1})
+		require.Error(t, err)
+
+		for _, seq := range []any{42, "text", map[string]int{}, func() {}} {
+			_, err := collectSeq(seq)
+			require.ErrorIs(t, err, errNotSequence, "%T", seq)
+		}
+	})
+}
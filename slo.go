@@ -0,0 +1,130 @@
+package templator
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// sloSamples is the number of recent render durations kept per template to estimate its p95.
+const sloSamples = 256
+
+// RenderStats reports recent render times of a template against its p95 budget.
+type RenderStats struct {
+	// Count is the number of renders observed.
+	Count uint64
+	// P95 is the 95th percentile over the most recent renders.
+	P95 time.Duration
+	// Budget is the expected p95 declared with WithRenderSLO.
+	Budget time.Duration
+	// Breaches is the number of renders that took longer than Budget.
+	Breaches uint64
+}
+
+// Breached reports whether the observed p95 is over budget.
+func (s RenderStats) Breached() bool {
+	return s.P95 > s.Budget
+}
+
+// SlowRender describes a render that took longer than its template's budget.
+type SlowRender struct {
+	Name     string
+	Duration time.Duration
+	// Stats includes the slow render.
+	Stats RenderStats
+}
+
+// WithRenderSLO returns an Option declaring the expected p95 render time of the named template.
+// Render times of the template are then tracked and reported by RenderStats, and renders
+// over budget are passed to the handler set with WithSlowRenderHandler.
+func WithRenderSLO[T any](name string, p95 time.Duration) Option[T] {
+	return func(r *Registry[T]) {
+		if r.config.slos == nil {
+			r.config.slos = make(map[string]time.Duration)
+		}
+		r.config.slos[name] = p95
+	}
+}
+
+// WithSlowRenderHandler returns an Option that calls fn after every render of a template
+// declared with WithRenderSLO that takes longer than its budget. fn is called synchronously
+// by the rendering goroutine and should return quickly.
+func WithSlowRenderHandler[T any](fn func(SlowRender)) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.onSlowRender = fn
+	}
+}
+
+// RenderStats returns the render statistics of the named template and
+// whether it was declared with WithRenderSLO.
+func (r *Registry[T]) RenderStats(name string) (RenderStats, bool) {
+	tracker, ok := r.slos[name]
+	if !ok {
+		return RenderStats{}, false
+	}
+	return tracker.stats(), true
+}
+
+// observe records a render of the named template that started at start.
+func (r *Registry[T]) observe(name string, start time.Time) {
+	tracker, ok := r.slos[name]
+	if !ok {
+		return
+	}
+
+	d := time.Since(start)
+	stats := tracker.record(d)
+	if d > stats.Budget && r.config.onSlowRender != nil {
+		r.config.onSlowRender(SlowRender{Name: name, Duration: d, Stats: stats})
+	}
+}
+
+// sloTracker keeps the most recent render durations of a template in a ring buffer.
+type sloTracker struct {
+	budget time.Duration
+
+	mu       sync.Mutex
+	samples  []time.Duration
+	next     int
+	count    uint64
+	breaches uint64
+}
+
+func newSLOTracker(budget time.Duration) *sloTracker {
+	return &sloTracker{budget: budget, samples: make([]time.Duration, 0, sloSamples)}
+}
+
+// record adds a render duration and returns the updated stats.
+func (t *sloTracker) record(d time.Duration) RenderStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.samples) < sloSamples {
+		t.samples = append(t.samples, d)
+	} else {
+		t.samples[t.next] = d
+		t.next = (t.next + 1) % sloSamples
+	}
+
+	t.count++
+	if d > t.budget {
+		t.breaches++
+	}
+	return t.statsLocked()
+}
+
+func (t *sloTracker) stats() RenderStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.statsLocked()
+}
+
+func (t *sloTracker) statsLocked() RenderStats {
+	stats := RenderStats{Count: t.count, Budget: t.budget, Breaches: t.breaches}
+	if len(t.samples) > 0 {
+		sorted := slices.Clone(t.samples)
+		slices.Sort(sorted)
+		stats.P95 = sorted[(len(sorted)*95+99)/100-1]
+	}
+	return stats
+}
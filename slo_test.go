@@ -0,0 +1,97 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRenderSLO(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/slow.html": &fstest.MapFile{Data: []byte("{{wait .Title}}")},
+		"templates/fast.html": &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+	}
+
+	var (
+		mu   sync.Mutex
+		slow []SlowRender
+	)
+
+	reg, err := NewRegistry(
+		fs,
+		WithTemplateFuncs[TestData](template.FuncMap{
+			"wait": func(d string) string {
+				dur, _ := time.ParseDuration(d)
+				time.Sleep(dur)
+				return ""
+			},
+		}),
+		WithRenderSLO[TestData]("slow", 5*time.Millisecond),
+		WithSlowRenderHandler[TestData](func(r SlowRender) {
+			mu.Lock()
+			slow = append(slow, r)
+			mu.Unlock()
+		}),
+	)
+	require.NoError(t, err)
+
+	handler, err := reg.Get("slow")
+	require.NoError(t, err)
+
+	for _, d := range []string{"0s", "0s", "10ms"} {
+		_, err := handler.ExecuteToString(context.Background(), TestData{Title: d})
+		require.NoError(t, err)
+	}
+
+	stats, ok := reg.RenderStats("slow")
+	require.True(t, ok)
+	assert.Equal(t, uint64(3), stats.Count)
+	assert.Equal(t, uint64(1), stats.Breaches)
+	assert.Equal(t, 5*time.Millisecond, stats.Budget)
+	assert.GreaterOrEqual(t, stats.P95, 10*time.Millisecond)
+	assert.True(t, stats.Breached())
+
+	mu.Lock()
+	require.Len(t, slow, 1)
+	assert.Equal(t, "slow", slow[0].Name)
+	assert.GreaterOrEqual(t, slow[0].Duration, 10*time.Millisecond)
+	assert.Equal(t, uint64(3), slow[0].Stats.Count)
+	mu.Unlock()
+
+	t.Run("templates without SLO are not tracked", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := reg.RenderStats("fast")
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects non-positive budget", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry[TestData](fs, WithRenderSLO[TestData]("fast", 0))
+		require.ErrorContains(t, err, "invalid option WithRenderSLO: budget of 'fast' must be positive")
+	})
+}
+
+func TestSLOTracker(t *testing.T) {
+	t.Parallel()
+
+	tracker := newSLOTracker(50 * time.Millisecond)
+	for i := range 300 {
+		tracker.record(time.Duration(i%100) * time.Millisecond)
+	}
+
+	stats := tracker.stats()
+	assert.Equal(t, uint64(300), stats.Count)
+	assert.Equal(t, uint64(147), stats.Breaches)
+	assert.Equal(t, 95*time.Millisecond, stats.P95)
+	assert.True(t, stats.Breached())
+}
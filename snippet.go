@@ -0,0 +1,35 @@
+package templator
+
+import "io/fs"
+
+// Parse parses src as a standalone template named name, without a filesystem, for one-off
+// snippets such as email subjects or tiny fragments that still want typed data and validation.
+// The options are those of NewRegistry; the snippet cannot invoke other templates, so partials
+// are not available.
+func Parse[T any](name, src string, opts ...Option[T]) (*Handler[T], error) {
+	reg, err := NewRegistry(emptyFS{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The source is served as a published draft, ahead of the empty filesystem.
+	reg.drafts.live = map[string][]byte{name: []byte(src)}
+	return reg.Get(name)
+}
+
+// MustParse is like Parse but panics if the snippet cannot be parsed.
+// It simplifies initialization of package-level snippets.
+func MustParse[T any](name, src string, opts ...Option[T]) *Handler[T] {
+	h, err := Parse(name, src, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// emptyFS is a filesystem without files.
+type emptyFS struct{}
+
+func (emptyFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
@@ -0,0 +1,62 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("renders snippet with typed data", func(t *testing.T) {
+		t.Parallel()
+
+		subject, err := Parse[TestData]("subject", `Welcome, {{upper .Title}}!`,
+			WithTemplateFuncs[TestData](template.FuncMap{"upper": strings.ToUpper}),
+		)
+		require.NoError(t, err)
+
+		got, err := subject.ExecuteToString(context.Background(), TestData{Title: "ana"})
+		require.NoError(t, err)
+		assert.Equal(t, "Welcome, ANA!", got)
+	})
+
+	t.Run("validates fields", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("subject", `{{.Missing}}`, WithFieldValidation(TestData{}))
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("returns parse errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse[TestData]("subject", "\n{{.Title")
+
+		var parseErr ErrTemplateParse
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, "subject", parseErr.Name)
+		assert.Equal(t, 2, parseErr.Line)
+	})
+
+	t.Run("cannot invoke other templates", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Parse("subject", `ok`, WithPartials[TestData]("header"))
+		require.ErrorIs(t, err, ErrTemplateNotFound{Name: "header"})
+	})
+
+	t.Run("must parse panics on error", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NotPanics(t, func() { MustParse[TestData]("ok", `{{.Title}}`) })
+		assert.Panics(t, func() { MustParse[TestData]("broken", `{{.Title`) })
+	})
+}
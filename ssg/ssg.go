@@ -0,0 +1,239 @@
+// Package ssg renders a static site from a manifest mapping output paths to templates and
+// data files:
+//
+//	pages:
+//	  - output: index.html
+//	    template: home
+//	    data: data/home.yaml
+//	  - output: pt/index.html
+//	    template: home
+//	    data: data/home.pt.json
+//	    locale: pt
+//
+// Data files are JSON or YAML, decoded into the registry's data type through its JSON tags:
+//
+//	m, err := ssg.LoadManifest("site.yaml")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	written, err := ssg.Build(ctx, reg, m, "public")
+package ssg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/alesr/templator"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrUnsupportedFormat is returned for manifests and data files that are neither JSON nor YAML.
+var ErrUnsupportedFormat = errors.New("unsupported format, expected .json, .yaml, or .yml")
+
+// Page is an entry of a manifest: a file of the site and how to render it.
+type Page struct {
+	// Output is the slash-separated path of the page within the output directory.
+	Output string `json:"output" yaml:"output"`
+	// Template is the name of the template rendering the page.
+	Template string `json:"template" yaml:"template"`
+	// Data is the path of the JSON or YAML file holding the page data, relative to the
+	// manifest directory. Pages without data render the zero value of the data type.
+	Data string `json:"data,omitempty" yaml:"data,omitempty"`
+	// Locale renders the page with the locale variant of its template, as with GetLocalized,
+	// and with the locale in the rendering context, as with templator.WithLocale.
+	Locale string `json:"locale,omitempty" yaml:"locale,omitempty"`
+}
+
+// Manifest lists the pages of a site.
+type Manifest struct {
+	Pages []Page `json:"pages" yaml:"pages"`
+	// Dir is the directory data files are resolved against. LoadManifest sets it to the
+	// directory of the manifest file.
+	Dir string `json:"-" yaml:"-"`
+}
+
+// ErrPage is returned by Build for a page that is invalid or fails to render.
+type ErrPage struct {
+	Output   string
+	Template string
+	Err      error
+}
+
+func (e ErrPage) Error() string {
+	return fmt.Sprintf("page '%s' (template '%s'): %v", e.Output, e.Template, e.Err)
+}
+
+func (e ErrPage) Unwrap() error {
+	return e.Err
+}
+
+// LoadManifest reads a JSON or YAML manifest, picking the format from the file extension.
+func LoadManifest(path string) (*Manifest, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := decode(path, content, &m); err != nil {
+		return nil, fmt.Errorf("manifest %s: %w", path, err)
+	}
+	m.Dir = filepath.Dir(path)
+	return &m, nil
+}
+
+// Option configures Build.
+type Option func(*config)
+
+type config struct {
+	concurrency int
+}
+
+// WithConcurrency returns an Option that sets how many pages render at once. It defaults to 4.
+func WithConcurrency(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// Build renders every page of m with reg and writes it under outDir, creating directories as
+// needed. Pages are checked before anything is written: outputs must be unique local paths,
+// and every page must name a template. Pages failing to render do not stop the others; their
+// ErrPage errors are joined in manifest order. Build returns the paths written.
+func Build[T any](ctx context.Context, reg *templator.Registry[T], m *Manifest, outDir string, opts ...Option) ([]string, error) {
+	cfg := config{concurrency: 4}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if err := check(m.Pages); err != nil {
+		return nil, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		errs    = make([]error, len(m.Pages))
+		written = make([]string, len(m.Pages))
+		sem     = make(chan struct{}, cfg.concurrency)
+	)
+	for i, page := range m.Pages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ErrPage{Output: page.Output, Template: page.Template, Err: ctx.Err()}
+				return
+			}
+
+			dest := filepath.Join(outDir, filepath.FromSlash(page.Output))
+			if err := render(ctx, reg, m.Dir, page, dest); err != nil {
+				errs[i] = ErrPage{Output: page.Output, Template: page.Template, Err: err}
+				return
+			}
+			written[i] = dest
+		}()
+	}
+	wg.Wait()
+
+	var paths []string
+	for _, dest := range written {
+		if dest != "" {
+			paths = append(paths, dest)
+		}
+	}
+	return paths, errors.Join(errs...)
+}
+
+// check reports the pages that cannot be built, before any is rendered.
+func check(pages []Page) error {
+	var (
+		errs    []error
+		outputs = make(map[string]bool, len(pages))
+	)
+	for _, page := range pages {
+		output := filepath.FromSlash(page.Output)
+		switch {
+		case page.Template == "":
+			errs = append(errs, ErrPage{Output: page.Output, Err: errors.New("missing template")})
+		case !filepath.IsLocal(output):
+			errs = append(errs, ErrPage{Output: page.Output, Template: page.Template, Err: errors.New("output must be a local path")})
+		case outputs[filepath.Clean(output)]:
+			errs = append(errs, ErrPage{Output: page.Output, Template: page.Template, Err: errors.New("duplicate output")})
+		}
+		outputs[filepath.Clean(output)] = true
+	}
+	return errors.Join(errs...)
+}
+
+// render renders a page into dest.
+func render[T any](ctx context.Context, reg *templator.Registry[T], dir string, page Page, dest string) error {
+	var data T
+	if page.Data != "" {
+		file := filepath.Join(dir, filepath.FromSlash(page.Data))
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if err := decode(file, content, &data); err != nil {
+			return fmt.Errorf("data %s: %w", page.Data, err)
+		}
+	}
+
+	var (
+		h   *templator.Handler[T]
+		err error
+	)
+	if page.Locale != "" {
+		ctx = templator.WithLocale(ctx, page.Locale)
+		h, err = reg.GetLocalized(page.Template, page.Locale)
+	} else {
+		h, err = reg.Get(page.Template)
+	}
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := h.Execute(ctx, &buf, data); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, buf.Bytes(), 0o644)
+}
+
+// decode decodes JSON or YAML content into v, picking the format from the file extension.
+// YAML goes through JSON, so the JSON tags of v apply to both formats.
+func decode(file string, content []byte, v any) error {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		return json.Unmarshal(content, v)
+	case ".yaml", ".yml":
+		var raw any
+		if err := yaml.Unmarshal(content, &raw); err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(encoded, v)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
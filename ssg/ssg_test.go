@@ -0,0 +1,173 @@
+package ssg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageData struct {
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"`
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+}
+
+func newRegistry(t *testing.T) *templator.Registry[pageData] {
+	t.Helper()
+
+	fs := fstest.MapFS{
+		"templates/home.html":    &fstest.MapFile{Data: []byte(`<h1>{{.Title}}</h1>{{range .Tags}}<i>{{.}}</i>{{end}}`)},
+		"templates/home.pt.html": &fstest.MapFile{Data: []byte(`<h1 lang="pt">{{.Title}}</h1>`)},
+		"templates/about.html":   &fstest.MapFile{Data: []byte(`<p>about</p>`)},
+		"templates/broken.html":  &fstest.MapFile{Data: []byte(`{{.Missing}}`)},
+	}
+	return templator.MustNewRegistry(fs, templator.WithLocales[pageData]("pt"))
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	for _, manifest := range []string{"site.yaml", "site.json"} {
+		t.Run(manifest, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"site.yaml": `
+pages:
+  - output: index.html
+    template: home
+    data: data/home.yaml
+  - output: pt/index.html
+    template: home
+    data: data/home.json
+    locale: pt
+  - output: about/index.html
+    template: about
+`,
+				"site.json": `{"pages": [
+  {"output": "index.html", "template": "home", "data": "data/home.yaml"},
+  {"output": "pt/index.html", "template": "home", "data": "data/home.json", "locale": "pt"},
+  {"output": "about/index.html", "template": "about"}
+]}`,
+				"data/home.yaml": "title: Home\ntags: [go, html]\n",
+				"data/home.json": `{"title": "Início"}`,
+			})
+
+			m, err := LoadManifest(filepath.Join(dir, manifest))
+			require.NoError(t, err)
+			require.Len(t, m.Pages, 3)
+
+			out := filepath.Join(dir, "public")
+			written, err := Build(context.Background(), newRegistry(t), m, out, WithConcurrency(2))
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				filepath.Join(out, "index.html"),
+				filepath.Join(out, "pt", "index.html"),
+				filepath.Join(out, "about", "index.html"),
+			}, written)
+
+			for file, want := range map[string]string{
+				"index.html":       "<h1>Home</h1><i>go</i><i>html</i>",
+				"pt/index.html":    `<h1 lang="pt">Início</h1>`,
+				"about/index.html": "<p>about</p>",
+			} {
+				got, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(file)))
+				require.NoError(t, err)
+				assert.Equal(t, want, string(got), file)
+			}
+		})
+	}
+}
+
+func TestBuild_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("checks pages before rendering", func(t *testing.T) {
+		t.Parallel()
+
+		out := t.TempDir()
+		m := &Manifest{Pages: []Page{
+			{Output: "index.html", Template: "home"},
+			{Output: "../escape.html", Template: "home"},
+			{Output: "/abs.html", Template: "home"},
+			{Output: "./index.html", Template: "about"},
+			{Output: "missing.html"},
+		}}
+
+		written, err := Build(context.Background(), newRegistry(t), m, out)
+		require.Error(t, err)
+		assert.Nil(t, written)
+
+		var pageErr ErrPage
+		require.ErrorAs(t, err, &pageErr)
+		assert.Equal(t, "../escape.html", pageErr.Output)
+		for _, msg := range []string{"local path", "duplicate output", "missing template"} {
+			assert.ErrorContains(t, err, msg)
+		}
+
+		entries, err := os.ReadDir(out)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "nothing is written")
+	})
+
+	t.Run("renders the other pages when one fails", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"data/bad.toml": "title = 'x'"})
+
+		m := &Manifest{Dir: dir, Pages: []Page{
+			{Output: "index.html", Template: "home"},
+			{Output: "broken.html", Template: "broken"},
+			{Output: "unknown.html", Template: "unknown"},
+			{Output: "bad.html", Template: "home", Data: "data/bad.toml"},
+			{Output: "nodata.html", Template: "home", Data: "data/missing.json"},
+		}}
+
+		out := filepath.Join(dir, "public")
+		written, err := Build(context.Background(), newRegistry(t), m, out)
+		assert.Equal(t, []string{filepath.Join(out, "index.html")}, written)
+
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+		require.ErrorIs(t, err, os.ErrNotExist)
+		assert.ErrorAs(t, err, &templator.ErrTemplateExecution{})
+		assert.ErrorAs(t, err, &templator.ErrTemplateNotFound{})
+	})
+
+	t.Run("stops on canceled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		m := &Manifest{Pages: []Page{{Output: "index.html", Template: "about"}}}
+		_, err := Build(ctx, newRegistry(t), m, t.TempDir())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("rejects unknown manifest formats", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"site.toml": ""})
+
+		_, err := LoadManifest(filepath.Join(dir, "site.toml"))
+		require.ErrorIs(t, err, ErrUnsupportedFormat)
+	})
+}
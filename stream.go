@@ -0,0 +1,65 @@
+package templator
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// DefaultFlushThreshold is the number of bytes ExecuteStream writes between flushes.
+const DefaultFlushThreshold = 4096
+
+// StreamOption configures ExecuteStream.
+type StreamOption func(*streamConfig)
+
+// WithFlushThreshold returns a StreamOption that sets how many bytes are written
+// between flushes. Non-positive values flush after every write.
+func WithFlushThreshold(bytes int) StreamOption {
+	return func(c *streamConfig) {
+		c.threshold = bytes
+	}
+}
+
+type streamConfig struct {
+	threshold int
+}
+
+// ExecuteStream renders the template like Execute, but when w implements http.Flusher
+// it flushes output as rendering progresses, every DefaultFlushThreshold bytes by default,
+// and once more at the end. This enables progressive rendering of very large pages
+// instead of buffering the whole document, so, unlike Execute, a render error may leave
+// a partial response.
+func (h *Handler[T]) ExecuteStream(ctx context.Context, w io.Writer, data T, opts ...StreamOption) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return h.Execute(ctx, w, data)
+	}
+
+	cfg := streamConfig{threshold: DefaultFlushThreshold}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	fw := &flushWriter{Writer: w, flusher: flusher, threshold: cfg.threshold}
+	defer flusher.Flush()
+
+	return h.executeTo(ctx, fw, data, false)
+}
+
+// flushWriter flushes the underlying writer once threshold bytes have been written since the last flush.
+type flushWriter struct {
+	io.Writer
+	flusher   http.Flusher
+	threshold int
+	pending   int
+}
+
+func (w *flushWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.pending += n
+	if w.pending >= w.threshold {
+		w.flusher.Flush()
+		w.pending = 0
+	}
+	return n, err
+}
@@ -0,0 +1,70 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingFlusher struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *countingFlusher) Flush() {
+	f.flushes++
+}
+
+func TestHandler_ExecuteStream(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/list.html": &fstest.MapFile{Data: []byte("{{range .}}<li>{{.}}</li>{{end}}")},
+	}
+
+	reg, err := NewRegistry[[]string](fs)
+	require.NoError(t, err)
+
+	handler, err := reg.Get("list")
+	require.NoError(t, err)
+
+	items := strings.Split(strings.Repeat("item,", 100), ",")
+
+	t.Run("flushes at threshold and at the end", func(t *testing.T) {
+		t.Parallel()
+
+		w := &countingFlusher{}
+		err := handler.ExecuteStream(context.Background(), w, items, WithFlushThreshold(100))
+		require.NoError(t, err)
+
+		assert.Equal(t, 100*len("<li>item</li>")+len("<li></li>"), w.Len())
+		assert.Greater(t, w.flushes, 1)
+		assert.LessOrEqual(t, w.flushes, w.Len()/100+1)
+	})
+
+	t.Run("streams to http response", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		err := handler.ExecuteStream(context.Background(), rec, items[:2])
+		require.NoError(t, err)
+
+		assert.True(t, rec.Flushed)
+		assert.Equal(t, "<li>item</li><li>item</li>", rec.Body.String())
+	})
+
+	t.Run("falls back to Execute without a flusher", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		err := handler.ExecuteStream(context.Background(), &buf, items[:1])
+		require.NoError(t, err)
+		assert.Equal(t, "<li>item</li>", buf.String())
+	})
+}
@@ -0,0 +1,80 @@
+package templator
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"text/template/parse"
+)
+
+// strictFunc is the function strict mode appends to the pipelines of actions printing fields.
+const strictFunc = "templatorStrict"
+
+var strictFuncs = template.FuncMap{
+	strictFunc: func(field string, value any) (any, error) {
+		if value == nil {
+			return nil, fmt.Errorf("field %s is nil", field)
+		}
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil, fmt.Errorf("field %s is nil", field)
+		}
+		return value, nil
+	},
+}
+
+// WithStrictMode returns an Option that fails renders, instead of printing "<no value>" or
+// an empty string, when a template prints a missing map key or a nil pointer or interface
+// field, so data bugs surface as errors rather than broken pages. Nil fields can still be
+// tested with if and with.
+func WithStrictMode[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.strict = true
+	}
+}
+
+// strictTree makes the actions of a parse tree that print fields fail on nil values.
+func strictTree(list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.ActionNode:
+			if len(n.Pipe.Decl) > 0 || !isFieldRef(n.Pipe.Cmds[len(n.Pipe.Cmds)-1]) {
+				continue
+			}
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Pos:      n.Pos,
+				Args: []parse.Node{
+					parse.NewIdentifier(strictFunc).SetPos(n.Pos),
+					&parse.StringNode{NodeType: parse.NodeString, Pos: n.Pos, Quoted: fmt.Sprintf("%q", n.Pipe.String()), Text: n.Pipe.String()},
+				},
+			})
+		case *parse.IfNode:
+			strictTree(n.List)
+			strictTree(n.ElseList)
+		case *parse.RangeNode:
+			strictTree(n.List)
+			strictTree(n.ElseList)
+		case *parse.WithNode:
+			strictTree(n.List)
+			strictTree(n.ElseList)
+		}
+	}
+}
+
+// isFieldRef reports whether cmd only evaluates a field, such as .User or $.User.Name.
+func isFieldRef(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) != 1 {
+		return false
+	}
+	switch arg := cmd.Args[0].(type) {
+	case *parse.FieldNode, *parse.ChainNode:
+		return true
+	case *parse.VariableNode:
+		return len(arg.Ident) > 1
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package templator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ErrNilFS is returned by NewRegistry when it is given a nil file system.
+var ErrNilFS = errors.New("nil file system")
+
+// ErrNoTemplates is returned, wrapped in ErrInvalidTemplateDir, by registries created with
+// WithStrictInit when the template directory holds no template.
+var ErrNoTemplates = errors.New("no templates found")
+
+// ErrInvalidTemplateDir is returned by NewRegistry with WithStrictInit when the template
+// directory is missing, is not a directory, or holds no template.
+type ErrInvalidTemplateDir struct {
+	Path string
+	Err  error
+}
+
+func (e ErrInvalidTemplateDir) Error() string {
+	return fmt.Sprintf("invalid template directory '%s': %v", e.Path, e.Err)
+}
+
+func (e ErrInvalidTemplateDir) Unwrap() error {
+	return e.Err
+}
+
+// WithStrictInit returns an Option that checks the template directory when the registry
+// is created, so a misconfigured path or an empty file system, such as an embed.FS whose
+// pattern matched nothing, fails at startup instead of on the first Get.
+func WithStrictInit[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.strictInit = true
+	}
+}
+
+// checkTemplateDir reports an ErrInvalidTemplateDir unless every template directory is a
+// directory of base, the registry filesystem before template paths are applied, and the
+// directories hold at least one template.
+func (r *Registry[T]) checkTemplateDir(base fs.FS) error {
+	dirs := r.config.paths
+	if len(dirs) == 0 {
+		dirs = []string{r.config.path}
+	}
+
+	for _, dir := range dirs {
+		info, err := fs.Stat(base, dir)
+		switch {
+		case err != nil:
+			return ErrInvalidTemplateDir{Path: dir, Err: err}
+		case !info.IsDir():
+			return ErrInvalidTemplateDir{Path: dir, Err: errors.New("not a directory")}
+		}
+	}
+
+	names, err := r.ownTemplates()
+	switch {
+	case err != nil:
+		return ErrInvalidTemplateDir{Path: strings.Join(dirs, ", "), Err: err}
+	case len(names) == 0:
+		return ErrInvalidTemplateDir{
+			Path: strings.Join(dirs, ", "),
+			Err:  fmt.Errorf("%w with extension %s", ErrNoTemplates, r.config.ext),
+		}
+	}
+	return nil
+}
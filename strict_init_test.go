@@ -0,0 +1,97 @@
+package templator
+
+import (
+	iofs "io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry_NilFS(t *testing.T) {
+	t.Parallel()
+
+	reg, err := NewRegistry[TestData](nil)
+	require.ErrorIs(t, err, ErrNilFS)
+	assert.Nil(t, reg)
+
+	assert.PanicsWithError(t, ErrNilFS.Error(), func() { MustNewRegistry[TestData](nil) })
+}
+
+func TestWithStrictInit(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":   &fstest.MapFile{Data: []byte("{{.Title}}")},
+		"templates/about.tmpl":  &fstest.MapFile{Data: []byte("{{.Title}}")},
+		"empty/readme.txt":      &fstest.MapFile{Data: []byte("nothing here")},
+		"file.html":             &fstest.MapFile{Data: []byte("{{.Title}}")},
+		"nested/pages/dir.html": &fstest.MapFile{Data: []byte("{{.Title}}")},
+	}
+
+	t.Run("accepts a directory holding templates", func(t *testing.T) {
+		t.Parallel()
+
+		for _, path := range []string{"templates", "nested"} {
+			_, err := NewRegistry(fs, WithTemplatesPath[TestData](path), WithStrictInit[TestData]())
+			require.NoError(t, err, path)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		fs      iofs.FS
+		opts    []Option[TestData]
+		wantErr error
+	}{
+		{
+			name:    "missing directory",
+			fs:      fs,
+			opts:    []Option[TestData]{WithTemplatesPath[TestData]("views")},
+			wantErr: iofs.ErrNotExist,
+		},
+		{
+			name:    "empty file system",
+			fs:      fstest.MapFS{},
+			wantErr: iofs.ErrNotExist,
+		},
+		{
+			name:    "directory without templates",
+			fs:      fs,
+			opts:    []Option[TestData]{WithTemplatesPath[TestData]("empty")},
+			wantErr: ErrNoTemplates,
+		},
+		{
+			name:    "templates of another extension",
+			fs:      fs,
+			opts:    []Option[TestData]{WithExtension[TestData](".gohtml")},
+			wantErr: ErrNoTemplates,
+		},
+		{
+			name: "file instead of a directory",
+			fs:   fs,
+			opts: []Option[TestData]{WithTemplatesPath[TestData]("file.html")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewRegistry(tt.fs, append(tt.opts, WithStrictInit[TestData]())...)
+
+			var dirErr ErrInvalidTemplateDir
+			require.ErrorAs(t, err, &dirErr)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+
+			// Without strict init, the same registry is created and fails on Get.
+			reg, err := NewRegistry(tt.fs, tt.opts...)
+			require.NoError(t, err)
+			_, err = reg.Get("home")
+			require.Error(t, err)
+		})
+	}
+}
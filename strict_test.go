@@ -0,0 +1,93 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictUser struct {
+	Name string
+}
+
+type strictData struct {
+	User   *strictUser
+	Extra  any
+	Labels map[string]string
+}
+
+func TestWithStrictMode(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/user.html":   &fstest.MapFile{Data: []byte("{{if .User}}<p>{{.User.Name}}</p>{{end}}<i>{{.User}}</i>")},
+		"templates/extra.html":  &fstest.MapFile{Data: []byte(`{{with $.Labels}}{{.color}}{{end}}{{$.Extra}}`)},
+		"templates/labels.html": &fstest.MapFile{Data: []byte(`<b>{{.Labels.color}}</b>`)},
+		"templates/guard.html":  &fstest.MapFile{Data: []byte(`{{with .User}}{{.Name}}{{else}}anonymous{{end}}`)},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		data     strictData
+		expected string
+		err      string
+	}{
+		{
+			name:     "renders set fields",
+			template: "extra",
+			data:     strictData{Extra: "x", Labels: map[string]string{"color": "red"}},
+			expected: "redx",
+		},
+		{
+			name:     "fails on nil pointer field",
+			template: "user",
+			err:      "field .User is nil",
+		},
+		{
+			name:     "fails on nil interface field",
+			template: "extra",
+			data:     strictData{Labels: map[string]string{"color": "red"}},
+			err:      "field $.Extra is nil",
+		},
+		{
+			name:     "fails on missing map key",
+			template: "labels",
+			data:     strictData{Labels: map[string]string{}},
+			err:      `map has no entry for key "color"`,
+		},
+		{
+			name:     "allows testing nil fields",
+			template: "guard",
+			expected: "anonymous",
+		},
+	}
+
+	reg := MustNewRegistry(fs, WithStrictMode[strictData]())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := reg.MustGet(tt.template).ExecuteToString(context.Background(), tt.data)
+			if tt.err != "" {
+				require.ErrorAs(t, err, new(ErrTemplateExecution))
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("lenient without strict mode", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := MustNewRegistry[strictData](fs).MustGet("labels").ExecuteToString(context.Background(), strictData{})
+		require.NoError(t, err)
+		assert.Equal(t, "<b></b>", got)
+	})
+}
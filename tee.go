@@ -0,0 +1,64 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PostProcessor transforms rendered output before it is written to a sink.
+type PostProcessor func(out []byte) ([]byte, error)
+
+// Sink is a destination of ExecuteTee: a writer and the post-processors applied, in order,
+// to the output written to it.
+type Sink struct {
+	W       io.Writer
+	Process []PostProcessor
+}
+
+// NewSink returns a Sink writing to w the output transformed by process.
+func NewSink(w io.Writer, process ...PostProcessor) Sink {
+	return Sink{W: w, Process: process}
+}
+
+// ExecuteTee renders the template once and writes the output to every sink, each through its
+// own post-processors, e.g. minified to the HTTP response and raw to an archive. Nothing is
+// written when rendering fails. A failing sink doesn't stop the others; the errors of all
+// failing sinks are joined into the returned ErrTemplateExecution.
+func (h *Handler[T]) ExecuteTee(ctx context.Context, data T, sinks ...Sink) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+
+	if err := h.Execute(ctx, buf, data); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, sink := range sinks {
+		if err := sink.write(buf.Bytes()); err != nil {
+			errs = append(errs, fmt.Errorf("sink %d: %w", i, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return ErrTemplateExecution{Name: h.file, Err: err}
+	}
+	return nil
+}
+
+// write post-processes out and writes it to the sink. Processors receive their own copy of
+// the output, so they may modify it in place.
+func (s Sink) write(out []byte) error {
+	if len(s.Process) > 0 {
+		out = bytes.Clone(out)
+	}
+	for _, process := range s.Process {
+		var err error
+		if out, err = process(out); err != nil {
+			return err
+		}
+	}
+	_, err := s.W.Write(out)
+	return err
+}
@@ -0,0 +1,79 @@
+package templator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingWriter fails every write with err.
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestHandler_ExecuteTee(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":   &fstest.MapFile{Data: []byte("<h1>  {{.Title}}  </h1>")},
+		"templates/broken.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+	}
+	reg := MustNewRegistry[TestData](fs)
+
+	squeeze := func(out []byte) ([]byte, error) {
+		return bytes.ReplaceAll(out, []byte(" "), nil), nil
+	}
+	upper := func(out []byte) ([]byte, error) {
+		return bytes.ToUpper(out), nil
+	}
+
+	t.Run("writes processed output to every sink", func(t *testing.T) {
+		t.Parallel()
+
+		var raw, minified, shouted bytes.Buffer
+		err := reg.MustGet("home").ExecuteTee(context.Background(), TestData{Title: "hi"},
+			NewSink(&raw),
+			NewSink(&minified, squeeze),
+			NewSink(&shouted, squeeze, upper),
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "<h1>  hi  </h1>", raw.String())
+		assert.Equal(t, "<h1>hi</h1>", minified.String())
+		assert.Equal(t, "<H1>HI</H1>", shouted.String())
+	})
+
+	t.Run("keeps writing after a sink fails", func(t *testing.T) {
+		t.Parallel()
+
+		errDisk, errProcess := errors.New("disk full"), errors.New("process failed")
+
+		var out bytes.Buffer
+		err := reg.MustGet("home").ExecuteTee(context.Background(), TestData{Title: "hi"},
+			NewSink(failingWriter{err: errDisk}),
+			NewSink(&out, squeeze),
+			NewSink(&out, func([]byte) ([]byte, error) { return nil, errProcess }),
+		)
+		require.ErrorIs(t, err, errDisk)
+		require.ErrorIs(t, err, errProcess)
+		assert.Contains(t, err.Error(), "sink 0: disk full")
+		assert.Contains(t, err.Error(), "sink 2: process failed")
+		assert.Equal(t, "<h1>hi</h1>", out.String())
+	})
+
+	t.Run("writes nothing when rendering fails", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := reg.MustGet("broken").ExecuteTee(context.Background(), TestData{}, NewSink(&out))
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,36 @@
+package templator
+
+import (
+	"fmt"
+	"html/template"
+	"slices"
+)
+
+// WithOption returns a handler of the same template executed with the given html/template
+// options, such as "missingkey=error", so execution semantics can be tuned per template
+// rather than globally. Options are applied after those of the registry, so they take
+// precedence. Unknown options are reported as ErrInvalidOption.
+//
+// Like a handler returned by Get with GetOptions, the new handler is parsed anew, with the
+// overrides of h, and is neither cached nor reloaded. Handlers returned by Compose are
+// parsed again without the templates composed into them.
+func (h *Handler[T]) WithOption(opts ...string) (*Handler[T], error) {
+	if err := checkTemplateOptions(opts); err != nil {
+		return nil, err
+	}
+
+	overrides := h.overrides
+	overrides.options = append(slices.Clip(overrides.options), opts...)
+	return h.reg.parse(h.name, overrides)
+}
+
+// checkTemplateOptions returns ErrInvalidOption for options html/template does not recognize.
+func checkTemplateOptions(opts []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrInvalidOption{Options: []string{"WithOption"}, Reason: fmt.Sprint(r)}
+		}
+	}()
+	template.New("").Option(opts...)
+	return nil
+}
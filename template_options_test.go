@@ -0,0 +1,53 @@
+package templator
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_WithOption(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/labels.html": &fstest.MapFile{Data: []byte(`<b>[[.color]]</b>`)},
+	}
+	data := map[string]string{}
+
+	reg := MustNewRegistry[map[string]string](fs)
+	lenient := reg.MustGet("labels", WithDelimsOnce("[[", "]]"))
+
+	strict, err := lenient.WithOption("missingkey=error")
+	require.NoError(t, err)
+
+	_, err = strict.ExecuteToString(context.Background(), data)
+	require.ErrorAs(t, err, new(ErrTemplateExecution))
+	require.ErrorContains(t, err, `map has no entry for key "color"`)
+
+	got, err := lenient.ExecuteToString(context.Background(), data)
+	require.NoError(t, err, "the original handler keeps its options")
+	assert.Equal(t, "<b></b>", got)
+
+	t.Run("overrides registry options", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithStrictMode[map[string]string]())
+		zero, err := reg.MustGet("labels", WithDelimsOnce("[[", "]]")).WithOption("missingkey=zero")
+		require.NoError(t, err)
+
+		got, err := zero.ExecuteToString(context.Background(), data)
+		require.NoError(t, err)
+		assert.Equal(t, "<b></b>", got)
+	})
+
+	t.Run("rejects unknown options", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := lenient.WithOption("missingkey=maybe")
+		require.ErrorAs(t, err, new(ErrInvalidOption))
+		require.ErrorContains(t, err, "missingkey=maybe")
+	})
+}
@@ -2,16 +2,11 @@ package templator
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
-	"regexp"
+	"slices"
 	"strings"
-)
-
-var (
-	// matches {{.FieldName}} and {{ .FieldName }} patterns
-	fieldPattern = regexp.MustCompile(`{{\s*\.([a-zA-Z][a-zA-Z0-9._]*)\s*}}`)
-	// matches {{if .FieldName}} patterns
-	ifPattern = regexp.MustCompile(`{{\s*if\s+\.([a-zA-Z][a-zA-Z0-9._]*)\s*}}`)
+	"text/template/parse"
 )
 
 type ValidationError struct {
@@ -24,86 +19,461 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("template '%s' validation error: '%s' - '%s'", e.TemplateName, e.FieldPath, e.Err)
 }
 
-// validateTemplateFields analyzes template content and validates
-// that all referenced fields exist in the data type
-func validateTemplateFields[T any](name, content string, dataType T) error {
-	typ := reflect.TypeOf(dataType)
-	fields := extractTemplateFields(content)
+// validateTemplateFields parses the template content and validates that all
+// referenced fields exist in the data type. Fields accessed inside range and
+// with blocks, or through variables, are validated against the type they refer to.
+func validateTemplateFields[T any](name, content, leftDelim, rightDelim string, dataType T) error {
+	return validateFieldsOfType(name, content, leftDelim, rightDelim, reflect.TypeOf(dataType))
+}
 
-	for _, field := range fields {
-		if err := validateField(typ, field); err != nil {
-			return &ValidationError{
-				TemplateName: name,
-				FieldPath:    field,
-				Err:          err,
-			}
+// validateFieldsOfType is validateTemplateFields for a data type known only at run time.
+// A nil root disables validation.
+//
+// The bodies of {{define}} and {{block}} are validated against the type of the data their
+// {{template}} call passes, or against root when the content doesn't call them.
+func validateFieldsOfType(name, content, leftDelim, rightDelim string, root reflect.Type) error {
+	trees, err := parseTree(name, content, leftDelim, rightDelim)
+	if err != nil {
+		return err
+	}
+
+	v := fieldValidator{name: name, calls: make(map[string]reflect.Type)}
+	validate := func(tree *parse.Tree, dot reflect.Type) error {
+		return v.walk(tree.Root, scope{
+			dot:  dot,
+			vars: map[string]reflect.Type{"$": dot},
+		})
+	}
+	if err := validate(trees[name], root); err != nil {
+		return err
+	}
+
+	// Templates are validated once their callers are, so the types they are called with
+	// are known; the remaining ones are not called by the content.
+	pending := slices.Sorted(maps.Keys(trees))
+	pending = slices.DeleteFunc(pending, func(n string) bool { return n == name })
+	for len(pending) > 0 {
+		i := slices.IndexFunc(pending, func(n string) bool {
+			_, called := v.calls[n]
+			return called
+		})
+		dot := root
+		if i < 0 {
+			i = 0
+		} else {
+			dot = v.calls[pending[i]]
+		}
+
+		defined := pending[i]
+		pending = slices.Delete(pending, i, i+1)
+		if err := validate(trees[defined], dot); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// extractTemplateFields returns a list of field paths used in the template
-func extractTemplateFields(content string) []string {
-	var fields []string
-	fieldMatches := fieldPattern.FindAllStringSubmatch(content, -1)
-	ifMatches := ifPattern.FindAllStringSubmatch(content, -1)
+// parseTree parses the template content without checking that functions are defined,
+// so validation can report on them itself. It returns the trees of the content, by name,
+// including those of its {{define}} and {{block}} actions.
+func parseTree(name, content, leftDelim, rightDelim string) (map[string]*parse.Tree, error) {
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	trees := make(map[string]*parse.Tree)
+	if _, err := tree.Parse(expandEditable(content, leftDelim), leftDelim, rightDelim, trees); err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+// scope tracks the type of dot and of declared variables at a point in the template.
+// A nil type means the type cannot be determined statically and is not validated.
+type scope struct {
+	dot  reflect.Type
+	vars map[string]reflect.Type
+}
 
-	// Add direct field references
-	for _, match := range fieldMatches {
-		if len(match) > 1 {
-			fields = append(fields, match[1])
+func (s scope) with(dot reflect.Type) scope {
+	return scope{dot: dot, vars: maps.Clone(s.vars)}
+}
+
+type fieldValidator struct {
+	name string
+	// calls records the type of the data passed to each template the content calls, by the
+	// first call validated. A nil type is not validated.
+	calls map[string]reflect.Type
+}
+
+func (v fieldValidator) walk(node parse.Node, s scope) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := v.walk(child, s); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		typ, err := v.pipe(n.Pipe, s)
+		if err != nil {
+			return err
 		}
+		declare(s, n.Pipe, typ)
+	case *parse.IfNode:
+		return v.branch(&n.BranchNode, s, func(typ reflect.Type) reflect.Type { return s.dot })
+	case *parse.WithNode:
+		return v.branch(&n.BranchNode, s, func(typ reflect.Type) reflect.Type { return typ })
+	case *parse.RangeNode:
+		typ, err := v.pipe(n.Pipe, s)
+		if err != nil {
+			return err
+		}
+
+		key, elem := rangeTypes(typ, len(n.Pipe.Decl))
+		inner := s.with(elem)
+		switch len(n.Pipe.Decl) {
+		case 1:
+			inner.vars[n.Pipe.Decl[0].Ident[0]] = elem
+		case 2:
+			inner.vars[n.Pipe.Decl[0].Ident[0]] = key
+			inner.vars[n.Pipe.Decl[1].Ident[0]] = elem
+		}
+
+		if err := v.walk(n.List, inner); err != nil {
+			return err
+		}
+		return v.walk(n.ElseList, s.with(s.dot))
+	case *parse.TemplateNode:
+		var typ reflect.Type
+		if n.Pipe != nil {
+			var err error
+			if typ, err = v.pipe(n.Pipe, s); err != nil {
+				return err
+			}
+		}
+		if _, ok := v.calls[n.Name]; !ok {
+			v.calls[n.Name] = typ
+		}
+	}
+	return nil
+}
+
+// branch validates an if or with block. dotFor maps the type of the block's
+// pipeline to the type of dot inside the block.
+func (v fieldValidator) branch(n *parse.BranchNode, s scope, dotFor func(reflect.Type) reflect.Type) error {
+	typ, err := v.pipe(n.Pipe, s)
+	if err != nil {
+		return err
+	}
+
+	inner := s.with(dotFor(typ))
+	declare(inner, n.Pipe, typ)
+
+	if err := v.walk(n.List, inner); err != nil {
+		return err
 	}
+	return v.walk(n.ElseList, s.with(s.dot))
+}
 
-	// Add fields from if statements
-	for _, match := range ifMatches {
-		if len(match) > 1 {
-			fields = append(fields, match[1])
+// pipe validates every field referenced in the pipeline and returns the pipeline's
+// type when it is a single field, variable or dot reference.
+func (v fieldValidator) pipe(pipe *parse.PipeNode, s scope) (reflect.Type, error) {
+	var typ reflect.Type
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			argType, err := v.arg(arg, s)
+			if err != nil {
+				return nil, err
+			}
+			typ = argType
 		}
 	}
-	return uniqueFields(fields)
+
+	if len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return nil, nil
+	}
+	return typ, nil
 }
 
-// uniqueFields removes duplicate field names
-func uniqueFields(fields []string) []string {
-	seen := make(map[string]struct{})
-	unique := make([]string, 0, len(fields))
+func (v fieldValidator) arg(node parse.Node, s scope) (reflect.Type, error) {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return s.dot, nil
+	case *parse.FieldNode:
+		if s.dot == nil {
+			return nil, nil
+		}
+		return v.resolve(s.dot, n.Ident, strings.Join(n.Ident, "."))
+	case *parse.VariableNode:
+		typ := s.vars[n.Ident[0]]
+		if typ == nil || len(n.Ident) == 1 {
+			return typ, nil
+		}
+		return v.resolve(typ, n.Ident[1:], strings.Join(n.Ident, "."))
+	case *parse.PipeNode:
+		return v.pipe(n, s)
+	case *parse.ChainNode:
+		_, err := v.arg(n.Node, s)
+		return nil, err
+	}
+	return nil, nil
+}
 
-	for _, field := range fields {
-		if _, ok := seen[field]; !ok {
-			seen[field] = struct{}{}
-			unique = append(unique, field)
+func (v fieldValidator) resolve(typ reflect.Type, ident []string, fieldPath string) (reflect.Type, error) {
+	resolved, err := resolveField(typ, strings.Join(ident, "."))
+	if err != nil {
+		return nil, &ValidationError{
+			TemplateName: v.name,
+			FieldPath:    fieldPath,
+			Err:          err,
 		}
 	}
-	return unique
+	return resolved, nil
 }
 
-// validateField checks if a field path exists in the given type
-func validateField(typ reflect.Type, fieldPath string) error {
+// declare records the variables declared by a pipeline, e.g. {{$title := .Title}}.
+func declare(s scope, pipe *parse.PipeNode, typ reflect.Type) {
+	for _, decl := range pipe.Decl {
+		s.vars[decl.Ident[0]] = typ
+	}
+}
+
+// rangeTypes returns the key and element types produced by ranging over typ with decls
+// declared variables. Ranging over an iter.Seq2 with fewer than two variables yields its keys.
+func rangeTypes(typ reflect.Type, decls int) (reflect.Type, reflect.Type) {
 	if typ == nil {
-		return fmt.Errorf("nil type")
+		return nil, nil
 	}
 
 	if typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
 	}
 
-	if typ.Kind() != reflect.Struct {
-		return fmt.Errorf("expected struct type, got %v", typ.Kind())
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeFor[int](), typ.Elem()
+	case reflect.Map:
+		return typ.Key(), typ.Elem()
+	case reflect.Chan:
+		return nil, typ.Elem()
+	case reflect.Func:
+		if typ.CanSeq2() {
+			yield := typ.In(0)
+			if decls < 2 {
+				return nil, yield.In(0)
+			}
+			return yield.In(0), yield.In(1)
+		}
+		if typ.CanSeq() && typ.In(0).NumIn() == 1 {
+			return nil, typ.In(0).In(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return typ, typ
+	}
+	return nil, nil
+}
+
+// validateField checks if a field path exists in the given type
+func validateField(typ reflect.Type, fieldPath string) error {
+	_, err := resolveField(typ, fieldPath)
+	return err
+}
+
+// resolveField walks a dotted field path through the given type and returns the
+// type it resolves to. Struct fields and methods are checked, map keys are accepted
+// as is. Paths through interface types cannot be resolved statically and yield a nil type.
+func resolveField(typ reflect.Type, fieldPath string) (reflect.Type, error) {
+	if typ == nil {
+		return nil, fmt.Errorf("nil type")
 	}
 
-	parts := strings.Split(fieldPath, ".")
 	current := typ
+	for _, part := range strings.Split(fieldPath, ".") {
+		if current.Kind() == reflect.Interface {
+			return nil, nil
+		}
 
-	for _, part := range parts {
-		field, found := current.FieldByName(part)
-		if !found {
-			return fmt.Errorf("field '%s' not found in type %s", part, current.Name())
+		if method, ok := methodByName(current, part); ok {
+			if method.Type.NumOut() == 0 {
+				return nil, fmt.Errorf("method '%s' of type %s returns no value", part, current)
+			}
+			current = method.Type.Out(0)
+			continue
 		}
-		current = field.Type
+
 		if current.Kind() == reflect.Ptr {
 			current = current.Elem()
 		}
+
+		switch current.Kind() {
+		case reflect.Struct:
+			field, found := current.FieldByName(part)
+			if !found {
+				return nil, fmt.Errorf("field '%s' not found in type %s", part, current)
+			}
+			current = field.Type
+		case reflect.Map:
+			current = current.Elem()
+		case reflect.Interface:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("expected struct type, got %v", current.Kind())
+		}
+	}
+
+	if current.Kind() == reflect.Ptr {
+		current = current.Elem()
+	}
+	return current, nil
+}
+
+// methodByName looks up an exported method on the type or on a pointer to it.
+func methodByName(typ reflect.Type, name string) (reflect.Method, bool) {
+	if typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface {
+		typ = reflect.PointerTo(typ)
+	}
+	return typ.MethodByName(name)
+}
+
+// FuncValidationError is returned when a template calls a function that is not
+// registered or calls it with an incompatible number of arguments.
+type FuncValidationError struct {
+	TemplateName string
+	FuncName     string
+	Err          error
+}
+
+func (e *FuncValidationError) Error() string {
+	return fmt.Sprintf("template '%s' function validation error: '%s' - '%s'", e.TemplateName, e.FuncName, e.Err)
+}
+
+func (e *FuncValidationError) Unwrap() error {
+	return e.Err
+}
+
+// builtinFuncs lists the functions predefined by text/template.
+var builtinFuncs = map[string]struct{}{
+	"and": {}, "call": {}, "html": {}, "index": {}, "slice": {}, "js": {}, "len": {}, "not": {},
+	"or": {}, "print": {}, "printf": {}, "println": {}, "urlquery": {},
+	"eq": {}, "ge": {}, "gt": {}, "le": {}, "lt": {}, "ne": {},
+}
+
+var errorType = reflect.TypeFor[error]()
+
+// validateTemplateFuncs parses the template content and validates that every function
+// called in a pipeline is either built in or present in funcMap, and that its argument
+// and return counts are compatible with the call.
+func validateTemplateFuncs(name, content, leftDelim, rightDelim string, funcMap map[string]any) error {
+	trees, err := parseTree(name, content, leftDelim, rightDelim)
+	if err != nil {
+		return err
+	}
+
+	for _, defined := range slices.Sorted(maps.Keys(trees)) {
+		err := walkPipes(trees[defined].Root, func(pipe *parse.PipeNode) error {
+			for i, cmd := range pipe.Cmds {
+				if err := validateCall(name, cmd, i > 0, funcMap); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkPipes calls fn for every pipeline in the tree, including parenthesized ones.
+func walkPipes(node parse.Node, fn func(*parse.PipeNode) error) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := walkPipes(child, fn); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return walkPipes(n.Pipe, fn)
+	case *parse.IfNode:
+		return walkBranchPipes(&n.BranchNode, fn)
+	case *parse.WithNode:
+		return walkBranchPipes(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		return walkBranchPipes(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			return walkPipes(n.Pipe, fn)
+		}
+	case *parse.PipeNode:
+		if err := fn(n); err != nil {
+			return err
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				if err := walkPipes(arg, fn); err != nil {
+					return err
+				}
+			}
+		}
+	case *parse.ChainNode:
+		return walkPipes(n.Node, fn)
+	}
+	return nil
+}
+
+func walkBranchPipes(n *parse.BranchNode, fn func(*parse.PipeNode) error) error {
+	for _, child := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+		if err := walkPipes(child, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCall checks a single pipeline command. piped reports whether the command
+// receives the result of the previous command as its final argument.
+func validateCall(name string, cmd *parse.CommandNode, piped bool, funcMap map[string]any) error {
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return nil
+	}
+
+	funcErr := func(err error) error {
+		return &FuncValidationError{TemplateName: name, FuncName: ident.Ident, Err: err}
+	}
+
+	fn, ok := funcMap[ident.Ident]
+	if !ok {
+		if _, builtin := builtinFuncs[ident.Ident]; builtin {
+			return nil
+		}
+		return funcErr(fmt.Errorf("function not defined"))
+	}
+
+	if err := validateFunc(fn); err != nil {
+		return funcErr(err)
+	}
+
+	typ := reflect.TypeOf(fn)
+	args := len(cmd.Args) - 1
+	if piped {
+		args++
+	}
+
+	if typ.IsVariadic() {
+		if args < typ.NumIn()-1 {
+			return funcErr(fmt.Errorf("expects at least %d arguments, got %d", typ.NumIn()-1, args))
+		}
+	} else if args != typ.NumIn() {
+		return funcErr(fmt.Errorf("expects %d arguments, got %d", typ.NumIn(), args))
 	}
 	return nil
 }
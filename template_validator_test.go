@@ -2,10 +2,13 @@ package templator
 
 import (
 	"errors"
+	"iter"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestValidator_Error(t *testing.T) {
@@ -22,33 +25,100 @@ func TestValidator_Error(t *testing.T) {
 	assert.Equal(t, "template 'dummy-template-name' validation error: 'dummy-file-path' - 'dummy-error'", got)
 }
 
-func TestUniqueFields(t *testing.T) {
+type validatorItem struct {
+	Name string
+	Tags []string
+}
+
+func (i validatorItem) Label() string { return i.Name }
+
+type validatorData struct {
+	Title   string
+	Items   []validatorItem
+	ByName  map[string]*validatorItem
+	Counts  map[string]int
+	Extra   any
+	Current *validatorItem
+	Seq     iter.Seq[validatorItem]
+	Pairs   iter.Seq2[string, *validatorItem]
+}
+
+func TestValidateTemplateFields(t *testing.T) {
 	t.Parallel()
 
 	testCases := []struct {
-		name        string
-		givenFields []string
-		expect      []string
+		name      string
+		content   string
+		wantField string
 	}{
-		{
-			name:        "unique fields",
-			givenFields: []string{"foo", "bar"},
-			expect:      []string{"foo", "bar"},
-		},
-		{
-			name:        "duplicated fields",
-			givenFields: []string{"foo", "bar", "foo"},
-			expect:      []string{"foo", "bar"},
-		},
+		{name: "root field", content: "{{.Title}}"},
+		{name: "unknown root field", content: "{{.Missing}}", wantField: "Missing"},
+		{name: "range over slice", content: "{{range .Items}}{{.Name}}{{end}}"},
+		{name: "unknown field in range", content: "{{range .Items}}{{.Nam}}{{end}}", wantField: "Nam"},
+		{name: "range else uses outer dot", content: "{{range .Items}}{{.Name}}{{else}}{{.Title}}{{end}}"},
+		{name: "range variables", content: "{{range $index, $element := .Items}}{{$index}}{{$element.Name}}{{end}}"},
+		{name: "unknown field on range variable", content: "{{range $i, $e := .Items}}{{$e.Title}}{{end}}", wantField: "$e.Title"},
+		{name: "single range variable is element", content: "{{range $e := .Items}}{{$e.Name}}{{end}}"},
+		{name: "root variable inside range", content: "{{range .Items}}{{$.Title}}{{end}}"},
+		{name: "unknown root variable field", content: "{{range .Items}}{{$.Name}}{{end}}", wantField: "$.Name"},
+		{name: "nested range", content: "{{range .Items}}{{range .Tags}}{{.}}{{end}}{{end}}"},
+		{name: "range over map of pointers", content: "{{range $k, $v := .ByName}}{{$k}}{{$v.Name}}{{end}}"},
+		{name: "range over iter.Seq", content: "{{range .Seq}}{{.Name}}{{end}}"},
+		{name: "unknown field in iter.Seq range", content: "{{range .Seq}}{{.Nam}}{{end}}", wantField: "Nam"},
+		{name: "range over iter.Seq2", content: "{{range $k, $v := .Pairs}}{{$k}}{{$v.Name}}{{end}}"},
+		{name: "unknown field on iter.Seq2 value", content: "{{range $k, $v := .Pairs}}{{$v.Title}}{{end}}", wantField: "$v.Title"},
+		{name: "single variable over iter.Seq2 is key", content: "{{range $k := .Pairs}}{{$k.Name}}{{end}}", wantField: "$k.Name"},
+		{name: "map key access", content: "{{.ByName.anything.Name}}"},
+		{name: "unknown field through map", content: "{{.ByName.anything.Nope}}", wantField: "ByName.anything.Nope"},
+		{name: "with narrows dot", content: "{{with .Current}}{{.Name}}{{end}}"},
+		{name: "unknown field in with", content: "{{with .Current}}{{.Title}}{{end}}", wantField: "Title"},
+		{name: "declared variable", content: "{{$item := .Current}}{{$item.Name}}"},
+		{name: "method call", content: "{{range .Items}}{{.Label}}{{end}}"},
+		{name: "interface fields are not validated", content: "{{.Extra.Anything}}"},
+		{name: "function arguments", content: "{{if eq .Title \"x\"}}{{len .Items}}{{end}}"},
+		{name: "unknown field in function argument", content: "{{printf \"%s\" .Nope}}", wantField: "Nope"},
+		{name: "unknown field in if", content: "{{if .Nope}}{{end}}", wantField: "Nope"},
+		{name: "define body", content: `{{define "row"}}{{.Title}}{{end}}`},
+		{name: "unknown field in define body", content: `{{define "row"}}{{.Missing}}{{end}}`, wantField: "Missing"},
+		{name: "unknown field in block body", content: `{{block "content" .}}{{.Missing}}{{end}}`, wantField: "Missing"},
+		{name: "define body gets the type it is called with", content: `{{define "item"}}{{.Name}}{{end}}{{range .Items}}{{template "item" .}}{{end}}`},
+		{name: "unknown field in define called with an element", content: `{{define "item"}}{{.Title}}{{end}}{{template "item" .Current}}`, wantField: "Title"},
+		{name: "define called by define", content: `{{define "list"}}{{range .}}{{template "item" .}}{{end}}{{end}}{{define "item"}}{{.Nam}}{{end}}{{template "list" .Items}}`, wantField: "Nam"},
+		{name: "define called with an unknown type", content: `{{define "any"}}{{.Anything}}{{end}}{{template "any" .Extra}}`},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			got := uniqueFields(tc.givenFields)
+			t.Parallel()
 
-			assert.ElementsMatch(t, tc.expect, got)
+			err := validateTemplateFields("test", tc.content, "", "", validatorData{})
+			if tc.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tc.wantField, validationErr.FieldPath)
 		})
 	}
+
+	t.Run("custom delimiters", func(t *testing.T) {
+		t.Parallel()
+
+		err := validateTemplateFields("test", "[[range .Items]][[.Nope]][[end]]", "[[", "]]", validatorData{})
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "Nope", validationErr.FieldPath)
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		t.Parallel()
+
+		err := validateTemplateFields("test", "{{range .Items}}", "", "", validatorData{})
+		require.Error(t, err)
+	})
 }
 
 func Test_validateField(t *testing.T) {
@@ -104,3 +174,71 @@ func Test_validateField(t *testing.T) {
 		})
 	}
 }
+
+func TestFuncValidationError_Error(t *testing.T) {
+	t.Parallel()
+
+	funcErr := FuncValidationError{
+		TemplateName: "dummy-template-name",
+		FuncName:     "dummy-func",
+		Err:          errors.New("dummy-error"),
+	}
+
+	got := funcErr.Error()
+
+	assert.Equal(t, "template 'dummy-template-name' function validation error: 'dummy-func' - 'dummy-error'", got)
+}
+
+func TestValidateTemplateFuncs(t *testing.T) {
+	t.Parallel()
+
+	funcMap := map[string]any{
+		"upper":    strings.ToUpper,
+		"join":     strings.Join,
+		"concat":   func(parts ...string) string { return strings.Join(parts, "") },
+		"safe":     func(s string) (string, error) { return s, nil },
+		"nothing":  func(string) {},
+		"notAFunc": "value",
+	}
+
+	testCases := []struct {
+		name     string
+		content  string
+		wantFunc string
+	}{
+		{name: "no functions", content: "{{.Title}}"},
+		{name: "piped call", content: "{{.Title | upper}}"},
+		{name: "direct call", content: "{{upper .Title}}"},
+		{name: "builtin", content: "{{printf \"%s\" .Title | upper}}"},
+		{name: "two arguments", content: "{{join .Items \",\"}}"},
+		{name: "variadic", content: "{{concat}}{{concat .A .B .C}}"},
+		{name: "value and error", content: "{{safe .Title}}"},
+		{name: "call in range pipeline", content: "{{range .Items}}{{. | upper}}{{end}}"},
+		{name: "call in parenthesized pipeline", content: "{{if eq (upper .Title) \"X\"}}{{end}}"},
+		{name: "unknown function", content: "{{.Title | lower}}", wantFunc: "lower"},
+		{name: "unknown function in with", content: "{{with .Title}}{{lower .}}{{end}}", wantFunc: "lower"},
+		{name: "too many arguments", content: "{{upper .Title .Content}}", wantFunc: "upper"},
+		{name: "too many arguments with pipe", content: "{{.Content | upper .Title}}", wantFunc: "upper"},
+		{name: "too few arguments", content: "{{join .Items}}", wantFunc: "join"},
+		{name: "no return value", content: "{{nothing .Title}}", wantFunc: "nothing"},
+		{name: "not a function", content: "{{notAFunc}}", wantFunc: "notAFunc"},
+		{name: "unknown function in define body", content: `{{define "row"}}{{lower .}}{{end}}`, wantFunc: "lower"},
+		{name: "unknown function in block body", content: `{{block "content" .}}{{lower .Title}}{{end}}`, wantFunc: "lower"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateTemplateFuncs("test", tc.content, "", "", funcMap)
+			if tc.wantFunc == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var funcErr *FuncValidationError
+			require.ErrorAs(t, err, &funcErr)
+			assert.Equal(t, tc.wantFunc, funcErr.FuncName)
+		})
+	}
+}
@@ -6,12 +6,24 @@ package templator
 //go:generate go run ./cmd/generate/generate_methods.go
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"html/template"
 	"io"
 	"io/fs"
+	"log/slog"
+	"maps"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -31,11 +43,38 @@ type Extension string
 type Option[T any] func(*Registry[T])
 
 // WithTemplatesPath returns an Option that sets a custom template directory path.
-// If an empty path is provided, the default path will be used.
-func WithTemplatesPath[T any](path string) Option[T] {
+// If an empty path is provided, the default path will be used. The path is
+// interpreted as an fs.FS path: backslashes are converted to forward slashes
+// and the result is cleaned, so OS-style paths work on every platform.
+func WithTemplatesPath[T any](dir string) Option[T] {
 	return func(r *Registry[T]) {
-		if path != "" {
-			r.config.path = path
+		if dir != "" {
+			r.config.path = fsPath(dir)
+			r.config.paths = nil
+		}
+	}
+}
+
+// WithTemplatePaths returns an Option that resolves templates across several directories
+// in priority order: templates and partials are read from the first directory holding them,
+// so application templates can override those of a shared library. ListTemplates merges
+// the directories. Like WithTemplatesPath, it replaces any previously configured path, and
+// empty paths are ignored.
+func WithTemplatePaths[T any](dirs ...string) Option[T] {
+	return func(r *Registry[T]) {
+		var paths []string
+		for _, dir := range dirs {
+			if dir != "" {
+				paths = append(paths, fsPath(dir))
+			}
+		}
+
+		switch len(paths) {
+		case 0:
+		case 1:
+			r.config.path, r.config.paths = paths[0], nil
+		default:
+			r.config.path, r.config.paths = paths[0], paths
 		}
 	}
 }
@@ -48,54 +87,294 @@ func WithFieldValidation[T any](model T) Option[T] {
 	}
 }
 
+// WithFuncValidation enables validation of template function calls against the
+// registered functions, checking that each function exists and is called with a
+// compatible number of arguments.
+func WithFuncValidation[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.validateFuncs = true
+	}
+}
+
+// WithExtension returns an Option that sets the template file extension, e.g. ".tmpl".
+// If an empty extension is provided, ExtensionHTML will be used.
+func WithExtension[T any](ext Extension) Option[T] {
+	return func(r *Registry[T]) {
+		if ext != "" {
+			r.config.ext = ext
+		}
+	}
+}
+
+// WithDelims returns an Option that sets the action delimiters of every template and partial,
+// e.g. "[[" and "]]" for templates also processed by front-end frameworks using "{{ }}".
+// Empty delimiters fall back to the defaults, "{{" and "}}". WithDelimsOnce takes precedence.
+func WithDelims[T any](left, right string) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.leftDelim = left
+		r.config.rightDelim = right
+	}
+}
+
+// WithPartials returns an Option that sets partial templates parsed alongside every template.
+// Partials are referenced by name, like Get, and patterns may use path.Match syntax,
+// e.g. "components/*". Templates invoke them by file name: {{template "components/menu.html" .}}.
+func WithPartials[T any](patterns ...string) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.partials = append(r.config.partials, patterns...)
+	}
+}
+
+// WithHotReload returns an Option that re-reads and re-parses templates on every Get,
+// so edits on disk show up without a restart. Intended for development only; NewRegistry
+// rejects it together with cache policies setting a max age.
+func WithHotReload[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.hotReload = true
+	}
+}
+
+// WithOverlayFS returns an Option that layers fsys over the registry filesystem. Templates
+// and partials are looked up in overlays first, the most recently added one winning, and fall
+// back to the base filesystem, so a theme or tenant only needs to ship the templates it changes.
+func WithOverlayFS[T any](fsys fs.FS) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.overlays = append(r.config.overlays, fsys)
+	}
+}
+
+// WithTemplateFuncs returns an Option that registers template functions. Functions are
+// installed with template.New(name).Funcs(...) before parsing, so they can be referenced
+// anywhere in templates and partials. Multiple calls are merged, later ones taking precedence.
 func WithTemplateFuncs[T any](funcMap template.FuncMap) Option[T] {
 	return func(r *Registry[T]) {
-		r.config.funcMap = funcMap
+		if r.config.funcMap == nil {
+			r.config.funcMap = funcMap
+			return
+		}
+
+		merged := maps.Clone(r.config.funcMap)
+		maps.Copy(merged, funcMap)
+		r.config.funcMap = merged
 	}
 }
 
-type config[T any] struct {
-	path            string
-	validateFields  bool
-	validationModel T
+// GetOption overrides registry defaults for a single call to Get.
+type GetOption func(*getConfig)
+
+// WithDelimsOnce returns a GetOption that sets custom action delimiters for a single handler.
+// Empty delimiters fall back to the defaults, "{{" and "}}".
+func WithDelimsOnce(left, right string) GetOption {
+	return func(c *getConfig) {
+		c.leftDelim = left
+		c.rightDelim = right
+	}
+}
+
+// WithFuncsOnce returns a GetOption that adds template functions for a single handler.
+// Functions with the same name as registry functions take precedence.
+func WithFuncsOnce(funcMap template.FuncMap) GetOption {
+	return func(c *getConfig) {
+		c.funcMap = funcMap
+	}
+}
+
+type getConfig struct {
+	leftDelim       string
+	rightDelim      string
 	funcMap         template.FuncMap
+	editableMarkers bool
+	options         []string
+	// source describes content parsed in place of the live source, such as a draft, whose
+	// frontmatter and source map are not recorded by the registry.
+	source *sourceInfo
+}
+
+type config[T any] struct {
+	path             string
+	ext              Extension
+	validateFields   bool
+	validateFuncs    bool
+	validationModel  T
+	funcMap          template.FuncMap
+	funcPacks        []FuncPack
+	capabilities     []string
+	leftDelim        string
+	rightDelim       string
+	cachePolicies    map[string]CachePolicy
+	fragmentPolicies map[string]FragmentPolicy
+	lazyPolicies     map[string]LazyPolicy
+	partials         []string
+	overlays         []fs.FS
+	paths            []string
+	locales          []string
+	translator       Translator
+	direction        bool
+	localeFormats    map[string]LocaleFormat
+	slos             map[string]time.Duration
+	onSlowRender     func(SlowRender)
+	draftValidators  []func(name, content string) error
+	hooks            []executionHooks[T]
+	contextData      []func(ctx context.Context, data T) T
+	preprocessors    []Preprocessor
+	macros           bool
+	metrics          Metrics
+	logger           *slog.Logger
+	minify           bool
+	trace            bool
+	normalize        bool
+	archive          *archiver[T]
+	chaos            ChaosPolicy
+	quota            *quotas
+	strict           bool
+	errorTemplate    string
+	unbufferedWrites bool
+	strictInit       bool
+	hotReload        bool
+	optionErrs       []error
 }
 
 // Registry manages template handlers in a concurrent-safe manner.
 type Registry[T any] struct {
-	fs        fs.FS
-	config    config[T]
-	mu        sync.RWMutex
-	templates map[string]*Handler[T]
+	fs          fs.FS
+	group       *RegistryGroup
+	config      config[T]
+	mu          sync.RWMutex
+	templates   map[string]*Handler[T]
+	localized   map[string]string
+	metadata    map[string]Metadata
+	mounts      map[string]*Registry[T]
+	slos        map[string]*sloTracker
+	drafts      drafts
+	processed   preprocessed
+	sourceMaps  sourceMaps
+	frontmatter frontmatters
+	partials    *parsedPartials
+	closed      atomic.Bool
+	closers     []func(context.Context) error
+	sweeper     cacheSweeper
+
+	deprecationsWarned sync.Map
+
+	funcNamesOnce sync.Once
+	funcNamesKey  string
 }
 
 // Handler manages a specific template instance with type-safe data handling.
 // It provides methods for template execution and customization.
 type Handler[T any] struct {
-	tmpl *template.Template
-	reg  *Registry[T]
+	name  string
+	file  string
+	src   *source
+	reg   *Registry[T]
+	cache *renderCache
+	keyFn func(T) string
+
+	translations *translations
+	overrides    getConfig
 }
 
 // NewRegistry creates a new template registry with the provided filesystem and options.
 // It accepts a filesystem interface and variadic options for customization.
+// Returns an error joining every ErrInvalidOption when options are invalid or conflict,
+// and ErrNilFS when fsys is nil.
 func NewRegistry[T any](fsys fs.FS, opts ...Option[T]) (*Registry[T], error) {
+	if fsys == nil {
+		return nil, ErrNilFS
+	}
+
 	reg := &Registry[T]{
 		fs: fsys,
 		config: config[T]{
 			path: DefaultTemplateDir,
+			ext:  ExtensionHTML,
 		},
 		templates: make(map[string]*Handler[T]),
+		localized: make(map[string]string),
+		metadata:  make(map[string]Metadata),
+		partials:  new(parsedPartials),
 	}
 	for _, opt := range opts {
 		opt(reg)
 	}
+
+	if err := reg.config.validate(); err != nil {
+		return nil, err
+	}
+
+	if len(reg.config.overlays) > 0 {
+		reg.fs = newOverlayFS(fsys, reg.config.overlays)
+	}
+	base := reg.fs
+	if len(reg.config.paths) > 0 {
+		reg.fs = newRootsFS(base, reg.config.paths)
+		reg.config.path = "."
+	}
+
+	if reg.config.strictInit {
+		if err := reg.checkTemplateDir(base); err != nil {
+			return nil, err
+		}
+	}
+
+	reg.slos = make(map[string]*sloTracker, len(reg.config.slos))
+	for name, budget := range reg.config.slos {
+		reg.slos[name] = newSLOTracker(budget)
+	}
 	return reg, nil
 }
 
+// MustNewRegistry is like NewRegistry but panics if the options are invalid.
+// It simplifies safe initialization of package-level variables and setup in main.
+func MustNewRegistry[T any](fsys fs.FS, opts ...Option[T]) *Registry[T] {
+	reg, err := NewRegistry(fsys, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return reg
+}
+
 // Get retrieves or creates a type-safe handler for a specific template.
-// It automatically appends the .html extension to the template name.
-// Returns an error if the template cannot be parsed.
-func (r *Registry[T]) Get(name string) (*Handler[T], error) {
+// The name is relative to the template directory and excludes the configured extension,
+// .html unless set with WithExtension, which is appended to it. Backslashes are treated as
+// slashes and the name is cleaned, so "pages\home" and "pages/./home" name the same file.
+// Returns ErrInvalidTemplateName for empty, absolute or escaping names,
+// ErrTemplateNotFound if the template or one of its partials is missing,
+// and ErrTemplateParse if it cannot be parsed.
+//
+// GetOptions override the registry defaults for the returned handler only.
+// Handlers created with options, or by a registry with hot reload enabled,
+// are parsed on every call and never cached, so shared registry state is left untouched.
+//
+// The first Get of a template using a Deprecation logs a warning to the WithLogger logger.
+func (r *Registry[T]) Get(name string, opts ...GetOption) (*Handler[T], error) {
+	if err := r.validateName(name); err != nil {
+		return nil, err
+	}
+	if sub, rest, ok := r.mounted(name); ok {
+		return sub.Get(rest, opts...)
+	}
+
+	h, err := r.get(name, opts...)
+	if err == nil && r.config.logger != nil {
+		r.warnDeprecated(h.src.deprecationsFrom(h.file))
+	}
+	return h, err
+}
+
+func (r *Registry[T]) get(name string, opts ...GetOption) (*Handler[T], error) {
+	if r.closed.Load() {
+		return nil, ErrRegistryClosed
+	}
+
+	if len(opts) > 0 || r.config.hotReload {
+		var overrides getConfig
+		for _, opt := range opts {
+			opt(&overrides)
+		}
+		return r.parse(name, overrides)
+	}
+
 	r.mu.RLock()
 	if h, ok := r.templates[name]; ok {
 		r.mu.RUnlock()
@@ -111,55 +390,561 @@ func (r *Registry[T]) Get(name string) (*Handler[T], error) {
 		return h, nil
 	}
 
+	handler, err := r.parse(name, getConfig{})
+	if err != nil {
+		return nil, err
+	}
+
+	r.templates[name] = handler
+	return handler, nil
+}
+
+// MustGet is like Get but panics if the template cannot be loaded.
+// It is intended for templates that must exist at startup.
+func (r *Registry[T]) MustGet(name string, opts ...GetOption) *Handler[T] {
+	h, err := r.Get(name, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// parse reads, validates and parses the named template into a new handler,
+// applying the given overrides on top of the registry configuration.
+func (r *Registry[T]) parse(name string, overrides getConfig) (*Handler[T], error) {
+	r.config.chaos.delayParse()
+
 	// Read template content first
-	content, err := fs.ReadFile(r.fs, r.config.path+"/"+name+".html")
+	content, err := r.readTemplate(name)
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate fields if enabled - validate content before parsing
-	if r.config.validateFields {
-		if err := validateTemplateFields(name, string(content), r.config.validationModel); err != nil {
-			return nil, err
+	h, err := r.parseContent(name, content, overrides)
+	r.logLoad(name, err)
+	if err != nil {
+		r.observeParseFailure(name)
+		return nil, err
+	}
+	return h, nil
+}
+
+// parseContent validates and parses content as the named template into a new handler.
+func (r *Registry[T]) parseContent(name string, content []byte, overrides getConfig) (*Handler[T], error) {
+	hashes := map[string]uint64{name: hashSource(content)}
+	overrides.leftDelim = cmp.Or(overrides.leftDelim, r.config.leftDelim)
+	overrides.rightDelim = cmp.Or(overrides.rightDelim, r.config.rightDelim)
+	content = []byte(expandEditable(string(content), overrides.leftDelim))
+
+	// validate checks the fields and functions used by the page and its layout before parsing
+	validate := func(name, content string) error {
+		if r.config.validateFields {
+			if err := validateTemplateFields(name, content, overrides.leftDelim, overrides.rightDelim, r.config.validationModel); err != nil {
+				return err
+			}
 		}
+
+		if r.config.validateFuncs {
+			funcMap := maps.Clone(r.config.funcMap)
+			if funcMap == nil {
+				funcMap = make(template.FuncMap, len(overrides.funcMap))
+			}
+			maps.Copy(funcMap, overrides.funcMap)
+			maps.Copy(funcMap, editableFuncs)
+
+			if err := validateTemplateFuncs(name, content, overrides.leftDelim, overrides.rightDelim, funcMap); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
 	// Parse template after validation
-	tmpl, err := template.New(name + ".html").Funcs(r.config.funcMap).Parse(string(content))
+	tmpl := template.New(name+string(r.config.ext)).
+		Delims(overrides.leftDelim, overrides.rightDelim).
+		Funcs(editableFuncs).
+		Funcs(r.config.funcMap).
+		Funcs(overrides.funcMap)
+	if r.config.strict {
+		tmpl.Option("missingkey=error").Funcs(strictFuncs)
+	}
+	if r.config.trace {
+		tmpl.Funcs((*renderTrace)(nil).funcs())
+	}
+	tmpl.Option(overrides.options...)
+
+	frontmatter, locate := r.frontmatter.get(name), r.locate
+	if overrides.source != nil {
+		frontmatter, locate = overrides.source.frontmatter, overrides.source.locate
+	}
+
+	md, err := r.declaredMetadata(name, content, frontmatter, overrides.leftDelim, overrides.rightDelim)
 	if err != nil {
 		return nil, err
 	}
 
-	handler := &Handler[T]{tmpl: tmpl, reg: r}
-	r.templates[name] = handler
-	return handler, nil
+	layout, err := layoutOf(name, md)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := requiredOf[T](name, md)
+	if err != nil {
+		return nil, err
+	}
+
+	cachePolicy, err := cachePolicyOf(name, md)
+	if err != nil {
+		return nil, err
+	}
+
+	// Missing capabilities are reported before the undefined functions they would cause.
+	if err := r.checkNeeds(name, md); err != nil {
+		return nil, err
+	}
+
+	if err := validate(name, string(content)); err != nil {
+		return nil, err
+	}
+
+	deprecations := make(map[string][]Deprecation)
+	deprecate := func(name, file string, content []byte, md Metadata) error {
+		declared, err := r.deprecationsOf(name, file, content, md, overrides.leftDelim, overrides.rightDelim)
+		for key, d := range declared {
+			deprecations[key] = append(deprecations[key], d)
+		}
+		return err
+	}
+	if err := deprecate(name, tmpl.Name(), content, md); err != nil {
+		return nil, err
+	}
+
+	// The layout is parsed before the page, so blocks the page defines override the layout's.
+	if layout != "" {
+		layoutContent, err := r.readTemplate(layout)
+		if err != nil {
+			return nil, err
+		}
+		hashes[layout] = hashSource(layoutContent)
+
+		layoutContent = []byte(expandEditable(string(layoutContent), overrides.leftDelim))
+		if err := validate(layout, string(layoutContent)); err != nil {
+			return nil, err
+		}
+
+		file := layout + string(r.config.ext)
+		if _, err := tmpl.New(file).Parse(string(layoutContent)); err != nil {
+			return nil, r.locate(newParseError(layout, file, err))
+		}
+
+		// The page renders through a copy of the layout, so a deprecated layout is
+		// reported along with the page.
+		if err := deprecate(layout, tmpl.Name(), layoutContent, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tmpl.Parse(string(content)); err != nil {
+		return nil, locate(newParseError(name, tmpl.Name(), err))
+	}
+
+	if layout != "" {
+		if tmpl, err = useLayout(tmpl, layout+string(r.config.ext)); err != nil {
+			return nil, err
+		}
+	}
+
+	partials, err := r.resolvePartials()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, partial := range partials {
+		if partial == name || partial == layout {
+			continue
+		}
+
+		partialContent, err := r.readTemplate(partial)
+		if err != nil {
+			return nil, err
+		}
+		hashes[partial] = hashSource(partialContent)
+
+		file := partial + string(r.config.ext)
+		partialContent = []byte(expandEditable(string(partialContent), overrides.leftDelim))
+		if err := r.addPartial(tmpl, file, partialContent, overrides); err != nil {
+			return nil, r.locate(newParseError(partial, file, err))
+		}
+		if err := deprecate(partial, file, partialContent, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if err := rewriteEditable(strings.TrimSuffix(t.Name(), string(r.config.ext)), t.Tree.Root, overrides.editableMarkers); err != nil {
+			return nil, err
+		}
+		if r.config.strict {
+			strictTree(t.Tree.Root)
+		}
+		if r.config.minify {
+			minifyTree(t.Tree.Root, new(minifier))
+		}
+		if r.config.trace {
+			traceTree(t.Tree.Root)
+		}
+	}
+
+	pristine, err := tmpl.Clone()
+	if err != nil {
+		return nil, newParseError(name, tmpl.Name(), err)
+	}
+
+	src := &source{tmpl: tmpl, pristine: pristine, deps: make(map[string]uint64), required: required, deprecations: deprecations, cache: cachePolicy}
+	if holdsLazy(reflect.TypeFor[T]()) {
+		src.fields = referencedFields(tmpl)
+	}
+	for _, dep := range dependencies(tmpl, r.config.ext) {
+		src.deps[dep] = hashes[dep]
+	}
+
+	h := &Handler[T]{name: name, file: tmpl.Name(), src: src, reg: r, overrides: overrides}
+	if r.config.translator != nil || r.config.direction || r.config.localeFormats != nil {
+		h.translations = newTranslations()
+	}
+	return h, nil
+}
+
+// readTemplate returns the content of the named template, preferring published drafts and
+// going through the group's shared source cache when the registry belongs to a group.
+// Missing files are reported as ErrTemplateNotFound.
+func (r *Registry[T]) readTemplate(name string) ([]byte, error) {
+	return r.readSource(name, false)
+}
+
+// readSource is readTemplate, refreshing the group's cached copy of the file when fresh is set.
+// The content is passed through frontmatter stripping, the registry macros, and preprocessors.
+func (r *Registry[T]) readSource(name string, fresh bool) ([]byte, error) {
+	content, err := r.readRaw(name, fresh)
+	if err != nil {
+		return nil, err
+	}
+
+	content, src, err := r.expand(name, content, fresh)
+	if err != nil {
+		return nil, err
+	}
+	r.storeSource(name, src)
+	return r.preprocess(name, content)
+}
+
+// expand strips the frontmatter of the named template source and expands its macros. What it
+// learns about the source is returned rather than recorded, so drafts can be expanded without
+// changing the state of the live template.
+func (r *Registry[T]) expand(name string, content []byte, fresh bool) ([]byte, sourceInfo, error) {
+	md, body, offset, err := splitFrontmatter(name, content)
+	if err != nil {
+		return nil, sourceInfo{}, err
+	}
+
+	src := sourceInfo{frontmatter: md, offset: offset}
+	if r.config.macros {
+		if body, src.lines, err = r.expandMacros(name, body, offset, fresh); err != nil {
+			return nil, sourceInfo{}, err
+		}
+	}
+	return body, src, nil
+}
+
+func (r *Registry[T]) readRaw(name string, fresh bool) ([]byte, error) {
+	// Names are validated again here since partials, includes and composed
+	// templates reach the file system without going through Get.
+	if err := r.validateName(name); err != nil {
+		return nil, err
+	}
+
+	if content, ok := r.drafts.published(name); ok {
+		return content, nil
+	}
+
+	var (
+		content []byte
+		err     error
+		p       = path.Join(r.config.path, fsPath(name)+string(r.config.ext))
+	)
+	if r.group != nil && !r.config.hotReload && len(r.config.overlays) == 0 && len(r.config.paths) == 0 {
+		content, err = r.group.readSource(p, fresh)
+	} else {
+		content, err = fs.ReadFile(r.fs, p)
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrTemplateNotFound{Name: name}
+	}
+	return content, err
+}
+
+// resolvePartials expands the configured partial patterns into template names.
+// Patterns without glob metacharacters are returned as is.
+func (r *Registry[T]) resolvePartials() ([]string, error) {
+	var (
+		names     []string
+		templates []string
+	)
+	for _, pattern := range r.config.partials {
+		if !strings.ContainsAny(pattern, "*?[\\") {
+			names = append(names, pattern)
+			continue
+		}
+
+		if templates == nil {
+			var err error
+			if templates, err = r.ownTemplates(); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, name := range templates {
+			matched, err := path.Match(pattern, name)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				names = append(names, name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// ListTemplates walks the configured template directory and returns the names of
+// all available templates. Names are relative to the template directory,
+// slash-separated and stripped of their extension, so they can be passed to Get.
+// The templates of mounted registries are listed under their prefix.
+func (r *Registry[T]) ListTemplates() ([]string, error) {
+	names, err := r.ownTemplates()
+	if err != nil {
+		return nil, err
+	}
+	return r.appendMounted(names)
+}
+
+// ownTemplates returns the names of the templates of the registry's own directory,
+// without those of mounted registries.
+func (r *Registry[T]) ownTemplates() ([]string, error) {
+	var names []string
+	err := fs.WalkDir(r.fs, r.config.path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(p) != string(r.config.ext) {
+			return nil
+		}
+
+		rel := p
+		if r.config.path != "." {
+			rel = strings.TrimPrefix(p, r.config.path+"/")
+		}
+		names = append(names, strings.TrimSuffix(rel, string(r.config.ext)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return names, nil
 }
 
 var ErrNilContext = errors.New("nil context")
 
+// ErrBlockNotFound is returned by ExecuteTemplate when the template defines no block of the given name.
+var ErrBlockNotFound = errors.New("block not found")
+
 // Execute renders the template with the provided data and writes the output to the writer.
-// Context cancellation and deadlines are checked before rendering, on each write,
-// and after rendering. Cancellation is best-effort at write boundaries.
+// Output is buffered and written only when rendering succeeds, unless the registry was created
+// with WithUnbufferedWrites. Context cancellation, deadlines, and render budgets (see WithBudget)
+// are checked before rendering and on each write; cancellation and deadlines also after
+// rendering. Cancellation is best-effort at write boundaries.
 func (h *Handler[T]) Execute(ctx context.Context, w io.Writer, data T) error {
+	return h.executeTo(ctx, w, data, !h.reg.config.unbufferedWrites)
+}
+
+// executeTo is Execute, buffering the output when buffered is set.
+func (h *Handler[T]) executeTo(ctx context.Context, w io.Writer, data T, buffered bool) error {
+	if h.reg.config.trace && ctx != nil {
+		return h.traced(ctx, w, func(ctx context.Context) error {
+			return h.executeUntraced(ctx, w, data, buffered)
+		})
+	}
+	return h.executeUntraced(ctx, w, data, buffered)
+}
+
+// executeUntraced is executeTo without WithRenderTracing.
+func (h *Handler[T]) executeUntraced(ctx context.Context, w io.Writer, data T, buffered bool) error {
 	if ctx == nil {
-		return ErrTemplateExecution{Name: h.tmpl.Name(), Err: ErrNilContext}
+		return ErrTemplateExecution{Name: h.file, Err: ErrNilContext}
+	}
+
+	if h.reg.closed.Load() {
+		return ErrTemplateExecution{Name: h.file, Err: ErrRegistryClosed}
+	}
+
+	if err := checkBudget(ctx); err != nil {
+		return ErrTemplateExecution{Name: h.file, Err: err}
+	}
+
+	data = h.enrich(ctx, data)
+
+	defer h.reg.observe(h.name, time.Now())
+
+	render := func(w io.Writer) error {
+		return h.hooked(ctx, data, func() error {
+			if err := h.checkRequired(data); err != nil {
+				return err
+			}
+			if h.cache != nil {
+				return h.executeCached(ctx, w, data)
+			}
+			return h.execute(ctx, w, data)
+		})
+	}
+
+	if archive := h.reg.config.archive; archive != nil {
+		if labels, ok := archive.capture(ctx); ok {
+			return h.renderOrFallback(ctx, w, data, buffered, func(w io.Writer) error {
+				return h.archived(w, data, labels, render)
+			})
+		}
+	}
+	return h.renderOrFallback(ctx, w, data, buffered, render)
+}
+
+// ExecuteTemplate renders the block the template defines with the given name, such as a
+// {{define "row"}} block, instead of the whole template, for partial responses to HTMX or
+// Turbo requests. Execution hooks run as for Execute, but the output is never cached.
+// Rendering a deprecated block logs a warning the first time, as Get does for templates.
+// It returns ErrBlockNotFound, wrapped in ErrTemplateExecution, when no such block exists.
+func (h *Handler[T]) ExecuteTemplate(ctx context.Context, w io.Writer, block string, data T) error {
+	if ctx == nil {
+		return ErrTemplateExecution{Name: h.file, Err: ErrNilContext}
+	}
+
+	if h.reg.closed.Load() {
+		return ErrTemplateExecution{Name: h.file, Err: ErrRegistryClosed}
+	}
+
+	if err := checkBudget(ctx); err != nil {
+		return ErrTemplateExecution{Name: h.file, Err: err}
+	}
+
+	if h.reg.config.logger != nil {
+		h.reg.warnDeprecated(h.src.deprecationsFrom(block))
+	}
+
+	data = h.enrich(ctx, data)
+
+	return h.hooked(ctx, data, func() error {
+		if err := h.checkRequired(data); err != nil {
+			return err
+		}
+		return h.executeBlock(ctx, w, block, data)
+	})
+}
+
+// execute renders the template through a writer that honors ctx.
+func (h *Handler[T]) execute(ctx context.Context, w io.Writer, data T) error {
+	return h.executeBlock(ctx, w, "", data)
+}
+
+// executeBlock renders the named block of the template, or the template itself when block
+// is empty, through a writer that honors ctx.
+func (h *Handler[T]) executeBlock(ctx context.Context, w io.Writer, block string, data T) error {
+	tmpl, err := h.template(ctx)
+	if err != nil {
+		return ErrTemplateExecution{Name: h.file, Err: err}
+	}
+
+	if block != "" {
+		if tmpl = tmpl.Lookup(block); tmpl == nil || tmpl.Tree == nil {
+			return ErrTemplateExecution{Name: h.file, Err: fmt.Errorf("%w: %s", ErrBlockNotFound, block)}
+		}
+	}
+
+	if fields := h.src.lazyFields(); fields != nil {
+		startLazy(ctx, reflect.ValueOf(data), fields, h.reg.config.lazyPolicies)
 	}
 
 	wrappedWriter := contextWriter{Writer: w, ctx: ctx}
 
-	if err := h.tmpl.Execute(wrappedWriter, data); err != nil {
+	if err := tmpl.Execute(wrappedWriter, data); err != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
-			return ErrTemplateExecution{Name: h.tmpl.Name(), Err: ctxErr}
+			return ErrTemplateExecution{Name: h.file, Err: ctxErr}
 		}
-		return ErrTemplateExecution{Name: h.tmpl.Name(), Err: err}
+		execErr := ErrTemplateExecution{Name: h.file, Err: err}
+		execErr.Location, _ = h.reg.execLocation(err)
+		return execErr
 	}
 
 	if err := ctx.Err(); err != nil {
-		return ErrTemplateExecution{Name: h.tmpl.Name(), Err: err}
+		return ErrTemplateExecution{Name: h.file, Err: err}
 	}
 	return nil
 }
 
+// ExecuteAny renders the template with data of any type. It returns ErrDataType when data
+// is not a T; a nil data renders the zero value of T. It is meant for integrations whose
+// interfaces pass data as any, such as web framework renderers.
+func (h *Handler[T]) ExecuteAny(ctx context.Context, w io.Writer, data any) error {
+	var typed T
+	if data != nil {
+		var ok bool
+		if typed, ok = data.(T); !ok {
+			return ErrDataType{
+				Name: h.name,
+				Want: fmt.Sprintf("%T", typed),
+				Got:  fmt.Sprintf("%T", data),
+			}
+		}
+	}
+	return h.Execute(ctx, w, typed)
+}
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// ExecuteToString renders the template with the provided data and returns the output as a string.
+// It renders into a pooled buffer to avoid allocating one per call.
+func (h *Handler[T]) ExecuteToString(ctx context.Context, data T) (string, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+
+	if err := h.Execute(ctx, buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// ExecuteToBytes renders the template with the provided data and returns the output as bytes.
+// The returned slice is a copy and remains valid after the pooled buffer is reused.
+func (h *Handler[T]) ExecuteToBytes(ctx context.Context, data T) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer putBuffer(buf)
+
+	if err := h.Execute(ctx, buf, data); err != nil {
+		return nil, err
+	}
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
 type contextWriter struct {
 	io.Writer
 	ctx context.Context
@@ -169,5 +954,36 @@ func (w contextWriter) Write(p []byte) (int, error) {
 	if err := w.ctx.Err(); err != nil {
 		return 0, err
 	}
+	if err := checkBudget(w.ctx); err != nil {
+		return 0, err
+	}
 	return w.Writer.Write(p)
 }
+
+// validateName rejects template names that could resolve outside the template
+// directory, so names taken from request data are safe to pass to Get.
+func (r *Registry[T]) validateName(name string) error {
+	slashed := strings.ReplaceAll(name, `\`, "/")
+	switch {
+	case name == "":
+		return ErrInvalidTemplateName{Name: name, Reason: "name is empty"}
+	case strings.HasPrefix(slashed, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "":
+		return ErrInvalidTemplateName{Name: name, Reason: "name must be relative to the template directory"}
+	case slices.Contains(strings.Split(slashed, "/"), ".."):
+		return ErrInvalidTemplateName{Name: name, Reason: "name must not contain '..'"}
+	case strings.HasSuffix(slashed, string(r.config.ext)):
+		return ErrInvalidTemplateName{
+			Name:   name,
+			Reason: fmt.Sprintf("name must not include the %s extension", r.config.ext),
+		}
+	case !fs.ValidPath(fsPath(name)) || fsPath(name) == ".":
+		return ErrInvalidTemplateName{Name: name, Reason: "name is not a valid file system path"}
+	}
+	return nil
+}
+
+// fsPath converts p into a slash-separated, cleaned fs.FS path. fs.FS paths
+// always use forward slashes, regardless of the host operating system.
+func fsPath(p string) string {
+	return path.Clean(strings.ReplaceAll(p, `\`, "/"))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	iofs "io/fs"
 	"strings"
 	"sync"
 	"testing"
@@ -67,6 +68,30 @@ func TestNewRegistry(t *testing.T) {
 			opts:    []Option[TestData]{WithTemplatesPath[TestData]("non-existent")},
 			wantErr: false, // Registry creation succeeds, template loading happens later
 		},
+		{
+			name:    "error with invalid path",
+			fs:      fstest.MapFS{},
+			opts:    []Option[TestData]{WithTemplatesPath[TestData]("../templates")},
+			wantErr: true,
+		},
+		{
+			name:    "error with extension missing dot",
+			fs:      fstest.MapFS{},
+			opts:    []Option[TestData]{WithExtension[TestData]("tmpl")},
+			wantErr: true,
+		},
+		{
+			name:    "error with non-function template func",
+			fs:      fstest.MapFS{},
+			opts:    []Option[TestData]{WithTemplateFuncs[TestData](template.FuncMap{"upper": "not a func"})},
+			wantErr: true,
+		},
+		{
+			name:    "error with conflicting cache policy",
+			fs:      fstest.MapFS{},
+			opts:    []Option[TestData]{WithCachePolicy[TestData]("home", CachePolicy{Public: true, Private: true})},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -121,6 +146,36 @@ func TestRegistry_Get(t *testing.T) {
 	})
 }
 
+func TestMustNewRegistry(t *testing.T) {
+	t.Parallel()
+
+	assert.NotPanics(t, func() {
+		assert.NotNil(t, MustNewRegistry[TestData](fstest.MapFS{}))
+	})
+	assert.Panics(t, func() {
+		MustNewRegistry(fstest.MapFS{}, WithTemplatesPath[TestData]("/abs"))
+	})
+}
+
+func TestRegistry_MustGet(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+	}
+
+	reg := MustNewRegistry[TestData](fs)
+
+	handler := reg.MustGet("home")
+	got, err := handler.ExecuteToString(context.Background(), TestData{Title: "Test", Content: "Content"})
+	require.NoError(t, err)
+	assert.Contains(t, got, "Test")
+
+	assert.Panics(t, func() {
+		reg.MustGet("missing")
+	})
+}
+
 func TestHandler(t *testing.T) {
 	t.Parallel()
 
@@ -385,6 +440,90 @@ func TestGet_Error(t *testing.T) {
 	}
 }
 
+func TestGet_TypedErrors(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/invalid.html":        &fstest.MapFile{Data: []byte("<p>\n{{.Title}}\n{{end}}")},
+		"templates/home.html":           &fstest.MapFile{Data: []byte("{{.Title}}")},
+		"templates/components/bad.html": &fstest.MapFile{Data: []byte("{{if .Title}}")},
+	}
+
+	t.Run("missing template", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+
+		_, err := reg.Get("missing")
+
+		var notFound ErrTemplateNotFound
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, "missing", notFound.Name)
+		assert.ErrorIs(t, err, iofs.ErrNotExist)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+
+		for _, name := range []string{
+			"",
+			"../home",
+			"components/../../home",
+			`..\home`,
+			"/templates/home",
+			"home.html",
+		} {
+			_, err := reg.Get(name)
+
+			var invalid ErrInvalidTemplateName
+			require.ErrorAs(t, err, &invalid, name)
+			assert.Equal(t, name, invalid.Name)
+			assert.ErrorIs(t, err, iofs.ErrInvalid)
+		}
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fs)
+
+		_, err := reg.Get("invalid")
+
+		var parseErr ErrTemplateParse
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, "invalid", parseErr.Name)
+		assert.Equal(t, 3, parseErr.Line)
+	})
+
+	t.Run("partial syntax error", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithPartials[TestData]("components/*"))
+
+		_, err := reg.Get("home")
+
+		var parseErr ErrTemplateParse
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, "components/bad", parseErr.Name)
+	})
+
+	t.Run("execution error", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry[TestData](fstest.MapFS{
+			"templates/exec.html": &fstest.MapFile{Data: []byte("{{.Missing}}")},
+		})
+
+		_, err := reg.MustGet("exec").ExecuteToString(context.Background(), TestData{})
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		assert.Equal(t, "exec.html", execErr.Name)
+	})
+}
+
 func TestHandler_WithFuncs(t *testing.T) {
 	t.Parallel()
 
@@ -471,6 +610,44 @@ func TestConcurrentAccess(t *testing.T) {
 	}
 }
 
+func TestRegistry_FSPaths(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"web/templates/home.html":            &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+		"web/templates/components/menu.html": &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+		"web/secret.html":                    &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+	}
+
+	tests := []struct {
+		name string
+		path string
+		tmpl string
+	}{
+		{name: "slash separated", path: "web/templates", tmpl: "components/menu"},
+		{name: "backslash separated path", path: `web\templates`, tmpl: "home"},
+		{name: "backslash separated name", path: "web/templates", tmpl: `components\menu`},
+		{name: "trailing slash", path: "web/templates/", tmpl: "home"},
+		{name: "dot prefixed", path: "./web/templates", tmpl: "./components/menu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reg, err := NewRegistry[TestData](fs, WithTemplatesPath[TestData](tt.path))
+			require.NoError(t, err)
+
+			_, err = reg.Get(tt.tmpl)
+			require.NoError(t, err)
+
+			names, err := reg.ListTemplates()
+			require.NoError(t, err)
+			assert.Equal(t, []string{"components/menu", "home"}, names)
+		})
+	}
+}
+
 func TestRegistry_Options(t *testing.T) {
 	t.Parallel()
 
@@ -538,6 +715,30 @@ func TestWithTemplateFuncs(t *testing.T) {
 	reg, err := NewRegistry(fstest.MapFS{}, WithTemplateFuncs[TestData](funcMap))
 	require.NoError(t, err)
 	require.Equal(t, funcMap, reg.config.funcMap)
+
+	t.Run("merges multiple calls", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"templates/home.html": &fstest.MapFile{Data: []byte("{{.Title | upper}} {{.Content | shout}}")},
+		}
+
+		reg, err := NewRegistry(fs,
+			WithTemplateFuncs[TestData](funcMap),
+			WithTemplateFuncs[TestData](template.FuncMap{
+				"shout": func(s string) string { return strings.ToUpper(s) + "!" },
+			}),
+		)
+		require.NoError(t, err)
+		assert.Len(t, funcMap, 2, "caller's map is not mutated")
+
+		handler, err := reg.Get("home")
+		require.NoError(t, err)
+
+		got, err := handler.ExecuteToString(context.Background(), TestData{Title: "a", Content: "b"})
+		require.NoError(t, err)
+		assert.Equal(t, "A B!", got)
+	})
 }
 
 func TestWithFieldValidation(t *testing.T) {
@@ -579,6 +780,20 @@ func TestWithFieldValidation(t *testing.T) {
 			data:        ComplexData{},
 			shouldError: false,
 		},
+		{
+			name:        "invalid field in define",
+			templateStr: `{{define "row"}}{{.Missing}}{{end}}{{.Title}}`,
+			model:       ComplexData{},
+			shouldError: true,
+			expectedErr: "field 'Missing' not found",
+		},
+		{
+			name:        "invalid field in block",
+			templateStr: `{{block "content" .}}{{.Missing}}{{end}}`,
+			model:       ComplexData{},
+			shouldError: true,
+			expectedErr: "field 'Missing' not found",
+		},
 	}
 
 	for _, tt := range tests {
@@ -612,3 +827,484 @@ func TestWithFieldValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistry_ListTemplates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists html templates relative to the templates path", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"templates/home.html":            &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+			"templates/components/menu.html": &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+			"templates/about.tmpl":           &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+			"other/ignored.html":             &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+		}
+
+		reg, err := NewRegistry[TestData](fs)
+		require.NoError(t, err)
+
+		got, err := reg.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"components/menu", "home"}, got)
+
+		for _, name := range got {
+			_, err := reg.Get(name)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("lists templates from the root of the file system", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"home.html":            &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+			"components/menu.html": &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+		}
+
+		reg, err := NewRegistry[TestData](fs, WithTemplatesPath[TestData]("."))
+		require.NoError(t, err)
+
+		got, err := reg.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"components/menu", "home"}, got)
+	})
+
+	t.Run("returns error for missing templates path", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry[TestData](fstest.MapFS{}, WithTemplatesPath[TestData]("missing"))
+		require.NoError(t, err)
+
+		got, err := reg.ListTemplates()
+		require.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestHandler_ExecuteToString(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/test.html": &fstest.MapFile{
+			Data: []byte("<h1>{{.Title}}</h1>"),
+		},
+		"templates/broken.html": &fstest.MapFile{
+			Data: []byte("{{.Missing}}"),
+		},
+	}
+
+	reg, err := NewRegistry[TestData](fs)
+	require.NoError(t, err)
+
+	handler, err := reg.Get("test")
+	require.NoError(t, err)
+
+	t.Run("renders to string", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := handler.ExecuteToString(context.Background(), TestData{Title: "Hello"})
+		require.NoError(t, err)
+		assert.Equal(t, "<h1>Hello</h1>", got)
+	})
+
+	t.Run("renders to bytes", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := handler.ExecuteToBytes(context.Background(), TestData{Title: "First"})
+		require.NoError(t, err)
+
+		second, err := handler.ExecuteToBytes(context.Background(), TestData{Title: "Second"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "<h1>First</h1>", string(first))
+		assert.Equal(t, "<h1>Second</h1>", string(second))
+	})
+
+	t.Run("returns execution error", func(t *testing.T) {
+		t.Parallel()
+
+		broken, err := reg.Get("broken")
+		require.NoError(t, err)
+
+		got, err := broken.ExecuteToString(context.Background(), TestData{})
+		require.Error(t, err)
+		assert.Empty(t, got)
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+
+		gotBytes, err := broken.ExecuteToBytes(context.Background(), TestData{})
+		require.Error(t, err)
+		assert.Nil(t, gotBytes)
+	})
+}
+
+func TestRegistry_GetWithOptions(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":  &fstest.MapFile{Data: []byte("{{.Title | shout}}")},
+		"templates/delim.html": &fstest.MapFile{Data: []byte("[[.Title | shout]] {{ .Content }}")},
+	}
+
+	reg, err := NewRegistry(fs, WithTemplateFuncs[TestData](template.FuncMap{
+		"shout": strings.ToUpper,
+	}))
+	require.NoError(t, err)
+
+	t.Run("overrides funcs for a single handler", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := reg.Get("home", WithFuncsOnce(template.FuncMap{
+			"shout": func(s string) string { return strings.ToUpper(s) + "!" },
+		}))
+		require.NoError(t, err)
+
+		got, err := handler.ExecuteToString(context.Background(), TestData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "HI!", got)
+
+		shared, err := reg.Get("home")
+		require.NoError(t, err)
+
+		got, err = shared.ExecuteToString(context.Background(), TestData{Title: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "HI", got)
+	})
+
+	t.Run("overrides delimiters for a single handler", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := reg.Get("delim", WithDelimsOnce("[[", "]]"))
+		require.NoError(t, err)
+
+		got, err := handler.ExecuteToString(context.Background(), TestData{Title: "hi", Content: "ignored"})
+		require.NoError(t, err)
+		assert.Equal(t, "HI {{ .Content }}", got)
+	})
+
+	t.Run("does not cache handlers created with options", func(t *testing.T) {
+		t.Parallel()
+
+		first, err := reg.Get("home", WithDelimsOnce("", ""))
+		require.NoError(t, err)
+
+		second, err := reg.Get("home", WithDelimsOnce("", ""))
+		require.NoError(t, err)
+
+		assert.NotSame(t, first, second)
+	})
+}
+
+func TestWithExtension(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/about.tmpl": &fstest.MapFile{Data: []byte("<p>{{.Title}}</p>")},
+		"templates/home.html":  &fstest.MapFile{Data: []byte(testHTMLTemplate)},
+	}
+
+	reg, err := NewRegistry(fs, WithExtension[TestData](".tmpl"))
+	require.NoError(t, err)
+
+	names, err := reg.ListTemplates()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"about"}, names)
+
+	about, err := reg.Get("about")
+	require.NoError(t, err)
+
+	got, err := about.ExecuteToString(context.Background(), TestData{Title: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "<p>hi</p>", got)
+
+	_, err = reg.Get("home")
+	require.Error(t, err)
+}
+
+func TestWithDelims(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/app.html": &fstest.MapFile{Data: []byte(
+			`[[/* templator: title="App" */]]<div id="app">{{ message }}</div><h1>[[.Title]]</h1>[[template "components/nav.html" .]]`,
+		)},
+		"templates/components/nav.html": &fstest.MapFile{Data: []byte(`<nav>[[.Content]]</nav>`)},
+		"templates/invalid.html":        &fstest.MapFile{Data: []byte(`[[.Missing]]`)},
+	}
+
+	reg, err := NewRegistry(fs,
+		WithDelims[TestData]("[[", "]]"),
+		WithPartials[TestData]("components/*"),
+		WithFieldValidation(TestData{}),
+	)
+	require.NoError(t, err)
+
+	got, err := reg.MustGet("app").ExecuteToString(context.Background(), TestData{Title: "Hi", Content: "menu"})
+	require.NoError(t, err)
+	assert.Equal(t, `<div id="app">{{ message }}</div><h1>Hi</h1><nav>menu</nav>`, got)
+
+	md, err := reg.Metadata("app")
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{"title": "App"}, md)
+
+	_, err = reg.Get("invalid")
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr, "field validation uses the registry delimiters")
+	assert.Equal(t, "Missing", validationErr.FieldPath)
+
+	h, err := reg.Get("app", WithDelimsOnce("{{", "}}"))
+	require.Error(t, err, "per-call delimiters take precedence")
+	assert.Nil(t, h)
+}
+
+func TestWithPartials(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html":              &fstest.MapFile{Data: []byte(`{{template "components/header.html" .}}{{template "layout/footer.html"}}`)},
+		"templates/components/header.html": &fstest.MapFile{Data: []byte(`<h1>{{.Title}}</h1>`)},
+		"templates/components/menu.html":   &fstest.MapFile{Data: []byte(`<nav></nav>`)},
+		"templates/layout/footer.html":     &fstest.MapFile{Data: []byte(`<footer></footer>`)},
+	}
+
+	reg, err := NewRegistry(fs, WithPartials[TestData]("components/*", "layout/footer"))
+	require.NoError(t, err)
+
+	home, err := reg.Get("home")
+	require.NoError(t, err)
+
+	got, err := home.ExecuteToString(context.Background(), TestData{Title: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>hi</h1><footer></footer>", got)
+
+	t.Run("returns error for malformed pattern", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(fs, WithPartials[TestData]("components/["))
+		require.Error(t, err)
+		require.Nil(t, reg)
+	})
+}
+
+func TestWithHotReload(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}
+
+	reg, err := NewRegistry(fs, WithHotReload[TestData]())
+	require.NoError(t, err)
+
+	handler, err := reg.Get("home")
+	require.NoError(t, err)
+
+	got, err := handler.ExecuteToString(context.Background(), TestData{Title: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "<h1>hi</h1>", got)
+
+	fs["templates/home.html"] = &fstest.MapFile{Data: []byte("<h2>{{.Title}}</h2>")}
+
+	handler, err = reg.Get("home")
+	require.NoError(t, err)
+
+	got, err = handler.ExecuteToString(context.Background(), TestData{Title: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "<h2>hi</h2>", got)
+}
+
+func TestWithFuncValidation(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("{{.Title | upper .Content}}")},
+	}
+
+	reg, err := NewRegistry(fs,
+		WithTemplateFuncs[TestData](template.FuncMap{"upper": strings.ToUpper}),
+		WithFuncValidation[TestData](),
+	)
+	require.NoError(t, err)
+
+	_, err = reg.Get("home")
+
+	var funcErr *FuncValidationError
+	require.ErrorAs(t, err, &funcErr)
+	assert.Equal(t, "upper", funcErr.FuncName)
+
+	handler, err := reg.Get("home", WithFuncsOnce(template.FuncMap{
+		"upper": func(a, b string) string { return strings.ToUpper(a + b) },
+	}))
+	require.NoError(t, err)
+
+	got, err := handler.ExecuteToString(context.Background(), TestData{Title: "a", Content: "b"})
+	require.NoError(t, err)
+	assert.Equal(t, "BA", got)
+}
+
+func TestNewRegistry_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports every invalid option", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(fstest.MapFS{},
+			WithTemplatesPath[TestData]("/abs"),
+			WithCachePolicy[TestData]("home", CachePolicy{NoStore: true, MaxAge: time.Minute}),
+		)
+		require.Error(t, err)
+		require.Nil(t, reg)
+
+		var optErr ErrInvalidOption
+		require.ErrorAs(t, err, &optErr)
+		assert.Equal(t, []string{"WithTemplatesPath"}, optErr.Options)
+		assert.Contains(t, err.Error(), "invalid option WithTemplatesPath: '/abs' is not a valid fs.FS path")
+		assert.Contains(t, err.Error(), "invalid option WithCachePolicy: template 'home' cache policy sets no-store together with a max age")
+	})
+
+	t.Run("rejects unbuffered writes with an error template", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fstest.MapFS{},
+			WithUnbufferedWrites[TestData](),
+			WithErrorTemplate[TestData]("errors/500"),
+		)
+
+		var optErr ErrInvalidOption
+		require.ErrorAs(t, err, &optErr)
+		assert.Equal(t, []string{"WithUnbufferedWrites", "WithErrorTemplate"}, optErr.Options)
+	})
+
+	t.Run("rejects minification of non-HTML templates", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fstest.MapFS{},
+			WithMinification[TestData](),
+			WithExtension[TestData](".txt"),
+		)
+
+		var optErr ErrInvalidOption
+		require.ErrorAs(t, err, &optErr)
+		assert.Equal(t, []string{"WithMinification", "WithExtension"}, optErr.Options)
+		assert.Contains(t, optErr.Reason, "'.txt' templates are not HTML")
+
+		_, err = NewRegistry(fstest.MapFS{},
+			WithMinification[TestData](),
+			WithExtension[TestData](".gohtml"),
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects cache policies with hot reload", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewRegistry(fstest.MapFS{},
+			WithHotReload[TestData](),
+			WithCachePolicy[TestData]("home", CachePolicy{Public: true, MaxAge: time.Minute}),
+		)
+
+		var optErr ErrInvalidOption
+		require.ErrorAs(t, err, &optErr)
+		assert.Equal(t, []string{"WithCachePolicy", "WithHotReload"}, optErr.Options)
+		assert.Contains(t, optErr.Reason, "template 'home'")
+
+		_, err = NewRegistry(fstest.MapFS{},
+			WithHotReload[TestData](),
+			WithCachePolicy[TestData]("home", CachePolicy{NoStore: true}),
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("rejects nil validation model", func(t *testing.T) {
+		t.Parallel()
+
+		reg, err := NewRegistry(fstest.MapFS{}, WithFieldValidation[any](nil))
+		require.Error(t, err)
+		require.Nil(t, reg)
+		assert.Contains(t, err.Error(), "validation model must not be nil")
+	})
+}
+
+func TestHandler_ExecuteAny(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte("<h1>{{.Title}}</h1>")},
+	}
+
+	reg, err := NewRegistry[TestData](fs)
+	require.NoError(t, err)
+
+	handler, err := reg.Get("home")
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, handler.ExecuteAny(context.Background(), &buf, TestData{Title: "hi"}))
+	assert.Equal(t, "<h1>hi</h1>", buf.String())
+
+	buf.Reset()
+	require.NoError(t, handler.ExecuteAny(context.Background(), &buf, nil))
+	assert.Equal(t, "<h1></h1>", buf.String())
+
+	err = handler.ExecuteAny(context.Background(), &buf, "not test data")
+
+	var dataErr ErrDataType
+	require.ErrorAs(t, err, &dataErr)
+	assert.Equal(t, ErrDataType{Name: "home", Want: "templator.TestData", Got: "string"}, dataErr)
+}
+
+func TestHandler_ExecuteTemplate(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/list.html": &fstest.MapFile{Data: []byte(
+			`<table>{{template "row" .}}</table>{{define "row"}}<tr><td>{{.Title}}</td></tr>{{end}}`,
+		)},
+		"templates/components/badge.html": &fstest.MapFile{Data: []byte(`{{define "badge"}}<b>{{.Content}}</b>{{end}}`)},
+	}
+
+	var hooked []string
+	reg, err := NewRegistry(fs,
+		WithPartials[TestData]("components/*"),
+		WithExecutionHooks[TestData](func(_ context.Context, name string, _ TestData) error {
+			hooked = append(hooked, name)
+			return nil
+		}, nil),
+	)
+	require.NoError(t, err)
+
+	h, err := reg.Get("list")
+	require.NoError(t, err)
+
+	t.Run("renders the named block", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, h.ExecuteTemplate(context.Background(), &buf, "row", TestData{Title: "<a>"}))
+		assert.Equal(t, "<tr><td>&lt;a&gt;</td></tr>", buf.String())
+		assert.Equal(t, []string{"list"}, hooked)
+	})
+
+	t.Run("renders blocks defined by partials", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, h.ExecuteTemplate(context.Background(), &buf, "badge", TestData{Content: "new"}))
+		assert.Equal(t, "<b>new</b>", buf.String())
+	})
+
+	t.Run("returns error for unknown block", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := h.ExecuteTemplate(context.Background(), &buf, "missing", TestData{})
+		require.ErrorIs(t, err, ErrBlockNotFound)
+
+		var execErr ErrTemplateExecution
+		require.ErrorAs(t, err, &execErr)
+		assert.Equal(t, "list.html", execErr.Name)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("leaves the whole template renderable", func(t *testing.T) {
+		got, err := h.ExecuteToString(context.Background(), TestData{Title: "x"})
+		require.NoError(t, err)
+		assert.Equal(t, "<table><tr><td>x</td></tr></table>", got)
+	})
+}
@@ -0,0 +1,155 @@
+package templatortest
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"sync"
+	"testing/fstest"
+
+	"github.com/alesr/templator"
+)
+
+// outputFunc is the template function rendering the canned output of a fake template.
+const outputFunc = "templatortestOutput"
+
+// Call is a render recorded by a FakeRegistry.
+type Call[T any] struct {
+	// Name is the name of the rendered template.
+	Name string
+	Data T
+	Ctx  context.Context
+}
+
+// FakeRegistry is a templator.Registry for testing code that renders templates, such as HTTP
+// handlers, without template files: its templates render canned output or fail with canned
+// errors, and it records every render.
+//
+//	fake := templatortest.NewFakeRegistry[PageData]()
+//	fake.Stub("home", "<h1>home</h1>")
+//	fake.StubError("about", errBoom)
+//
+//	srv := NewServer(fake.Registry)
+//	// ...
+//	calls := fake.CallsTo("home")
+//
+// Templates must be stubbed before they are first retrieved with Get.
+type FakeRegistry[T any] struct {
+	*templator.Registry[T]
+
+	mu      sync.Mutex
+	files   fstest.MapFS
+	outputs map[string]string
+	errs    map[string]error
+	calls   []Call[T]
+}
+
+// NewFakeRegistry returns a FakeRegistry without templates. Options are applied to the
+// underlying registry, after those of the fake.
+func NewFakeRegistry[T any](opts ...templator.Option[T]) *FakeRegistry[T] {
+	f := &FakeRegistry[T]{
+		files:   make(fstest.MapFS),
+		outputs: make(map[string]string),
+		errs:    make(map[string]error),
+	}
+
+	opts = append([]templator.Option[T]{
+		templator.WithTemplateFuncs[T](template.FuncMap{outputFunc: f.output}),
+		templator.WithExecutionHooks[T](f.record, nil),
+	}, opts...)
+
+	reg, err := templator.NewRegistry(fakeFS[T]{f}, opts...)
+	if err != nil {
+		// The fake's own options are valid; only options passed by the test can fail.
+		panic(fmt.Sprintf("templatortest: creating fake registry: %v", err))
+	}
+	f.Registry = reg
+	return f
+}
+
+// Stub makes the named template render output, verbatim.
+func (f *FakeRegistry[T]) Stub(name, output string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.outputs[name] = output
+	delete(f.errs, name)
+	f.addTemplate(name)
+}
+
+// StubError makes the renders of the named template fail with err, wrapped in
+// templator.ErrTemplateExecution as errors of execution hooks are.
+func (f *FakeRegistry[T]) StubError(name string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.errs[name] = err
+	f.addTemplate(name)
+}
+
+// Calls returns the renders recorded so far, in order.
+func (f *FakeRegistry[T]) Calls() []Call[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call[T](nil), f.calls...)
+}
+
+// CallsTo returns the recorded renders of the named template, in order.
+func (f *FakeRegistry[T]) CallsTo(name string) []Call[T] {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []Call[T]
+	for _, call := range f.calls {
+		if call.Name == name {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded renders.
+func (f *FakeRegistry[T]) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+}
+
+// addTemplate adds the template of a stub. f.mu must be held.
+func (f *FakeRegistry[T]) addTemplate(name string) {
+	f.files["templates/"+name+".html"] = &fstest.MapFile{
+		Data: fmt.Appendf(nil, "{{%s %q}}", outputFunc, name),
+	}
+}
+
+// record is the execution hook recording renders and failing those stubbed with an error.
+func (f *FakeRegistry[T]) record(ctx context.Context, name string, data T) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = append(f.calls, Call[T]{Name: name, Data: data, Ctx: ctx})
+	return f.errs[name]
+}
+
+// output returns the canned output of the named template.
+func (f *FakeRegistry[T]) output(name string) template.HTML {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return template.HTML(f.outputs[name])
+}
+
+// fakeFS serves the templates of a fake, which stubs add to concurrently.
+type fakeFS[T any] struct {
+	f *FakeRegistry[T]
+}
+
+func (fsys fakeFS[T]) Open(name string) (fs.File, error) {
+	fsys.f.mu.Lock()
+	defer fsys.f.mu.Unlock()
+
+	return fsys.f.files.Open(name)
+}
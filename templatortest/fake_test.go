@@ -0,0 +1,91 @@
+package templatortest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ctxKey struct{}
+
+func TestFakeRegistry(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	fake := NewFakeRegistry[pageData]()
+	fake.Stub("home", `<h1 class="x">home</h1>`)
+	fake.StubError("about", errBoom)
+
+	// A handler under test, taking the registry as its dependency.
+	handler := func(reg *templator.Registry[pageData]) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			h, err := reg.Get(r.URL.Query().Get("page"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			ctx := context.WithValue(r.Context(), ctxKey{}, "request")
+			if err := h.Execute(ctx, w, pageData{Title: r.URL.Query().Get("title")}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+	}(fake.Registry)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	rec := serve("/?page=home&title=Hi")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `<h1 class="x">home</h1>`, rec.Body.String())
+
+	rec = serve("/?page=about")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = serve("/?page=missing")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	calls := fake.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, "home", calls[0].Name)
+	assert.Equal(t, pageData{Title: "Hi"}, calls[0].Data)
+	assert.Equal(t, "request", calls[0].Ctx.Value(ctxKey{}))
+	assert.Equal(t, "about", calls[1].Name)
+	assert.Equal(t, []Call[pageData]{calls[0]}, fake.CallsTo("home"))
+
+	h, err := fake.Get("about")
+	require.NoError(t, err)
+	err = h.Execute(context.Background(), &httptest.ResponseRecorder{}, pageData{})
+	require.ErrorIs(t, err, errBoom)
+	var execErr templator.ErrTemplateExecution
+	require.ErrorAs(t, err, &execErr)
+
+	fake.Reset()
+	assert.Empty(t, fake.Calls())
+
+	t.Run("restub", func(t *testing.T) {
+		t.Parallel()
+
+		fake := NewFakeRegistry[pageData]()
+		fake.StubError("home", errBoom)
+		h := fake.MustGet("home")
+
+		fake.Stub("home", "ok")
+		out, err := h.ExecuteToString(context.Background(), pageData{})
+		require.NoError(t, err)
+		assert.Equal(t, "ok", out)
+
+		names, err := fake.ListTemplates()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"home"}, names)
+	})
+}
@@ -0,0 +1,107 @@
+// Package templatortest provides helpers for testing templates and the code rendering them:
+// FakeRegistry stands in for a registry in tests of HTTP handlers, and AssertRenders
+// snapshot tests renders against golden files:
+//
+//	func TestHome(t *testing.T) {
+//		reg := templator.MustNewRegistry[HomeData](os.DirFS("."))
+//		templatortest.AssertRenders(t, reg.MustGet("home"), HomeData{Title: "Welcome"}, "testdata/home.golden.html")
+//	}
+//
+// Golden files are created and updated by running the tests with the -update-goldens flag:
+//
+//	go test ./... -update-goldens
+package templatortest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGoldens makes AssertRenders write renders to their golden files instead of comparing
+// them. It is set by the -update-goldens test flag.
+var UpdateGoldens = flag.Bool("update-goldens", false, "write template renders to their golden files")
+
+// Option configures AssertRenders.
+type Option func(*options)
+
+type options struct {
+	ctx   context.Context
+	exact bool
+}
+
+// WithContext returns an Option rendering with ctx, e.g. one carrying a locale, instead of
+// the context of the test.
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// Exact returns an Option comparing renders byte for byte, instead of normalized with
+// templator.NormalizeHTML.
+func Exact() Option {
+	return func(o *options) {
+		o.exact = true
+	}
+}
+
+// AssertRenders renders h with data and compares the output with the golden file at path,
+// reporting a diff when they differ. Outputs are compared normalized with
+// templator.NormalizeHTML, so attribute order, quoting and whitespace changes don't fail the
+// test, unless Exact is set. With UpdateGoldens, the output is written to the golden file
+// instead, creating its directory if needed. It reports whether the assertion passed.
+func AssertRenders[T any](t testing.TB, h *templator.Handler[T], data T, path string, opts ...Option) bool {
+	t.Helper()
+
+	o := options{ctx: t.Context()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var buf bytes.Buffer
+	if err := h.Execute(o.ctx, &buf, data); err != nil {
+		t.Errorf("rendering %s: %v", path, err)
+		return false
+	}
+
+	if *UpdateGoldens {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("updating golden file: %v", err)
+			return false
+		}
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Errorf("updating golden file: %v", err)
+			return false
+		}
+		t.Logf("updated golden file %s", path)
+		return true
+	}
+
+	golden, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("golden file %s does not exist, run the test with -update-goldens to create it", path)
+		return false
+	}
+	if err != nil {
+		t.Errorf("reading golden file: %v", err)
+		return false
+	}
+
+	want, got := golden, buf.Bytes()
+	if !o.exact {
+		// NormalizeHTML doesn't fail.
+		want, _ = templator.NormalizeHTML(want)
+		got, _ = templator.NormalizeHTML(got)
+	}
+	return assert.Equal(t, string(want), string(got),
+		"render differs from golden file %s, run the test with -update-goldens to update it", path)
+}
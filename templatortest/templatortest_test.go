@@ -0,0 +1,135 @@
+package templatortest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageData struct {
+	Title string
+}
+
+// recorder records the failures of an assertion instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Logf(string, ...any) {}
+
+func newHandler(t *testing.T, name, content string) *templator.Handler[pageData] {
+	t.Helper()
+
+	reg, err := templator.NewRegistry[pageData](fstest.MapFS{
+		"templates/" + name + ".html": &fstest.MapFile{Data: []byte(content)},
+	}, templator.WithTranslator[pageData](func(locale, key string, _ ...any) string { return locale + ":" + key }))
+	require.NoError(t, err)
+	return reg.MustGet(name)
+}
+
+func TestAssertRenders(t *testing.T) {
+	t.Parallel()
+
+	h := newHandler(t, "home", "<h1 class=\"title\" id=\"top\">\n  {{.Title}}\n</h1>\n")
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	tests := []struct {
+		name       string
+		golden     string
+		opts       []Option
+		wantErrors []string
+	}{
+		{
+			name:   "equal",
+			golden: "<h1 class=\"title\" id=\"top\">\n  Hi\n</h1>\n",
+		},
+		{
+			name:   "normalized",
+			golden: "<h1 id='top' class='title'>\nHi\n</h1>",
+		},
+		{
+			name:       "exact",
+			golden:     "<h1 id='top' class='title'>\nHi\n</h1>",
+			opts:       []Option{Exact()},
+			wantErrors: []string{"render differs from golden file"},
+		},
+		{
+			name:       "different",
+			golden:     "<h1 class=\"title\" id=\"top\">Bye</h1>",
+			wantErrors: []string{"render differs from golden file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := &recorder{TB: t}
+			ok := AssertRenders(rec, h, pageData{Title: "Hi"}, write(tt.name+".golden.html", tt.golden), tt.opts...)
+			assert.Equal(t, len(tt.wantErrors) == 0, ok)
+			require.Len(t, rec.errors, len(tt.wantErrors))
+			for i, want := range tt.wantErrors {
+				assert.Contains(t, rec.errors[i], want)
+			}
+		})
+	}
+
+	t.Run("missing golden file", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{TB: t}
+		assert.False(t, AssertRenders(rec, h, pageData{}, filepath.Join(dir, "missing.html")))
+		require.Len(t, rec.errors, 1)
+		assert.Contains(t, rec.errors[0], "run the test with -update-goldens to create it")
+	})
+
+	t.Run("render error", func(t *testing.T) {
+		t.Parallel()
+
+		broken := newHandler(t, "broken", "{{.Title.Missing}}")
+		rec := &recorder{TB: t}
+		assert.False(t, AssertRenders(rec, broken, pageData{}, write("broken.golden.html", "")))
+		require.Len(t, rec.errors, 1)
+		assert.Contains(t, rec.errors[0], "rendering")
+	})
+
+	t.Run("context", func(t *testing.T) {
+		t.Parallel()
+
+		translated := newHandler(t, "translated", `{{t "greeting"}}`)
+		ctx := templator.WithLocale(context.Background(), "pt")
+		assert.True(t, AssertRenders(t, translated, pageData{}, write("translated.golden.html", "pt:greeting"), WithContext(ctx)))
+	})
+}
+
+func TestAssertRenders_UpdateGoldens(t *testing.T) {
+	// UpdateGoldens is global, so this test doesn't run in parallel with those reading it.
+	*UpdateGoldens = true
+	t.Cleanup(func() { *UpdateGoldens = false })
+
+	h := newHandler(t, "home", "<p>{{.Title}}</p>")
+	path := filepath.Join(t.TempDir(), "testdata", "home.golden.html")
+
+	assert.True(t, AssertRenders(t, h, pageData{Title: "Hi"}, path))
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hi</p>", string(got))
+}
@@ -0,0 +1,305 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"text/template/parse"
+	"time"
+)
+
+const (
+	// traceEnterFunc and traceLeaveFunc are called around template calls by WithRenderTracing.
+	traceEnterFunc = "templatorTraceEnter"
+	traceLeaveFunc = "templatorTraceLeave"
+	// traceVar is the variable the trace calls are assigned to, so they produce no output.
+	traceVar = "$templatorTrace"
+)
+
+// WithRenderTracing returns an Option for development that times the renders of every
+// template: Execute appends an HTML comment to the output summarizing the time spent in each
+// template the page called, such as partials, layouts and blocks, and the cache hits of
+// handlers with WithCache rendered in the trace, so composition costs show in the browser:
+//
+//	<!-- templator trace: home 1.204ms
+//	kind      name                 calls  time     notes
+//	render    home                 1      1.204ms
+//	template  components/nav.html  1      0.101ms
+//	load      Reviews              1      0.912ms  1 failed, placeholder rendered
+//	-->
+//
+// Template times include the templates they call. Load rows time the Lazy values of the data
+// by field, concurrently with the templates. Every render clones its template to bind
+// the trace, so tracing must not be enabled in production.
+func WithRenderTracing[T any]() Option[T] {
+	return func(r *Registry[T]) {
+		r.config.trace = true
+	}
+}
+
+type (
+	traceKey         struct{}
+	traceFragmentKey struct{}
+)
+
+// StartRenderTrace returns a context collecting the renders made with it into one trace, with
+// WithRenderTracing. Traces otherwise cover a single Execute; starting one per request, e.g. in
+// a middleware, also summarizes in the page's comment the fragments and cached handlers
+// rendered before the page. The fragments themselves get no comment.
+func StartRenderTrace(ctx context.Context) context.Context {
+	if traceFrom(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, traceKey{}, &renderTrace{
+		renders:   make(map[string]*traceStat),
+		templates: make(map[string]*traceStat),
+		loads:     make(map[string]*traceStat),
+	})
+}
+
+// traceFrom returns the trace of ctx, or nil.
+func traceFrom(ctx context.Context) *renderTrace {
+	tr, _ := ctx.Value(traceKey{}).(*renderTrace)
+	return tr
+}
+
+// traceStat aggregates the renders of a handler, the calls of a template or the loads of a
+// field.
+type traceStat struct {
+	calls        int
+	time         time.Duration
+	hits, misses int
+	failed       int
+	placeholder  bool
+}
+
+// renderTrace collects the renders of a trace. Its methods do nothing on a nil trace.
+type renderTrace struct {
+	mu                                    sync.Mutex
+	renders, templates, loads             map[string]*traceStat
+	renderOrder, templateOrder, loadOrder []string
+}
+
+// stat returns the stat of name in stats, adding it to order when new. t.mu must be held.
+func stat(stats map[string]*traceStat, order *[]string, name string) *traceStat {
+	s, ok := stats[name]
+	if !ok {
+		s = new(traceStat)
+		stats[name] = s
+		*order = append(*order, name)
+	}
+	return s
+}
+
+func (t *renderTrace) addRender(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := stat(t.renders, &t.renderOrder, name)
+	s.calls++
+	s.time += d
+}
+
+// enter records the call of a template, listing it in the order templates are first called.
+func (t *renderTrace) enter(name string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stat(t.templates, &t.templateOrder, name)
+}
+
+func (t *renderTrace) addCall(name string, d time.Duration) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := stat(t.templates, &t.templateOrder, name)
+	s.calls++
+	s.time += d
+}
+
+func (t *renderTrace) addCacheLookup(name string, hit bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := stat(t.renders, &t.renderOrder, name)
+	if hit {
+		s.hits++
+	} else {
+		s.misses++
+	}
+}
+
+// addLoad records the load of a Lazy value held by field, failed when err is not nil.
+func (t *renderTrace) addLoad(field string, d time.Duration, err error, mode LazyMode) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := stat(t.loads, &t.loadOrder, field)
+	s.calls++
+	s.time += d
+	if err != nil {
+		s.failed++
+		s.placeholder = s.placeholder || mode == LazyPlaceholder
+	}
+}
+
+// funcs returns the trace functions of a render, timing the template calls it makes.
+func (t *renderTrace) funcs() template.FuncMap {
+	var starts []time.Time
+	return template.FuncMap{
+		traceEnterFunc: func(name string) string {
+			t.enter(name)
+			starts = append(starts, time.Now())
+			return ""
+		},
+		traceLeaveFunc: func(name string) string {
+			if len(starts) == 0 {
+				return ""
+			}
+			start := starts[len(starts)-1]
+			starts = starts[:len(starts)-1]
+			t.addCall(name, time.Since(start))
+			return ""
+		},
+	}
+}
+
+// writeComment writes the trace as an HTML comment, headed by the page it was appended to.
+func (t *renderTrace) writeComment(w io.Writer, page string, d time.Duration) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Names can't end the comment early.
+	safe := strings.NewReplacer("--", "- -").Replace
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n<!-- templator trace: %s %s\n", safe(page), formatTraceTime(d))
+
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "kind\tname\tcalls\ttime\tnotes")
+	for _, name := range t.renderOrder {
+		s := t.renders[name]
+		cache := ""
+		if s.hits+s.misses > 0 {
+			cache = fmt.Sprintf("%d hits, %d misses", s.hits, s.misses)
+		}
+		fmt.Fprintf(tw, "render\t%s\t%d\t%s\t%s\n", safe(name), s.calls, formatTraceTime(s.time), cache)
+	}
+	for _, name := range t.templateOrder {
+		s := t.templates[name]
+		fmt.Fprintf(tw, "template\t%s\t%d\t%s\t\n", safe(name), s.calls, formatTraceTime(s.time))
+	}
+	for _, name := range t.loadOrder {
+		s := t.loads[name]
+		notes := ""
+		if s.failed > 0 {
+			notes = fmt.Sprintf("%d failed", s.failed)
+		}
+		if s.placeholder {
+			notes += ", placeholder rendered"
+		}
+		fmt.Fprintf(tw, "load\t%s\t%d\t%s\t%s\n", safe(name), s.calls, formatTraceTime(s.time), notes)
+	}
+	tw.Flush()
+	b.WriteString("-->\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func formatTraceTime(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(d)/float64(time.Millisecond))
+}
+
+// traced runs render with the trace of ctx, starting one when ctx has none, and appends the
+// trace comment to w when render succeeds, unless ctx renders a fragment.
+func (h *Handler[T]) traced(ctx context.Context, w io.Writer, render func(context.Context) error) error {
+	ctx = StartRenderTrace(ctx)
+	tr := traceFrom(ctx)
+
+	start := time.Now()
+	err := render(ctx)
+	d := time.Since(start)
+	tr.addRender(h.name, d)
+
+	if err != nil || ctx.Value(traceFragmentKey{}) != nil {
+		return err
+	}
+	if err := tr.writeComment(contextWriter{Writer: w, ctx: ctx}, h.name, d); err != nil {
+		return ErrTemplateExecution{Name: h.file, Err: err}
+	}
+	return nil
+}
+
+// traceTree wraps the template calls of a parse tree with calls to the trace functions:
+//
+//	{{$templatorTrace := templatorTraceEnter "nav"}}{{template "nav" .}}{{$templatorTrace := templatorTraceLeave "nav"}}
+//
+// Assignments produce no output, so the output and the escaping contexts are unchanged.
+func traceTree(list *parse.ListNode) {
+	if list == nil {
+		return
+	}
+
+	nodes := make([]parse.Node, 0, len(list.Nodes))
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *parse.TemplateNode:
+			nodes = append(nodes, traceAction(traceEnterFunc, n), n, traceAction(traceLeaveFunc, n))
+			continue
+		case *parse.IfNode:
+			traceTree(n.List)
+			traceTree(n.ElseList)
+		case *parse.RangeNode:
+			traceTree(n.List)
+			traceTree(n.ElseList)
+		case *parse.WithNode:
+			traceTree(n.List)
+			traceTree(n.ElseList)
+		}
+		nodes = append(nodes, node)
+	}
+	list.Nodes = nodes
+}
+
+// traceAction returns an action assigning the call of fn with the name of the called template.
+func traceAction(fn string, call *parse.TemplateNode) *parse.ActionNode {
+	return &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      call.Pos,
+		Line:     call.Line,
+		Pipe: &parse.PipeNode{
+			NodeType: parse.NodePipe,
+			Pos:      call.Pos,
+			Line:     call.Line,
+			Decl:     []*parse.VariableNode{{NodeType: parse.NodeVariable, Pos: call.Pos, Ident: []string{traceVar}}},
+			Cmds: []*parse.CommandNode{{
+				NodeType: parse.NodeCommand,
+				Pos:      call.Pos,
+				Args: []parse.Node{
+					parse.NewIdentifier(fn).SetPos(call.Pos),
+					&parse.StringNode{NodeType: parse.NodeString, Pos: call.Pos, Quoted: fmt.Sprintf("%q", call.Name), Text: call.Name},
+				},
+			}},
+		},
+	}
+}
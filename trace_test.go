@@ -0,0 +1,151 @@
+package templator
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// traceLines returns the rows of the trace comment of out, without times, and the output before it.
+func traceLines(t *testing.T, out string) (string, []string) {
+	t.Helper()
+
+	before, comment, ok := strings.Cut(out, "\n<!-- templator trace: ")
+	require.True(t, ok, "no trace in %q", out)
+	require.True(t, strings.HasSuffix(comment, "-->\n"))
+
+	times := regexp.MustCompile(`\d+\.\d{3}ms`)
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(comment, "-->\n"), "\n") {
+		if line = strings.Join(strings.Fields(times.ReplaceAllString(line, "T")), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return before, lines
+}
+
+func TestWithRenderTracing(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(
+			`<h1>{{.Title}}</h1>{{template "components/nav.html" .}}{{range .Items}}{{template "components/item.html" .}}{{end}}` +
+				`<script>var x = {{.Title}};</script>`)},
+		"templates/components/nav.html":  &fstest.MapFile{Data: []byte(`<nav>{{template "components/item.html" "home"}}</nav>`)},
+		"templates/components/item.html": &fstest.MapFile{Data: []byte(`<a title="{{.}}">{{.}}</a>`)},
+		"templates/card.html":            &fstest.MapFile{Data: []byte(`<div>{{.Title}}</div>`)},
+	}
+
+	type data struct {
+		Title string
+		Items []string
+	}
+
+	t.Run("appends the trace", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithPartials[data]("components/*"), WithRenderTracing[data]())
+
+		out, err := reg.MustGet("home").ExecuteToString(context.Background(), data{Title: "Hi", Items: []string{"a", "b"}})
+		require.NoError(t, err)
+
+		before, lines := traceLines(t, out)
+		assert.Equal(t, `<h1>Hi</h1><nav><a title="home">home</a></nav><a title="a">a</a><a title="b">b</a>`+
+			`<script>var x = "Hi";</script>`, before)
+		assert.Equal(t, []string{
+			"home T",
+			"kind name calls time notes",
+			"render home 1 T",
+			"template components/nav.html 1 T",
+			"template components/item.html 3 T",
+		}, lines)
+	})
+
+	t.Run("without tracing", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithPartials[data]("components/*"))
+		out, err := reg.MustGet("home").ExecuteToString(context.Background(), data{Title: "Hi"})
+		require.NoError(t, err)
+		assert.NotContains(t, out, "templator trace")
+	})
+
+	t.Run("request trace", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithPartials[data]("components/*"), WithRenderTracing[data]())
+		card := reg.MustGet("card").WithCache(time.Minute, func(d data) string { return d.Title })
+		ctx := StartRenderTrace(context.Background())
+
+		for _, title := range []string{"a", "a", "b"} {
+			fragment, err := card.ExecuteFragment(ctx, data{Title: title})
+			require.NoError(t, err)
+			assert.NotContains(t, string(fragment), "templator trace")
+		}
+
+		out, err := reg.MustGet("home").ExecuteToString(ctx, data{Title: "Hi"})
+		require.NoError(t, err)
+
+		_, lines := traceLines(t, out)
+		assert.Equal(t, []string{
+			"home T",
+			"kind name calls time notes",
+			"render card 3 T 1 hits, 2 misses",
+			"render home 1 T",
+			"template components/nav.html 1 T",
+			"template components/item.html 1 T",
+		}, lines)
+	})
+
+	t.Run("translations", func(t *testing.T) {
+		t.Parallel()
+
+		fs := fstest.MapFS{
+			"templates/greet.html": &fstest.MapFile{Data: []byte(`{{t "hello"}}`)},
+		}
+		reg := MustNewRegistry(fs, WithRenderTracing[data](),
+			WithTranslator[data](func(lang, key string, _ ...any) string { return lang + ":" + key }))
+
+		for _, lang := range []string{"pt", "de"} {
+			out, err := reg.MustGet("greet").ExecuteToString(WithLocale(context.Background(), lang), data{})
+			require.NoError(t, err)
+			before, _ := traceLines(t, out)
+			assert.Equal(t, lang+":hello", before)
+		}
+	})
+
+	t.Run("renders repeatedly", func(t *testing.T) {
+		t.Parallel()
+
+		reg := MustNewRegistry(fs, WithPartials[data]("components/*"), WithRenderTracing[data]())
+		h := reg.MustGet("home")
+		for range 3 {
+			out, err := h.ExecuteToString(context.Background(), data{Title: "Hi"})
+			require.NoError(t, err)
+			_, lines := traceLines(t, out)
+			assert.Contains(t, lines, "render home 1 T")
+		}
+
+		var buf strings.Builder
+		require.NoError(t, h.ExecuteTemplate(context.Background(), &buf, "components/item.html", data{}))
+		assert.NotContains(t, buf.String(), "templator trace")
+	})
+}
+
+func TestRenderTrace_CommentSafe(t *testing.T) {
+	t.Parallel()
+
+	ctx := StartRenderTrace(context.Background())
+	traceFrom(ctx).addCall("a-->b", time.Millisecond)
+
+	var buf strings.Builder
+	require.NoError(t, traceFrom(ctx).writeComment(&buf, "page--", time.Millisecond))
+	assert.Equal(t, 1, strings.Count(buf.String(), "-->"))
+	assert.Contains(t, buf.String(), "a- ->b")
+}
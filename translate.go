@@ -0,0 +1,150 @@
+package templator
+
+import (
+	"context"
+	"html/template"
+	"sync"
+)
+
+// Translator looks up the message for key in the catalog of lang, formatting it with args.
+type Translator func(lang, key string, args ...any) string
+
+type localeKey struct{}
+
+// WithLocale returns a context carrying the locale used by translation functions during rendering.
+func WithLocale(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, localeKey{}, lang)
+}
+
+// LocaleFromContext returns the locale carried by ctx and whether it carries one.
+func LocaleFromContext(ctx context.Context) (string, bool) {
+	lang, ok := ctx.Value(localeKey{}).(string)
+	return lang, ok
+}
+
+// WithTranslator returns an Option that installs the template functions t and T, translating
+// message keys with fn in the locale carried by the rendering context (see WithLocale):
+//
+//	<h1>{{t "home.title"}}</h1>
+//	<p>{{T "home.welcome" .Name}}</p>
+//
+// t output is escaped like any other string, while T output is trusted as HTML for messages
+// containing markup. Locales are matched against those declared with WithLocales, when any,
+// and undeclared ones translate with an empty locale. Each handler keeps one copy of its
+// template per locale, with t and T bound to that locale.
+func WithTranslator[T any](fn Translator) Option[T] {
+	return func(r *Registry[T]) {
+		r.config.translator = fn
+		WithTemplateFuncs[T](translatorFuncs(fn, ""))(r)
+	}
+}
+
+// translatorFuncs returns the t and T template functions bound to lang.
+func translatorFuncs(fn Translator, lang string) template.FuncMap {
+	if fn == nil {
+		return nil
+	}
+	return template.FuncMap{
+		"t": func(key string, args ...any) string {
+			return fn(lang, key, args...)
+		},
+		"T": func(key string, args ...any) template.HTML {
+			return template.HTML(fn(lang, key, args...))
+		},
+	}
+}
+
+// translations holds the per-locale copies of a handler template, cloned from base.
+type translations struct {
+	mu     sync.RWMutex
+	base   *template.Template
+	byLang map[string]*template.Template
+}
+
+func newTranslations() *translations {
+	return &translations{byLang: make(map[string]*template.Template)}
+}
+
+// template returns the template to execute for the locale carried by ctx. Without a
+// translator, direction functions or locale formats, it is the parsed template itself.
+// Otherwise, the parsed template is never executed, so it can keep being cloned for new
+// locales. With WithRenderTracing, it is a copy for the render.
+func (h *Handler[T]) template(ctx context.Context) (*template.Template, error) {
+	base := h.src.template()
+	if h.reg.config.trace {
+		return h.tracedTemplate(ctx, base)
+	}
+	if h.translations == nil {
+		return base, nil
+	}
+
+	lang := h.reg.locale(ctx)
+
+	h.translations.mu.RLock()
+	tmpl, ok := h.translations.byLang[lang]
+	current := h.translations.base == base
+	h.translations.mu.RUnlock()
+	if ok && current {
+		return tmpl, nil
+	}
+
+	h.translations.mu.Lock()
+	defer h.translations.mu.Unlock()
+
+	// The copies of a template replaced by Reload are dropped.
+	if h.translations.base != base {
+		clear(h.translations.byLang)
+		h.translations.base = base
+	}
+
+	if tmpl, ok := h.translations.byLang[lang]; ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	h.bindLocale(tmpl, lang)
+
+	h.translations.byLang[lang] = tmpl
+	return tmpl, nil
+}
+
+// tracedTemplate returns a copy of base bound to the trace of ctx, and to its locale when the
+// handler uses translations. The parsed template is never executed with WithRenderTracing,
+// so it can be cloned for every render.
+func (h *Handler[T]) tracedTemplate(ctx context.Context, base *template.Template) (*template.Template, error) {
+	tmpl, err := base.Clone()
+	if err != nil {
+		return nil, err
+	}
+	if h.translations != nil {
+		h.bindLocale(tmpl, h.reg.locale(ctx))
+	}
+	return tmpl.Funcs(traceFrom(ctx).funcs()), nil
+}
+
+// bindLocale binds the locale-dependent functions of tmpl to lang.
+func (h *Handler[T]) bindLocale(tmpl *template.Template, lang string) {
+	tmpl.Funcs(translatorFuncs(h.reg.config.translator, lang))
+	if h.reg.config.direction {
+		tmpl.Funcs(directionFuncs(lang))
+	}
+	if h.reg.config.localeFormats != nil {
+		tmpl.Funcs(localeFormatFuncs(h.reg.LocaleFormat(lang)))
+	}
+}
+
+// locale returns the locale carried by ctx, normalized to a declared locale when WithLocales is used.
+func (r *Registry[T]) locale(ctx context.Context) string {
+	lang, _ := LocaleFromContext(ctx)
+	if len(r.config.locales) == 0 {
+		return lang
+	}
+
+	if candidates := r.localeCandidates(lang); len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package templator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testCatalog = map[string]map[string]string{
+	"":   {"greeting": "Hello, %s!", "note": "<em>new</em>"},
+	"de": {"greeting": "Hallo, %s!", "note": "<em>neu</em>"},
+}
+
+func testTranslator(lang, key string, args ...any) string {
+	return fmt.Sprintf(testCatalog[lang][key], args...)
+}
+
+func TestWithTranslator(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/home.html": &fstest.MapFile{Data: []byte(`<h1>{{t "greeting" .Title}}</h1>{{t "note"}}|{{T "note"}}`)},
+	}
+
+	reg, err := NewRegistry[TestData](
+		fs,
+		WithLocales[TestData]("de"),
+		WithTranslator[TestData](testTranslator),
+		WithFuncValidation[TestData](),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { reg.Close(context.Background()) })
+
+	handler, err := reg.Get("home")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{
+			name: "default locale",
+			ctx:  context.Background(),
+			want: "<h1>Hello, Ana!</h1>&lt;em&gt;new&lt;/em&gt;|<em>new</em>",
+		},
+		{
+			name: "context locale",
+			ctx:  WithLocale(context.Background(), "de-AT"),
+			want: "<h1>Hallo, Ana!</h1>&lt;em&gt;neu&lt;/em&gt;|<em>neu</em>",
+		},
+		{
+			name: "undeclared locale",
+			ctx:  WithLocale(context.Background(), "fr"),
+			want: "<h1>Hello, Ana!</h1>&lt;em&gt;new&lt;/em&gt;|<em>new</em>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			out, err := handler.ExecuteToString(tt.ctx, TestData{Title: "Ana"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out)
+		})
+	}
+
+	t.Run("concurrent locales", func(t *testing.T) {
+		t.Parallel()
+
+		var wg sync.WaitGroup
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				ctx, want := context.Background(), "<h1>Hello, Ana!</h1>"
+				if i%2 == 0 {
+					ctx, want = WithLocale(ctx, "de"), "<h1>Hallo, Ana!</h1>"
+				}
+
+				out, err := handler.ExecuteToString(ctx, TestData{Title: "Ana"})
+				assert.NoError(t, err)
+				assert.Contains(t, out, want)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("cache is scoped by locale", func(t *testing.T) {
+		t.Parallel()
+
+		cached := handler.WithCache(time.Minute, func(d TestData) string { return d.Title })
+
+		en, err := cached.ExecuteToString(context.Background(), TestData{Title: "Ana"})
+		require.NoError(t, err)
+		de, err := cached.ExecuteToString(WithLocale(context.Background(), "de"), TestData{Title: "Ana"})
+		require.NoError(t, err)
+
+		assert.Contains(t, en, "Hello")
+		assert.Contains(t, de, "Hallo")
+	})
+}
+
+func TestLocaleFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := LocaleFromContext(context.Background())
+	assert.False(t, ok)
+
+	lang, ok := LocaleFromContext(WithLocale(context.Background(), "de"))
+	assert.True(t, ok)
+	assert.Equal(t, "de", lang)
+}
@@ -0,0 +1,310 @@
+// Package worker renders templates for jobs consumed from a message queue, for large email or
+// report campaigns that shouldn't render on the request path.
+//
+// Jobs are received in batches and rendered concurrently with Registry.ExecuteBatch. Failures
+// are retried with exponential backoff, and jobs that fail permanently, or run out of
+// attempts, are published to a dead letter queue:
+//
+//	w := worker.New(reg, queue, func(ctx context.Context, dest string) (io.WriteCloser, error) {
+//		return bucket.NewWriter(ctx, dest, nil)
+//	}, worker.WithConcurrency(16), worker.WithDeadLetterQueue(dlq))
+//
+//	if err := w.Run(ctx); err != nil {
+//		log.Fatal(err)
+//	}
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/alesr/templator"
+)
+
+// Job is a render request: the template to render, its JSON-encoded data, and where the output goes.
+type Job struct {
+	ID          string          `json:"id"`
+	Template    string          `json:"template"`
+	Data        json.RawMessage `json:"data"`
+	Destination string          `json:"destination"`
+}
+
+// Queue delivers render jobs. Implementations wrap a message broker, such as SQS, NATS or Kafka.
+type Queue interface {
+	// Receive blocks until jobs are available and returns at most max of them.
+	Receive(ctx context.Context, max int) ([]Job, error)
+	// Ack removes a handled job, rendered or dead-lettered, from the queue. Jobs that
+	// are not acknowledged are expected to be redelivered by the broker.
+	Ack(ctx context.Context, job Job) error
+}
+
+// DeadLetterQueue receives the jobs that failed permanently or ran out of attempts.
+type DeadLetterQueue interface {
+	Publish(ctx context.Context, job Job, err error) error
+}
+
+// Opener opens the destination of a job for writing, e.g. an object store key or a mail outbox.
+type Opener func(ctx context.Context, destination string) (io.WriteCloser, error)
+
+// ErrPermanent wraps the errors that retrying a job cannot fix, such as undecodable data,
+// unknown templates, or templates and data failing validation.
+type ErrPermanent struct {
+	Err error
+}
+
+func (e ErrPermanent) Error() string {
+	return fmt.Sprintf("permanent failure: %v", e.Err)
+}
+
+func (e ErrPermanent) Unwrap() error {
+	return e.Err
+}
+
+// Option configures a Worker.
+type Option func(*config)
+
+type config struct {
+	batchSize   int
+	concurrency int
+	attempts    int
+	baseDelay   time.Duration
+	dlq         DeadLetterQueue
+	onError     func(Job, error)
+}
+
+// WithBatchSize returns an Option that sets how many jobs are received at once. It defaults to 64.
+func WithBatchSize(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// WithConcurrency returns an Option that sets how many jobs of a batch render at once.
+// It defaults to 4.
+func WithConcurrency(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithRetry returns an Option that sets the attempts per job and the backoff before the first
+// retry, doubled on every subsequent one. It defaults to 3 attempts and 100ms.
+func WithRetry(attempts int, baseDelay time.Duration) Option {
+	return func(c *config) {
+		if attempts > 0 {
+			c.attempts = attempts
+		}
+		c.baseDelay = baseDelay
+	}
+}
+
+// WithDeadLetterQueue returns an Option that publishes failed jobs to dlq and acknowledges
+// them. Without one, failed jobs are left unacknowledged for the broker to redeliver.
+func WithDeadLetterQueue(dlq DeadLetterQueue) Option {
+	return func(c *config) {
+		c.dlq = dlq
+	}
+}
+
+// WithErrorHandler returns an Option that reports every failed job, and every failure to
+// acknowledge or dead-letter one, to fn.
+func WithErrorHandler(fn func(Job, error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// Worker renders the jobs of a queue with a registry.
+type Worker[T any] struct {
+	reg    *templator.Registry[T]
+	queue  Queue
+	open   Opener
+	config config
+}
+
+// New creates a Worker rendering the jobs of queue with reg and writing the output to the
+// destinations opened by open.
+func New[T any](reg *templator.Registry[T], queue Queue, open Opener, opts ...Option) *Worker[T] {
+	cfg := config{
+		batchSize:   64,
+		concurrency: 4,
+		attempts:    3,
+		baseDelay:   100 * time.Millisecond,
+		onError:     func(Job, error) {},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Worker[T]{reg: reg, queue: queue, open: open, config: cfg}
+}
+
+// Run consumes jobs until ctx is done, then returns nil once the jobs in flight are handled.
+// Jobs interrupted by the cancellation are left for the broker to redeliver. Run returns the
+// error of a failed Receive.
+func (w *Worker[T]) Run(ctx context.Context) error {
+	for {
+		jobs, err := w.queue.Receive(ctx, w.config.batchSize)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("receive jobs: %w", err)
+		}
+		w.Process(ctx, jobs)
+	}
+}
+
+// Process renders a batch of jobs with Registry.ExecuteBatch, at most the configured
+// concurrency at once, and acknowledges or dead-letters each one. Failed jobs of the batch
+// are retried together. Jobs failing with templator.ErrRegistryClosed are left for the
+// broker to redeliver, even with a dead letter queue.
+func (w *Worker[T]) Process(ctx context.Context, jobs []Job) {
+	var (
+		data    = make([]T, len(jobs))
+		errs    = make([]error, len(jobs))
+		pending = make([]int, 0, len(jobs))
+	)
+	for i, job := range jobs {
+		if len(job.Data) > 0 {
+			if err := json.Unmarshal(job.Data, &data[i]); err != nil {
+				errs[i] = ErrPermanent{Err: fmt.Errorf("decode data of job %s: %w", job.ID, err)}
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+
+	for attempt := 0; attempt < w.config.attempts && len(pending) > 0; attempt++ {
+		if attempt > 0 && !sleep(ctx, w.config.baseDelay<<(attempt-1)) {
+			break
+		}
+		pending = w.attempt(ctx, jobs, data, pending, errs)
+	}
+
+	w.forEach(len(jobs), func(i int) {
+		w.settle(ctx, jobs[i], errs[i])
+	})
+}
+
+// attempt renders the pending jobs, writes their output, and records the outcome of each job
+// in errs. It returns the jobs worth retrying.
+func (w *Worker[T]) attempt(ctx context.Context, jobs []Job, data []T, pending []int, errs []error) []int {
+	var (
+		bufs  = make([]bytes.Buffer, len(pending))
+		batch = make([]templator.BatchItem[T], len(pending))
+	)
+	for k, i := range pending {
+		batch[k] = templator.BatchItem[T]{Template: jobs[i].Template, Data: data[i], W: &bufs[k]}
+	}
+
+	renderErrs := w.reg.ExecuteBatch(ctx, batch, w.config.concurrency)
+	w.forEach(len(pending), func(k int) {
+		err := renderErrs[k]
+		switch {
+		case err == nil:
+			err = w.write(ctx, jobs[pending[k]].Destination, &bufs[k])
+		case permanent(err):
+			err = ErrPermanent{Err: err}
+		}
+		errs[pending[k]] = err
+	})
+
+	var retry []int
+	for _, i := range pending {
+		if err := errs[i]; err != nil && !errors.As(err, new(ErrPermanent)) && !errors.Is(err, templator.ErrRegistryClosed) {
+			retry = append(retry, i)
+		}
+	}
+	return retry
+}
+
+// sleep waits for d and reports whether it elapsed before ctx was done.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// permanent reports whether retrying cannot fix the render error err: the template is unknown
+// or invalid, or the template or the job's data fail validation.
+func permanent(err error) bool {
+	return errors.As(err, new(templator.ErrTemplateNotFound)) ||
+		errors.As(err, new(templator.ErrInvalidTemplateName)) ||
+		errors.As(err, new(templator.ErrTemplateParse)) ||
+		errors.As(err, new(*templator.ValidationError)) ||
+		errors.As(err, new(*templator.FuncValidationError)) ||
+		errors.As(err, new(templator.ErrMissingFields)) ||
+		errors.As(err, new(templator.ErrDataType))
+}
+
+// forEach calls fn with every index below n, at most the configured concurrency at once.
+func (w *Worker[T]) forEach(n int, fn func(i int)) {
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, w.config.concurrency)
+	)
+	for i := range n {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}()
+	}
+	wg.Wait()
+}
+
+// settle acknowledges a job, dead-lettering it first when it failed.
+func (w *Worker[T]) settle(ctx context.Context, job Job, err error) {
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		w.config.onError(job, err)
+		if w.config.dlq == nil || errors.Is(err, templator.ErrRegistryClosed) {
+			return
+		}
+		if err := w.config.dlq.Publish(ctx, job, err); err != nil {
+			w.config.onError(job, fmt.Errorf("publish to dead letter queue: %w", err))
+			return
+		}
+	}
+
+	if err := w.queue.Ack(ctx, job); err != nil {
+		w.config.onError(job, fmt.Errorf("ack: %w", err))
+	}
+}
+
+// write writes rendered output to a destination, which is opened only once rendering succeeded.
+func (w *Worker[T]) write(ctx context.Context, destination string, buf *bytes.Buffer) error {
+	dst, err := w.open(ctx, destination)
+	if err != nil {
+		return fmt.Errorf("open destination %s: %w", destination, err)
+	}
+	if _, err := buf.WriteTo(dst); err != nil {
+		dst.Close()
+		return fmt.Errorf("write destination %s: %w", destination, err)
+	}
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("close destination %s: %w", destination, err)
+	}
+	return nil
+}
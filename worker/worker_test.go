@@ -0,0 +1,261 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/alesr/templator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mailData struct {
+	Name string `json:"name"`
+}
+
+type memQueue struct {
+	mu     sync.Mutex
+	jobs   []Job
+	acked  []string
+	dead   map[string]error
+	writes map[string]string
+	fail   map[string]int // destination: failures left before writes succeed
+}
+
+func newMemQueue(jobs ...Job) *memQueue {
+	return &memQueue{
+		jobs:   jobs,
+		dead:   make(map[string]error),
+		writes: make(map[string]string),
+		fail:   make(map[string]int),
+	}
+}
+
+func (q *memQueue) Receive(ctx context.Context, max int) ([]Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.jobs) == 0 {
+		q.mu.Unlock()
+		<-ctx.Done()
+		q.mu.Lock()
+		return nil, ctx.Err()
+	}
+	n := min(max, len(q.jobs))
+	batch := q.jobs[:n]
+	q.jobs = q.jobs[n:]
+	return batch, nil
+}
+
+func (q *memQueue) Ack(_ context.Context, job Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.acked = append(q.acked, job.ID)
+	return nil
+}
+
+func (q *memQueue) Publish(_ context.Context, job Job, err error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dead[job.ID] = err
+	return nil
+}
+
+func (q *memQueue) open(_ context.Context, dest string) (io.WriteCloser, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.fail[dest] > 0 {
+		q.fail[dest]--
+		return nil, errors.New("storage unavailable")
+	}
+	return &memFile{q: q, dest: dest}, nil
+}
+
+type memFile struct {
+	bytes.Buffer
+	q    *memQueue
+	dest string
+}
+
+func (f *memFile) Close() error {
+	f.q.mu.Lock()
+	defer f.q.mu.Unlock()
+	f.q.writes[f.dest] = f.String()
+	return nil
+}
+
+func TestWorker(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/welcome.html": &fstest.MapFile{Data: []byte("Hello {{.Name}}")},
+	}
+	reg, err := templator.NewRegistry[mailData](fs)
+	require.NoError(t, err)
+
+	q := newMemQueue(
+		Job{ID: "1", Template: "welcome", Data: []byte(`{"name":"Ada"}`), Destination: "out/1"},
+		Job{ID: "2", Template: "welcome", Data: []byte(`{"name":"Bob"}`), Destination: "out/2"},
+		Job{ID: "3", Template: "welcome", Data: []byte(`{"name":`), Destination: "out/3"},
+		Job{ID: "4", Template: "missing", Destination: "out/4"},
+		Job{ID: "5", Template: "welcome", Data: []byte(`{"name":"Eve"}`), Destination: "out/5"},
+		Job{ID: "6", Template: "welcome", Data: []byte(`{"name":"Max"}`), Destination: "out/6"},
+	)
+	q.fail["out/5"] = 1
+	q.fail["out/6"] = 5
+
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+	w := New(reg, q, q.open,
+		WithBatchSize(4),
+		WithConcurrency(2),
+		WithRetry(3, time.Millisecond),
+		WithDeadLetterQueue(q),
+		WithErrorHandler(func(job Job, _ error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, job.ID)
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		return len(q.acked) == 6
+	}, time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	assert.Equal(t, map[string]string{
+		"out/1": "Hello Ada",
+		"out/2": "Hello Bob",
+		"out/5": "Hello Eve",
+	}, q.writes)
+
+	assert.Len(t, q.dead, 3)
+	assert.ErrorAs(t, q.dead["3"], new(ErrPermanent))
+	assert.ErrorAs(t, q.dead["4"], new(templator.ErrTemplateNotFound))
+	assert.ErrorContains(t, q.dead["6"], "storage unavailable")
+	assert.NotErrorAs(t, q.dead["6"], new(ErrPermanent))
+	assert.Equal(t, 2, q.fail["out/6"], "three attempts were made")
+	assert.ElementsMatch(t, []string{"3", "4", "6"}, failed)
+}
+
+func TestWorker_WithoutDeadLetterQueue(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/welcome.html": &fstest.MapFile{Data: []byte("Hello {{.Name}}")},
+	}
+	reg, err := templator.NewRegistry[mailData](fs)
+	require.NoError(t, err)
+
+	q := newMemQueue()
+	w := New(reg, q, q.open, WithRetry(1, 0))
+	w.Process(context.Background(), []Job{
+		{ID: "1", Template: "welcome", Destination: "out/1"},
+		{ID: "2", Template: "missing", Destination: "out/2"},
+	})
+
+	assert.Equal(t, []string{"1"}, q.acked, "failed jobs are left for redelivery")
+	assert.Equal(t, "Hello ", q.writes["out/1"])
+	assert.Empty(t, q.dead)
+}
+
+// flakyFS fails the first opens of a file, like a network file system timing out.
+type flakyFS struct {
+	fs.FS
+
+	mu    sync.Mutex
+	file  string
+	fails int
+}
+
+func (f *flakyFS) Open(name string) (fs.File, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if name == f.file && f.fails > 0 {
+		f.fails--
+		return nil, errors.New("i/o timeout")
+	}
+	return f.FS.Open(name)
+}
+
+func TestWorker_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	fsys := &flakyFS{
+		FS:    fstest.MapFS{"templates/welcome.html": &fstest.MapFile{Data: []byte("Hello {{.Name}}")}},
+		file:  "templates/welcome.html",
+		fails: 2,
+	}
+	reg, err := templator.NewRegistry[mailData](fsys)
+	require.NoError(t, err)
+
+	q := newMemQueue()
+	w := New(reg, q, q.open, WithRetry(3, time.Millisecond), WithDeadLetterQueue(q))
+	w.Process(context.Background(), []Job{
+		{ID: "1", Template: "welcome", Data: []byte(`{"name":"Ada"}`), Destination: "out/1"},
+	})
+
+	assert.Equal(t, []string{"1"}, q.acked)
+	assert.Equal(t, "Hello Ada", q.writes["out/1"])
+	assert.Empty(t, q.dead)
+}
+
+func TestWorker_ClosedRegistry(t *testing.T) {
+	t.Parallel()
+
+	fs := fstest.MapFS{
+		"templates/welcome.html": &fstest.MapFile{Data: []byte("Hello {{.Name}}")},
+	}
+	reg, err := templator.NewRegistry[mailData](fs)
+	require.NoError(t, err)
+	require.NoError(t, reg.Close(context.Background()))
+
+	var failed []error
+	q := newMemQueue()
+	w := New(reg, q, q.open,
+		WithRetry(3, time.Millisecond),
+		WithDeadLetterQueue(q),
+		WithErrorHandler(func(_ Job, err error) { failed = append(failed, err) }),
+	)
+	w.Process(context.Background(), []Job{{ID: "1", Template: "welcome", Destination: "out/1"}})
+
+	assert.Empty(t, q.acked, "jobs are left for another worker")
+	assert.Empty(t, q.dead)
+	require.Len(t, failed, 1)
+	assert.ErrorIs(t, failed[0], templator.ErrRegistryClosed)
+}
+
+func TestWorker_ReceiveError(t *testing.T) {
+	t.Parallel()
+
+	reg, err := templator.NewRegistry[mailData](fstest.MapFS{})
+	require.NoError(t, err)
+
+	w := New(reg, failingQueue{}, nil)
+	assert.ErrorContains(t, w.Run(context.Background()), "receive jobs: broker down")
+}
+
+type failingQueue struct{}
+
+func (failingQueue) Receive(context.Context, int) ([]Job, error) {
+	return nil, errors.New("broker down")
+}
+func (failingQueue) Ack(context.Context, Job) error { return nil }